@@ -153,16 +153,16 @@ import pywebio
 # Unfortunately, only tornado provides an auto-reload feature.  IMPORTANT:
 # the copy of PyWebIO used by Foliage is a fork where I've modified an
 # important function for detecting when the user has closed the app window.
-from   pywebio import start_server
 from   pywebio.output import put_html, put_warning, put_success
 from   pywebio.output import put_tabs, put_image
 from   pywebio.pin import pin, pin_wait_change, put_actions
-from   pywebio.session import run_js
+from   pywebio.session import run_js, eval_js
 from   sidetrack import set_debug, log
 from   tornado.template import Template
 
 import foliage
 from   foliage import __version__
+from   foliage.batch import run_requested_batch
 from   foliage.change_tab import ChangeTab
 from   foliage.credentials import credentials_from_user, credentials_from_keyring
 from   foliage.credentials import use_credentials, credentials_complete
@@ -171,10 +171,12 @@ from   foliage.delete_tab import DeleteTab
 from   foliage.enum_utils import MetaEnum, ExtendedEnum
 from   foliage.folio import Folio
 from   foliage.list_tab import ListTab
-from   foliage.lookup_tab import LookupTab
+from   foliage.lookup_tab import LookupTab, run_lookup, run_requested_lookup
 from   foliage.other_tab import OtherTab
+from   foliage.recent import recent_entry
+from   foliage.server import start_foliage_server, stop_foliage_server
 from   foliage.clean_tab import CleanTab
-from   foliage.system_widget import SystemWidget
+from   foliage.system_widget import SystemWidget, ParentWidget
 from   foliage.ui import quit_app, reload_page, confirm, notify, inside_pyinstaller_app
 from   foliage.ui import note_info, note_warn, note_error, tell_success, tell_failure
 from   foliage.ui import image_data, user_file, JS_CODE, CSS_CODE
@@ -276,8 +278,9 @@ command-line option.
 Additional command-line arguments
 ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
-The default port for the local web server started by Foliage is 8080.  To
-change this port, you can use the option --port followed by a port number.
+The default port for the local web server started by Foliage is 8080, or the
+value of the setting PORT if it is set.  To change this port, you can use the
+option --port followed by a port number.
 
 If given the -V option, this program will print the version and other
 information, and exit without doing anything else.
@@ -306,7 +309,7 @@ Command-line arguments summary
     config_signals()
     config_backup_dir(None if backup_dir == 'B' else backup_dir)
     config_credentials(None if creds_file == 'C' else creds_file, not no_keyring)
-    config_port(port if (port != 'P' and isint(port)) else 8080)
+    config_port(port if (port != 'P' and isint(port)) else config('PORT', default = 8080))
     config_demo_mode(demo_mode)
 
     log_config()
@@ -329,17 +332,31 @@ Command-line arguments summary
         pywebio.platform.utils._index_page_tpl = index_page_template
 
         # Start the widget outside the PyWebIO app so we can stop it later.
-        widget = SystemWidget() if not no_widget else None
+        # If the widget started us, it's already running and it will open
+        # the browser itself once the server is answering; we exit when it
+        # does, since on Windows it can't ask us to.
+        started_by_widget = isint(os.environ.get('FOLIAGE_WIDGET_PID', ''))
+        if started_by_widget:
+            log(f'started by widget process {os.environ["FOLIAGE_WIDGET_PID"]}')
+            widget = ParentWidget(int(os.environ['FOLIAGE_WIDGET_PID']))
+            widget.on_exit(stop_foliage_server)
+        else:
+            widget = SystemWidget() if not no_widget else None
 
-        # cdn = False makes it load PyWebIO JS code from our local copy.
         log('starting PyWebIO server')
         foliage = partial(foliage_page, widget)
-        start_server(foliage, auto_open_webbrowser = True, cdn = False,
-                     port = os.environ['PORT'], debug = os.environ['DEBUG'])
+        start_foliage_server(foliage, port = os.environ['PORT'],
+                             debug = os.environ['DEBUG'] == 'True',
+                             open_webbrowser = not started_by_widget)
     except KeyboardInterrupt as ex:
         # Catch it, but don't treat it as an error; just stop execution.
         log('keyboard interrupt received')
         pass
+    except Interrupted as ex:
+        # Our signal handler raises this on SIGINT.  The systray widget sends
+        # SIGINT when the user chooses Quit from the widget's menu.
+        log('interrupt signal received')
+        pass
     except SystemExit as ex:
         # Thrown by quit_app() during a normal exit.
         log('exit requested')
@@ -386,7 +403,7 @@ Command-line arguments summary
 
 def foliage_page(widget):
     '''Main page creation function and main loop for Foliage.
-    This is handed to the PyWebIO start_server() function in our main().
+    This is handed to start_foliage_server() in our main().
     '''
     os.environ['FOLIAGE_GUI_STARTED'] = 'True'   # Used by ui.py functions.
     log('generating main Foliage page')
@@ -409,8 +426,15 @@ def foliage_page(widget):
         notify('No network -- cannot proceed.')
         quit_app(ask_confirm = False)
 
-    # Make sure we have valid FOLIO credentials.
-    check_credentials()
+    # Make sure we have valid FOLIO credentials.  The system tray widget's
+    # "Re-authenticate" menu item opens the page with ?reauthenticate in the
+    # URL, to let the user get a new token before the current one expires.
+    check_credentials(reauthenticate = reauthentication_requested())
+
+    # Entries in the widget's "Recent" submenu for lookups open the page with
+    # ?recent=N in the URL, to do the lookup again.
+    if (entry := recent_entry(recent_requested())) and entry['kind'] == 'lookup':
+        run_lookup(entry['identifiers'], entry['record_kind'])
 
     # Create a single dict from all the separate pin_watchers dicts.
     watchers  = dict(ChainMap(*[tab.pin_watchers() for tab in _TABS]))
@@ -421,6 +445,10 @@ def foliage_page(widget):
         # Block, waiting for a change event on any of the pins being watched.
         # The timeout is so we can check if the user quit the taskbar widget.
         changed = pin_wait_change(pin_names, timeout = 1)
+        # Lookups asked for by foliage:// links, and batch operations on
+        # files dropped on the widget, arrive through the server.
+        run_requested_lookup()
+        run_requested_batch()
         if (not widget or widget.running()) and not changed:
              continue
         if (widget and not widget.running()):
@@ -622,9 +650,23 @@ def warn_if_demo_mode():
         log('Demo mode not in effect')
 
 
-def check_credentials():
+def reauthentication_requested():
+    '''Return True if the page was opened asking to re-enter credentials.'''
+    search = 'new URLSearchParams(window.location.search).has("reauthenticate")'
+    return bool(eval_js(search))
+
+
+def recent_requested():
+    '''Return the number of the recent entry the page was opened for, or 0.'''
+    search = 'new URLSearchParams(window.location.search).get("recent")'
+    value = eval_js(search)
+    return int(value) if value and value.isdigit() else 0
+
+
+def check_credentials(reauthenticate = False):
     '''Check that the credentials we have are complete and valid.
-    If they are not, ask the user if they want to edit them.
+    If they are not, ask the user if they want to edit them.  If the
+    argument "reauthenticate" is true, ask the user to edit them anyway.
     '''
     def edit_and_use_credentials():
         creds = credentials_from_user(initial_creds = credentials_from_env())
@@ -633,7 +675,7 @@ def check_credentials():
             quit_app(ask_confirm = False)
         use_credentials(creds)
 
-    if not credentials_complete(credentials_from_env()):
+    if reauthenticate or not credentials_complete(credentials_from_env()):
         edit_and_use_credentials()
     if not Folio().credentials_valid():
         # FOLIO might have invalidated users' tokens.
@@ -0,0 +1,103 @@
+'''
+batch.py: start batch operations on files handed to Foliage from outside
+
+The system tray widget lets users drop a file of identifiers (such as a CSV
+file exported from a spreadsheet) on Foliage's icon in the Dock, or on its
+"Send To" entry or desktop launcher, instead of pasting the identifiers into
+the Foliage page.  Pasting a very long list into the browser's text box is
+slow and sometimes fails altogether.  The widget asks the user which
+operation to do and sends the name of the file to the server's /batch
+endpoint (see server.py), which reads the file itself.
+
+As with lookups asked for by foliage:// links, the request is carried out by
+the next Foliage page to check for one (see run_requested_batch()):
+
+* a lookup is done right away, on the "Look up records" tab
+
+* a deletion is started on the "Delete records" tab; the server only takes
+  a request for one that says the user has confirmed it, which the widget
+  asks for with the same warning the tab gives when the user clicks the
+  button, so the tab doesn't ask again
+
+* for a change, the identifiers are put in the "Change records" tab, where
+  the user chooses the change to make; there are too many choices to make
+  in the widget's dialog
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   os.path import basename
+from   pywebio.pin import pin
+from   sidetrack import log
+import threading
+
+from   foliage.change_tab import clear_tab as clear_change_tab
+from   foliage.delete_tab import do_delete
+from   foliage.folio import unique_identifiers
+from   foliage.lookup_tab import run_lookup
+from   foliage.ui import file_text, note_info, show_tab
+
+
+# Exported constants.
+# .............................................................................
+
+OPERATIONS = ['lookup', 'change', 'delete']
+'''The operations that can be asked for.'''
+
+
+# Internal variables.
+# .............................................................................
+
+_requests = []
+'''Batch operations asked for from outside Foliage, as (operation, ids, name).'''
+
+_requests_lock = threading.Lock()
+
+
+# Exported functions.
+# .............................................................................
+
+def request_batch(operation, path, confirmed = False):
+    '''Ask for a batch operation on the identifiers in the file at path.
+
+    A deletion must be "confirmed" by the user beforehand.  Raises ValueError
+    if it isn't, or if the file is not of a kind Foliage can read or has no
+    identifiers in it, and OSError if it can't be read.  This is called
+    from the web server's thread, so the operation itself is left to
+    run_requested_batch().
+    '''
+    if operation not in OPERATIONS:
+        raise ValueError(f'unknown operation: {operation}')
+    if operation == 'delete' and not confirmed:
+        raise ValueError('deletions must be confirmed by the user first')
+    identifiers = unique_identifiers(file_text(path))
+    if not identifiers:
+        raise ValueError(f'no identifiers found in {basename(path)}')
+    log(f'{operation} requested for {len(identifiers)} identifiers in {path}')
+    with _requests_lock:
+        _requests.append((operation, identifiers, basename(path)))
+
+
+def run_requested_batch():
+    '''If a batch operation has been asked for, start it in its tab.'''
+    with _requests_lock:
+        if not _requests:
+            return
+        operation, identifiers, name = _requests.pop(0)
+    if operation == 'lookup':
+        run_lookup(identifiers)
+    elif operation == 'delete':
+        show_tab('Delete records')
+        pin.textbox_delete = '\n'.join(identifiers)
+        do_delete(confirmed = True)
+    else:
+        show_tab('Change records')
+        clear_change_tab()
+        pin.textbox_ids = '\n'.join(identifiers)
+        note_info(f'Loaded {len(identifiers)} identifiers from {name}.'
+                  ' Please choose the change to make.')
@@ -39,6 +39,9 @@ from   foliage.folio import unique_identifiers, back_up_record
 from   foliage.ui import confirm, notify, user_file
 from   foliage.ui import PROGRESS_BOX, PROGRESS_TEXT
 from   foliage.ui import tell_success, tell_warning, tell_failure, stop_processbar
+from   foliage.jobs import job_progress, job_finished
+from   foliage.recent import record_job
+from   foliage.widget_control import widget_last_record
 from   foliage.ui import note_info, note_warn, note_error, tell_success, tell_failure
 
 
@@ -265,6 +268,7 @@ def stop():
     log(f'stopping')
     interrupt()
     stop_processbar()
+    job_finished()
 
 
 _results = []
@@ -274,6 +278,16 @@ def clear_results():
     _results = []
 
 
+def failure_count():
+    return sum(1 for result in _results if not result['success'])
+
+
+def show_progress(operation, done, steps):
+    # This is also where the job waits if the user pauses it from the widget.
+    set_processbar('bar', done/steps)
+    job_progress(operation, done, steps, failure_count())
+
+
 def record_result(record_or_id, success, notes):
     global _results
     id = record_or_id if isinstance(record_or_id, str) else record_or_id.id
@@ -322,6 +336,7 @@ def do_change():
     reset_interrupts()
     steps = 2*len(identifiers)         # We need 2 passes => 2x number of items
     folio = Folio()
+    stopped = False
     with use_scope('output', clear = True):
         try:
             done = 0
@@ -339,7 +354,7 @@ def do_change():
             for id in identifiers:
                 record = folio.record(id)
                 done += 1
-                set_processbar('bar', done/steps)
+                show_progress('Gathering records', done, steps)
                 if not record:
                     failed(id, f'unrecognized identifier **{id}**')
                     continue
@@ -354,7 +369,7 @@ def do_change():
             holdings_done = []
             for record in filter(lambda r: r.kind is RecordKind.HOLDINGS, records):
                 done += 1
-                set_processbar('bar', done/steps)
+                show_progress('Changing records', done, steps)
                 if not change_holdings(record):
                     log(f'couldn\'t change and/or save holdings rec. – skipping items')
                     continue
@@ -373,11 +388,13 @@ def do_change():
                     log(f'skipping {item.id}, assuming it was done in holdings pass')
                 change_item(item)
                 done += 1
-                set_processbar('bar', done/steps)
+                show_progress('Changing records', done, steps)
             set_processbar('bar', 1)
         except Interrupted as ex:
+            # Stopped by the user, here or from the widget.  Still offer the
+            # summary, so they can see what was done before it stopped.
             tell_warning('**Stopped**.')
-            return
+            stopped = True
         except Exception as ex:
             import traceback
             log('Exception info: ' + str(ex) + '\n' + traceback.format_exc())
@@ -385,11 +402,18 @@ def do_change():
             return
         finally:
             stop_processbar()
+            job_finished()
             clear_scope('current_activity')
 
-        what = pluralized('record', identifiers, True)
+        if stopped:
+            what = pluralized('record', _results, True)
+            text = f'Stopped after processing {what}; the rest were not changed.'
+        else:
+            what = pluralized('record', identifiers, True)
+            text = f'Finished changing {what}.'
+        record_job('Changing records', text, _results)
         put_grid([[
-            put_markdown(f'Finished changing {what}.').style('margin-top: 6px'),
+            put_markdown(text).style('margin-top: 6px'),
             put_button('Export summary', outline = True,
                        onclick = lambda: do_export('foliage-changes.csv'),
                        ).style('text-align: right')
@@ -610,6 +634,7 @@ def save_changes(record, context = ''):
     text = {'add': 'added to', 'change': 'changed in', 'delete': 'deleted from'}
     action = text[pin.chg_op]
     succeeded(record.id, f'{field} {action} {record.kind} record', context)
+    widget_last_record(record)
     return True
 
 
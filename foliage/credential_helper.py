@@ -0,0 +1,121 @@
+'''
+credential_helper.py: store secrets using the Go helper program
+
+Foliage keeps the user's FOLIO credentials in the system keyring, using the
+Python keyring package.  That package finds a backend for the system at run
+time, which has been a source of trouble, particularly in PyInstaller-built
+applications.  The Go helper program (foliage-helper, in data/macos-systray-widget/) has a
+"keyring" subcommand that talks to the system's credential store directly,
+and the functions in this module use it:
+
+    foliage-helper keyring get SERVICE ACCOUNT
+    foliage-helper keyring set SERVICE ACCOUNT < secret
+    foliage-helper keyring delete SERVICE ACCOUNT
+
+The program uses the same service and account names as the Python keyring
+package, so credentials stored by one can be read by the other.  It supports
+the macOS Keychain, the Windows Credential Manager, and on Linux, the Secret
+Service (GNOME Keyring or KWallet).  On Linux systems without a Secret
+Service, the program keeps the secrets in a file encrypted with the
+passphrase given by the setting FOLIAGE_KEYRING_PASSPHRASE.
+
+The helper also reads the widget's list of FOLIO tenants (see the widget's
+README), which is a YAML file that Python can't read without another
+package, so that the server only switches to tenants in it:
+
+    foliage-helper tenants --json
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   os.path import exists
+from   sidetrack import log
+import json
+import subprocess
+import sys
+
+from   foliage.system_widget import go_widget_path
+
+
+# Internal constants.
+# .............................................................................
+
+_EXIT_NOT_FOUND = 4
+'''Exit status of the keyring subcommand when there is no such secret.'''
+
+_PLATFORMS = ('darwin', 'win', 'linux')
+'''Values of sys.platform on which the helper supports a credential store.'''
+
+_TIMEOUT = 30
+'''Number of seconds to wait for the helper (which may ask for permission).'''
+
+
+# Exported functions.
+# .............................................................................
+
+def helper_available():
+    '''Return True if the credential helper can be used on this system.'''
+    return sys.platform.startswith(_PLATFORMS) and exists(go_widget_path())
+
+
+def helper_get(service, account):
+    '''Return the secret stored for the service and account, or None.'''
+    result = _run('get', service, account)
+    if result.returncode == _EXIT_NOT_FOUND:
+        return None
+    _check(result, 'get')
+    return result.stdout
+
+
+def helper_set(service, account, secret):
+    '''Store the secret for the service and account.'''
+    _check(_run('set', service, account, input = secret), 'set')
+
+
+def helper_delete(service, account):
+    '''Delete the secret stored for the service and account, if any.'''
+    result = _run('delete', service, account)
+    if result.returncode != _EXIT_NOT_FOUND:
+        _check(result, 'delete')
+
+
+def helper_tenants():
+    '''Return the widget's list of FOLIO tenants, as a list of dicts.
+
+    Each dict has at least the keys "name", "url" and "tenant_id".  Without
+    the helper, or if it can't read the list, the list is empty.
+    '''
+    if not exists(go_widget_path()):
+        return []
+    try:
+        result = subprocess.run([go_widget_path(), 'tenants', '--json'],
+                                capture_output = True, text = True,
+                                timeout = _TIMEOUT)
+        if result.returncode != 0:
+            log('unable to read the list of tenants: ' + result.stderr.strip())
+            return []
+        return json.loads(result.stdout)
+    except (OSError, ValueError, subprocess.TimeoutExpired) as ex:
+        log(f'unable to read the list of tenants: {ex}')
+        return []
+
+
+# Internal functions.
+# .............................................................................
+
+def _run(command, service, account, input = ''):
+    log(f'running credential helper: keyring {command} {service}')
+    return subprocess.run([go_widget_path(), 'keyring', command, service, account],
+                          input = input, capture_output = True, text = True,
+                          timeout = _TIMEOUT)
+
+
+def _check(result, command):
+    if result.returncode != 0:
+        raise RuntimeError(f'credential helper {command} failed: '
+                           + result.stderr.strip())
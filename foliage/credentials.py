@@ -17,7 +17,14 @@ Storing credentials
 The Foliage code only stores credentials outside of itself in one way: by
 writing a combination of the FOLIO token, FOLIO tenant id, and FOLIO OKAPI URL
 under the key "org.caltechlibrary.foliage" in the user's system keyring.
-The values are never written to a file by Foliage.
+Foliage also keeps a copy under a key specific to the tenant (see
+_tenant_ring() below), so that the system tray widget's "FOLIO Server" menu
+can switch Foliage back to a tenant it has used before without asking the
+user to log in again.
+The values are never written to a file by Foliage.  Where the Go widget
+program supports the system's credential store, Foliage uses the program to
+access it (see credential_helper.py); otherwise, it uses the Python keyring
+package.
 
 Passing credentials around within Foliage
 -----------------------------------------
@@ -43,6 +50,7 @@ is open-source software released under a 3-clause BSD license.  Please see the
 file "LICENSE" for more information.
 '''
 
+import base64
 from   collections import namedtuple
 from   commonpy.interrupt import wait
 from   decouple import AutoConfig, Config, RepositoryEmpty, config
@@ -56,6 +64,7 @@ from   pywebio.pin import pin, put_input, put_actions, put_textarea
 from   sidetrack import set_debug, log
 import sys
 import threading
+from   urllib.parse import urlparse
 
 if sys.platform.startswith('win'):
     import keyring.backends
@@ -64,6 +73,8 @@ if sys.platform.startswith('darwin'):
     import keyring.backends
     from keyring.backends.OS_X import Keyring
 
+from foliage.credential_helper import helper_available, helper_get, helper_set
+from foliage.credential_helper import helper_tenants
 from foliage.folio import Folio
 from foliage.ui import confirm, note_info, notify
 
@@ -191,6 +202,14 @@ def credentials_from_user(warn_empty = True, initial_creds = None):
 def credentials_from_keyring(partial_ok = False, ring = _KEYRING):
     '''Look up the user's credentials.
     If partial_ok is False, return None if the keyring value is incomplete.'''
+    if helper_available():
+        log(f'trying to read value from {ring} using credential helper')
+        try:
+            value = helper_get(ring, getpass.getuser())
+        except Exception as ex:
+            log('exception trying to get password from helper: ' + str(ex))
+            return None
+        return _creds_from_keyring_value(value, partial_ok, ring)
     if sys.platform.startswith('win'):
         log('using windows keyring vault')
         keyring.set_keyring(WinVaultKeyring())
@@ -203,13 +222,7 @@ def credentials_from_keyring(partial_ok = False, ring = _KEYRING):
     except Exception as ex:
         log('exception trying to get password from keyring: ' + str(ex))
         return None
-    if value:
-        if __debug__: log(f'got credentials from keyring {ring}')
-        parts = _decoded(value)
-        if all(parts) or partial_ok:
-            return Credentials(url = parts[0], tenant_id = parts[1], token = parts[2])
-    log(f'did not find a value in keyring {ring}')
-    return None
+    return _creds_from_keyring_value(value, partial_ok, ring)
 
 
 def use_credentials(creds):
@@ -222,6 +235,37 @@ def use_credentials(creds):
         keyring_creds = credentials_from_keyring()
         if creds != keyring_creds:
             _store_credentials(creds)
+        if credentials_complete(creds):
+            ring = _tenant_ring(creds.url, creds.tenant_id)
+            if creds != credentials_from_keyring(ring = ring):
+                _store_credentials(creds, ring)
+
+
+def switch_tenant(url, tenant_id):
+    '''Switch to the FOLIO tenant "tenant_id" at the OKAPI URL "url".
+
+    This uses the token last used with the tenant, if one was kept in the
+    keyring.  Returns True if there was one; otherwise the credentials are
+    left without a token, so that the user is asked to log in.
+    '''
+    log(f'switching to tenant {tenant_id} at {url}')
+    creds = None
+    if config('USE_KEYRING', cast = bool):
+        creds = credentials_from_keyring(ring = _tenant_ring(url, tenant_id))
+    if not credentials_complete(creds):
+        creds = Credentials(url = url, tenant_id = tenant_id, token = '')
+    use_credentials(creds)
+    return bool(creds.token)
+
+
+def tenant_configured(url, tenant_id):
+    '''Return True if the tenant is in the widget's list of FOLIO tenants.
+
+    Only those can be switched to, so that nothing can point Foliage (and
+    the credentials the user enters next) at some other server.
+    '''
+    return any(t.get('url', '').rstrip('/') == url.rstrip('/')
+               and t.get('tenant_id') == tenant_id for t in helper_tenants())
 
 
 def current_credentials():
@@ -235,6 +279,26 @@ def credentials_complete(creds):
     '''Return True if the given credentials are complete.'''
     return (creds and creds.url and creds.tenant_id and creds.token)
 
+
+def token_expiration(token):
+    '''Return the time (in seconds since the epoch) when the token expires.
+
+    FOLIO tokens are JSON Web Tokens, and tokens that expire say when in the
+    "exp" claim of their payload.  Returns None if the token does not expire,
+    or if it can't be decoded.
+    '''
+    if not token or token.count('.') != 2:
+        return None
+    payload = token.split('.')[1]
+    try:
+        # JWTs use base64url without padding, which Python insists on.
+        data = base64.urlsafe_b64decode(payload + '=' * (-len(payload) % 4))
+        exp = json.loads(data).get('exp')
+    except Exception as ex:
+        log(f'unable to decode FOLIO token: {str(ex)}')
+        return None
+    return int(exp) if isinstance(exp, (int, float)) else None
+
 
 # Private helper functions.
 # .............................................................................
@@ -246,6 +310,11 @@ very unlikely to be part of a legitimate string value typed by user at a
 shell prompt, because control-c is normally used to interrupt programs.
 '''
 
+def _tenant_ring(url, tenant_id):
+    '''Return the name of the keyring entry for a specific tenant.'''
+    return f'{_KEYRING}.{tenant_id}@{urlparse(url).netloc or url}'
+
+
 def _encoded(url, tenant_id, token):
     return f'{url}{_SEP}{tenant_id}{_SEP}{token}'
 
@@ -269,12 +338,26 @@ def _creds_from_source(source = None, where = ''):
     return creds
 
 
+def _creds_from_keyring_value(value, partial_ok, ring):
+    if value:
+        if __debug__: log(f'got credentials from keyring {ring}')
+        parts = _decoded(value)
+        if all(parts) or partial_ok:
+            return Credentials(url = parts[0], tenant_id = parts[1], token = parts[2])
+    log(f'did not find a value in keyring {ring}')
+    return None
+
+
 def _store_credentials(creds, ring = _KEYRING):
     '''Save the user's credentials.'''
+    value = _encoded(creds.url, creds.tenant_id, creds.token)
+    if helper_available():
+        if __debug__: log(f'storing credentials to {ring} using credential helper')
+        helper_set(ring, getpass.getuser(), value)
+        return
     if sys.platform.startswith('win'):
         keyring.set_keyring(WinVaultKeyring())
     if sys.platform.startswith('darwin'):
         keyring.set_keyring(Keyring())
-    value = _encoded(creds.url, creds.tenant_id, creds.token)
     if __debug__: log(f'storing credentials to keyring {_KEYRING}')
     keyring.set_password(ring, getpass.getuser(), value)
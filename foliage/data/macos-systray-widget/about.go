@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/status"
+)
+
+// showAbout asks the Foliage server for its version and the FOLIO tenant it
+// is using, and shows them in a dialog.  If the server doesn't answer, the
+// dialog says so.
+func showAbout() {
+	var text string
+	if info, err := status.Fetch(serverURL()); err == nil {
+		text = aboutText(info)
+	} else {
+		log.Printf("unable to get status from %s: %v", serverURL(), err)
+		text = locale.Sprintf("Foliage is not responding at %s.", serverURL())
+	}
+	if err := dialog.Info(locale.T("About Foliage"), text); err != nil {
+		log.Printf("unable to show dialog: %v", err)
+		if err == dialog.ErrNotSupported {
+			// Better than nothing: a notification with the same text.
+			notify.Post(notify.Notification{Title: locale.T("About Foliage"), Message: text})
+		}
+	}
+}
+
+// aboutText describes the server for the About dialog.
+func aboutText(info *status.Info) string {
+	var b strings.Builder
+	b.WriteString(locale.Sprintf("Foliage version %s", info.Version) + "\n\n")
+	b.WriteString(locale.Sprintf("Server: %s (process %d)", serverURL(), info.Pid) + "\n")
+	if info.FolioURL != "" {
+		b.WriteString(locale.Sprintf("FOLIO: %s", info.FolioURL) + "\n")
+	}
+	if info.TenantID != "" {
+		b.WriteString(locale.Sprintf("Tenant: %s", info.TenantID) + "\n")
+	}
+	if !info.LoggedIn {
+		b.WriteString(locale.T("Foliage does not have a valid FOLIO token.") + "\n")
+	}
+	if info.DemoMode {
+		b.WriteString(locale.T("Demo mode is on.") + "\n")
+	}
+	b.WriteString("\n" + locale.Sprintf("Python %s on %s", info.Python, info.Platform))
+	return b.String()
+}
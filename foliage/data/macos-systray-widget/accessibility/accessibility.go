@@ -0,0 +1,20 @@
+// Package accessibility tells screen readers what the tray icon shows.
+// Menu items and the widget's dialogs are read out by their titles and
+// labels, but the icon is only a picture, and the states it shows (dimmed,
+// or with a colored dot) mean nothing to someone who can't see them.  On
+// macOS, VoiceOver reads the icon's accessibility label, which this package
+// sets; on Windows, Narrator reads the icon's tooltip, and on Linux, screen
+// readers get the tray entry's title and tooltip, so there is nothing more
+// for this package to do there.
+package accessibility
+
+import "errors"
+
+// ErrNotSupported is returned on systems where the label can't be set.
+var ErrNotSupported = errors.New("accessibility labels are not supported on this system")
+
+// SetTrayLabel makes screen readers call the tray icon by the label, such
+// as "Foliage (not responding)".
+func SetTrayLabel(label string) error {
+	return setTrayLabel(label)
+}
@@ -0,0 +1,55 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package accessibility
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc
+#cgo LDFLAGS: -framework Cocoa
+#import <Cocoa/Cocoa.h>
+#include <stdlib.h>
+
+// statusButton returns the status bar button in the view, or in one of its
+// subviews, or nil.
+static NSStatusBarButton *statusButton(NSView *view) {
+	if ([view isKindOfClass:[NSStatusBarButton class]]) {
+		return (NSStatusBarButton *)view;
+	}
+	for (NSView *sub in [view subviews]) {
+		NSStatusBarButton *b = statusButton(sub);
+		if (b != nil) {
+			return b;
+		}
+	}
+	return nil;
+}
+
+// setTrayLabel sets the accessibility label of the program's status items.
+// The systray library keeps its NSStatusItem to itself, but each status
+// item has a window of its own in the application, holding its button.
+// AppKit is only used on the main thread.
+static void setTrayLabel(char *clabel) {
+	@autoreleasepool {
+		NSString *label = [NSString stringWithUTF8String:clabel];
+		free(clabel);
+		dispatch_async(dispatch_get_main_queue(), ^{
+			for (NSWindow *w in [NSApp windows]) {
+				if (![NSStringFromClass([w class]) isEqualToString:@"NSStatusBarWindow"]) {
+					continue;
+				}
+				NSStatusBarButton *b = statusButton([w contentView]);
+				if (b != nil) {
+					[b setAccessibilityLabel:label];
+				}
+			}
+		});
+	}
+}
+*/
+import "C"
+
+func setTrayLabel(label string) error {
+	// setTrayLabel frees the copy once it has made its own.
+	C.setTrayLabel(C.CString(label))
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin || !cgo
+// +build !darwin !cgo
+
+package accessibility
+
+func setTrayLabel(label string) error {
+	return ErrNotSupported
+}
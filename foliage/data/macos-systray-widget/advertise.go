@@ -0,0 +1,98 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"macos-systray-widget/config"
+	"macos-systray-widget/mdns"
+	"macos-systray-widget/status"
+)
+
+// The service type under which Foliage is advertised.
+const serviceType = "_foliage._tcp"
+
+// The advertisement of Foliage on the local network, if there is one, and
+// what it says.
+var (
+	advertMu   sync.Mutex
+	advert     *mdns.Publication
+	advertText []string
+)
+
+// advertise advertises Foliage on the local network with multicast DNS, as
+// a service of type _foliage._tcp on the port of the Foliage URL, with the
+// FOLIO tenant Foliage is using in the TXT record, so that other tools can
+// find Foliage without being told where it is.  It is given the status of
+// Foliage, or nil if Foliage isn't running, in which case it withdraws the
+// advertisement.  Foliage is only advertised if the setting
+// FOLIAGE_ADVERTISE is true (the Foliage interface has no login of its
+// own), and if the Foliage URL is on this machine.
+func advertise(info *status.Info) {
+	port := localPort(serverURL())
+	if info == nil || port == 0 || !config.Bool("FOLIAGE_ADVERTISE", false) {
+		withdrawAdvert()
+		return
+	}
+	text := []string{"txtvers=1", "version=" + info.Version, "tenant=" + info.TenantID, "path=/"}
+	if u, err := url.Parse(info.FolioURL); err == nil && u.Host != "" {
+		text = append(text, "folio="+u.Host)
+	}
+	advertMu.Lock()
+	defer advertMu.Unlock()
+	if advert != nil && reflect.DeepEqual(text, advertText) {
+		return
+	}
+	if advert != nil {
+		advert.Close()
+		advert = nil
+	}
+	host, _ := os.Hostname()
+	name := "Foliage on " + strings.SplitN(host, ".", 2)[0]
+	p, err := mdns.Publish(mdns.Service{Name: name, Type: serviceType, Port: port, Text: text})
+	if err != nil {
+		log.Printf("unable to advertise Foliage on the local network: %v", err)
+		return
+	}
+	debugf("advertising %q on port %d: %v", name, port, text)
+	advert, advertText = p, text
+}
+
+// withdrawAdvert stops advertising Foliage, if it is being advertised.
+func withdrawAdvert() {
+	advertMu.Lock()
+	defer advertMu.Unlock()
+	if advert != nil {
+		if err := advert.Close(); err != nil {
+			log.Printf("unable to withdraw the advertisement of Foliage: %v", err)
+		}
+		advert, advertText = nil, nil
+	}
+}
+
+// localPort returns the port of a URL for a server on this machine, or 0 if
+// the URL is for a server elsewhere.
+func localPort(rawurl string) int {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return 0
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return 0
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		if u.Scheme == "https" {
+			return 443
+		}
+		return 80
+	}
+	return port
+}
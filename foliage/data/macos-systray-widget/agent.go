@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"macos-systray-widget/agent"
+	"macos-systray-widget/appdirs"
+)
+
+// runAgentCommand carries out --install-agent or --uninstall-agent and
+// returns the exit status.  The agent runs the widget with the same options
+// as this command, plus --start if it knows how to start Foliage, so that
+// the agent keeps both the widget and the Foliage server running.
+func runAgentCommand(o *options) int {
+	var err error
+	if o.uninstallAgent {
+		if err = agent.Uninstall(); err == nil {
+			fmt.Println("Removed the Foliage agent.")
+		}
+	} else {
+		var args []string
+		var logPath string
+		if args, err = loginArgs(o, serverURL()); err == nil {
+			if o.command != "" {
+				args = append(args, "--start")
+			}
+			if logPath, err = agentLogFile(); err == nil {
+				err = agent.Install(args, logPath)
+			}
+		}
+		if err == nil {
+			fmt.Printf("Installed the Foliage agent; its log goes to %s.\n", logPath)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// agentLogFile returns the path of the agent's log file, next to Foliage's
+// own log.
+func agentLogFile() (string, error) {
+	dir, err := appdirs.UserLogDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "widget.log"), nil
+}
@@ -0,0 +1,23 @@
+// Package agent installs the widget as a background service for the current
+// user, for sites that deploy Foliage to many machines.  On macOS, that is a
+// launchd agent, which launchd starts at login and starts again if it
+// crashes.  Unlike the "Start at Login" entries made by package autostart,
+// the agent also records the widget's output in a log file.
+package agent
+
+import "errors"
+
+// ErrNotSupported is returned on systems where no kind of agent is supported.
+var ErrNotSupported = errors.New("installing an agent is not supported on this system")
+
+// Install registers the program and arguments in args (the first element is
+// the path of the program) as the agent, replacing any earlier one, with its
+// log going to the file logPath, and starts it.
+func Install(args []string, logPath string) error {
+	return install(args, logPath)
+}
+
+// Uninstall stops the agent, if it is running, and removes it.
+func Uninstall() error {
+	return uninstall()
+}
@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The label of the launchd agent, which is also the name of its file in
+// ~/Library/LaunchAgents.
+const label = "org.caltechlibrary.foliage.agent"
+
+func plistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", label+".plist"), nil
+}
+
+// install writes the agent's property list and loads it into the user's
+// GUI session, so that it starts now as well as at each login.  KeepAlive
+// restarts the widget if it crashes, but not after the user quits it,
+// which is a successful exit.  The widget writes its log to logPath itself,
+// using its --log-file option, so that it can rotate the file; launchd
+// can't be asked to reopen its output files.  What the widget prints
+// outside its log, such as the report of a crash, goes to another file
+// next to it.
+func install(args []string, logPath string) error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	args = append(args, "--log-file", logPath)
+	outPath := strings.TrimSuffix(logPath, ".log") + "-output.log"
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + label + `</string>
+	<key>ProgramArguments</key>
+	<array>
+`)
+	for _, arg := range args {
+		b.WriteString("\t\t<string>")
+		xml.EscapeText(&b, []byte(arg))
+		b.WriteString("</string>\n")
+	}
+	b.WriteString(`	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ThrottleInterval</key>
+	<integer>30</integer>
+	<key>ProcessType</key>
+	<string>Interactive</string>
+	<key>StandardOutPath</key>
+	<string>`)
+	xml.EscapeText(&b, []byte(outPath))
+	b.WriteString(`</string>
+	<key>StandardErrorPath</key>
+	<string>`)
+	xml.EscapeText(&b, []byte(outPath))
+	b.WriteString(`</string>
+</dict>
+</plist>
+`)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Unload any earlier version first; launchd won't load a label twice.
+	launchctl("bootout", domain()+"/"+label)
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return launchctl("bootstrap", domain(), path)
+}
+
+func uninstall() error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	launchctl("bootout", domain()+"/"+label)
+	return os.Remove(path)
+}
+
+// domain returns the launchd domain of the user's GUI session.
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
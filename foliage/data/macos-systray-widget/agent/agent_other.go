@@ -0,0 +1,12 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package agent
+
+func install(args []string, logPath string) error {
+	return ErrNotSupported
+}
+
+func uninstall() error {
+	return ErrNotSupported
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+)
+
+// The name of the scheduled task, in the root folder of the Task Scheduler
+// library.
+const taskName = "Foliage Agent"
+
+// install registers a Task Scheduler task that runs the widget when the
+// user logs on, and runs it now.  Unlike a shortcut in the Startup folder,
+// a task can be set to restart the widget if it fails.  A task has nowhere
+// to send the program's output, so the widget is told to write its log to
+// logPath itself, using its --log-file option.
+func install(args []string, logPath string) error {
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return err
+	}
+	var quoted []string
+	for _, arg := range args[1:] {
+		quoted = append(quoted, syscall.EscapeArg(arg))
+	}
+	quoted = append(quoted, "--log-file", syscall.EscapeArg(logPath))
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-16"?>
+<Task version="1.2" xmlns="http://schemas.microsoft.com/windows/2004/02/mit/task">
+  <RegistrationInfo>
+    <Description>Keeps the Foliage system tray widget running.</Description>
+  </RegistrationInfo>
+  <Triggers>
+    <LogonTrigger>
+      <Enabled>true</Enabled>
+      <UserId>` + escape(u.Username) + `</UserId>
+    </LogonTrigger>
+  </Triggers>
+  <Principals>
+    <Principal id="Author">
+      <UserId>` + escape(u.Username) + `</UserId>
+      <LogonType>InteractiveToken</LogonType>
+      <RunLevel>LeastPrivilege</RunLevel>
+    </Principal>
+  </Principals>
+  <Settings>
+    <MultipleInstancesPolicy>IgnoreNew</MultipleInstancesPolicy>
+    <DisallowStartIfOnBatteries>false</DisallowStartIfOnBatteries>
+    <StopIfGoingOnBatteries>false</StopIfGoingOnBatteries>
+    <ExecutionTimeLimit>PT0S</ExecutionTimeLimit>
+    <RestartOnFailure>
+      <Interval>PT1M</Interval>
+      <Count>10</Count>
+    </RestartOnFailure>
+  </Settings>
+  <Actions Context="Author">
+    <Exec>
+      <Command>` + escape(args[0]) + `</Command>
+      <Arguments>` + escape(strings.Join(quoted, " ")) + `</Arguments>
+      <WorkingDirectory>` + escape(filepath.Dir(args[0])) + `</WorkingDirectory>
+    </Exec>
+  </Actions>
+</Task>
+`)
+	// schtasks reads the definition from a file, which must be UTF-16 to
+	// match the declaration above.
+	f, err := os.CreateTemp("", "foliage-task-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	err = binary.Write(f, binary.LittleEndian, utf16.Encode([]rune("\ufeff"+b.String())))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	// /F replaces any earlier version of the task.
+	if err := schtasks("/Create", "/TN", taskName, "/XML", f.Name(), "/F"); err != nil {
+		return err
+	}
+	return schtasks("/Run", "/TN", taskName)
+}
+
+func uninstall() error {
+	if schtasks("/Query", "/TN", taskName) != nil {
+		return nil
+	}
+	// Ending a task that isn't running fails, which doesn't matter here.
+	schtasks("/End", "/TN", taskName)
+	return schtasks("/Delete", "/TN", taskName, "/F")
+}
+
+// escape returns s with the characters that are special in XML escaped.
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func schtasks(args ...string) error {
+	cmd := exec.Command("schtasks", args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("schtasks %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Package appdirs returns the directories where Foliage keeps its files.
+// Foliage uses the Python appdirs package, with the application name
+// "Foliage" and the author "CaltechLibrary", and these functions return the
+// same paths that appdirs does for those names.
+package appdirs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"macos-systray-widget/internal/xdg"
+)
+
+const (
+	appName   = "Foliage"
+	appAuthor = "CaltechLibrary"
+)
+
+// UserDataDir returns the directory for Foliage's data files, such as
+// record backups.
+func UserDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support", appName), nil
+	case "windows":
+		return filepath.Join(localAppData(), appAuthor, appName), nil
+	}
+	dir, err := xdg.DataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// UserLogDir returns the directory for Foliage's log file.
+func UserLogDir() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Logs", appName), nil
+	case "windows":
+		dir, err := UserDataDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "Logs"), nil
+	}
+	// appdirs uses the cache directory on other systems, and so does Go's
+	// os.UserCacheDir (honoring XDG_CACHE_HOME in the same way).
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName, "log"), nil
+}
+
+// localAppData returns the Windows local application data folder.
+func localAppData() string {
+	if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.Getenv("USERPROFILE"), "AppData", "Local")
+}
@@ -0,0 +1,252 @@
+// Package audit keeps a log of the changes Foliage's Go programs make to
+// FOLIO, for showing auditors what a batch edit actually changed: each
+// record created, updated or deleted, as it was before and after, who made
+// the change, when, and in which job.  The log is a file with a line of
+// JSON for each change, which is only ever added to, and each line is on the
+// disk before the program goes on.
+//
+// With hash chaining, each line also has a hash of itself and of the line
+// before it (SHA-256, in hex), so that Verify can tell if a line has been
+// changed, taken out or put in since it was written; changing one would
+// mean changing the hashes of every line after it, and of the copy of the
+// last hash kept elsewhere, such as in a ticket or an email, by whoever
+// needs to be sure.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"macos-systray-widget/foliolib"
+)
+
+// Entry is a line of the log.
+type Entry struct {
+	Time     time.Time       `json:"time"`
+	Operator string          `json:"operator"`       // The account on this computer the program ran as.
+	User     string          `json:"user,omitempty"` // The FOLIO user the change was made as.
+	URL      string          `json:"url"`
+	Tenant   string          `json:"tenant"`
+	Job      string          `json:"job,omitempty"` // The job the change was part of.
+	Action   string          `json:"action"`        // create, update or delete.
+	Kind     string          `json:"kind"`
+	ID       string          `json:"id"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+	Prev     string          `json:"prev,omitempty"` // The hash of the line before, with chaining.
+	Hash     string          `json:"hash,omitempty"` // The hash of this line, with chaining.
+}
+
+// Log is an audit log, open for adding to.  It is a foliolib.Auditor.
+type Log struct {
+	Path  string
+	Chain bool // Whether the lines are chained by their hashes.
+
+	mu       sync.Mutex
+	f        *os.File
+	last     string // The hash of the last line.
+	operator string
+}
+
+// Open opens the audit log at path, making it if there is none, to add
+// lines to, chaining them by their hashes if chain is true.
+func Open(path string, chain bool) (*Log, error) {
+	l := &Log{Path: path, Chain: chain, operator: operator()}
+	last, whole, err := lastHash(path)
+	if err != nil {
+		return nil, err
+	}
+	l.last = last
+	if l.f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); err != nil {
+		return nil, err
+	}
+	if !whole {
+		// A line left unfinished by a crash: the next one starts on a line
+		// of its own, and Verify reports the broken one.
+		l.f.Write([]byte("\n"))
+	}
+	return l, nil
+}
+
+// lastHash returns the hash of the last line of the log at path that has
+// one, and whether the file ends with a whole line.
+func lastHash(path string) (string, bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", true, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	var last string
+	whole := true
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			whole = line[len(line)-1] == '\n'
+			var e Entry
+			if json.Unmarshal(line, &e) == nil && e.Hash != "" {
+				last = e.Hash
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return last, whole, nil
+}
+
+// operator returns the name of the account the program is running as.
+func operator() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+type jobKey struct{}
+
+// WithJob returns a context that says changes made with it are part of the
+// job with the id.
+func WithJob(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, jobKey{}, id)
+}
+
+// Audit adds the change to the log.
+func (l *Log) Audit(ctx context.Context, c foliolib.Change) error {
+	e := Entry{
+		Time:     time.Now().UTC(),
+		Operator: l.operator,
+		User:     c.User,
+		URL:      c.URL,
+		Tenant:   c.Tenant,
+		Action:   c.Action,
+		Kind:     string(c.Kind),
+		ID:       c.ID,
+	}
+	e.Job, _ = ctx.Value(jobKey{}).(string)
+	var err error
+	if c.Before != nil {
+		if e.Before, err = json.Marshal(c.Before); err != nil {
+			return err
+		}
+	}
+	if c.After != nil {
+		if e.After, err = json.Marshal(c.After); err != nil {
+			return err
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Chain {
+		e.Prev = l.last
+		if e.Hash, err = hash(e); err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	if l.Chain {
+		l.last = e.Hash
+	}
+	return nil
+}
+
+// Close closes the log.
+func (l *Log) Close() error {
+	return l.f.Close()
+}
+
+// hash returns the hash of the entry, which is of its JSON without the hash,
+// and so includes the hash of the line before.
+func hash(e Entry) (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Read calls each for each entry in the log at path, in order, with its line
+// number, stopping at the first error from each.  Lines that aren't
+// entries are an error.
+func Read(path string, each func(line int, e Entry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var e Entry
+			if jerr := json.Unmarshal(line, &e); jerr != nil {
+				return fmt.Errorf("%s, line %d: not an audit log entry: %v", path, n, jerr)
+			}
+			if eerr := each(n, e); eerr != nil {
+				return eerr
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// Verification is what Verify found.
+type Verification struct {
+	Entries int
+	Chained int    // Entries with hashes.
+	Last    string // The hash of the last entry with one.
+}
+
+// Verify checks the hash chain of the log at path: that each entry with a
+// hash has the right one, and says that the one before it is the last
+// entry with a hash before it.  Entries without hashes are only allowed
+// before the first one with a hash, as written before chaining was turned
+// on; one after it fails, since it could have been put in.  The error says
+// where the first entry that fails is.
+func Verify(path string) (Verification, error) {
+	var v Verification
+	err := Read(path, func(n int, e Entry) error {
+		v.Entries++
+		if e.Hash == "" {
+			if v.Last != "" {
+				return fmt.Errorf("%s, line %d: the entry has no hash, though the ones before it are chained; entries have been put in, or chaining has been turned off", path, n)
+			}
+			return nil
+		}
+		if e.Prev != v.Last {
+			return fmt.Errorf("%s, line %d: the entry doesn't follow the one before it; entries have been taken out, put in or changed", path, n)
+		}
+		if h, err := hash(e); err != nil || h != e.Hash {
+			return fmt.Errorf("%s, line %d: the entry has been changed since it was written", path, n)
+		}
+		v.Chained++
+		v.Last = e.Hash
+		return nil
+	})
+	return v, err
+}
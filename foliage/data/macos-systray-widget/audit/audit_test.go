@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"macos-systray-widget/foliolib"
+)
+
+// write adds a change to the log at path for each of the ids, chaining them
+// if chain is true.
+func write(t *testing.T, path string, chain bool, ids ...string) {
+	t.Helper()
+	l, err := Open(path, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	for _, id := range ids {
+		c := foliolib.Change{Action: foliolib.Updated, Kind: foliolib.Item, ID: id,
+			Before: foliolib.Record{"id": id, "status": "Available"},
+			After:  foliolib.Record{"id": id, "status": "Withdrawn"}}
+		if err := l.Audit(context.Background(), c); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		make    func(t *testing.T, path string)
+		entries int
+		chained int
+		wantErr string // "" if the log should be intact.
+	}{
+		{"chained", func(t *testing.T, path string) {
+			write(t, path, true, "i1", "i2", "i3")
+		}, 3, 3, ""},
+		{"legacy prefix", func(t *testing.T, path string) {
+			write(t, path, false, "i1", "i2")
+			write(t, path, true, "i3")
+		}, 3, 1, ""},
+		{"unchained after chained", func(t *testing.T, path string) {
+			write(t, path, true, "i1", "i2")
+			write(t, path, false, "i3")
+		}, 3, 2, "line 3: the entry has no hash"},
+		{"changed", func(t *testing.T, path string) {
+			write(t, path, true, "i1", "i2")
+			edit(t, path, func(lines []string) []string {
+				lines[0] = strings.Replace(lines[0], "Withdrawn", "Missing", 1)
+				return lines
+			})
+		}, 1, 0, "line 1: the entry has been changed"},
+		{"taken out", func(t *testing.T, path string) {
+			write(t, path, true, "i1", "i2", "i3")
+			edit(t, path, func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			})
+		}, 2, 1, "line 2: the entry doesn't follow"},
+		{"hash taken off", func(t *testing.T, path string) {
+			write(t, path, true, "i1", "i2")
+			edit(t, path, func(lines []string) []string {
+				lines[1] = lines[1][:strings.Index(lines[1], `,"prev"`)] + "}"
+				return lines
+			})
+		}, 2, 1, "line 2: the entry has no hash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			tt.make(t, path)
+			v, err := Verify(path)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Verify: got error %v, want one with %q", err, tt.wantErr)
+			}
+			if v.Entries != tt.entries || v.Chained != tt.chained {
+				t.Errorf("got %d entries, %d chained; want %d, %d", v.Entries, v.Chained, tt.entries, tt.chained)
+			}
+			if tt.wantErr == "" {
+				last, _, err := lastHash(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v.Last != last || last == "" {
+					t.Errorf("got last hash %q, want %q", v.Last, last)
+				}
+			}
+		})
+	}
+}
+
+// edit changes the lines of the log at path with f.
+func edit(t *testing.T, path string, f func([]string) []string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := f(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
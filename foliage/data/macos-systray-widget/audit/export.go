@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Filter says which entries to export.  The zero Filter takes them all.
+type Filter struct {
+	From, To time.Time // Entries from From up to, but not including, To.
+	Job      string
+}
+
+func (f Filter) takes(e Entry) bool {
+	return (f.From.IsZero() || !e.Time.Before(f.From)) &&
+		(f.To.IsZero() || e.Time.Before(f.To)) &&
+		(f.Job == "" || e.Job == f.Job)
+}
+
+// Export writes the entries in the log at path that the filter takes to w as
+// CSV, with a header.  An update has a row for each field it changed, with
+// the field's values before and after as JSON; a record created or deleted
+// has a single row with the whole record.  It returns the number of
+// entries written.
+func Export(path string, w io.Writer, filter Filter) (int, error) {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Time", "Operator", "FOLIO user", "Tenant", "Job", "Action", "Kind", "ID", "Field", "Before", "After", "Hash"})
+	n := 0
+	err := Read(path, func(_ int, e Entry) error {
+		if !filter.takes(e) {
+			return nil
+		}
+		n++
+		row := func(field, before, after string) error {
+			return cw.Write([]string{e.Time.Format(time.RFC3339), e.Operator, e.User, e.Tenant, e.Job,
+				e.Action, e.Kind, e.ID, field, before, after, e.Hash})
+		}
+		if e.Action != "update" || e.Before == nil || e.After == nil {
+			return row("", string(e.Before), string(e.After))
+		}
+		var before, after map[string]json.RawMessage
+		if json.Unmarshal(e.Before, &before) != nil || json.Unmarshal(e.After, &after) != nil {
+			return row("", string(e.Before), string(e.After))
+		}
+		for _, field := range changed(before, after) {
+			if err := row(field, string(before[field]), string(after[field])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	return n, err
+}
+
+// changed returns the names of the top-level fields that differ between
+// before and after, in order, leaving out the ones FOLIO keeps up to date
+// itself.
+func changed(before, after map[string]json.RawMessage) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for _, m := range []map[string]json.RawMessage{before, after} {
+		for field := range m {
+			if seen[field] || field == "_version" || field == "metadata" {
+				continue
+			}
+			seen[field] = true
+			if !same(before[field], after[field]) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// same returns whether a and b are the same JSON value.
+func same(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return string(a) == string(b)
+	}
+	ax, _ := json.Marshal(x)
+	by, _ := json.Marshal(y)
+	return string(ax) == string(by)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/audit"
+	"macos-systray-widget/config"
+)
+
+// The audit log of the changes foliaged makes to FOLIO (see package audit),
+// opened when it first makes a client.
+var (
+	auditOnce sync.Once
+	auditLog  *audit.Log
+	auditErr  error
+)
+
+// auditPath returns where the audit log is kept: the setting
+// FOLIAGE_AUDIT_LOG, or audit.jsonl in foliaged's data directory.  A
+// setting of "off" turns the log off, and the path is "".
+func auditPath() (string, error) {
+	path := config.Get("FOLIAGE_AUDIT_LOG", "")
+	if strings.EqualFold(path, "off") {
+		return "", nil
+	} else if path != "" {
+		return path, nil
+	}
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "foliaged", "audit.jsonl"), nil
+}
+
+// openAudit returns the audit log, opening it the first time, chained if
+// FOLIAGE_AUDIT_CHAIN is set; it is nil if the log is turned off.
+func openAudit() (*audit.Log, error) {
+	auditOnce.Do(func() {
+		var path string
+		if path, auditErr = auditPath(); auditErr != nil || path == "" {
+			return
+		}
+		if auditErr = os.MkdirAll(filepath.Dir(path), 0o700); auditErr != nil {
+			return
+		}
+		auditLog, auditErr = audit.Open(path, config.Bool("FOLIAGE_AUDIT_CHAIN", false))
+		if auditErr != nil {
+			auditErr = fmt.Errorf("unable to open the audit log: %w", auditErr)
+		}
+	})
+	return auditLog, auditErr
+}
+
+// runAudit is "foliaged audit": "export" writes the audit log as CSV, and
+// "verify" checks its hash chain.
+func runAudit(args []string) int {
+	usage := func() int {
+		name := foliagedName()
+		fmt.Fprintf(os.Stderr, "usage: %s audit export [--from DATE] [--to DATE] [--job ID] [--log FILE]\n"+
+			"       %s audit verify [--log FILE]\n", name, name)
+		return 2
+	}
+	if len(args) == 0 {
+		return usage()
+	}
+	path, err := auditPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fs := subcommandFlags("foliaged")
+	logPath := fs.String("log", path, "the audit log")
+	switch args[0] {
+	case "export":
+		from := fs.String("from", "", "export the changes from this date (YYYY-MM-DD) or time (RFC 3339) on")
+		to := fs.String("to", "", "export the changes up to, and including, this date, or up to this time")
+		job := fs.String("job", "", "export only the changes made by the job with this id")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 || *logPath == "" {
+			return usage()
+		}
+		filter := audit.Filter{Job: *job}
+		var err error
+		if filter.From, err = auditTime(*from, false); err == nil {
+			filter.To, err = auditTime(*to, true)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		n, err := audit.Export(*logPath, os.Stdout, filter)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "%d changes\n", n)
+		return 0
+	case "verify":
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 || *logPath == "" {
+			return usage()
+		}
+		v, err := audit.Verify(*logPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%d entries, %d of them chained, all intact\n", v.Entries, v.Chained)
+		if v.Last != "" {
+			fmt.Printf("last hash %s\n", v.Last)
+		}
+		return 0
+	}
+	return usage()
+}
+
+// auditTime parses a --from or --to time, which is a date or an RFC 3339
+// time; a date for --to (end) means up to the end of the day.
+func auditTime(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("%q is neither a date (YYYY-MM-DD) nor an RFC 3339 time", s)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
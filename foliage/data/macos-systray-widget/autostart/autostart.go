@@ -0,0 +1,26 @@
+// Package autostart registers a program to run when the user logs in: as a
+// launchd agent on macOS, a shortcut in the Startup folder on Windows, and
+// an XDG autostart entry on Linux and other Unix desktops.  Only the current
+// user is affected, and no administrator rights are needed.
+package autostart
+
+// Name is the name under which the entry is registered, as shown by the
+// system's login items settings.
+const Name = "Foliage"
+
+// Enable registers the program and arguments in args (the first element is
+// the path of the program) to run when the user logs in, replacing any
+// entry made earlier.
+func Enable(args []string) error {
+	return enable(args)
+}
+
+// Disable removes the entry, if there is one.
+func Disable() error {
+	return disable()
+}
+
+// Enabled reports whether there is an entry.
+func Enabled() bool {
+	return enabled()
+}
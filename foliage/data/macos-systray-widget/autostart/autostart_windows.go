@@ -0,0 +1,71 @@
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// shortcutPath returns the path of the shortcut in the user's Startup
+// folder, whose programs Windows runs at login.
+func shortcutPath() (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("APPDATA is not set")
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs",
+		"Startup", Name+".lnk"), nil
+}
+
+// enable creates the shortcut.  Shortcuts are binary files that are only
+// practical to create through the Windows shell's COM interface, which
+// PowerShell makes easy to reach.
+func enable(args []string) error {
+	path, err := shortcutPath()
+	if err != nil {
+		return err
+	}
+	var quoted []string
+	for _, arg := range args[1:] {
+		quoted = append(quoted, syscall.EscapeArg(arg))
+	}
+	script := fmt.Sprintf(`$s = (New-Object -ComObject WScript.Shell).CreateShortcut(%s)
+$s.TargetPath = %s
+$s.Arguments = %s
+$s.WorkingDirectory = %s
+$s.Save()`, shellquote.PowerShell(path), shellquote.PowerShell(args[0]),
+		shellquote.PowerShell(strings.Join(quoted, " ")),
+		shellquote.PowerShell(filepath.Dir(args[0])))
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to create %s: %v: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func disable() error {
+	path, err := shortcutPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func enabled() bool {
+	path, err := shortcutPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package autostart
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+	"macos-systray-widget/internal/xdg"
+)
+
+// The name of the entry's file in the XDG autostart directory.
+const desktopFile = "foliage-widget.desktop"
+
+// desktopPath returns the path of the entry, following the XDG Base
+// Directory and Desktop Application Autostart specifications.
+func desktopPath() (string, error) {
+	dir, err := xdg.ConfigHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "autostart", desktopFile), nil
+}
+
+func enable(args []string) error {
+	path, err := desktopPath()
+	if err != nil {
+		return err
+	}
+	var exec []string
+	for _, arg := range args {
+		exec = append(exec, shellquote.DesktopExec(arg))
+	}
+	entry := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=" + Name + "\n" +
+		"Comment=Foliage system tray widget\n" +
+		"Exec=" + strings.Join(exec, " ") + "\n" +
+		"Terminal=false\n" +
+		"X-GNOME-Autostart-enabled=true\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(entry), 0o644)
+}
+
+func disable() error {
+	path, err := desktopPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func enabled() bool {
+	path, err := desktopPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,203 @@
+// Package backup keeps backups of FOLIO records, made before they are
+// changed or deleted, in the same form and the same folder as Foliage
+// itself does: a folder for each record, named by its id, in the backups
+// folder, holding a file of the record's JSON for each time it was backed
+// up, named by the time (such as 2026-10-14T093000-0700.json, since
+// Windows doesn't allow colons in file names).  A backup made by the
+// widget's tools can be found and restored in the same way as one made by
+// Foliage, and the other way round.
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/config"
+	"macos-systray-widget/foliolib"
+)
+
+// timeFormat is how Foliage names backup files: the local time in ISO 8601,
+// to the second, without colons.
+const timeFormat = "2006-01-02T150405-0700"
+
+// Dir returns the backups folder.  Foliage uses the setting BACKUP_DIR
+// (which it also sets for the widget), or else the folder Backups in its
+// data directory.
+func Dir() (string, error) {
+	if dir := config.Get("BACKUP_DIR", ""); dir != "" {
+		return dir, nil
+	}
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "Backups"), nil
+}
+
+// Write backs up the record in the backups folder dir, and returns the
+// backup's path.  A record backed up more than once in the same second
+// gets a file for each time, the later ones named with -2, -3 and so on
+// after the time.
+func Write(dir string, r foliolib.Record) (string, error) {
+	id := r.ID()
+	if id == "" || id != filepath.Base(id) || id == "." || id == ".." {
+		return "", fmt.Errorf("unable to back up a record whose id is %q", id)
+	}
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return "", err
+	}
+	folder := filepath.Join(dir, id)
+	if err := os.MkdirAll(folder, 0o700); err != nil {
+		return "", fmt.Errorf("unable to create the backup folder: %w", err)
+	}
+	stamp := time.Now().Format(timeFormat)
+	for n := 1; ; n++ {
+		path := filepath.Join(folder, stamp+".json")
+		if n > 1 {
+			path = filepath.Join(folder, fmt.Sprintf("%s-%d.json", stamp, n))
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		_, err = f.Write(data.Bytes())
+		if err == nil {
+			err = f.Sync()
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		return path, nil
+	}
+}
+
+// Snapshot is a backup of a record.
+type Snapshot struct {
+	Path string
+	Time time.Time
+}
+
+// List returns the backups of the record with the id in the backups folder
+// dir, oldest first.  Files in the record's folder that aren't named as
+// backups are left out.
+func List(dir, id string) ([]Snapshot, error) {
+	if id == "" || id != filepath.Base(id) {
+		return nil, fmt.Errorf("%q is not a record id", id)
+	}
+	folder := filepath.Join(dir, id)
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	var list []Snapshot
+	order := map[string]int{}
+	for _, e := range entries {
+		t, n, ok := parseName(e.Name())
+		if e.IsDir() || !ok {
+			continue
+		}
+		path := filepath.Join(folder, e.Name())
+		order[path] = n
+		list = append(list, Snapshot{Path: path, Time: t})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Time.Equal(list[j].Time) {
+			return list[i].Time.Before(list[j].Time)
+		}
+		return order[list[i].Path] < order[list[j].Path]
+	})
+	return list, nil
+}
+
+// parseName returns the time in the name of a backup file, and its number
+// among the backups made in the same second, and false if it isn't named as
+// one.
+func parseName(name string) (time.Time, int, bool) {
+	base := strings.TrimSuffix(name, ".json")
+	if base == name || len(base) < len(timeFormat) {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(timeFormat, base[:len(timeFormat)])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	n := 1
+	if rest := base[len(timeFormat):]; rest != "" {
+		if n, err = strconv.Atoi(strings.TrimPrefix(rest, "-")); err != nil || rest[0] != '-' {
+			return time.Time{}, 0, false
+		}
+	}
+	return t, n, true
+}
+
+// Stat returns the snapshot of the backup file at path, whose time is the
+// one in its name, or, if it isn't named as a backup, when it was last
+// written.
+func Stat(path string) (Snapshot, error) {
+	if t, _, ok := parseName(filepath.Base(path)); ok {
+		return Snapshot{Path: path, Time: t}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{Path: path, Time: info.ModTime()}, nil
+}
+
+// Read returns the record backed up in the file at path.
+func Read(path string) (foliolib.Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r foliolib.Record
+	if err := json.Unmarshal(data, &r); err != nil || r == nil {
+		return nil, fmt.Errorf("%s is not a backup of a FOLIO record", path)
+	}
+	return r, nil
+}
+
+// KindOf guesses the kind of the record from its fields, since backups
+// don't say, and returns false if it can't tell.
+func KindOf(r foliolib.Record) (foliolib.Kind, bool) {
+	has := func(fields ...string) bool {
+		for _, f := range fields {
+			if _, ok := r[f]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("holdingsRecordId"):
+		return foliolib.Item, true
+	case has("instanceId", "permanentLocationId"):
+		return foliolib.Holdings, true
+	case has("itemId", "loanDate"):
+		return foliolib.Loan, true
+	case has("username") || has("patronGroup"):
+		return foliolib.User, true
+	case has("title", "instanceTypeId"):
+		return foliolib.Instance, true
+	}
+	return "", false
+}
@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"macos-systray-widget/foliolib"
+)
+
+// A Difference is a way in which two versions of a record differ.
+type Difference struct {
+	Path string      `json:"path"` // Where, as a JSONPath, such as $.notes[0].note.
+	Op   string      `json:"op"`   // Added, Removed or Changed.
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// The ways two versions of a record can differ.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Bookkeeping is the fields FOLIO changes by itself whenever a record is
+// saved, which Diff leaves out if asked to.
+var Bookkeeping = []string{"_version", "metadata"}
+
+// Diff returns the differences between the old and new versions of a
+// record, field by field, going into objects and arrays; it leaves out the
+// top-level fields named in skip.  Arrays are compared element by element,
+// so an element put in at the start of one shows up as a change to every
+// element after it.
+func Diff(old, new foliolib.Record, skip ...string) []Difference {
+	a, b := map[string]interface{}(old), map[string]interface{}(new)
+	for _, field := range skip {
+		if _, ok := a[field]; ok {
+			a = without(a, field)
+		}
+		if _, ok := b[field]; ok {
+			b = without(b, field)
+		}
+	}
+	var d []Difference
+	diff("$", a, b, &d)
+	return d
+}
+
+// without returns a copy of m without the key.
+func without(m map[string]interface{}, key string) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+func diff(path string, a, b interface{}, d *[]Difference) {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for k := range a {
+				keys = append(keys, k)
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				x, inA := a[k]
+				y, inB := b[k]
+				switch p := member(path, k); {
+				case !inB:
+					*d = append(*d, Difference{Path: p, Op: Removed, Old: x})
+				case !inA:
+					*d = append(*d, Difference{Path: p, Op: Added, New: y})
+				default:
+					diff(p, x, y, d)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			for i := 0; i < len(a) || i < len(b); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(b):
+					*d = append(*d, Difference{Path: p, Op: Removed, Old: a[i]})
+				case i >= len(a):
+					*d = append(*d, Difference{Path: p, Op: Added, New: b[i]})
+				default:
+					diff(p, a[i], b[i], d)
+				}
+			}
+			return
+		}
+	}
+	if !equal(a, b) {
+		*d = append(*d, Difference{Path: path, Op: Changed, Old: a, New: b})
+	}
+}
+
+var plainName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// member returns the JSONPath of the member of the object at path with the
+// key.
+func member(path, key string) string {
+	if plainName.MatchString(key) {
+		return path + "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+// equal returns whether JSON values a and b are the same.
+func equal(a, b interface{}) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(x) == string(y)
+}
+
+// String describes the difference in a line, with its path starting at the
+// record rather than at $.
+func (d Difference) String() string {
+	path := strings.TrimPrefix(strings.TrimPrefix(d.Path, "$"), ".")
+	switch d.Op {
+	case Added:
+		return fmt.Sprintf("%s: added %s", path, show(d.New))
+	case Removed:
+		return fmt.Sprintf("%s: removed %s", path, show(d.Old))
+	}
+	return fmt.Sprintf("%s: changed from %s to %s", path, show(d.Old), show(d.New))
+}
+
+// show returns a value as JSON.
+func show(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Policy says which backups Prune removes: those older than MaxAge, if it
+// isn't 0, and those that aren't among the Keep newest of their record, if
+// Keep isn't 0.  The oldest backup of each record, which is of the record
+// as it was before it was first changed, is always kept.
+type Policy struct {
+	Keep   int
+	MaxAge time.Duration
+}
+
+// Removal is a backup Prune removed, or would remove, and why.
+type Removal struct {
+	Snapshot
+	ID     string
+	Size   int64
+	Reason string
+}
+
+// Prune removes the backups in the backups folder dir that the policy
+// doesn't keep, as of now, and returns them; with dryRun, it only returns
+// them.  Files that aren't named as backups are left alone.
+func Prune(dir string, p Policy, now time.Time, dryRun bool) ([]Removal, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []Removal
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		list, err := List(dir, e.Name())
+		if err != nil {
+			return removed, err
+		}
+		// The oldest, list[0], is always kept.
+		for i := 1; i < len(list); i++ {
+			var reason string
+			switch {
+			case p.MaxAge > 0 && now.Sub(list[i].Time) > p.MaxAge:
+				reason = "older than " + age(p.MaxAge)
+			case p.Keep > 0 && i < len(list)-p.Keep:
+				reason = fmt.Sprintf("not among the %d newest", p.Keep)
+			default:
+				continue
+			}
+			r := Removal{Snapshot: list[i], ID: e.Name(), Reason: reason}
+			if info, err := os.Stat(r.Path); err == nil {
+				r.Size = info.Size()
+			}
+			if !dryRun {
+				if err := os.Remove(r.Path); err != nil {
+					return removed, err
+				}
+			}
+			removed = append(removed, r)
+		}
+	}
+	return removed, nil
+}
+
+// age returns the duration as it is written in reasons: in days, if it is a
+// number of them, and otherwise as Go writes durations.
+func age(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d == day:
+		return "1 day"
+	case d%day == 0:
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
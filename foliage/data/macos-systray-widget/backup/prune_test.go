@@ -0,0 +1,104 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2024, time.May, 15, 12, 0, 0, 0, time.Local)
+	// The ages of the backups of the record, oldest first.
+	ages := []time.Duration{40 * 24 * time.Hour, 30*24*time.Hour + time.Second, 30 * 24 * time.Hour, 2 * time.Hour, time.Hour}
+	tests := []struct {
+		name   string
+		policy Policy
+		want   map[int]string // The backups removed, by their place in ages, and why.
+	}{
+		{"nothing", Policy{}, nil},
+		{"max age", Policy{MaxAge: 30 * 24 * time.Hour}, map[int]string{1: "older than 30 days"}},
+		{"max age of a day", Policy{MaxAge: 24 * time.Hour}, map[int]string{1: "older than 1 day", 2: "older than 1 day"}},
+		{"max age under a day", Policy{MaxAge: 90 * time.Minute}, map[int]string{
+			1: "older than 1h30m0s", 2: "older than 1h30m0s", 3: "older than 1h30m0s"}},
+		{"keep", Policy{Keep: 2}, map[int]string{1: "not among the 2 newest", 2: "not among the 2 newest"}},
+		{"keep all", Policy{Keep: 5}, nil},
+		{"keep more than all", Policy{Keep: 10}, nil},
+		{"keep the newest", Policy{Keep: 1}, map[int]string{
+			1: "not among the 1 newest", 2: "not among the 1 newest", 3: "not among the 1 newest"}},
+		{"keep and max age", Policy{Keep: 3, MaxAge: 30 * 24 * time.Hour}, map[int]string{1: "older than 30 days"}},
+		{"max age and keep", Policy{Keep: 2, MaxAge: 30 * 24 * time.Hour}, map[int]string{
+			1: "older than 30 days", 2: "not among the 2 newest"}},
+	}
+	for _, tt := range tests {
+		for _, dryRun := range []bool{false, true} {
+			name := tt.name
+			if dryRun {
+				name += ", dry run"
+			}
+			t.Run(name, func(t *testing.T) {
+				dir := t.TempDir()
+				var paths []string
+				for _, a := range ages {
+					paths = append(paths, writeBackup(t, dir, "i1", now.Add(-a)))
+				}
+				// A record with a single backup, which is always kept.
+				only := writeBackup(t, dir, "i2", now.Add(-ages[0]))
+				other := filepath.Join(dir, "i1", "notes.txt")
+				if err := os.WriteFile(other, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+
+				removed, err := Prune(dir, tt.policy, now, dryRun)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got, want []string
+				for _, r := range removed {
+					got = append(got, r.Path+": "+r.Reason)
+					if r.ID != "i1" || r.Size == 0 {
+						t.Errorf("%s: got id %q, size %d", r.Path, r.ID, r.Size)
+					}
+				}
+				for i, reason := range tt.want {
+					want = append(want, paths[i]+": "+reason)
+				}
+				sort.Strings(got)
+				sort.Strings(want)
+				if strings.Join(got, "\n") != strings.Join(want, "\n") {
+					t.Errorf("removed:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+				}
+				for i, path := range append(paths, only, other) {
+					_, err := os.Stat(path)
+					_, gone := tt.want[i]
+					if i >= len(paths) {
+						gone = false
+					}
+					if gone && !dryRun {
+						if !os.IsNotExist(err) {
+							t.Errorf("%s is still there", path)
+						}
+					} else if err != nil {
+						t.Errorf("%s was removed: %v", path, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// writeBackup writes a backup of the record with the id, made at t, in the
+// backups folder dir, and returns its path.
+func writeBackup(t *testing.T, dir, id string, at time.Time) string {
+	t.Helper()
+	path := filepath.Join(dir, id, at.Format(timeFormat)+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"id": "`+id+`"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
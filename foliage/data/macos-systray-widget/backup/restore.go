@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"macos-systray-widget/foliolib"
+)
+
+// What Restore did with a backup, or would have done, for a dry run.
+const (
+	Restored  = "restored"
+	Recreated = "re-created" // The record had been deleted.
+	Unchanged = "unchanged"  // The record is already as it was backed up.
+	Conflict  = "conflict"   // The record has been changed since; see RestoreOptions.Force.
+	Failed    = "failed"
+)
+
+// RestoreOptions says how Restore restores a backup.
+type RestoreOptions struct {
+	// DryRun says what would be done, without changing FOLIO.
+	DryRun bool
+
+	// Force restores records that have been changed again since the
+	// change that followed the backup, which are otherwise left alone.
+	Force bool
+
+	// Dir is the backups folder, where the record is backed up, as it is,
+	// before it is restored.
+	Dir string
+}
+
+// Restoration is what Restore did.
+type Restoration struct {
+	ID      string
+	Backup  Snapshot
+	Status  string
+	Message string
+
+	// How the record in FOLIO differs from the backup, if it does.
+	Differences []Difference
+}
+
+// Restore puts the record backed up in the snapshot, of the kind given,
+// back in FOLIO, as it was.  A record that has been deleted since is
+// created again.  Since the backup was made just before a change, the
+// record is expected to have been changed once since; if it has been
+// changed again, by someone else or a later job, it is a conflict, and the
+// record is left alone unless opts.Force is set.  The record's versions
+// (_version) tell, where FOLIO keeps them; otherwise, the time the record
+// was last changed (metadata.updatedDate) must be within a minute of the
+// backup.
+func Restore(ctx context.Context, c *foliolib.Client, kind foliolib.Kind, snap Snapshot, opts RestoreOptions) Restoration {
+	res := Restoration{Backup: snap}
+	fail := func(err error) Restoration {
+		res.Status, res.Message = Failed, err.Error()
+		return res
+	}
+	old, err := Read(snap.Path)
+	if err != nil {
+		return fail(err)
+	}
+	res.ID = old.ID()
+	current, err := c.Record(ctx, kind, res.ID)
+	if errors.Is(err, foliolib.ErrNotFound) {
+		res.Status, res.Message = Recreated, fmt.Sprintf("re-created the %s record, which had been deleted", kind)
+		if opts.DryRun {
+			res.Message = fmt.Sprintf("would re-create the %s record, which has been deleted", kind)
+			return res
+		}
+		r := old.Copy()
+		delete(r, "_version")
+		if _, err := c.Create(ctx, kind, r); err != nil {
+			return fail(err)
+		}
+		return res
+	} else if err != nil {
+		return fail(err)
+	}
+	res.Differences = Diff(current, old, Bookkeeping...)
+	if len(res.Differences) == 0 {
+		res.Status, res.Message = Unchanged, fmt.Sprintf("the %s record is already as it was backed up", kind)
+		return res
+	}
+	if why, changed := changedSince(old, current, snap.Time); changed && !opts.Force {
+		res.Status, res.Message = Conflict, why
+		return res
+	}
+	res.Status, res.Message = Restored, fmt.Sprintf("restored the %s record", kind)
+	if opts.DryRun {
+		res.Message = fmt.Sprintf("would restore the %s record", kind)
+		return res
+	}
+	if _, err := Write(opts.Dir, current); err != nil {
+		return fail(fmt.Errorf("unable to back up the record, so it was left as it is: %w", err))
+	}
+	r := old.Copy()
+	if v, ok := current["_version"]; ok {
+		r["_version"] = v
+	} else {
+		delete(r, "_version")
+	}
+	if err := c.Update(ctx, kind, r); err != nil {
+		return fail(err)
+	}
+	return res
+}
+
+// changedSince reports whether the record, now current, has been changed
+// more than once since it was backed up as backup at t, and, if so, says
+// why it seems to have been.
+func changedSince(backup, current foliolib.Record, t time.Time) (string, bool) {
+	was, ok1 := backup["_version"].(float64)
+	is, ok2 := current["_version"].(float64)
+	if ok1 && ok2 {
+		if is > was+1 {
+			return fmt.Sprintf("the record has been changed since the change after the backup: it is at version %v, and the backup at version %v", is, was), true
+		}
+		return "", false
+	}
+	if updated, ok := updatedDate(current); ok && updated.After(t.Add(time.Minute)) {
+		return fmt.Sprintf("the record has been changed since the change after the backup, at %s", updated.Local().Format("2006-01-02 15:04:05")), true
+	}
+	return "", false
+}
+
+// updatedDate returns when the record was last changed, by its metadata.
+func updatedDate(r foliolib.Record) (time.Time, bool) {
+	metadata, _ := r["metadata"].(map[string]interface{})
+	s, _ := metadata["updatedDate"].(string)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Latest returns the newest backup of the record with the id in the
+// backups folder dir made no later than t, and false if there is none.
+func Latest(dir, id string, t time.Time) (Snapshot, bool, error) {
+	list, err := List(dir, id)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, false, nil
+	} else if err != nil {
+		return Snapshot{}, false, err
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		if !list[i].Time.After(t) {
+			return list[i], true, nil
+		}
+	}
+	return Snapshot{}, false, nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"macos-systray-widget/backup"
+	"macos-systray-widget/browser"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// openBackups shows the backups folder in the system's file manager.
+func openBackups() {
+	dir, err := backup.Dir()
+	if err == nil {
+		_, err = os.Stat(dir)
+	}
+	if err != nil {
+		log.Printf("unable to find the Foliage backups folder: %v", err)
+		notify.Post(notify.Notification{
+			Message: locale.T("No backups folder was found. Foliage creates it when it first changes a record."),
+		})
+		return
+	}
+	if err := browser.OpenFolder(dir); err != nil {
+		log.Printf("unable to open %s: %v", dir, err)
+	}
+}
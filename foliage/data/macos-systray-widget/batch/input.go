@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadIdentifiers returns the identifiers in the job's input file, in order,
+// without blank ones or repeats (which Foliage also leaves out).
+func (j *Job) ReadIdentifiers() ([]string, error) {
+	path := j.InputPath()
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := j.inputReader(f)
+	column, row := 0, 0
+	var ids []string
+	seen := map[string]bool{}
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		row++
+		if row == 1 {
+			var header bool
+			if column, header, err = j.columnOf(fields); err != nil {
+				return nil, err
+			} else if header {
+				continue
+			}
+		}
+		if id := identifierIn(fields, column); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// inputReader returns a reader of the input file f, as CSV or, if its name
+// ends in .tsv, tab-separated values.
+func (j *Job) inputReader(f io.Reader) *csv.Reader {
+	r := csv.NewReader(f)
+	if strings.ToLower(filepath.Ext(j.InputPath())) == ".tsv" {
+		r.Comma = '\t'
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r
+}
+
+// columnOf returns the column of the input file the identifiers are in,
+// given its first row, and whether that row is headings.
+func (j *Job) columnOf(first []string) (int, bool, error) {
+	if j.Column != "" {
+		column := headingIndex(first, j.Column)
+		if column < 0 {
+			return 0, false, fmt.Errorf("%s has no column %q", j.InputPath(), j.Column)
+		}
+		return column, true, nil
+	}
+	return 0, len(first) > 0 && headingIndex(first[:1], j.Identifier) == 0, nil
+}
+
+// identifierIn returns the identifier in the column of a row of the input
+// file, or "" if it has none.
+func identifierIn(fields []string, column int) string {
+	if column >= len(fields) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(fields[column], "\ufeff"))
+}
+
+// headingIndex returns the index of the heading among the fields, ignoring
+// case (and the byte order mark that spreadsheet programs start files
+// with), or -1.
+func headingIndex(fields []string, heading string) int {
+	for i, f := range fields {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(f, "\ufeff")), heading) {
+			return i
+		}
+	}
+	return -1
+}
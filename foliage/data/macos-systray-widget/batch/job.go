@@ -0,0 +1,257 @@
+// Package batch runs Foliage's batch operations (looking up, changing and
+// deleting records) directly against FOLIO, without Foliage's web interface,
+// for large jobs run from a server.  A job is described by a job file, in
+// YAML or JSON, that names a file of identifiers and says what to do with the
+// records they identify:
+//
+//	name: withdrawn-2024
+//	operation: change
+//	kind: item
+//	identifier: barcode
+//	input: withdrawn.csv
+//	column: Barcode
+//	change:
+//	  field: temporaryLocationId
+//	  op: change
+//	  old: 0b1e4c58-…
+//	  new: 5f3a2b1c-…
+//
+// Each record's result is written, as it is known, to a CSV file of
+// results.  A job can also have a schedule, in cron's form (see package
+// cron), for jobs that recur, such as suppressing the records on a list of
+// withdrawals every week.
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"macos-systray-widget/cron"
+	"macos-systray-widget/foliolib"
+)
+
+// The operations a job can do.
+const (
+	Lookup = "lookup" // Get the records, for the results file.
+	Change = "change" // Add, change or delete a field of each record.
+	Delete = "delete" // Delete the records.
+	Move   = "move"   // Move items or holdings records to other locations.
+)
+
+// What a scheduled job does about runs that were missed, because the
+// program running it wasn't running then.
+const (
+	CatchUp = "catch-up" // Run once, as soon as it can.
+	Skip    = "skip"     // Wait until the next time the schedule comes round.
+)
+
+// Auto is the Identifier of jobs whose input file can have identifiers of
+// more than one kind.
+const Auto = "auto"
+
+// Job is a batch job, as a job file describes it.
+type Job struct {
+	Name      string        `json:"name" yaml:"name"`
+	Operation string        `json:"operation" yaml:"operation"`
+	Kind      foliolib.Kind `json:"kind" yaml:"kind"`
+
+	// Identifier is the CQL index the identifiers are looked up by, such
+	// as "barcode" or "hrid", or "id" (the default) for record ids, or
+	// Auto to tell each identifier's kind from the tenant's kinds of
+	// identifiers (see package identifiers).
+	Identifier string `json:"identifier,omitempty" yaml:"identifier,omitempty"`
+
+	// Input is the CSV (or, if its name ends in .tsv, tab-separated) file
+	// of identifiers, and Column is the heading of the column they are
+	// in.  Without a Column, they are in the first column, and the file
+	// has no headings unless its first row is "id" or the Identifier.
+	Input  string `json:"input" yaml:"input"`
+	Column string `json:"column,omitempty" yaml:"column,omitempty"`
+
+	// Output is the CSV file the results are written to.  By default, it
+	// is next to the job file, named after the job.
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+
+	Change   *FieldChange    `json:"change,omitempty" yaml:"change,omitempty"`
+	Location *LocationChange `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// DryRun makes the job look the records up and work out what it would
+	// do to them, without changing anything in FOLIO, and write a report
+	// of that instead of the results (see ReportPath).
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+
+	// Verify makes the job get each record from FOLIO again after changing
+	// or deleting it, to check that the change is there: FOLIO sometimes
+	// accepts a change, and then a module's own rules quietly undo some of
+	// it.  Records whose change isn't there fail (see Result.Unverified).
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+
+	// Schedule, if given, says when the job is to run again and again, in
+	// cron's form (see package cron), and Missed says what to do about runs
+	// that were missed: CatchUp (the default) or Skip.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Missed   string `json:"missed,omitempty" yaml:"missed,omitempty"`
+
+	// Webhooks are the URLs of chat webhooks (see package webhook) that are
+	// sent a summary of the job when it finishes, or fails.
+	Webhooks []string `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+
+	// Email are the addresses that are sent the summary by email, with the
+	// results file attached (see package email).
+	Email []string `json:"email,omitempty" yaml:"email,omitempty"`
+
+	dir    string         // Directory of the job file, for relative paths.
+	sched  *cron.Schedule // The schedule, read.
+	moveTo *locations     // Where a move job is moving records to, once Run has found out.
+}
+
+// FieldChange is the change a change job makes to each record, to one of
+// its top-level fields, in the same way as Foliage's Change tab.
+type FieldChange struct {
+	Field string `json:"field" yaml:"field"`
+
+	// Op is "add" (the record must not have the field yet), "change" (the
+	// default) or "delete" (the record must have the field).
+	Op string `json:"op,omitempty" yaml:"op,omitempty"`
+
+	// Old, if given, is the value the field must have for the record to be
+	// changed; records with other values are skipped.  New is the value to
+	// set, for add and change.
+	Old interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// LoadJob reads the job file at path.  Files whose names end in ".json" are
+// read as JSON; anything else is read as YAML.
+func LoadJob(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(data, &job)
+	} else {
+		err = yaml.Unmarshal(data, &job)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	job.dir = filepath.Dir(path)
+	if job.Name == "" {
+		job.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := job.check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &job, nil
+}
+
+// check reports what is wrong with the job, and fills in the defaults.
+func (j *Job) check() error {
+	if j.Identifier == "" {
+		j.Identifier = "id"
+	}
+	if j.Schedule != "" {
+		sched, err := cron.Parse(j.Schedule)
+		if err != nil {
+			return err
+		}
+		j.sched = sched
+	}
+	switch j.Missed {
+	case "":
+		j.Missed = CatchUp
+	case CatchUp, Skip:
+	default:
+		return fmt.Errorf("unknown policy for missed runs %q; it can be %s or %s", j.Missed, CatchUp, Skip)
+	}
+	if j.Input == "" {
+		return errors.New("the job needs an input file of identifiers")
+	}
+	switch j.Kind {
+	case foliolib.Instance, foliolib.Holdings, foliolib.Item, foliolib.Loan, foliolib.User:
+	case "":
+		return errors.New("the job needs a kind of record")
+	default:
+		return fmt.Errorf("unknown kind of record %q", j.Kind)
+	}
+	switch j.Operation {
+	case Lookup, Delete:
+	case Change:
+		if j.Change == nil || j.Change.Field == "" {
+			return errors.New("a change job needs the field to change")
+		}
+		switch j.Change.Op {
+		case "":
+			j.Change.Op = "change"
+		case "add", "change", "delete":
+		default:
+			return fmt.Errorf("unknown change %q; it can be add, change or delete", j.Change.Op)
+		}
+		if j.Change.Op != "delete" && j.Change.New == nil {
+			return fmt.Errorf("a change job that does %s needs a new value", j.Change.Op)
+		}
+		if j.Change.Field == "id" || strings.HasPrefix(j.Change.Field, "_") {
+			return fmt.Errorf("the field %s can't be changed", j.Change.Field)
+		}
+	case Move:
+		return j.Location.check(j.Kind)
+	case "":
+		return errors.New("the job needs an operation")
+	default:
+		return fmt.Errorf("unknown operation %q; it can be lookup, change, delete or move", j.Operation)
+	}
+	return nil
+}
+
+// Next returns the first time after t the job is scheduled to run, or the
+// zero time if it isn't scheduled.
+func (j *Job) Next(t time.Time) time.Time {
+	if j.sched == nil {
+		return time.Time{}
+	}
+	return j.sched.Next(t)
+}
+
+// InputPath returns the path of the file of identifiers.
+func (j *Job) InputPath() string {
+	return j.path(j.Input)
+}
+
+// OutputPath returns the path of the file of results.  For a dry run, it is
+// the CSV form of the report, named so that it doesn't replace the results
+// of a real run.
+func (j *Job) OutputPath() string {
+	switch {
+	case j.Output != "" && j.DryRun:
+		p := j.path(j.Output)
+		return strings.TrimSuffix(p, filepath.Ext(p)) + "-dry-run" + filepath.Ext(p)
+	case j.Output != "":
+		return j.path(j.Output)
+	case j.DryRun:
+		return filepath.Join(j.dir, j.Name+"-dry-run.csv")
+	}
+	return filepath.Join(j.dir, j.Name+"-results.csv")
+}
+
+// ReportPath returns the path of the plain text report of a dry run, next to
+// its CSV form.
+func (j *Job) ReportPath() string {
+	p := j.OutputPath()
+	return strings.TrimSuffix(p, filepath.Ext(p)) + ".txt"
+}
+
+// path returns the path p, relative to the job file's directory.
+func (j *Job) path(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(j.dir, p)
+}
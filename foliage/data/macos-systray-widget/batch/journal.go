@@ -0,0 +1,331 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Names of the files in a journal's directory.
+const (
+	journalJob     = "job.json"
+	journalEntries = "journal.jsonl"
+)
+
+// A Journal records a job as it runs, so that it can be resumed where it
+// stopped after a crash, a network outage or a reboot.  Each job that runs
+// has one, in a directory of its own named by the job's id; it holds the
+// job, and an append-only file with a line of JSON for each record done,
+// written to the disk before the next one is started, and a last line
+// summing up the run when the job finishes or is stopped.
+type Journal struct {
+	ID     string
+	Dir    string
+	Source string // The job file the job came from.
+	Job    *Job
+
+	f       *os.File
+	results map[string]Status // The latest result for each identifier.
+	resumed bool
+	whole   bool // Whether the entries end with a whole line.
+}
+
+// journalMeta is what a journal's job file holds.
+type journalMeta struct {
+	ID      string    `json:"id"`
+	Source  string    `json:"source"`
+	Created time.Time `json:"created"`
+	Job     *Job      `json:"job"`
+}
+
+// journalEntry is a line of a journal.
+type journalEntry struct {
+	Time       time.Time `json:"time"`
+	Identifier string    `json:"identifier,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Status     Status    `json:"status,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Summary    *Summary  `json:"summary,omitempty"`
+}
+
+// CreateJournal starts the journal for a new run of the job from the job
+// file source, in a new directory in root.  Its id is the job's name
+// followed by the time.
+func CreateJournal(root, source string, job *Job) (*Journal, error) {
+	now := time.Now()
+	id := job.Name + "-" + now.Format("20060102-150405")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(root, id)); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		id = fmt.Sprintf("%s-%s-%d", job.Name, now.Format("20060102-150405"), n)
+	}
+	dir := filepath.Join(root, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	// The paths are made absolute, so that the job can be resumed from
+	// anywhere.
+	saved := *job
+	saved.Input, saved.Output = job.InputPath(), job.OutputPath()
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+	for _, p := range []*string{&saved.Input, &saved.Output} {
+		if abs, err := filepath.Abs(*p); err == nil {
+			*p = abs
+		}
+	}
+	data, err := json.MarshalIndent(journalMeta{id, source, now, &saved}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, journalJob), data, 0o644); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, journalEntries), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{ID: id, Dir: dir, Source: source, Job: &saved, f: f, results: map[string]Status{}}, nil
+}
+
+// OpenJournal opens the journal of the job with the id in root, for
+// resuming it.
+func OpenJournal(root, id string) (*Journal, error) {
+	dir := filepath.Join(root, id)
+	data, err := os.ReadFile(filepath.Join(dir, journalJob))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("there is no job %s", id)
+	} else if err != nil {
+		return nil, err
+	}
+	var meta journalMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Job == nil {
+		return nil, fmt.Errorf("the journal of job %s is damaged: %v", id, err)
+	}
+	meta.Job.dir = filepath.Dir(meta.Source)
+	if err := meta.Job.check(); err != nil {
+		return nil, err
+	}
+	j := &Journal{ID: id, Dir: dir, Source: meta.Source, Job: meta.Job, results: map[string]Status{}, resumed: true}
+	if _, err := j.read(); err != nil {
+		return nil, err
+	}
+	if j.f, err = os.OpenFile(filepath.Join(dir, journalEntries), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+		return nil, err
+	}
+	if !j.whole {
+		// A line left unfinished by a crash: the next one starts on a line
+		// of its own.
+		if _, err := j.f.Write([]byte("\n")); err != nil {
+			j.f.Close()
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+// read reads the journal's entries, and returns the summary of its last
+// run, if it has one.  A last line left unfinished by a crash is ignored.
+func (j *Journal) read() (*Summary, error) {
+	j.whole = true
+	f, err := os.Open(filepath.Join(j.Dir, journalEntries))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil {
+			j.whole = last[0] == '\n'
+		}
+	}
+	var last *Summary
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var e journalEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if e.Summary != nil {
+			last = e.Summary
+		} else if e.Identifier != "" {
+			j.results[e.Identifier] = e.Status
+			last = nil
+		}
+	}
+	return last, scanner.Err()
+}
+
+// Done reports whether the record for the identifier needs nothing more
+// done: it succeeded, or was skipped, in an earlier run.  Records that
+// failed are tried again.
+func (j *Journal) Done(identifier string) (Status, bool) {
+	s, ok := j.results[identifier]
+	return s, ok && s != Failed
+}
+
+// Resumed reports whether the journal is of a job that ran before.
+func (j *Journal) Resumed() bool {
+	return j.resumed
+}
+
+// Record adds the result of a record to the journal, and makes sure it is on
+// the disk.
+func (j *Journal) Record(r Result) error {
+	j.results[r.Identifier] = r.Status
+	return j.write(journalEntry{Time: time.Now(), Identifier: r.Identifier, ID: r.ID, Status: r.Status, Message: r.Message})
+}
+
+// Finish adds the summary of the run to the journal, and closes it.
+func (j *Journal) Finish(s Summary) error {
+	err := j.write(journalEntry{Time: time.Now(), Summary: &s})
+	if cerr := j.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (j *Journal) write(e journalEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := j.f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return j.f.Sync()
+}
+
+// Changed is a record a job did (changed, deleted or looked up), and when.
+type Changed struct {
+	ID   string
+	Time time.Time
+}
+
+// Changes returns the job with the id in root, and the records it changed
+// or deleted, in the order it did them.  Dry runs and lookups change
+// nothing.
+func Changes(root, id string) (*Job, []Changed, error) {
+	job, done, err := Done(root, id)
+	if err != nil || job.DryRun || job.Operation == Lookup {
+		return job, nil, err
+	}
+	return job, done, nil
+}
+
+// Done returns the job with the id in root, and the records it did
+// successfully, in the order it did them, whatever the job does.
+func Done(root, id string) (*Job, []Changed, error) {
+	dir := filepath.Join(root, id)
+	data, err := os.ReadFile(filepath.Join(dir, journalJob))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("there is no job %s", id)
+	} else if err != nil {
+		return nil, nil, err
+	}
+	var meta journalMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Job == nil {
+		return nil, nil, fmt.Errorf("the journal of job %s is damaged: %v", id, err)
+	}
+	f, err := os.Open(filepath.Join(dir, journalEntries))
+	if errors.Is(err, os.ErrNotExist) {
+		return meta.Job, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	var done []Changed
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var e journalEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Status == Succeeded && e.ID != "" {
+			done = append(done, Changed{e.ID, e.Time})
+		}
+	}
+	return meta.Job, done, scanner.Err()
+}
+
+// JobInfo describes a job that has a journal.
+type JobInfo struct {
+	ID      string
+	Source  string
+	Created time.Time
+	Done    int      // Records done that needn't be done again.
+	Failed  int      // Records that failed the last time they were tried.
+	Last    *Summary // The summary of the last run, if it finished or was stopped.
+}
+
+// Finished reports whether the job ran to the end, with nothing left to try
+// again.
+func (i JobInfo) Finished() bool {
+	return i.Last != nil && !i.Last.Stopped && i.Failed == 0
+}
+
+// Jobs returns the jobs with journals in root, oldest first.
+func Jobs(root string) ([]JobInfo, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var jobs []JobInfo
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(root, e.Name(), journalJob))
+		if err != nil {
+			continue
+		}
+		var meta journalMeta
+		if json.Unmarshal(data, &meta) != nil {
+			continue
+		}
+		j := &Journal{Dir: filepath.Join(root, e.Name()), results: map[string]Status{}}
+		last, err := j.read()
+		if err != nil {
+			continue
+		}
+		info := JobInfo{ID: e.Name(), Source: meta.Source, Created: meta.Created, Last: last}
+		for _, s := range j.results {
+			if s == Failed {
+				info.Failed++
+			} else {
+				info.Done++
+			}
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].Created.Before(jobs[b].Created) })
+	return jobs, nil
+}
+
+// Unfinished returns the id of the latest job from the job file source that
+// was stopped or crashed before it finished, if there is one.
+func Unfinished(root, source string) (string, bool) {
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+	jobs, err := Jobs(root)
+	if err != nil {
+		return "", false
+	}
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].Source == source {
+			if jobs[i].Last == nil || jobs[i].Last.Stopped {
+				return jobs[i].ID, true
+			}
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestResumeTruncated checks that a job whose journal was left with half a
+// line by a crash is resumed from the records before it, and that what is
+// journaled after it can be read.
+func TestResumeTruncated(t *testing.T) {
+	all := ids(5)
+	_, c := newFolio(t, all)
+	job, path := writeJob(t, "operation: delete\nkind: item\n", all)
+	root := t.TempDir()
+	j, err := CreateJournal(root, path, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(context.Background(), c, job, Options{Journal: j}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The crash: the journal has the first two records, and half of the
+	// third, and no summary.
+	entries := filepath.Join(j.Dir, journalEntries)
+	data, err := os.ReadFile(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines) < 3 {
+		t.Fatalf("the journal has %d lines", len(lines))
+	}
+	var done []string
+	for _, line := range lines[:2] {
+		var e journalEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatal(err)
+		}
+		done = append(done, e.Identifier)
+	}
+	data = append(bytes.Join(lines[:2], nil), lines[2][:len(lines[2])/2]...)
+	if err := os.WriteFile(entries, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := Unfinished(root, path); !ok || id != j.ID {
+		t.Errorf("Unfinished: got %q, %v, want %q", id, ok, j.ID)
+	}
+
+	j, err = OpenJournal(root, j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range all {
+		want := id == done[0] || id == done[1]
+		if _, ok := j.Done(id); ok != want {
+			t.Errorf("%s: done is %v after the crash, want %v", id, ok, want)
+		}
+	}
+	f, c := newFolio(t, all)
+	s, err := Run(context.Background(), c, j.Job, Options{Journal: j})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.writes != 3 || s.Succeeded != len(all) {
+		t.Errorf("resuming made %d writes: %s", f.writes, s)
+	}
+
+	j, err = OpenJournal(root, j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range all {
+		if status, done := j.Done(id); !done {
+			t.Errorf("%s isn't done after resuming: %q", id, status)
+		}
+	}
+	if id, ok := Unfinished(root, path); ok {
+		t.Errorf("Unfinished: got %q after resuming", id)
+	}
+}
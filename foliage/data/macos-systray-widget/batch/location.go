@@ -0,0 +1,153 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"macos-systray-widget/foliolib"
+)
+
+// LocationChange is where a move job moves each item or holdings record.
+// Each location is given by its code, its name or its id; a move job needs
+// at least one of them.  A Temporary location of NoLocation takes records'
+// temporary locations away, so that they are back at their permanent ones.
+type LocationChange struct {
+	Permanent string `json:"permanent,omitempty" yaml:"permanent,omitempty"`
+	Temporary string `json:"temporary,omitempty" yaml:"temporary,omitempty"`
+}
+
+// NoLocation is the Temporary location that takes temporary locations away.
+const NoLocation = "none"
+
+// The fields of items and holdings records that hold their locations.
+const (
+	permanentField = "permanentLocationId"
+	temporaryField = "temporaryLocationId"
+)
+
+// locations are the tenant's locations, and the ones a move job moves
+// records to, by id ("" for none, or for a location the job leaves alone).
+type locations struct {
+	codes                map[string]string // The code of each location, by id.
+	permanent, temporary string
+}
+
+// check reports what is wrong with the location change.
+func (l *LocationChange) check(kind foliolib.Kind) error {
+	if kind != foliolib.Item && kind != foliolib.Holdings {
+		return fmt.Errorf("a move job moves items or holdings records, not %s records", kind)
+	}
+	if l == nil || (l.Permanent == "" && l.Temporary == "") {
+		return errors.New("a move job needs a permanent or temporary location to move records to")
+	}
+	if strings.EqualFold(l.Permanent, NoLocation) {
+		return errors.New("records can't be without a permanent location")
+	}
+	return nil
+}
+
+// findLocations gets the tenant's locations from FOLIO, and finds the ones
+// the job moves records to among them.  It fails if they aren't there, or
+// are no longer in use.
+func (j *Job) findLocations(ctx context.Context, c *foliolib.Client) (*locations, error) {
+	var all []foliolib.Record
+	err := c.SearchAll(ctx, foliolib.Location, "cql.allRecords=1 sortBy code", func(r foliolib.Record) error {
+		all = append(all, r)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the locations from FOLIO: %w", err)
+	}
+	l := &locations{codes: map[string]string{}}
+	for _, r := range all {
+		code, _ := r["code"].(string)
+		l.codes[r.ID()] = code
+	}
+	find := func(which, ref string) (string, error) {
+		var found []foliolib.Record
+		for _, r := range all {
+			code, _ := r["code"].(string)
+			name, _ := r["name"].(string)
+			if r.ID() == ref || strings.EqualFold(code, ref) || strings.EqualFold(name, ref) {
+				found = append(found, r)
+			}
+		}
+		switch {
+		case len(found) == 0:
+			return "", fmt.Errorf("the tenant has no location %q, for the %s location", ref, which)
+		case len(found) > 1:
+			return "", fmt.Errorf("%d of the tenant's locations are called %q; give the %s location by its code or id", len(found), ref, which)
+		}
+		if active, ok := found[0]["isActive"].(bool); ok && !active {
+			return "", fmt.Errorf("the location %q, for the %s location, is no longer in use", ref, which)
+		}
+		return found[0].ID(), nil
+	}
+	if ref := j.Location.Permanent; ref != "" {
+		if l.permanent, err = find("permanent", ref); err != nil {
+			return nil, err
+		}
+	}
+	if ref := j.Location.Temporary; ref != "" && !strings.EqualFold(ref, NoLocation) {
+		if l.temporary, err = find("temporary", ref); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// show returns the location with the id, as it is written in results: by
+// its code, if it has one.
+func (l *locations) show(id interface{}) interface{} {
+	if s, ok := id.(string); ok && l.codes[s] != "" {
+		return l.codes[s]
+	}
+	return id
+}
+
+// applyMove moves the record to the job's locations, without saving it,
+// and returns the status and a description of what it did, or why it
+// skipped the record, along with the record's locations before and after,
+// by code.
+func (l *locations) applyMove(record foliolib.Record, kind foliolib.Kind, ch *LocationChange) (Status, string, interface{}, interface{}) {
+	where := func() map[string]interface{} {
+		return map[string]interface{}{"permanent": l.show(record[permanentField]), "temporary": l.show(record[temporaryField])}
+	}
+	before := where()
+	changed := false
+	if l.permanent != "" && !sameValue(record[permanentField], l.permanent) {
+		record[permanentField] = l.permanent
+		changed = true
+	}
+	switch {
+	case l.temporary != "" && !sameValue(record[temporaryField], l.temporary):
+		record[temporaryField] = l.temporary
+		changed = true
+	case strings.EqualFold(ch.Temporary, NoLocation) && record[temporaryField] != nil:
+		delete(record, temporaryField)
+		changed = true
+	}
+	if !changed {
+		return Skipped, fmt.Sprintf("the %s record is already there", kind), before, before
+	}
+	after := where()
+	return Succeeded, fmt.Sprintf("moved %s record to %s", kind, showPlace(after)), before, after
+}
+
+// showPlace describes the locations of a record, as applyMove gives them.
+func showPlace(where interface{}) string {
+	m, ok := where.(map[string]interface{})
+	if !ok {
+		return showValue(where)
+	}
+	text := fmt.Sprint(m["permanent"])
+	if m["permanent"] == nil {
+		text = "no permanent location"
+	}
+	if m["temporary"] != nil {
+		text += fmt.Sprintf(" (temporarily %v)", m["temporary"])
+	}
+	return text
+}
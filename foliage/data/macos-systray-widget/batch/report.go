@@ -0,0 +1,55 @@
+package batch
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+)
+
+// reportWriter writes the plain text report of a dry run, for catalogers to
+// read before they run the job for real: a line for each record, saying
+// what the job would do to it, or why it would leave it alone, and a summary
+// at the end.
+type reportWriter struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func newReportWriter(job *Job) (*reportWriter, error) {
+	f, err := os.Create(job.ReportPath())
+	if err != nil {
+		return nil, err
+	}
+	rw := &reportWriter{f, bufio.NewWriter(f)}
+	fmt.Fprintf(rw.w, "Dry run of job %s, %s %s records, on %s.\n", job.Name, job.Operation, job.Kind,
+		time.Now().Format("2006-01-02 15:04"))
+	fmt.Fprintf(rw.w, "Nothing has been changed in FOLIO.\n\n")
+	return rw, nil
+}
+
+func (rw *reportWriter) write(r Result) error {
+	who := r.Identifier
+	if r.ID != "" && r.ID != r.Identifier {
+		who += " (" + r.ID + ")"
+	}
+	switch {
+	case r.Status == Succeeded && r.Message == "":
+		fmt.Fprintf(rw.w, "%s: found\n", who)
+	case r.Status == Succeeded:
+		fmt.Fprintf(rw.w, "%s: %s\n", who, r.Message)
+	default:
+		fmt.Fprintf(rw.w, "%s: %s: %s\n", who, r.Status, r.Message)
+	}
+	return rw.w.Flush()
+}
+
+// finish writes the summary, and closes the report.
+func (rw *reportWriter) finish(s Summary) error {
+	fmt.Fprintf(rw.w, "\n%s\n", s)
+	err := rw.w.Flush()
+	if cerr := rw.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
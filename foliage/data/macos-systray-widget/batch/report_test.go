@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestDryRun checks that a dry run changes nothing in FOLIO, and that its
+// report, and the CSV form of it, say what the job would do to each record.
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     string
+		heading string
+		report  []string // The lines for the records, in any order.
+		csv     []string // The rows for the records, in any order.
+		summary string
+	}{
+		{
+			name:    "change",
+			job:     "operation: change\nkind: item\ndry_run: true\nchange:\n  field: barcode\n  old: b-item-000\n  new: b-new\n",
+			heading: "Dry run of job job, change item records, on ",
+			report: []string{
+				`item-000: would change barcode from "b-item-000" to "b-new"`,
+				"item-001: skipped: the value of barcode is not b-item-000",
+				"item-002: skipped: the value of barcode is not b-item-000",
+				"item-999: failed: no item record has the id item-999",
+			},
+			csv: []string{
+				"Identifier,Record ID,Result,Notes,Field,Current value,New value",
+				`item-000,item-000,would change,"would change barcode from ""b-item-000"" to ""b-new""",barcode,"""b-item-000""","""b-new"""`,
+				`item-001,item-001,skipped,the value of barcode is not b-item-000,barcode,"""b-item-001""",`,
+				`item-002,item-002,skipped,the value of barcode is not b-item-000,barcode,"""b-item-002""",`,
+				"item-999,,failed,no item record has the id item-999,,,",
+			},
+			summary: "job: 1 would be done, 1 failed, 2 skipped of 4",
+		},
+		{
+			name:    "delete",
+			job:     "operation: delete\nkind: item\ndry_run: true\n",
+			heading: "Dry run of job job, delete item records, on ",
+			report: []string{
+				"item-000: would delete item record",
+				"item-001: would delete item record",
+				"item-002: would delete item record",
+				"item-999: failed: no item record has the id item-999",
+			},
+			csv: []string{
+				"Identifier,Record ID,Result,Notes",
+				"item-000,item-000,would delete,would delete item record",
+				"item-001,item-001,would delete,would delete item record",
+				"item-002,item-002,would delete,would delete item record",
+				"item-999,,failed,no item record has the id item-999",
+			},
+			summary: "job: 3 would be done, 1 failed, 0 skipped of 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := ids(3)
+			f, c := newFolio(t, all)
+			job, _ := writeJob(t, tt.job, append(all, "item-999"))
+			if _, err := Run(context.Background(), c, job, Options{}); err != nil {
+				t.Fatal(err)
+			}
+			if f.writes != 0 || len(f.items) != len(all) || f.items["item-000"]["barcode"] != "b-item-000" {
+				t.Errorf("the dry run made %d writes", f.writes)
+			}
+
+			lines := readLines(t, job.ReportPath())
+			if len(lines) != len(tt.report)+5 {
+				t.Fatalf("the report has %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+			}
+			if !strings.HasPrefix(lines[0], tt.heading) || lines[1] != "Nothing has been changed in FOLIO." || lines[2] != "" {
+				t.Errorf("the report starts with:\n%s", strings.Join(lines[:3], "\n"))
+			}
+			end := len(lines) - 2
+			equalLines(t, "report", lines[3:end], tt.report)
+			if lines[end] != "" || !strings.HasPrefix(lines[end+1], tt.summary) || !strings.HasSuffix(lines[end+1], "(dry run)") {
+				t.Errorf("the report ends with:\n%s", strings.Join(lines[end:], "\n"))
+			}
+
+			rows := readLines(t, job.OutputPath())
+			if len(rows) == 0 || rows[0] != tt.csv[0] {
+				t.Fatalf("the CSV starts with %q", rows)
+			}
+			equalLines(t, "CSV", rows[1:], tt.csv[1:])
+		})
+	}
+}
+
+// readLines returns the lines of the file at path.
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// equalLines checks that got has the lines of want, in any order, since
+// records are done in no particular order.
+func equalLines(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	sort.Strings(got)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("the %s has:\n%s\nwant:\n%s", what, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,571 @@
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"macos-systray-widget/foliolib"
+)
+
+// Status is what became of a record.
+type Status string
+
+// The statuses of records.
+const (
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Skipped   Status = "skipped"
+)
+
+// Result is what became of the record for one identifier.
+type Result struct {
+	Identifier string
+	ID         string // The record's id, if it was found.
+	Status     Status
+	Message    string
+	Record     foliolib.Record // The record, for lookups.
+
+	// For a dry run of a change, the field, and its value before and
+	// after the change (nil if there is none); for a move, "location",
+	// and the record's locations.
+	Field         string
+	Before, After interface{}
+
+	// Unverified is set for records FOLIO took the change to, but which,
+	// when got again to verify it, didn't have it (see Job.Verify).
+	Unverified bool
+}
+
+// Summary sums up a job that has run.
+type Summary struct {
+	Job       string
+	Started   time.Time
+	Finished  time.Time
+	Total     int // Identifiers in the input.
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Stopped   bool // Whether the job was stopped before the end.
+
+	// Unverified is how many of the failures were changes FOLIO took but
+	// didn't keep (see Job.Verify).
+	Unverified int `json:",omitempty"`
+
+	DryRun bool // Whether nothing was changed in FOLIO.
+
+	// Failures are the first MaxFailures records that failed, with what
+	// went wrong, such as "35047019219626: FOLIO answered 500", for
+	// reporting the job without the results file.
+	Failures []string `json:",omitempty"`
+}
+
+// MaxFailures is how many failures a summary lists.
+const MaxFailures = 10
+
+// Done returns the number of identifiers that have a result.
+func (s Summary) Done() int {
+	return s.Succeeded + s.Failed + s.Skipped
+}
+
+func (s Summary) String() string {
+	succeeded := "succeeded"
+	if s.DryRun {
+		succeeded = "would be done"
+	}
+	text := fmt.Sprintf("%s: %d %s, %d failed, %d skipped of %d, in %s", s.Job,
+		s.Succeeded, succeeded, s.Failed, s.Skipped, s.Total, s.Finished.Sub(s.Started).Round(time.Second))
+	if s.Unverified > 0 {
+		text += fmt.Sprintf(" (%d of the failures FOLIO took but didn't keep)", s.Unverified)
+	}
+	if s.DryRun {
+		text += " (dry run)"
+	}
+	if s.Stopped {
+		text += " (stopped)"
+	}
+	return text
+}
+
+// Options change how a job runs.
+type Options struct {
+	// Progress, if not nil, is called after each record with the result
+	// and the number of identifiers done so far and in all.
+	Progress func(r Result, done, total int)
+
+	// Journal, if not nil, records each result as it comes (see
+	// CreateJournal).  If it is of a job that ran before, the records it
+	// says are done are skipped, and the results are added to the end of
+	// the results file.  Run finishes the journal when it returns.
+	Journal *Journal
+
+	// Workers is how many records are worked on at once, or, if it is 0,
+	// DefaultWorkers.  Results come in the order the records are done in,
+	// which needn't be the order of the input.  The client's limits (see
+	// foliolib.Limits) still hold, so more workers than the client has
+	// requests in flight only wait their turn.
+	Workers int
+
+	// Backup, if not nil, is called with each record before it is changed
+	// or deleted, as it was in FOLIO (see package backup).  If it fails,
+	// the record is left as it is, and its result is a failure.
+	Backup func(foliolib.Record) error
+
+	// Lock, if not nil, is called with the id of each record before it is
+	// changed or deleted, to lock the record for the job (see package
+	// locks), and returns who else has it locked, if anyone does.  That
+	// is added to the record's result as a warning, unless SkipLocked is
+	// set, when the record is skipped instead.  The locks are advisory,
+	// so if Lock fails, the record is done anyway, with a warning.
+	Lock       func(ctx context.Context, id string) (string, error)
+	SkipLocked bool
+
+	// Verify makes the job verify its changes, as if its Verify were set.
+	Verify bool
+}
+
+// DefaultWorkers is how many records a job works on at once by default.
+const DefaultWorkers = 4
+
+// Run runs the job with the FOLIO client, writing the results to the job's
+// results file as it goes.  When the context is done, it stops, and the
+// summary says so; records being worked on then get a result (failed, if
+// they were interrupted), and records not reached have none.  The error
+// is for problems that stop the job from running at all, such as an input
+// file that can't be read, or a location to move records to that the
+// tenant doesn't have; problems with records are in their results.
+//
+// A dry run writes a report, in plain text as well as CSV, of what the job
+// would do, instead.
+func Run(ctx context.Context, c *foliolib.Client, job *Job, opts Options) (s Summary, err error) {
+	s = Summary{Job: job.Name, Started: time.Now(), DryRun: job.DryRun}
+	if opts.Journal != nil {
+		defer func() {
+			if err != nil {
+				s.Stopped = true
+			}
+			opts.Journal.Finish(s)
+		}()
+	}
+	ids, err := job.ReadIdentifiers()
+	if err != nil {
+		return s, err
+	}
+	s.Total = len(ids)
+	if job.Operation == Move {
+		if job.moveTo, err = job.findLocations(ctx, c); err != nil {
+			return s, err
+		}
+	}
+	out, header, err := openResults(job.OutputPath(), opts.Journal)
+	if err != nil {
+		return s, err
+	}
+	defer out.Close()
+	w := newResultsWriter(out, job, header)
+	var report *reportWriter
+	if job.DryRun {
+		if report, err = newReportWriter(job); err != nil {
+			return s, err
+		}
+		defer func() {
+			s.Finished = time.Now()
+			if rerr := report.finish(s); err == nil && rerr != nil {
+				err = fmt.Errorf("unable to write the report: %w", rerr)
+			}
+		}()
+	}
+	var todo []string
+	for _, id := range ids {
+		if opts.Journal != nil {
+			if status, done := opts.Journal.Done(id); done {
+				s.count(status)
+				continue
+			}
+		}
+		todo = append(todo, id)
+	}
+	// The results are written here, one at a time; if they can't be, the
+	// workers are stopped, rather than going on with records whose results
+	// would be lost.
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for r := range work(wctx, c, job, todo, opts) {
+		if err != nil {
+			continue
+		}
+		s.count(r.Status)
+		if r.Unverified {
+			s.Unverified++
+		}
+		if r.Status == Failed && len(s.Failures) < MaxFailures {
+			s.Failures = append(s.Failures, r.Identifier+": "+r.Message)
+		}
+		if err = w.write(r); err != nil {
+			err = fmt.Errorf("unable to write results: %w", err)
+		} else if report != nil {
+			if err = report.write(r); err != nil {
+				err = fmt.Errorf("unable to write the report: %w", err)
+			}
+		}
+		if err == nil && opts.Journal != nil {
+			if err = opts.Journal.Record(r); err != nil {
+				err = fmt.Errorf("unable to write the journal: %w", err)
+			}
+		}
+		if err != nil {
+			cancel()
+			continue
+		}
+		if opts.Progress != nil {
+			opts.Progress(r, s.Done(), s.Total)
+		}
+	}
+	if err != nil {
+		return s, err
+	}
+	s.Stopped = s.Done() < s.Total || ctx.Err() != nil
+	s.Finished = time.Now()
+	return s, out.Close()
+}
+
+// count counts a record with the status.
+func (s *Summary) count(status Status) {
+	switch status {
+	case Succeeded:
+		s.Succeeded++
+	case Failed:
+		s.Failed++
+	default:
+		s.Skipped++
+	}
+}
+
+// openResults opens the results file: a new one, or, for a job being
+// resumed, the one it had, to add to.  It reports whether the file needs a
+// heading.
+func openResults(path string, journal *Journal) (*os.File, bool, error) {
+	if journal == nil || !journal.Resumed() {
+		f, err := os.Create(path)
+		return f, true, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, false, err
+	}
+	return f, info.Size() == 0, nil
+}
+
+// work does the job to the records with the identifiers, with the number of
+// workers given, and sends back their results.  When the context is done,
+// the workers take no more records, but still send the results of the ones
+// they were working on, since FOLIO may already have carried out their
+// changes, which must be journaled so that resuming the job doesn't make
+// them again; the channel is closed once the workers have stopped.
+func work(ctx context.Context, c *foliolib.Client, job *Job, ids []string, opts Options) <-chan Result {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	todo := make(chan string)
+	results := make(chan Result)
+	go func() {
+		defer close(todo)
+		for _, id := range ids {
+			select {
+			case todo <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range todo {
+				if ctx.Err() != nil {
+					return
+				}
+				results <- runOne(ctx, c, job, id, opts)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// runOne does the job's operation on the record with the identifier, locking
+// it and backing it up first, as the options say.
+func runOne(ctx context.Context, c *foliolib.Client, job *Job, id string, opts Options) Result {
+	r := Result{Identifier: id}
+	record, err := find(ctx, c, job, id)
+	if err != nil {
+		r.Status, r.Message = Failed, err.Error()
+		return r
+	}
+	r.ID = record.ID()
+	warning := ""
+	if opts.Lock != nil && !job.DryRun && job.Operation != Lookup {
+		held, err := opts.Lock(ctx, r.ID)
+		switch {
+		case err != nil:
+			warning = fmt.Sprintf("unable to lock the record: %v", err)
+		case held != "" && opts.SkipLocked:
+			r.Status, r.Message = Skipped, "the record is locked: "+held
+			return r
+		case held != "":
+			warning = "the record is also locked: " + held
+		}
+	}
+	backup, verify := opts.Backup, opts.Verify || job.Verify
+	switch {
+	case job.Operation == Lookup:
+		r.Status, r.Record = Succeeded, record
+	case job.DryRun && job.Operation == Delete:
+		r.Status, r.Message = Succeeded, fmt.Sprintf("would delete %s record", job.Kind)
+	case job.DryRun && job.Operation == Move:
+		r.Field = "location"
+		r.Status, r.Message, r.Before, r.After = job.moveTo.applyMove(record, job.Kind, job.Location)
+		if r.Status == Succeeded {
+			r.Message = fmt.Sprintf("would move %s record from %s to %s", job.Kind, showPlace(r.Before), showPlace(r.After))
+		}
+	case job.DryRun:
+		r.Field, r.Before = job.Change.Field, record[job.Change.Field]
+		r.Status, r.Message = applyChange(record, job.Kind, job.Change)
+		if r.Status == Succeeded {
+			r.After = record[job.Change.Field]
+			r.Message = "would " + describeChange(job.Change.Op, r)
+		}
+	case job.Operation == Delete:
+		if err := backUp(backup, record); err != nil {
+			r.Status, r.Message = Failed, err.Error()
+		} else if err := c.DeleteRecord(ctx, job.Kind, r.ID); err != nil && !errors.Is(err, foliolib.ErrNotAudited) {
+			r.Status, r.Message = Failed, err.Error()
+		} else {
+			r.Status, r.Message = Succeeded, fmt.Sprintf("deleted %s record", job.Kind)
+			if err != nil {
+				// Made, but not recorded in the audit log: failing it would
+				// have it made again on resuming.
+				warning = addWarning(warning, err.Error())
+			}
+			if verify {
+				warning = r.verify(ctx, c, job.Kind, nil, nil, warning)
+			}
+		}
+	case job.Operation == Change, job.Operation == Move:
+		original := record.Copy()
+		if job.Operation == Move {
+			r.Status, r.Message, _, _ = job.moveTo.applyMove(record, job.Kind, job.Location)
+		} else {
+			r.Status, r.Message = applyChange(record, job.Kind, job.Change)
+		}
+		if r.Status == Succeeded {
+			if err := backUp(backup, original); err != nil {
+				r.Status, r.Message = Failed, err.Error()
+			} else if err := c.Update(ctx, job.Kind, record); err != nil && !errors.Is(err, foliolib.ErrNotAudited) {
+				r.Status, r.Message = Failed, err.Error()
+			} else {
+				if err != nil {
+					warning = addWarning(warning, err.Error())
+				}
+				if verify {
+					warning = r.verify(ctx, c, job.Kind, record, job.changedFields(), warning)
+				}
+			}
+		}
+	}
+	if warning != "" && r.Message != "" {
+		r.Message += "; warning: " + warning
+	} else if warning != "" {
+		r.Message = "warning: " + warning
+	}
+	return r
+}
+
+// backUp backs the record up with backup, if it isn't nil.
+func backUp(backup func(foliolib.Record) error, record foliolib.Record) error {
+	if backup == nil {
+		return nil
+	}
+	if err := backup(record); err != nil {
+		return fmt.Errorf("unable to back up the record, so it was left as it is: %w", err)
+	}
+	return nil
+}
+
+// find returns the record of the job's kind with the identifier.  Lookups
+// may use the client's cache; records to be changed, or to be dry-run, are
+// always got from FOLIO.
+func find(ctx context.Context, c *foliolib.Client, job *Job, id string) (foliolib.Record, error) {
+	if job.Identifier == Auto {
+		_, record, err := c.Resolve(ctx, id, job.Kind)
+		if err != nil || job.Operation == Lookup || c.Cache == nil {
+			return record, err
+		}
+		return c.Record(ctx, job.Kind, record.ID())
+	}
+	if job.Operation == Lookup {
+		return c.Lookup(ctx, job.Kind, job.Identifier, id)
+	}
+	if job.Identifier == "id" {
+		record, err := c.Record(ctx, job.Kind, id)
+		if errors.Is(err, foliolib.ErrNotFound) {
+			return nil, fmt.Errorf("no %s record has the id %s", job.Kind, id)
+		}
+		return record, err
+	}
+	records, total, err := c.Search(ctx, job.Kind, foliolib.Exact(job.Identifier, id), 0, 2)
+	switch {
+	case err != nil:
+		return nil, err
+	case total == 0 || len(records) == 0:
+		return nil, fmt.Errorf("no %s record has the %s %s", job.Kind, job.Identifier, id)
+	case total > 1:
+		return nil, fmt.Errorf("%d %s records have the %s %s", total, job.Kind, job.Identifier, id)
+	}
+	return records[0], nil
+}
+
+// applyChange makes the change to the record, without saving it, and returns
+// the status and a description of what it did, or why it skipped the
+// record.
+func applyChange(record foliolib.Record, kind foliolib.Kind, ch *FieldChange) (Status, string) {
+	current, has := record[ch.Field]
+	if has && current == nil {
+		has = false
+	}
+	switch {
+	case ch.Op == "add" && has:
+		return Skipped, fmt.Sprintf("the %s record already has a value for %s", kind, ch.Field)
+	case ch.Op != "add" && !has:
+		return Skipped, fmt.Sprintf("the %s record has no value for %s", kind, ch.Field)
+	case ch.Op != "add" && ch.Old != nil && !sameValue(current, ch.Old):
+		return Skipped, fmt.Sprintf("the value of %s is not %v", ch.Field, ch.Old)
+	}
+	switch ch.Op {
+	case "delete":
+		delete(record, ch.Field)
+		return Succeeded, fmt.Sprintf("deleted %s from %s record", ch.Field, kind)
+	case "add":
+		record[ch.Field] = ch.New
+		return Succeeded, fmt.Sprintf("added %s to %s record", ch.Field, kind)
+	}
+	record[ch.Field] = ch.New
+	return Succeeded, fmt.Sprintf("changed %s in %s record", ch.Field, kind)
+}
+
+// describeChange says what a change did to the record of the result.
+func describeChange(op string, r Result) string {
+	switch op {
+	case "add":
+		return fmt.Sprintf("add %s %s", r.Field, showValue(r.After))
+	case "delete":
+		return fmt.Sprintf("delete %s %s", r.Field, showValue(r.Before))
+	}
+	return fmt.Sprintf("change %s from %s to %s", r.Field, showValue(r.Before), showValue(r.After))
+}
+
+// showValue returns a field's value as it is written in reports: as JSON, or
+// "(none)" if there is none.
+func showValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// sameValue reports whether a value from FOLIO's JSON is the same as one
+// from a job file, which may have been read as a different Go type (such
+// as an int rather than a float64).
+func sameValue(folio, job interface{}) bool {
+	a, err1 := json.Marshal(folio)
+	b, err2 := json.Marshal(job)
+	if err1 != nil || err2 != nil {
+		return reflect.DeepEqual(folio, job)
+	}
+	var x, y interface{}
+	json.Unmarshal(a, &x)
+	json.Unmarshal(b, &y)
+	return reflect.DeepEqual(x, y)
+}
+
+// resultsWriter writes results to a CSV file, a row at a time, so that the
+// file is up to date if the program stops.
+type resultsWriter struct {
+	w       *csv.Writer
+	records bool                     // Whether to include the records.
+	changes bool                     // Whether to include the fields' values, for dry runs.
+	show    func(interface{}) string // How to write the values.
+	would   string                   // What a dry run would do to each record.
+}
+
+func newResultsWriter(f *os.File, job *Job, header bool) *resultsWriter {
+	rw := &resultsWriter{w: csv.NewWriter(f), records: job.Operation == Lookup, show: showValue}
+	if job.DryRun && job.Operation != Lookup {
+		rw.changes = job.Operation == Change || job.Operation == Move
+		rw.would = "would " + job.Operation
+	}
+	if job.Operation == Move {
+		rw.show = showPlace
+	}
+	if !header {
+		return rw
+	}
+	heading := []string{"Identifier", "Record ID", "Result", "Notes"}
+	if rw.records {
+		heading = append(heading, "Record")
+	}
+	if rw.changes {
+		heading = append(heading, "Field", "Current value", "New value")
+	}
+	rw.w.Write(heading)
+	return rw
+}
+
+func (rw *resultsWriter) write(r Result) error {
+	status := string(r.Status)
+	if r.Status == Succeeded && rw.would != "" {
+		status = rw.would
+	}
+	row := []string{r.Identifier, r.ID, status, r.Message}
+	if rw.changes {
+		row = append(row, r.Field, "", "")
+		if r.Field != "" {
+			row[len(row)-2] = rw.show(r.Before)
+		}
+		if r.Field != "" && r.Status == Succeeded {
+			row[len(row)-1] = rw.show(r.After)
+		}
+	}
+	if rw.records {
+		data := ""
+		if r.Record != nil {
+			b, _ := json.Marshal(r.Record)
+			data = string(b)
+		}
+		row = append(row, data)
+	}
+	rw.w.Write(row)
+	rw.w.Flush()
+	return rw.w.Error()
+}
@@ -0,0 +1,212 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"macos-systray-widget/foliolib"
+)
+
+// folio is a fake FOLIO with items, for running jobs against.
+type folio struct {
+	mu      sync.Mutex
+	items   map[string]foliolib.Record
+	deleted []string
+	writes  int // Requests that would change FOLIO.
+}
+
+// newFolio returns a fake FOLIO with an item for each id, and a client for it.
+func newFolio(t *testing.T, ids []string) (*folio, *foliolib.Client) {
+	t.Helper()
+	f := &folio{items: map[string]foliolib.Record{}}
+	for _, id := range ids {
+		f.items[id] = foliolib.Record{"id": id, "barcode": "b-" + id, "status": map[string]interface{}{"name": "Available"}}
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	c := foliolib.New(srv.URL, "diku")
+	c.SetLimits(foliolib.Limits{})
+	c.SetToken("token")
+	return f, c
+}
+
+func (f *folio) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+	item, ok := f.items[id]
+	switch {
+	case r.Method != http.MethodGet:
+		f.writes++
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.items, id)
+			f.deleted = append(f.deleted, id)
+		} else {
+			var changed foliolib.Record
+			json.NewDecoder(r.Body).Decode(&changed)
+			f.items[id] = changed
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case !ok:
+		http.NotFound(w, r)
+	default:
+		json.NewEncoder(w).Encode(item)
+	}
+}
+
+// ids returns n item ids.
+func ids(n int) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		ids = append(ids, fmt.Sprintf("item-%03d", i))
+	}
+	return ids
+}
+
+// writeJob writes the job file, and an input file, ids.csv, of the ids, in a
+// new folder, and loads the job.  It returns the job, and the job file's
+// path.
+func writeJob(t *testing.T, job string, ids []string) (*Job, string) {
+	t.Helper()
+	dir := t.TempDir()
+	input := "id\n" + strings.Join(ids, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "ids.csv"), []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "job.yaml")
+	if err := os.WriteFile(path, []byte("input: ids.csv\n"+job), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	j, err := LoadJob(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j, path
+}
+
+// cancelAfter is a transport that cancels a context once the answer to the
+// first request with the method has been read, as if the program were
+// asked to stop just after FOLIO had done what the request asked.
+type cancelAfter struct {
+	method string
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func (t *cancelAfter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && req.Method == t.method {
+		resp.Body = closer{resp.Body, func() { t.once.Do(t.cancel) }}
+	}
+	return resp, err
+}
+
+// closer is a body that calls done once it is closed.
+type closer struct {
+	io.ReadCloser
+	done func()
+}
+
+func (c closer) Close() error {
+	err := c.ReadCloser.Close()
+	c.done()
+	return err
+}
+
+// TestRunStopped checks that a job stopped while records are being worked
+// on journals the records FOLIO has already done, so that resuming it
+// doesn't do them again.
+func TestRunStopped(t *testing.T) {
+	all := ids(20)
+	f, c := newFolio(t, all)
+	job, path := writeJob(t, "operation: delete\nkind: item\n", all)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.HTTP.Transport = &cancelAfter{method: http.MethodDelete, cancel: cancel}
+	root := t.TempDir()
+	j, err := CreateJournal(root, path, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Run(ctx, c, job, Options{Journal: j, Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stopped || s.Done() == len(all) {
+		t.Errorf("the job wasn't stopped: %s", s)
+	}
+	if len(f.deleted) == 0 || len(f.deleted) == len(all) {
+		t.Fatalf("deleted %d of %d records", len(f.deleted), len(all))
+	}
+	if s.Succeeded != len(f.deleted) {
+		t.Errorf("%d records succeeded, but FOLIO deleted %d", s.Succeeded, len(f.deleted))
+	}
+	j, err = OpenJournal(root, j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range f.deleted {
+		if status, done := j.Done(id); !done || status != Succeeded {
+			t.Errorf("%s was deleted, but the journal has %q", id, status)
+		}
+	}
+}
+
+// failingAuditor is an auditor that can't record anything.
+type failingAuditor struct{}
+
+func (failingAuditor) Audit(ctx context.Context, c foliolib.Change) error {
+	return errors.New("the disk is full")
+}
+
+// TestRunNotAudited checks that a change FOLIO made, but which couldn't be
+// recorded in the audit log, counts as made, with a warning, rather than
+// as failed, which would have it made again on resuming.
+func TestRunNotAudited(t *testing.T) {
+	tests := []struct {
+		name string
+		job  string
+		want string // What the message starts with.
+	}{
+		{"change", "operation: change\nkind: item\nchange:\n  field: barcode\n  new: b-new\n", "changed barcode"},
+		{"delete", "operation: delete\nkind: item\n", "deleted item record"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := ids(3)
+			f, c := newFolio(t, all)
+			c.Audit = failingAuditor{}
+			job, _ := writeJob(t, tt.job, all)
+			var results []Result
+			s, err := Run(context.Background(), c, job, Options{
+				Progress: func(r Result, done, total int) { results = append(results, r) },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Succeeded != len(all) || f.writes != len(all) {
+				t.Errorf("%d of %d records succeeded, with %d writes: %s", s.Succeeded, len(all), f.writes, s)
+			}
+			for _, r := range results {
+				if r.Status != Succeeded || !strings.HasPrefix(r.Message, tt.want) || !strings.Contains(r.Message, "warning: ") ||
+					!strings.Contains(r.Message, "not recorded in the audit log: the disk is full") {
+					t.Errorf("%s: got %s %q", r.Identifier, r.Status, r.Message)
+				}
+			}
+		})
+	}
+}
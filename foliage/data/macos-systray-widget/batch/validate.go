@@ -0,0 +1,244 @@
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"macos-systray-widget/foliolib"
+	"macos-systray-widget/identifiers"
+)
+
+// Validation is what Validate found in a job's input file.
+type Validation struct {
+	Rows        int // Rows of identifiers, without the headings.
+	Identifiers int // Identifiers, without repeats.
+	Problems    []Problem
+	Checked     bool // Whether the identifiers were looked up in FOLIO.
+}
+
+// Problem is something wrong with a row of the input file.
+type Problem struct {
+	Row        int // Counting the headings, if there are any, as row 1.
+	Identifier string
+	Message    string
+}
+
+func (p Problem) String() string {
+	if p.Identifier == "" {
+		return fmt.Sprintf("row %d: %s", p.Row, p.Message)
+	}
+	return fmt.Sprintf("row %d: %s: %s", p.Row, p.Identifier, p.Message)
+}
+
+// checkBatch is how many identifiers Validate looks up in FOLIO at once.
+const checkBatch = 50
+
+// ValidationPath returns the path of the annotated copy of the input file
+// that Validate writes: NAME-validation, next to the job file, with the
+// input file's extension.
+func (j *Job) ValidationPath() string {
+	ext := ".csv"
+	if strings.ToLower(filepath.Ext(j.Input)) == ".tsv" {
+		ext = ".tsv"
+	}
+	return j.path(j.Name + "-validation" + ext)
+}
+
+// Validate checks the job's input file before the job is run: that it can
+// be read, has the job's column, and that each row has an identifier that
+// looks like the job's kind of identifier, as the tenant's kinds of
+// identifiers have it (identifiers.Default, if types is nil), and isn't a
+// repeat.  With a client, it also looks the identifiers up in FOLIO,
+// checkBatch at a time (or, for Auto, one at a time), to check that each
+// is of exactly one record, and, for a move job, that the tenant has the
+// locations it moves records to.  It writes a copy of the input file to
+// annotated, if it isn't nil, with a column at the end saying what is
+// wrong with each row.  The error is for problems that stop it checking
+// the file at all.
+func (j *Job) Validate(ctx context.Context, types identifiers.Types, c *foliolib.Client, annotated io.Writer) (Validation, error) {
+	var v Validation
+	if types == nil {
+		types = identifiers.Default()
+	}
+	path := j.InputPath()
+	f, err := os.Open(path)
+	if err != nil {
+		return v, err
+	}
+	defer f.Close()
+	r := j.inputReader(f)
+	type row struct {
+		fields   []string
+		id       string
+		problems []string
+	}
+	var rows []*row
+	var header []string
+	column, offset := 0, 1    // The row number of rows[0] is offset.
+	first := map[string]int{} // The row number each identifier is first in.
+	var ids []string          // The identifiers to look up, in order.
+	for n := 1; ; n++ {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) && !errors.Is(err, csv.ErrFieldCount) {
+			rows = append(rows, &row{problems: []string{"unreadable: " + perr.Err.Error()}})
+			continue
+		} else if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return v, fmt.Errorf("%s: %w", path, err)
+		}
+		if n == 1 {
+			var isHeader bool
+			if column, isHeader, err = j.columnOf(fields); err != nil {
+				return v, err
+			} else if isHeader {
+				header, offset = fields, 2
+				continue
+			}
+		}
+		rw := &row{fields: fields, id: identifierIn(fields, column)}
+		rows = append(rows, rw)
+		switch {
+		case rw.id == "":
+			rw.problems = append(rw.problems, "no identifier; the row will be skipped")
+		case first[rw.id] > 0:
+			rw.problems = append(rw.problems, fmt.Sprintf("repeats row %d; it will be done once", first[rw.id]))
+		default:
+			first[rw.id] = len(rows) - 1 + offset
+			if msg := j.checkFormat(types, rw.id); msg != "" {
+				rw.problems = append(rw.problems, msg)
+			} else {
+				ids = append(ids, rw.id)
+			}
+		}
+	}
+	v.Rows, v.Identifiers = len(rows), len(first)
+	if c != nil && j.Operation == Move {
+		if _, err := j.findLocations(ctx, c); err != nil {
+			return v, err
+		}
+	}
+	if c != nil {
+		found, err := j.lookUp(ctx, c, ids)
+		if err != nil {
+			return v, err
+		}
+		v.Checked = true
+		for _, id := range ids {
+			n := first[id]
+			switch count := found[strings.ToLower(id)]; {
+			case count == 0 && j.Identifier == Auto:
+				rows[n-offset].problems = append(rows[n-offset].problems, fmt.Sprintf("no %s record has the identifier %s", j.Kind, id))
+			case count == 0:
+				rows[n-offset].problems = append(rows[n-offset].problems, fmt.Sprintf("no %s record has the %s %s", j.Kind, j.Identifier, id))
+			case count > 1:
+				rows[n-offset].problems = append(rows[n-offset].problems, fmt.Sprintf("%d %s records have the %s %s", count, j.Kind, j.Identifier, id))
+			}
+		}
+	}
+	for i, rw := range rows {
+		for _, msg := range rw.problems {
+			v.Problems = append(v.Problems, Problem{Row: i + offset, Identifier: rw.id, Message: msg})
+		}
+	}
+	if annotated != nil {
+		w := csv.NewWriter(annotated)
+		w.Comma = r.Comma
+		if header != nil {
+			w.Write(append(append([]string{}, header...), "Problems"))
+		}
+		for _, rw := range rows {
+			fields := append([]string{}, rw.fields...)
+			for len(fields) < len(header) {
+				fields = append(fields, "")
+			}
+			w.Write(append(fields, strings.Join(rw.problems, "; ")))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// checkFormat says what is wrong with the identifier, for the job's kind of
+// identifier, or returns "" if nothing is.  Identifiers looked up by an
+// index none of the types has are only checked for spaces.
+func (j *Job) checkFormat(types identifiers.Types, id string) string {
+	for _, r := range id {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return "has spaces or characters that can't be printed in it"
+		}
+	}
+	types = types.Of(string(j.Kind))
+	if j.Identifier == "id" {
+		types = identifiers.Types{{Name: "id", Pattern: identifiers.UUID}}
+	} else if j.Identifier != Auto {
+		types = types.Index(j.Identifier)
+		if len(types) == 0 {
+			return ""
+		}
+	}
+	if len(types.Matching(id)) > 0 {
+		return ""
+	}
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = fmt.Sprintf("%q", t.Name)
+	}
+	switch len(names) {
+	case 0:
+		return fmt.Sprintf("no kind of identifier of %s records is listed for the tenant", j.Kind)
+	case 1:
+		return "doesn't look like an identifier of the kind " + names[0]
+	}
+	return "doesn't look like an identifier of any of the kinds " + strings.Join(names, ", ")
+}
+
+// lookUp looks the identifiers up in FOLIO, checkBatch at a time, and
+// returns the number of records with each, by the identifier in lower case.
+// Identifiers of a job whose Identifier is Auto are resolved one by one
+// instead (see foliolib.Client.Resolve), and count once if any record has
+// them.
+func (j *Job) lookUp(ctx context.Context, c *foliolib.Client, todo []string) (map[string]int, error) {
+	found := map[string]int{}
+	if j.Identifier == Auto {
+		for _, id := range todo {
+			_, _, err := c.Resolve(ctx, id, j.Kind)
+			if err == nil {
+				found[strings.ToLower(id)]++
+			} else if !errors.Is(err, foliolib.ErrNotFound) {
+				return nil, err
+			}
+		}
+		return found, nil
+	}
+	for len(todo) > 0 {
+		n := checkBatch
+		if n > len(todo) {
+			n = len(todo)
+		}
+		query := foliolib.AnyOf(j.Identifier, todo[:n])
+		err := c.SearchAll(ctx, j.Kind, query, func(r foliolib.Record) error {
+			if v, ok := r[j.Identifier].(string); ok {
+				found[strings.ToLower(v)]++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		todo = todo[n:]
+	}
+	return found, nil
+}
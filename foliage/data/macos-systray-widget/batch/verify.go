@@ -0,0 +1,59 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"macos-systray-widget/foliolib"
+)
+
+// changedFields returns the fields of records the job changes.
+func (j *Job) changedFields() []string {
+	switch j.Operation {
+	case Change:
+		return []string{j.Change.Field}
+	case Move:
+		return []string{permanentField, temporaryField}
+	}
+	return nil
+}
+
+// verify gets the record of the result from FOLIO again, after it was
+// changed to want (or deleted, if want is nil), and checks that the fields
+// have the values they were given.  If they don't, the result is a failure,
+// saying what FOLIO has instead.  If the record can't be got, the result
+// stands, and verify returns the warning with that added.
+func (r *Result) verify(ctx context.Context, c *foliolib.Client, kind foliolib.Kind, want foliolib.Record, fields []string, warning string) string {
+	got, err := c.Record(ctx, kind, r.ID)
+	switch {
+	case want == nil && err == nil:
+		r.Status, r.Unverified = Failed, true
+		r.Message = fmt.Sprintf("FOLIO said it deleted the %s record, but still has it", kind)
+		return warning
+	case want == nil && errors.Is(err, foliolib.ErrNotFound):
+		return warning
+	case err != nil:
+		return addWarning(warning, fmt.Sprintf("unable to get the record again to verify the change: %v", err))
+	}
+	var wrong []string
+	for _, f := range fields {
+		if !sameValue(got[f], want[f]) {
+			wrong = append(wrong, fmt.Sprintf("%s is %s, not %s", f, showValue(got[f]), showValue(want[f])))
+		}
+	}
+	if len(wrong) > 0 {
+		r.Status, r.Unverified = Failed, true
+		r.Message = fmt.Sprintf("FOLIO took the change, but didn't keep it: %s", strings.Join(wrong, ", "))
+	}
+	return warning
+}
+
+// addWarning adds a warning to the ones given.
+func addWarning(warnings, warning string) string {
+	if warnings == "" {
+		return warning
+	}
+	return warnings + "; " + warning
+}
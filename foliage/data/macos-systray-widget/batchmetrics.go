@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"macos-systray-widget/batch"
+	"macos-systray-widget/foliolib"
+	"macos-systray-widget/metrics"
+)
+
+// batchMetrics are the metrics foliaged serve keeps, for Prometheus (see
+// package metrics).  Its methods do nothing to a nil *batchMetrics, which is
+// what foliaged has when it isn't serving metrics.
+type batchMetrics struct {
+	registry *metrics.Registry
+	latency  *metrics.Histogram
+	retries  *metrics.Counter
+	errors   *metrics.Counter
+	records  *metrics.Counter
+	active   *metrics.Gauge
+	jobs     *metrics.Counter
+}
+
+// foliagedMetrics are the metrics foliaged is keeping, if it is.
+var foliagedMetrics *batchMetrics
+
+func newBatchMetrics() *batchMetrics {
+	r := metrics.NewRegistry()
+	m := &batchMetrics{
+		registry: r,
+		latency: r.Histogram("foliaged_folio_request_duration_seconds",
+			"How long FOLIO took to answer requests, by method and endpoint.",
+			[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}, "method", "endpoint"),
+		retries: r.Counter("foliaged_folio_retries_total",
+			"Requests to FOLIO tried again after a failure, by method.", "method"),
+		errors: r.Counter("foliaged_folio_errors_total",
+			"Requests to FOLIO that failed, by the type of failure.", "type"),
+		records: r.Counter("foliaged_records_total",
+			"Records that batch jobs have done, by operation and result.", "operation", "result"),
+		active: r.Gauge("foliaged_jobs_active",
+			"Batch jobs running now."),
+		jobs: r.Counter("foliaged_jobs_total",
+			"Batch jobs that have run, by how they ended (finished, stopped or failed).", "outcome"),
+	}
+	m.active.Set(0)
+	return m
+}
+
+// serve serves the metrics at /metrics on the address, until the context is
+// done.
+func (m *batchMetrics) serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.registry)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go srv.Serve(l)
+	log.Printf("serving metrics at http://%s/metrics", l.Addr())
+	return nil
+}
+
+// trace records a try of a request to FOLIO (see foliolib.Client.Trace).
+func (m *batchMetrics) trace(t foliolib.Try) {
+	if m == nil {
+		return
+	}
+	if t.Err == nil {
+		m.latency.Observe(t.Duration.Seconds(), t.Method, endpointOf(t.Path))
+	}
+	if t.Retry > 1 {
+		m.retries.Inc(t.Method)
+	}
+	if kind := failure(t); kind != "" {
+		m.errors.Inc(kind)
+	}
+}
+
+// endpointOf returns the endpoint of a request's path, without the query,
+// and with the ids in it (UUIDs, numbers, HRIDs and the like) replaced by
+// {id}, such as "/item-storage/items/{id}", so that there are few of them
+// and no record ids end up in the metrics.  FOLIO's endpoints are named in
+// lowercase words joined by hyphens; any other segment is taken to be an id.
+func endpointOf(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "/"
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if !endpointName.MatchString(part) {
+			parts[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// endpointName matches a segment of a path that names an endpoint, rather
+// than a record.
+var endpointName = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
+// failure returns the type of failure of a try, or "" if it didn't fail.
+func failure(t foliolib.Try) string {
+	var ne net.Error
+	var ua x509.UnknownAuthorityError
+	var he x509.HostnameError
+	var ci x509.CertificateInvalidError
+	switch {
+	case errors.Is(t.Err, context.Canceled):
+		return ""
+	case errors.As(t.Err, &ua), errors.As(t.Err, &he), errors.As(t.Err, &ci):
+		return "tls"
+	case errors.As(t.Err, &ne) && ne.Timeout(), errors.Is(t.Err, context.DeadlineExceeded):
+		return "timeout"
+	case t.Err != nil:
+		return "network"
+	case t.Status == http.StatusTooManyRequests:
+		return "rate_limited"
+	case t.Status == http.StatusUnauthorized, t.Status == http.StatusForbidden:
+		return "unauthorized"
+	case t.Status == http.StatusNotFound:
+		return "not_found"
+	case t.Status == http.StatusConflict:
+		return "conflict"
+	case t.Status == http.StatusUnprocessableEntity:
+		return "validation"
+	case t.Status >= 500:
+		return "server"
+	case t.Status >= 400:
+		return "client"
+	}
+	return ""
+}
+
+// started records that a job has started.
+func (m *batchMetrics) started() {
+	if m != nil {
+		m.active.Add(1)
+	}
+}
+
+// record records the result of a record of a job.
+func (m *batchMetrics) record(job *batch.Job, r batch.Result) {
+	if m != nil {
+		m.records.Inc(job.Operation, string(r.Status))
+	}
+}
+
+// finished records that a job has ended, with the summary and error Run
+// returned.
+func (m *batchMetrics) finished(s batch.Summary, err error) {
+	if m == nil {
+		return
+	}
+	m.active.Add(-1)
+	switch {
+	case err != nil:
+		m.jobs.Inc("failed")
+	case s.Stopped:
+		m.jobs.Inc("stopped")
+	default:
+		m.jobs.Inc("finished")
+	}
+}
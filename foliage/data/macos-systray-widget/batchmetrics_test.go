@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestEndpointOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/0c1b5c2e-3e4a-4b5f-9c8d-7e6f5a4b3c2d", "/users/{id}"},
+		{"/locations/b241764c-1466-4e1d-a028-1a3684a5da87", "/locations/{id}"},
+		{"/inventory/items/7212ba6a-8dcf-45a1-be9a-ffaa847c4423", "/inventory/items/{id}"},
+		{"/item-storage/items?query=barcode%3D%3D%2235047019%22&limit=1", "/item-storage/items"},
+		{"/users?query=username%3D%3Djdoe", "/users"},
+		{"/source-storage/records/fb3d3e46-6d49-4b39-8fe9-0e8a0a4e3a51/formatted?idType=INSTANCE",
+			"/source-storage/records/{id}/formatted"},
+		{"/inventory/instances/in00000001", "/inventory/instances/{id}"},
+		{"/loan-storage/loans/12345", "/loan-storage/loans/{id}"},
+		{"/instance-statuses?limit=0", "/instance-statuses"},
+		{"/authn/login-with-expiry", "/authn/login-with-expiry"},
+		{"/", "/"},
+	}
+	for _, tt := range tests {
+		if got := endpointOf(tt.path); got != tt.want {
+			t.Errorf("endpointOf(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
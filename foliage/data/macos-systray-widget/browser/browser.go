@@ -0,0 +1,33 @@
+// Package browser opens URLs in the user's default web browser, and files
+// and folders in the applications the system uses for them.
+package browser
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Open opens the given URL in the user's default web browser.
+func Open(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Run()
+}
+
+// OpenFolder shows the given folder in the system's file manager (Finder,
+// Explorer, or whatever xdg-open uses on Linux).
+func OpenFolder(path string) error {
+	if runtime.GOOS == "windows" {
+		// Explorer's exit status is 1 even when it succeeds, so it's
+		// not worth waiting for.
+		return exec.Command("explorer", path).Start()
+	}
+	return Open(path)
+}
@@ -0,0 +1,63 @@
+package browser
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// The browsers whose tabs Focus looks through.  The Chromium-based ones all
+// have the same scripting dictionary as Chrome.
+var scriptableBrowsers = []string{"Safari", "Google Chrome", "Microsoft Edge", "Brave Browser"}
+
+// focusScript looks for a tab whose URL starts with its argument in each
+// running browser, and if it finds one, brings it to the front.  It is
+// written in JavaScript rather than AppleScript because AppleScript wants
+// every application it mentions to be installed.
+const focusScript = `
+function run(argv) {
+	var prefix = argv[0];
+	var names = argv.slice(1);
+	for (var n = 0; n < names.length; n++) {
+		var app;
+		try {
+			app = Application(names[n]);
+			if (!app.running()) continue;
+		} catch (e) {
+			continue;
+		}
+		var windows = app.windows();
+		for (var w = 0; w < windows.length; w++) {
+			var tabs = windows[w].tabs();
+			for (var t = 0; t < tabs.length; t++) {
+				if (String(tabs[t].url()).indexOf(prefix) !== 0) continue;
+				if (names[n] === "Safari") {
+					windows[w].currentTab = tabs[t];
+				} else {
+					windows[w].activeTabIndex = t + 1;
+				}
+				windows[w].index = 1;
+				app.activate();
+				return "found";
+			}
+		}
+	}
+	return "";
+}`
+
+// Focus brings to the front a browser tab already showing a page whose URL
+// starts with url, and reports whether it found one.  Browsers don't let
+// other programs choose among their tabs except through scripting, so this
+// only works on macOS, and only after the user allows the program to control
+// the browser (macOS asks the first time).  Elsewhere it reports false.
+func Focus(url string) (bool, error) {
+	if runtime.GOOS != "darwin" {
+		return false, nil
+	}
+	args := append([]string{"-l", "JavaScript", "-e", focusScript, url}, scriptableBrowsers...)
+	out, err := exec.Command("osascript", args...).Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) == "found", nil
+}
@@ -0,0 +1,187 @@
+// Package cache keeps the FOLIO records Foliage's Go programs get, on the
+// disk, so that looking the same records up again, as a cleanup project
+// does over and over, needn't wait for FOLIO, and can be done while FOLIO
+// can't be reached (see foliolib.Client.Lookup).  It is a foliolib.Cache.
+//
+// The cache is an SQLite database, with a table of records holding the
+// JSON of each record, the time it was got, and its id, HRID and barcode,
+// which are indexed.  It uses a driver written in Go, so that programs
+// using it still build without cgo.  The database is in write-ahead
+// logging mode, and waits for other writers rather than failing, so that
+// several foliaged workers can use the same cache at once.
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"macos-systray-widget/foliolib"
+
+	_ "modernc.org/sqlite" // The "sqlite" database/sql driver.
+)
+
+// How long a program waits for another one writing to the cache before
+// giving up.
+const busyTimeout = 10 * time.Second
+
+// schema makes the table of records, if the database has none.
+const schema = `
+CREATE TABLE IF NOT EXISTS records (
+	kind    TEXT NOT NULL,
+	id      TEXT NOT NULL,
+	hrid    TEXT,
+	barcode TEXT,
+	fetched INTEGER NOT NULL, -- When the record was got, in Unix nanoseconds.
+	record  TEXT NOT NULL,
+	PRIMARY KEY (kind, id)
+);
+CREATE INDEX IF NOT EXISTS records_hrid ON records (kind, hrid) WHERE hrid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS records_barcode ON records (kind, barcode) WHERE barcode IS NOT NULL;
+`
+
+// columns is the column of the table for each field in foliolib.CacheKeys.
+var columns = map[string]string{"id": "id", "hrid": "hrid", "barcode": "barcode"}
+
+// Store is a cache of records in the database at Path.  Records got more
+// than TTL ago are stale.
+type Store struct {
+	Path string
+	TTL  time.Duration
+
+	db *sql.DB
+}
+
+// Open returns the cache in the database at path, making the database, and
+// the folder it is in, if there is none.
+func Open(path string, ttl time.Duration) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create the cache of FOLIO records: %w", err)
+	}
+	v := url.Values{}
+	v.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout/time.Millisecond))
+	v.Add("_pragma", "journal_mode(WAL)")
+	v.Add("_pragma", "synchronous(NORMAL)")
+	db, err := sql.Open("sqlite", path+"?"+v.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the cache of FOLIO records: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open the cache of FOLIO records in %s: %w", path, err)
+	}
+	return &Store{Path: path, TTL: ttl, db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the record of the kind whose field has the value, if the cache
+// has it, and whether it was got less than TTL ago.  If more than one record
+// has the value, which happens when a barcode moves from one item to
+// another, the one got last is returned.
+func (s *Store) Get(kind foliolib.Kind, field, value string) (foliolib.Record, bool, bool) {
+	column, ok := columns[field]
+	if !ok {
+		return nil, false, false
+	}
+	var (
+		data    string
+		fetched int64
+	)
+	err := s.db.QueryRow("SELECT record, fetched FROM records WHERE kind = ? AND "+column+" = ? "+
+		"ORDER BY fetched DESC LIMIT 1", string(kind), value).Scan(&data, &fetched)
+	if err != nil {
+		return nil, false, false
+	}
+	var r foliolib.Record
+	if json.Unmarshal([]byte(data), &r) != nil || r == nil {
+		return nil, false, false
+	}
+	return r, time.Since(time.Unix(0, fetched)) < s.TTL, true
+}
+
+// Put adds the record to the cache, replacing any it had with the same id.
+func (s *Store) Put(kind foliolib.Kind, r foliolib.Record) error {
+	id := r.ID()
+	if id == "" {
+		return nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO records (kind, id, hrid, barcode, fetched, record) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (kind, id) DO UPDATE SET hrid = excluded.hrid, barcode = excluded.barcode,
+			fetched = excluded.fetched, record = excluded.record`,
+		string(kind), id, key(r, "hrid"), key(r, "barcode"), time.Now().UnixNano(), string(data))
+	return err
+}
+
+// key returns the record's value of the field, or nil (NULL) if it has
+// none.
+func key(r foliolib.Record, field string) interface{} {
+	if v, _ := r[field].(string); v != "" {
+		return v
+	}
+	return nil
+}
+
+// Invalidate removes the record of the kind with the id.
+func (s *Store) Invalidate(kind foliolib.Kind, id string) error {
+	_, err := s.db.Exec("DELETE FROM records WHERE kind = ? AND id = ?", string(kind), id)
+	return err
+}
+
+// Stats describes what is in the cache.
+type Stats struct {
+	Records int
+	Stale   int   // Records got more than TTL ago.
+	Size    int64 // The size of the database and its log.
+}
+
+// Stats counts what is in the cache.
+func (s *Store) Stats() (Stats, error) {
+	var st Stats
+	err := s.db.QueryRow("SELECT count(*), count(CASE WHEN fetched <= ? THEN 1 END) FROM records",
+		s.staleBefore()).Scan(&st.Records, &st.Stale)
+	if err != nil {
+		return st, err
+	}
+	for _, path := range []string{s.Path, s.Path + "-wal"} {
+		if info, err := os.Stat(path); err == nil {
+			st.Size += info.Size()
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return st, err
+		}
+	}
+	return st, nil
+}
+
+// Clear empties the cache; with staleOnly, it removes only the stale
+// records.  It returns the number of records removed.
+func (s *Store) Clear(staleOnly bool) (int, error) {
+	query, args := "DELETE FROM records", []interface{}{}
+	if staleOnly {
+		query, args = query+" WHERE fetched <= ?", append(args, s.staleBefore())
+	}
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// staleBefore returns the time, in Unix nanoseconds, records got at or
+// before which are stale.
+func (s *Store) staleBefore() int64 {
+	return time.Now().Add(-s.TTL).UnixNano()
+}
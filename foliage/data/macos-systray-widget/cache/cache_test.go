@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"macos-systray-widget/foliolib"
+)
+
+// open returns a cache in a new database.
+func open(t *testing.T, ttl time.Duration) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "cache", "diku.db"), ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestGet(t *testing.T) {
+	s := open(t, time.Hour)
+	item := foliolib.Record{"id": "i1", "hrid": "it001", "barcode": "35047019", "status": map[string]interface{}{"name": "Available"}}
+	holdings := foliolib.Record{"id": "i1", "hrid": "ho001"}
+	for kind, r := range map[foliolib.Kind]foliolib.Record{foliolib.Item: item, foliolib.Holdings: holdings} {
+		if err := s.Put(kind, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		kind   foliolib.Kind
+		field  string
+		value  string
+		wantID string // "" if the cache shouldn't have it.
+	}{
+		{foliolib.Item, "id", "i1", "i1"},
+		{foliolib.Item, "hrid", "it001", "i1"},
+		{foliolib.Item, "barcode", "35047019", "i1"},
+		{foliolib.Item, "barcode", "35047020", ""},
+		{foliolib.Item, "hrid", "ho001", ""},
+		{foliolib.Holdings, "hrid", "ho001", "i1"},
+		{foliolib.Holdings, "barcode", "35047019", ""},
+		{foliolib.Item, "title", "x", ""},
+	}
+	for _, tt := range tests {
+		r, fresh, ok := s.Get(tt.kind, tt.field, tt.value)
+		if ok != (tt.wantID != "") || (ok && (r.ID() != tt.wantID || !fresh)) {
+			t.Errorf("Get(%s, %s, %s) = %v, %v, %v", tt.kind, tt.field, tt.value, r, fresh, ok)
+		}
+	}
+	if r, _, _ := s.Get(foliolib.Item, "id", "i1"); fmt.Sprint(r["status"]) != "map[name:Available]" {
+		t.Errorf("got back %v", r)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	s := open(t, time.Hour)
+	put := func(r foliolib.Record) {
+		t.Helper()
+		if err := s.Put(foliolib.Item, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(foliolib.Record{"id": "i1", "barcode": "b1"})
+	put(foliolib.Record{"id": "i1", "barcode": "b2"})
+	if _, _, ok := s.Get(foliolib.Item, "barcode", "b1"); ok {
+		t.Error("found the record by its old barcode")
+	}
+	// The barcode moves to another item.
+	put(foliolib.Record{"id": "i2", "barcode": "b2"})
+	if r, _, _ := s.Get(foliolib.Item, "barcode", "b2"); r.ID() != "i2" {
+		t.Errorf("barcode b2 gave %v, want the record got last", r)
+	}
+	if err := s.Invalidate(foliolib.Item, "i2"); err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _ := s.Get(foliolib.Item, "barcode", "b2"); r.ID() != "i1" {
+		t.Errorf("barcode b2 gave %v after invalidating i2", r)
+	}
+	if err := s.Invalidate(foliolib.Item, "none"); err != nil {
+		t.Errorf("Invalidate of a record not in the cache: %v", err)
+	}
+}
+
+func TestStale(t *testing.T) {
+	s := open(t, time.Hour)
+	for i := 0; i < 5; i++ {
+		if err := s.Put(foliolib.Item, foliolib.Record{"id": fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.TTL = 0
+	if _, fresh, ok := s.Get(foliolib.Item, "id", "1"); !ok || fresh {
+		t.Errorf("Get of a stale record gave fresh %v, ok %v", fresh, ok)
+	}
+	st, err := s.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Records != 5 || st.Stale != 5 || st.Size == 0 {
+		t.Errorf("got stats %+v", st)
+	}
+	s.TTL = time.Hour
+	if n, err := s.Clear(true); err != nil || n != 0 {
+		t.Errorf("Clear(true) removed %d, %v; want 0", n, err)
+	}
+	if n, err := s.Clear(false); err != nil || n != 5 {
+		t.Errorf("Clear(false) removed %d, %v; want 5", n, err)
+	}
+}
+
+// TestWorkers checks that several programs can use the cache at once, as
+// foliaged's workers do.
+func TestWorkers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "diku.db")
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		s, err := Open(path, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := fmt.Sprintf("%d-%d", w, i)
+				if err := s.Put(foliolib.Item, foliolib.Record{"id": id, "barcode": id}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, _, ok := s.Get(foliolib.Item, "barcode", id); !ok {
+					t.Errorf("worker %d didn't find %s", w, id)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	s, err := Open(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if st, err := s.Stats(); err != nil || st.Records != 200 {
+		t.Errorf("got stats %+v, %v; want 200 records", st, err)
+	}
+}
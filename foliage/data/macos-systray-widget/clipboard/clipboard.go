@@ -0,0 +1,54 @@
+// Package clipboard puts text on the system clipboard and reads it back.
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNotSupported is returned by Copy and Paste when no way of reaching the
+// clipboard is available.  On Linux, one of wl-copy and wl-paste (Wayland),
+// xclip or xsel (X11) must be installed.
+var ErrNotSupported = errors.New("no clipboard program is available")
+
+// Copy puts text on the clipboard.
+func Copy(text string) error {
+	cmd, err := command()
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// Paste returns the text on the clipboard, which is empty if the clipboard
+// holds something other than text.
+func Paste() (string, error) {
+	return paste()
+}
+
+// command returns a command that copies its standard input to the clipboard.
+func command() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("pbcopy"), nil
+	case "windows":
+		return exec.Command("clip"), nil
+	}
+	candidates := [][]string{
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([][]string{{"wl-copy"}}, candidates...)
+	}
+	for _, c := range candidates {
+		if path, err := exec.LookPath(c[0]); err == nil {
+			return exec.Command(path, c[1:]...), nil
+		}
+	}
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,49 @@
+package clipboard
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32                         = syscall.NewLazyDLL("user32.dll")
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procGlobalLock                 = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock               = kernel32.NewProc("GlobalUnlock")
+)
+
+const cfUnicodeText = 13
+
+// paste reads the clipboard directly rather than through a program, since
+// the only program Windows has for it is PowerShell, which is slow to start
+// for something the widget may do every few seconds.
+func paste() (string, error) {
+	if r, _, _ := procIsClipboardFormatAvailable.Call(cfUnicodeText); r == 0 {
+		return "", nil
+	}
+	// The clipboard is opened for the calling thread, and has to be closed
+	// by the same thread.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if r, _, err := procOpenClipboard.Call(0); r == 0 {
+		return "", err
+	}
+	defer procCloseClipboard.Call()
+	h, _, err := procGetClipboardData.Call(cfUnicodeText)
+	if h == 0 {
+		return "", err
+	}
+	p, _, err := procGlobalLock.Call(h)
+	if p == 0 {
+		return "", err
+	}
+	defer procGlobalUnlock.Call(h)
+	return windows.UTF16PtrToString(*(**uint16)(unsafe.Pointer(&p))), nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/clipboard"
+	"macos-systray-widget/config"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// How often to look at the clipboard while watching it.  The clipboard has
+// no portable way to announce changes, so it is polled.
+const clipboardInterval = 2 * time.Second
+
+// The most identifiers offered for lookup from the clipboard, and the most
+// text looked at; more than that is probably not a list of identifiers.
+const (
+	clipboardMaxIDs  = 50
+	clipboardMaxText = 4096
+)
+
+// The "Watch Clipboard" menu items, which are checked while the widget is
+// watching the clipboard, and the "Look Up in Foliage" items, which are shown
+// while the clipboard holds identifiers.
+var (
+	clipboardItems       []*systray.MenuItem
+	clipboardLookupItems []*systray.MenuItem
+)
+
+// Nonzero while the clipboard is being watched.
+var watchingClipboard int32
+
+// The identifiers found on the clipboard, and the last text looked at.
+var (
+	clipboardMu   sync.Mutex
+	clipboardIDs  []string
+	clipboardText string
+)
+
+// clipboardIdentifiers returns the identifiers in text, if it is nothing
+// but a short list of the kinds of identifiers the tenant has (see
+// identifierTypes), one per line or separated by spaces, commas or
+// semicolons (as cells copied from a spreadsheet are).  Anything else gives
+// nil, so that copying ordinary text offers nothing.
+func clipboardIdentifiers(text string) []string {
+	ids := identifierFields(text)
+	if len(ids) == 0 {
+		return nil
+	}
+	types := identifierTypes()
+	for _, id := range ids {
+		if len(types.Matching(id)) == 0 {
+			return nil
+		}
+	}
+	return ids
+}
+
+// identifierFields splits text into the identifiers in it, one per line or
+// separated by spaces, commas or semicolons, without duplicates.  It returns
+// nil if there is too much text or there are too many of them.
+func identifierFields(text string) []string {
+	if len(text) > clipboardMaxText {
+		return nil
+	}
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	})
+	if len(fields) > clipboardMaxIDs {
+		return nil
+	}
+	var ids []string
+	seen := map[string]bool{}
+	for _, field := range fields {
+		field = strings.Trim(field, `"'`)
+		if field != "" && !seen[field] {
+			seen[field] = true
+			ids = append(ids, field)
+		}
+	}
+	return ids
+}
+
+// describeIDs names the identifiers for a menu item or notification: the
+// identifier itself if there is just one, or how many there are.
+func describeIDs(ids []string) string {
+	if len(ids) == 1 {
+		return ids[0]
+	}
+	return locale.Sprintf("%d identifiers", len(ids))
+}
+
+// clipboardStateFile returns the path of the file whose presence records
+// that the user has turned on watching the clipboard, so that it stays on
+// from one run of the widget to the next.
+func clipboardStateFile() (string, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watch-clipboard"), nil
+}
+
+// clipboardWatchWanted reports whether to watch the clipboard from the
+// start: if the user turned it on before, or the setting
+// FOLIAGE_WATCH_CLIPBOARD is true.
+func clipboardWatchWanted() bool {
+	if config.Bool("FOLIAGE_WATCH_CLIPBOARD", false) {
+		return true
+	}
+	path, err := clipboardStateFile()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// saveClipboardWatch records whether the user wants the clipboard watched.
+func saveClipboardWatch(on bool) error {
+	path, err := clipboardStateFile()
+	if err != nil {
+		return err
+	}
+	if !on {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0o644)
+}
+
+// startClipboardWatch starts watching the clipboard if the user wants it.
+func startClipboardWatch() {
+	if clipboardWatchWanted() {
+		setClipboardWatch(true)
+	}
+	go watchClipboard()
+}
+
+// toggleClipboardWatch starts watching the clipboard if the item was
+// unchecked, or stops if it was checked, and remembers the choice.
+func toggleClipboardWatch(mi *systray.MenuItem) {
+	on := !mi.Checked()
+	if on {
+		if _, err := clipboard.Paste(); err == clipboard.ErrNotSupported {
+			notify.Post(notify.Notification{Message: locale.Sprintf("Unable to watch the clipboard: %v", err)})
+			return
+		}
+	}
+	if err := saveClipboardWatch(on); err != nil {
+		log.Printf("unable to remember whether to watch the clipboard: %v", err)
+	}
+	setClipboardWatch(on)
+}
+
+// setClipboardWatch checks or unchecks the "Watch Clipboard" items and
+// starts or stops the watching.
+func setClipboardWatch(on bool) {
+	for _, mi := range clipboardItems {
+		if on {
+			mi.Check()
+		} else {
+			mi.Uncheck()
+		}
+	}
+	if on {
+		// Whatever is on the clipboard already is offered, but without a
+		// notification; it wasn't just copied.
+		text, _ := clipboard.Paste()
+		clipboardMu.Lock()
+		clipboardText = text
+		clipboardMu.Unlock()
+		showClipboardIDs(clipboardIdentifiers(text))
+		atomic.StoreInt32(&watchingClipboard, 1)
+		return
+	}
+	atomic.StoreInt32(&watchingClipboard, 0)
+	clipboardMu.Lock()
+	clipboardText = ""
+	clipboardMu.Unlock()
+	showClipboardIDs(nil)
+}
+
+// watchClipboard looks at the clipboard every so often while watching is on.
+// When new text on the clipboard turns out to be identifiers, it shows the
+// "Look Up in Foliage" items and posts a notification pointing to them.
+func watchClipboard() {
+	defer crash.Recover()
+	for range time.Tick(clipboardInterval) {
+		if atomic.LoadInt32(&watchingClipboard) == 0 {
+			continue
+		}
+		text, err := clipboard.Paste()
+		if err != nil {
+			debugf("unable to read the clipboard: %v", err)
+			continue
+		}
+		clipboardMu.Lock()
+		changed := text != clipboardText
+		clipboardText = text
+		clipboardMu.Unlock()
+		if !changed {
+			continue
+		}
+		ids := clipboardIdentifiers(text)
+		showClipboardIDs(ids)
+		if len(ids) > 0 {
+			debugf("found %d identifiers on the clipboard", len(ids))
+			notify.Post(notify.Notification{
+				Message: locale.Sprintf("Copied %[1]s. To see the records, choose"+
+					" \"Look Up %[1]s in Foliage\" in the Foliage menu.", describeIDs(ids)),
+			})
+		}
+	}
+}
+
+// showClipboardIDs remembers the identifiers found on the clipboard, and
+// shows the "Look Up in Foliage" items with them, or hides the items if
+// there are none.
+func showClipboardIDs(ids []string) {
+	clipboardMu.Lock()
+	clipboardIDs = ids
+	clipboardMu.Unlock()
+	for _, mi := range clipboardLookupItems {
+		if len(ids) > 0 {
+			mi.SetTitle(locale.Sprintf("Look Up %s in Foliage", describeIDs(ids)))
+		}
+	}
+	showItems(clipboardLookupItems, len(ids) > 0)
+}
+
+// lookUpClipboard asks Foliage to look up the identifiers found on the
+// clipboard.
+func lookUpClipboard() {
+	clipboardMu.Lock()
+	ids := clipboardIDs
+	clipboardMu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+	if err := lookUp(ids, ""); err != nil {
+		log.Printf("unable to hand the lookup to Foliage: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to look up %s: %v", describeIDs(ids), err)})
+	}
+}
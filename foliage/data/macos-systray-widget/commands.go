@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"macos-systray-widget/config"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// A subcommand of the helper program.  The program is built as
+// foliage-helper, so that Foliage has one program per platform to find and
+// bundle; the tray icon is only one of the things it does.  Without a
+// subcommand, or with "tray", it runs the tray icon, and its options are
+// those of the tray.
+type subcommand struct {
+	name    string
+	usage   string // The arguments it takes, for the help.
+	summary string
+	run     func(args []string) int
+}
+
+// subcommands are set in init, because "help" lists them.
+var subcommands []subcommand
+
+func init() {
+	subcommands = []subcommand{
+		{"tray", "[options]", "run the tray icon (the default)", nil},
+		{"keyring", "get|set|delete SERVICE ACCOUNT", "use the system's credential store", runKeyring},
+		{"status", "[--json] [--url URL] [--port PORT]", "print the status of the tray, Foliage and FOLIO", runStatus},
+		{"notify", "[--title TITLE] MESSAGE", "post a notification", runNotify},
+		{"tenants", "[--json]", "list the FOLIO tenants Foliage may switch to", runTenants},
+		{"https", "[--listen PORT] [--trust] [--url URL] [--port PORT]", "serve Foliage over HTTPS", runHTTPS},
+		{"kill", "[--list] [--yes] [--url URL] [--port PORT]", "stop processes Foliage has left behind", runKill},
+		{"share", "[--listen PORT] [--http] [--url URL] [--port PORT]", "share Foliage on the local network", runShare},
+		{"watchdog", "--pid PID [--notify]", "wait for the Foliage process to exit", runWatchdog},
+		{"foliaged", "run JOB | resume ID | jobs | validate JOB | serve [--spool DIR] | audit export|verify | cache [clear] | locks [serve]", "run batch jobs against FOLIO, without Foliage", runFoliaged},
+		{"diff", "[--json] [--all] [--kind KIND] BACKUP [BACKUP]", "compare backups of a record, or a backup with FOLIO", runDiff},
+		{"restore", "[--dry-run] [--force] [--kind KIND] BACKUP... | --job ID", "put records back in FOLIO as they were backed up", runRestore},
+		{"marc", "[--format marc21|marcxml] [--output FILE] [--kind KIND] ID... | --job ID", "write the MARC records of instances from SRS to a file", runMARC},
+		{"prune", "[--keep N] [--older-than DAYS] [--dry-run] [--quiet]", "remove old backups of records", runPrune},
+		{"help", "", "list the subcommands", runHelp},
+	}
+}
+
+// lookUpSubcommand returns the subcommand with the given name.  The tray
+// has no function of its own, since it is what the program does anyway.
+func lookUpSubcommand(name string) (subcommand, bool) {
+	for _, c := range subcommands {
+		if c.name == name && c.run != nil {
+			return c, true
+		}
+	}
+	return subcommand{}, false
+}
+
+// programName returns the name the program was run by, for messages.
+func programName() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+}
+
+// subcommandFlags returns a flag set for the named subcommand, whose usage
+// message gives its arguments.
+func subcommandFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, c := range subcommands {
+			if c.name == name {
+				fmt.Fprintf(fs.Output(), "usage: %s %s %s\n", programName(), c.name, c.usage)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// runHelp runs the help subcommand.
+func runHelp(args []string) int {
+	fmt.Printf("usage: %s [SUBCOMMAND] [ARGUMENTS]\n\nSubcommands:\n", programName())
+	for _, c := range subcommands {
+		fmt.Printf("  %-9s %s\n", c.name, c.summary)
+		fmt.Println(strings.TrimRight(fmt.Sprintf("  %-9s   %s %s %s", "", programName(), c.name, c.usage), " "))
+	}
+	fmt.Printf("\nRun \"%s tray -h\" for the options of the tray icon.\n", programName())
+	return 0
+}
+
+// runStatus runs the status subcommand, which asks the running tray how it,
+// Foliage and FOLIO are, or asks Foliage itself if no tray is running, and
+// prints a summary, or with --json, the whole report.  It exits with 1 if
+// Foliage is down, for monitoring scripts.
+func runStatus(args []string) int {
+	fs := subcommandFlags("status")
+	asJSON := fs.Bool("json", false, "print the status as JSON")
+	url := fs.String("url", "", "the URL of Foliage, if no tray is running")
+	port := fs.Int("port", 0, "the port of Foliage on this computer, if no tray is running")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	setServerURL(resolveURL(*url, *port))
+	return printHelperStatus(helperStatus(serverURL()), *asJSON)
+}
+
+// runNotify runs the notify subcommand, which posts a notification the same
+// way the tray does, so that Foliage can post its own on every platform.
+// The setting FOLIAGE_NOTIFICATIONS turns these off too.
+func runNotify(args []string) int {
+	fs := subcommandFlags("notify")
+	title := fs.String("title", "Foliage", "the title of the notification")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	notify.SetEnabled(config.Bool("FOLIAGE_NOTIFICATIONS", true))
+	err := notify.Post(notify.Notification{Title: *title, Message: strings.Join(fs.Args(), " ")})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to post notification: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runWatchdog runs the watchdog subcommand, which waits until the Foliage
+// process with the given pid has exited, checking as often as the tray's
+// watchdog does, and then exits.  It is for running Foliage without the tray
+// icon; with --notify, it also tells the user that Foliage has stopped.
+func runWatchdog(args []string) int {
+	fs := subcommandFlags("watchdog")
+	pid := fs.Int("pid", 0, "the process ID of Foliage")
+	post := fs.Bool("notify", false, "post a notification when Foliage exits")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pid <= 0 {
+		fs.Usage()
+		return 2
+	}
+	for processAlive(*pid) {
+		time.Sleep(watchInterval)
+	}
+	fmt.Printf("Foliage process %d has exited.\n", *pid)
+	if *post {
+		notify.SetEnabled(config.Bool("FOLIAGE_NOTIFICATIONS", true))
+		selectLanguage(config.Get("FOLIAGE_LANG", ""))
+		err := notify.Post(notify.Notification{Message: locale.T("Foliage has stopped.")})
+		if err != nil && err != notify.ErrNotSupported {
+			fmt.Fprintf(os.Stderr, "unable to post notification: %v\n", err)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,232 @@
+// Package config reads Foliage settings the same way the Python Foliage
+// application does.  Foliage uses python-decouple, which looks for a value
+// first in the process environment and then in a settings file named either
+// "settings.ini" (with a [settings] section) or ".env", found by searching
+// upward from the directory containing the code.  Foliage starts the widget
+// as a subprocess, so the widget also inherits the environment variables that
+// Foliage sets for itself at startup (e.g., PORT and BACKUP_DIR).
+//
+// Settings the user changes in the widget's Preferences window are kept in
+// a file of their own, in the same format as a .env file, in Foliage's user
+// data directory.  They come before the settings file, so that a site can
+// set defaults for everyone, but after the environment.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"macos-systray-widget/appdirs"
+)
+
+// Name of the file of the user's preferences, in the user data directory.
+const preferencesFile = "widget-preferences.env"
+
+// Names of the settings files recognized by python-decouple, in the order in
+// which decouple tests for them in a given directory.
+var settingsFiles = []string{"settings.ini", ".env"}
+
+// Values read from the settings file and the preferences file, if any.
+// Loaded once, on first use.
+var (
+	mu         sync.Mutex
+	fileValues map[string]string
+	prefValues map[string]string
+)
+
+// Lookup returns the value of the setting named key, looking first in the
+// environment, then in the user's preferences, and then in the settings
+// file.  The boolean is false if the setting is not defined in any of them.
+func Lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	loadFiles()
+	if value, ok := prefValues[key]; ok {
+		return value, true
+	}
+	value, ok := fileValues[key]
+	return value, ok
+}
+
+// Save records the given settings in the user's preferences, replacing the
+// values saved before, if any.  A setting with an empty value is removed
+// from the preferences, so that it goes back to its default.
+func Save(values map[string]string) error {
+	path, err := PreferencesFile()
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	loadFiles()
+	prefs := map[string]string{}
+	for k, v := range prefValues {
+		prefs[k] = v
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(prefs, k)
+		} else {
+			prefs[k] = v
+		}
+	}
+	keys := make([]string, 0, len(prefs))
+	for k := range prefs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("# Foliage widget preferences, written by its Preferences window.\n")
+	for _, k := range keys {
+		// Quoted the way unquote expects: there are no escapes.
+		q := `"`
+		if strings.Contains(prefs[k], q) {
+			q = "'"
+		}
+		fmt.Fprintf(&b, "%s=%s%s%s\n", k, q, prefs[k], q)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	prefValues = prefs
+	return nil
+}
+
+// PreferencesFile returns the path to the file of the user's preferences,
+// which need not exist.
+func PreferencesFile() (string, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, preferencesFile), nil
+}
+
+// loadFiles reads the settings file and the preferences file, the first
+// time it is called.  The caller must hold mu.
+func loadFiles() {
+	if fileValues != nil {
+		return
+	}
+	fileValues = readSettingsFile()
+	prefValues = map[string]string{}
+	if path, err := PreferencesFile(); err == nil {
+		prefValues = readFile(path, false)
+	}
+}
+
+// Get returns the value of the setting named key, or def if it is not set
+// or is set to an empty string.
+func Get(key string, def string) string {
+	if value, ok := Lookup(key); ok && value != "" {
+		return value
+	}
+	return def
+}
+
+// Bool returns the value of the setting named key interpreted as a boolean
+// the way python-decouple does it, or def if the setting is not set or is
+// not a recognized boolean value.
+func Bool(key string, def bool) bool {
+	switch strings.ToLower(Get(key, "")) {
+	case "y", "yes", "t", "true", "on", "1":
+		return true
+	case "n", "no", "f", "false", "off", "0":
+		return false
+	}
+	return def
+}
+
+// SettingsFile returns the path to the settings file that would be used by
+// Foliage, or an empty string if none can be found.
+func SettingsFile() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	for dir := filepath.Dir(exe); ; dir = filepath.Dir(dir) {
+		for _, name := range settingsFiles {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+func readSettingsFile() map[string]string {
+	path := SettingsFile()
+	if path == "" {
+		return map[string]string{}
+	}
+	return readFile(path, strings.HasSuffix(path, ".ini"))
+}
+
+// readFile reads the settings in an ini file or a .env file.
+func readFile(path string, isIni bool) map[string]string {
+	values := map[string]string{}
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	// Ini files only count for values in the [settings] section; .env files
+	// have no sections at all.
+	inSettings := !isIni
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if isIni && strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSettings = strings.TrimSpace(line[1:len(line)-1]) == "settings"
+			continue
+		}
+		if !inSettings {
+			continue
+		}
+		sep := "="
+		if isIni && !strings.Contains(line, "=") {
+			sep = ":"
+		}
+		parts := strings.SplitN(line, sep, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		if !isIni {
+			key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		}
+		values[key] = unquote(strings.TrimSpace(parts[1]))
+	}
+	return values
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
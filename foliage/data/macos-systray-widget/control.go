@@ -0,0 +1,230 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/control"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/notify"
+)
+
+// The systray library can only append items to the end of the menu, but we
+// want items added by Foliage to appear in a particular place (by default,
+// above the Quit item).  So, we create a number of hidden items in advance,
+// and use them as they are needed.
+const menuSlots = 8
+
+// Menu items that show the progress of the current batch operation.  They
+// are disabled, so that they can't be clicked.
+var progressItems []*systray.MenuItem
+
+// trayControl carries out control API commands on the tray widget.
+type trayControl struct {
+	mu    sync.Mutex
+	slots []*systray.MenuItem
+	used  int
+}
+
+// newTrayControl creates n hidden menu slots for items added by Foliage,
+// at the current position in the menu.
+func newTrayControl(n int) *trayControl {
+	tc := &trayControl{}
+	for i := 0; i < n; i++ {
+		item := systray.AddMenuItem("", "")
+		item.Hide()
+		tc.slots = append(tc.slots, item)
+	}
+	return tc
+}
+
+func (tc *trayControl) SetTooltip(text string) error {
+	systray.SetTooltip(text)
+	return nil
+}
+
+func (tc *trayControl) SetIconState(state string) error {
+	return showState(state)
+}
+
+func (tc *trayControl) AddMenuItem(item control.MenuItem) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.used == len(tc.slots) {
+		return errors.New("no room for more menu items")
+	}
+	slot := tc.slots[tc.used]
+	tc.used++
+	slot.SetTitle(item.Title)
+	slot.SetTooltip(item.Tooltip)
+	slot.Show()
+	url := absoluteURL(item.URL)
+	go func() {
+		for range slot.ClickedCh {
+			if url != "" {
+				open(url)
+			}
+		}
+	}()
+	return nil
+}
+
+// AddRecent puts an entry for a lookup or batch job at the top of the
+// "Recent" submenu.
+func (tc *trayControl) AddRecent(item control.MenuItem) error {
+	addRecent(item)
+	return nil
+}
+
+func (tc *trayControl) Notify(n control.Notification) error {
+	note := notify.Notification{Title: n.Title, Message: n.Message, URL: absoluteURL(n.URL)}
+	if n.Action != nil {
+		note.Action = &notify.Action{Label: n.Action.Label, URL: absoluteURL(n.Action.URL)}
+	}
+	err := notify.Post(note)
+	if errors.Is(err, notify.ErrNotSupported) {
+		return control.ErrNotSupported
+	}
+	return err
+}
+
+// Progress shows the progress of the current batch operation in the tooltip
+// and in the menu's progress items.
+func (tc *trayControl) Progress(p control.Progress) error {
+	debugf("progress: %s", p)
+	setJob(p)
+	setBusy(p.Operation != "")
+	updateTooltip()
+	for _, item := range progressItems {
+		item.SetTitle(p.String())
+	}
+	return nil
+}
+
+// LastRecord remembers the record Foliage last showed or changed, for the
+// "Open Last Record in FOLIO" menu items.
+func (tc *trayControl) LastRecord(r control.Record) error {
+	setLastRecord(r)
+	return nil
+}
+
+// Status reports on the widget, Foliage and FOLIO, for monitoring scripts.
+func (tc *trayControl) Status() (control.Status, error) {
+	return trayStatus(), nil
+}
+
+// ChooseFile shows a native open, save or folder dialog for the Foliage page.
+func (tc *trayControl) ChooseFile(d control.FileDialog) (string, error) {
+	o := dialog.FileOptions{Title: d.Title, Directory: d.Directory, Name: d.Name, Types: d.Types}
+	var path string
+	var err error
+	switch d.Kind {
+	case "save":
+		path, err = dialog.SaveFile(o)
+	case "folder":
+		path, err = dialog.ChooseFolder(o)
+	default:
+		path, err = dialog.OpenFile(o)
+	}
+	if errors.Is(err, dialog.ErrNotSupported) {
+		return "", control.ErrNotSupported
+	}
+	return path, err
+}
+
+// absoluteURL returns url, with paths (beginning with "/") interpreted as
+// relative to the Foliage URL.
+func absoluteURL(url string) string {
+	if strings.HasPrefix(url, "/") {
+		return serverURL() + url
+	}
+	return url
+}
+
+// The control server's listener, while it is running.
+var (
+	controlMu       sync.Mutex
+	controlListener net.Listener
+)
+
+// The control token, once controlToken has worked it out.
+var (
+	controlTokenOnce sync.Once
+	controlTokenText string
+)
+
+// controlToken returns the token for the control APIs, both the widget's
+// and Foliage's: the setting FOLIAGE_CONTROL_TOKEN, which Foliage sets when
+// it starts the widget, or if there is none, a random token made for this
+// run of the widget.  The widget gives it to the Foliage it starts (see
+// startFoliage), so that neither API is ever open without one.
+func controlToken() string {
+	controlTokenOnce.Do(func() {
+		controlTokenText = config.Get("FOLIAGE_CONTROL_TOKEN", "")
+		if controlTokenText != "" {
+			return
+		}
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			log.Printf("unable to make a control token: %v", err)
+			return
+		}
+		controlTokenText = hex.EncodeToString(b)
+	})
+	return controlTokenText
+}
+
+// startControlServer starts the control API server on the given port on
+// the loopback interface, with the token from controlToken.
+func startControlServer(tc *trayControl, port int) {
+	server, err := control.NewServer(tc, controlToken())
+	if err != nil {
+		log.Printf("unable to start control server: %v", err)
+		return
+	}
+	l, err := control.Listen(port)
+	if err != nil {
+		log.Printf("unable to start control server: %v", err)
+		return
+	}
+	log.Printf("control server listening on %s", l.Addr())
+	server.OnRequest = logControlRequest
+	controlMu.Lock()
+	controlListener = l
+	controlMu.Unlock()
+	go server.Serve(l)
+}
+
+// stopControlServer stops the control server, if it is running.
+func stopControlServer() {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	if controlListener != nil {
+		controlListener.Close()
+		controlListener = nil
+	}
+}
+
+// logControlRequest logs a request to the control API.  Foliage sends many
+// of them (one for each record of a batch operation), so they are only
+// logged at the debug level, unless they fail.
+func logControlRequest(command string, status int, err error, elapsed time.Duration) {
+	level := logDebug
+	fields := []interface{}{"command", command, "status", status, "elapsed", elapsed}
+	if err != nil {
+		fields = append(fields, "error", err)
+		if status != http.StatusNotImplemented {
+			level = logInfo
+		}
+	}
+	logEvent(level, "control request", fields...)
+}
@@ -0,0 +1,492 @@
+// Package control implements a small HTTP server that lets the Foliage
+// Python process control the widget while it runs.  The server listens only
+// on the loopback interface.  There are two ways to send commands.
+//
+// One-shot commands are POST requests with a JSON body, sent to a path
+// named after the command:
+//
+//	POST /set-tooltip     {"text": "3 of 120 records changed"}
+//	POST /set-icon-state  {"state": "running"}
+//	POST /add-menu-item   {"title": "Results", "tooltip": "...", "url": "/#results"}
+//	POST /add-recent      {"title": "Changed 120 records", "tooltip": "...",
+//	                       "url": "/recent/3"}
+//	POST /notify          {"title": "Foliage", "message": "Batch change complete",
+//	                       "url": "/#results"}
+//	POST /progress        {"operation": "Changing records", "done": 57, "total": 300,
+//	                       "errors": 2, "paused": false}
+//	POST /choose-file     {"kind": "save", "title": "Save results as",
+//	                       "directory": "/Users/me/Documents",
+//	                       "name": "results.csv", "types": ["csv"]}
+//	POST /last-record     {"kind": "item", "id": "...", "instance_id": "...",
+//	                       "holdings_id": "...", "title": "35047019219626"}
+//
+// The last-record command says which FOLIO record the user looked at or
+// changed last, so that the widget can open it in FOLIO's own interface.
+// Holdings records and items need their instance (and items their holdings
+// record), and loans their user, for a link straight to them.
+//
+// The choose-file command shows a native dialog for choosing a file to open
+// ("kind": "open"), a file to save to ("save") or a folder ("folder"), so
+// that the Foliage web page can use the system's own dialogs.  It does not
+// answer until the user closes the dialog.  The answer has the path chosen
+// in the field "path", which is empty if the user canceled.
+//
+// Alternatively, a client can open a WebSocket connection to /events and
+// send a stream of messages over it.  Each message is a JSON object with a
+// field named "command", giving the command name, plus the same fields as
+// the body of the corresponding POST request.  This avoids the overhead of a
+// new connection for every update during long batch operations.
+//
+// A GET request for /status answers with the widget's Status: its own
+// version and state, whether Foliage is up, the FOLIO tenant it uses and
+// when its token expires, and the job it is running.  It is the one
+// request that needs no token, since it changes nothing and tells nothing
+// secret, so that monitoring scripts can ask for it (see FetchStatus).
+//
+// Other requests must include the server's token in the header
+// X-Foliage-Token.  All requests must be for 127.0.0.1 or localhost, at the
+// server's port, in their Host header, so that a web page can't reach the
+// server under a name of its own.  Responses to POST requests are JSON objects of the form
+// {"ok": true} or {"ok": false, "error": "message"}, with any other fields
+// of the answer (such as "path") added.
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"macos-systray-widget/locale"
+)
+
+// TokenHeader is the HTTP header that carries the control token.
+const TokenHeader = "X-Foliage-Token"
+
+// ErrNotSupported is returned by Handler methods for commands that cannot
+// be carried out on the current system.
+var ErrNotSupported = errors.New("not supported on this system")
+
+// errUnknownCommand is returned for commands that don't exist.
+var errUnknownCommand = errors.New("unknown command")
+
+// MenuItem describes a menu item added by the add-menu-item command.
+type MenuItem struct {
+	Title   string `json:"title"`
+	Tooltip string `json:"tooltip"`
+	URL     string `json:"url"` // Opened when the item is clicked.
+}
+
+// Notification describes a notification posted by the notify command.
+type Notification struct {
+	Title   string  `json:"title"`
+	Message string  `json:"message"`
+	URL     string  `json:"url"`    // Opened when the notification is clicked.
+	Action  *Action `json:"action"` // Optional button, where supported.
+}
+
+// Action describes a button in a notification that opens a URL.
+type Action struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Progress reports the state of a batch operation in the Foliage server.
+// A Progress with an empty Operation means no operation is running.
+type Progress struct {
+	Operation string `json:"operation"` // E.g., "Changing records".
+	Done      int    `json:"done"`      // Number of records processed so far.
+	Total     int    `json:"total"`     // Total number of records, if known.
+	Errors    int    `json:"errors"`    // Number of records with errors.
+	Paused    bool   `json:"paused"`    // Has the user paused the operation?
+}
+
+// String returns a short description such as "Changing records: 57/300
+// (2 errors)", or "Idle" if no operation is running.  A paused operation
+// has "(paused)" at the end.
+func (p Progress) String() string {
+	if p.Operation == "" {
+		return locale.T("Idle")
+	}
+	s := fmt.Sprintf("%s: %d", p.Operation, p.Done)
+	if p.Total > 0 {
+		s += fmt.Sprintf("/%d", p.Total)
+	}
+	switch p.Errors {
+	case 0:
+	case 1:
+		s += " (" + locale.T("1 error") + ")"
+	default:
+		s += " (" + locale.Sprintf("%d errors", p.Errors) + ")"
+	}
+	if p.Paused {
+		s += " (" + locale.T("paused") + ")"
+	}
+	return s
+}
+
+// FileDialog describes the dialog shown by the choose-file command.
+type FileDialog struct {
+	Kind      string   `json:"kind"`      // "open", "save" or "folder".
+	Title     string   `json:"title"`     // Prompt shown in the dialog.
+	Directory string   `json:"directory"` // Folder to start in, if any.
+	Name      string   `json:"name"`      // Suggested file name, for "save".
+	Types     []string `json:"types"`     // File extensions allowed, if limited.
+}
+
+// Record is a FOLIO record reported by the last-record command.
+type Record struct {
+	Kind       string `json:"kind"` // "instance", "holdings", "item", "user" or "loan".
+	ID         string `json:"id"`
+	InstanceID string `json:"instance_id"` // For holdings records and items.
+	HoldingsID string `json:"holdings_id"` // For items.
+	UserID     string `json:"user_id"`     // For loans.
+	Title      string `json:"title"`       // How to show the record, such as its barcode.
+}
+
+// Status is the widget's report on itself, on Foliage and on FOLIO, the
+// answer to GET /status.
+type Status struct {
+	Widget *WidgetStatus `json:"widget"` // Nil if the widget isn't running.
+	Server ServerStatus  `json:"server"`
+	Folio  FolioStatus   `json:"folio"`
+	Job    *Progress     `json:"job"` // Nil if no job is running.
+}
+
+// WidgetStatus describes the widget.
+type WidgetStatus struct {
+	Version     string `json:"version"`
+	Pid         int    `json:"pid"`
+	State       string `json:"state"` // The icon's state, such as "running" or "not-responding".
+	ControlPort int    `json:"control_port"`
+}
+
+// ServerStatus describes the Foliage server, as it describes itself.
+type ServerStatus struct {
+	URL      string `json:"url"`
+	Up       bool   `json:"up"`
+	Version  string `json:"version,omitempty"`
+	Pid      int    `json:"pid,omitempty"`
+	Python   string `json:"python,omitempty"`
+	Platform string `json:"platform,omitempty"`
+	Error    string `json:"error,omitempty"` // Why Foliage didn't answer, if it didn't.
+}
+
+// FolioStatus describes the FOLIO tenant Foliage uses.
+type FolioStatus struct {
+	URL          string     `json:"url,omitempty"`
+	TenantID     string     `json:"tenant_id,omitempty"`
+	Reachable    bool       `json:"reachable"` // Can this computer reach the server?
+	LoggedIn     bool       `json:"logged_in"` // Does Foliage hold a valid token?
+	TokenExpires *time.Time `json:"token_expires,omitempty"`
+	DemoMode     bool       `json:"demo_mode"`
+}
+
+// Handler carries out the commands received by the server.
+type Handler interface {
+	SetTooltip(text string) error
+	SetIconState(state string) error
+	AddMenuItem(item MenuItem) error
+	AddRecent(item MenuItem) error
+	Notify(n Notification) error
+	Progress(p Progress) error
+	ChooseFile(d FileDialog) (string, error) // Returns "" if canceled.
+	LastRecord(r Record) error
+	Status() (Status, error)
+}
+
+// Server is the control server.
+type Server struct {
+	// OnRequest, if it is set, is called after each request other than
+	// those on the event channel, with the command, the HTTP status of the
+	// answer, the error if the command failed, and how long it took.
+	OnRequest func(command string, status int, err error, elapsed time.Duration)
+
+	handler  Handler
+	token    string
+	upgrader websocket.Upgrader
+}
+
+// NewServer returns a server that passes commands to h.  Requests must
+// supply the token, which can't be empty, since the server would then do
+// what any program on the computer asks.
+func NewServer(h Handler, token string) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("no control token")
+	}
+	return &Server{handler: h, token: token}, nil
+}
+
+// Listen opens a listener on the loopback interface at the given port.  If
+// port is 0, the system picks a free port; use the listener's Addr method
+// to find out which.
+func Listen(port int) (net.Listener, error) {
+	return net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+}
+
+// Serve accepts connections on l until l is closed.
+func (s *Server) Serve(l net.Listener) error {
+	return http.Serve(l, s)
+}
+
+// ServeHTTP checks that the request is for this computer, and the token,
+// unless the request is for the status, then handles the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !localHost(r) {
+		// A web page whose host name has been pointed at 127.0.0.1 (DNS
+		// rebinding) could otherwise read the status, which needs no token.
+		log.Printf("refused control request for host %q", r.Host)
+		reply(w, http.StatusForbidden, nil, errors.New("requests must be for 127.0.0.1 or localhost"))
+		return
+	}
+	if r.URL.Path == "/events" && s.authorized(r) {
+		s.events(w, r)
+		return
+	}
+	start := time.Now()
+	var status int
+	var err error
+	if r.URL.Path == "/status" && r.Method == http.MethodGet {
+		status, err = s.status(w)
+	} else {
+		status, err = s.serve(w, r)
+	}
+	if s.OnRequest != nil {
+		s.OnRequest(strings.TrimPrefix(r.URL.Path, "/"), status, err, time.Since(start))
+	}
+}
+
+// localHost reports whether the request is for 127.0.0.1 or localhost, at
+// the port it came in on, which is how the widget's clients address it.
+func localHost(r *http.Request) bool {
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil || host != "127.0.0.1" && !strings.EqualFold(host, "localhost") {
+		return false
+	}
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		_, local, err := net.SplitHostPort(addr.String())
+		return err == nil && port == local
+	}
+	return true
+}
+
+// authorized returns true if the request has the server's token.  The
+// tokens are compared in constant time, so that timing the answers gives
+// away nothing about the token.
+func (s *Server) authorized(r *http.Request) bool {
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(s.token)) == 1
+}
+
+// serve handles a request other than one for the event channel or the
+// status, and returns the HTTP status of the answer and the error, if there
+// was one.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) (int, error) {
+	status, fields, err := s.handle(r)
+	if status == http.StatusBadRequest && err != nil {
+		log.Printf("control command %s failed: %v", r.URL.Path[1:], err)
+	}
+	reply(w, status, fields, err)
+	return status, err
+}
+
+// status answers a GET request for /status, and returns the HTTP status of
+// the answer and the error, if there was one.
+func (s *Server) status(w http.ResponseWriter) (int, error) {
+	st, err := s.handler.Status()
+	if err != nil {
+		reply(w, http.StatusInternalServerError, nil, err)
+		return http.StatusInternalServerError, err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(st)
+	return http.StatusOK, nil
+}
+
+// handle carries out the request, and returns the HTTP status and fields of
+// the answer, or the error.
+func (s *Server) handle(r *http.Request) (int, map[string]interface{}, error) {
+	if !s.authorized(r) {
+		return http.StatusForbidden, nil, errors.New("invalid or missing token")
+	}
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, nil, errors.New("only POST is supported")
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return http.StatusBadRequest, nil, errors.New("invalid JSON in request body")
+	}
+	fields, err := s.dispatch(r.URL.Path[1:], body)
+	switch {
+	case err == nil:
+		return http.StatusOK, fields, nil
+	case errors.Is(err, errUnknownCommand):
+		return http.StatusNotFound, nil, err
+	case errors.Is(err, ErrNotSupported):
+		return http.StatusNotImplemented, nil, err
+	}
+	return http.StatusBadRequest, nil, err
+}
+
+// events handles a WebSocket connection, reading messages until the client
+// closes the connection.  The reply to each message is sent back over the
+// same connection.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already sent an HTTP error response.
+		log.Printf("unable to open event channel: %v", err)
+		return
+	}
+	defer conn.Close()
+	log.Printf("event channel opened from %s", conn.RemoteAddr())
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("event channel closed: %v", err)
+			}
+			return
+		}
+		var msg struct {
+			Command string `json:"command"`
+		}
+		var fields map[string]interface{}
+		if err = json.Unmarshal(data, &msg); err == nil {
+			fields, err = s.dispatch(msg.Command, data)
+		}
+		if err != nil {
+			log.Printf("event message failed: %v", err)
+		}
+		conn.WriteJSON(result(fields, err))
+	}
+}
+
+// dispatch parses the body for the named command and passes it to the
+// handler.  Besides any error, it returns the fields to add to the answer.
+func (s *Server) dispatch(command string, body []byte) (map[string]interface{}, error) {
+	switch command {
+	case "set-tooltip":
+		var args struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(body, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.handler.SetTooltip(args.Text)
+	case "set-icon-state":
+		var args struct {
+			State string `json:"state"`
+		}
+		if err := json.Unmarshal(body, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.handler.SetIconState(args.State)
+	case "add-menu-item":
+		var item MenuItem
+		if err := json.Unmarshal(body, &item); err != nil {
+			return nil, err
+		}
+		if item.Title == "" {
+			return nil, errors.New("missing title")
+		}
+		return nil, s.handler.AddMenuItem(item)
+	case "add-recent":
+		var item MenuItem
+		if err := json.Unmarshal(body, &item); err != nil {
+			return nil, err
+		}
+		if item.Title == "" || item.URL == "" {
+			return nil, errors.New("missing title or url")
+		}
+		return nil, s.handler.AddRecent(item)
+	case "notify":
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			return nil, err
+		}
+		return nil, s.handler.Notify(n)
+	case "progress":
+		var p Progress
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.handler.Progress(p)
+	case "choose-file":
+		var d FileDialog
+		if err := json.Unmarshal(body, &d); err != nil {
+			return nil, err
+		}
+		switch d.Kind {
+		case "open", "save", "folder":
+		default:
+			return nil, fmt.Errorf("unknown kind of dialog %q", d.Kind)
+		}
+		path, err := s.handler.ChooseFile(d)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"path": path}, nil
+	case "last-record":
+		var rec Record
+		if err := json.Unmarshal(body, &rec); err != nil {
+			return nil, err
+		}
+		switch rec.Kind {
+		case "instance", "holdings", "item", "user", "loan":
+		default:
+			return nil, fmt.Errorf("unknown kind of record %q", rec.Kind)
+		}
+		if rec.ID == "" {
+			return nil, errors.New("missing id")
+		}
+		return nil, s.handler.LastRecord(rec)
+	}
+	return nil, fmt.Errorf("%w %q", errUnknownCommand, command)
+}
+
+func result(fields map[string]interface{}, err error) map[string]interface{} {
+	body := map[string]interface{}{"ok": err == nil}
+	for k, v := range fields {
+		body[k] = v
+	}
+	if err != nil {
+		body["error"] = err.Error()
+	}
+	return body
+}
+
+func reply(w http.ResponseWriter, status int, fields map[string]interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result(fields, err))
+}
+
+// How long FetchStatus waits for the widget, which asks Foliage and FOLIO in
+// turn before it answers.
+const statusTimeout = 30 * time.Second
+
+// FetchStatus asks the widget whose control server listens at the port on
+// this computer for its Status.
+func FetchStatus(port int) (*Status, error) {
+	client := &http.Client{Timeout: statusTimeout}
+	resp, err := client.Get("http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) + "/status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status request failed: %s", resp.Status)
+	}
+	var st Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, fmt.Errorf("unable to read the widget's status: %w", err)
+	}
+	return &st, nil
+}
@@ -0,0 +1,117 @@
+package control
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeHandler records the tooltip it is given.  Methods other than
+// SetTooltip and Status aren't used by the tests, so they are left to the
+// embedded (nil) Handler.
+type fakeHandler struct {
+	Handler
+	tooltip string
+}
+
+func (h *fakeHandler) SetTooltip(text string) error {
+	h.tooltip = text
+	return nil
+}
+
+func (h *fakeHandler) Status() (Status, error) {
+	return Status{Widget: &WidgetStatus{State: "running"}}, nil
+}
+
+func TestNewServerNeedsToken(t *testing.T) {
+	if _, err := NewServer(&fakeHandler{}, ""); err == nil {
+		t.Error("NewServer accepted an empty token")
+	}
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"right token", http.MethodPost, "/set-tooltip", "secret", http.StatusOK},
+		{"no token", http.MethodPost, "/set-tooltip", "", http.StatusForbidden},
+		{"wrong token", http.MethodPost, "/set-tooltip", "secreT", http.StatusForbidden},
+		{"prefix of the token", http.MethodPost, "/set-tooltip", "secre", http.StatusForbidden},
+		{"status without a token", http.MethodGet, "/status", "", http.StatusOK},
+		{"events without a token", http.MethodGet, "/events", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &fakeHandler{}
+			s, err := NewServer(h, "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest(tt.method, "http://127.0.0.1:8731"+tt.path, strings.NewReader(`{"text": "hello"}`))
+			if tt.token != "" {
+				r.Header.Set(TokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d (%s)", w.Code, tt.want, w.Body)
+			}
+			if tt.path == "/set-tooltip" && (h.tooltip == "hello") != (tt.want == http.StatusOK) {
+				t.Errorf("tooltip is %q after a request with status %d", h.tooltip, w.Code)
+			}
+		})
+	}
+}
+
+func TestHost(t *testing.T) {
+	s, err := NewServer(&fakeHandler{}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"127.0.0.1:" + port, http.StatusOK},
+		{"localhost:" + port, http.StatusOK},
+		{"LocalHost:" + port, http.StatusOK},
+		{"rebound.example.com:" + port, http.StatusForbidden},
+		{"127.0.0.2:" + port, http.StatusForbidden},
+		{"127.0.0.1:1", http.StatusForbidden},
+		{"127.0.0.1", http.StatusForbidden},
+		{"localhost", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		for _, path := range []string{"/status", "/set-tooltip"} {
+			t.Run(tt.host+path, func(t *testing.T) {
+				method := http.MethodPost
+				if path == "/status" {
+					method = http.MethodGet
+				}
+				r, err := http.NewRequest(method, srv.URL+path, strings.NewReader(`{"text": "hello"}`))
+				if err != nil {
+					t.Fatal(err)
+				}
+				r.Host = tt.host
+				r.Header.Set(TokenHeader, "secret")
+				resp, err := srv.Client().Do(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.want {
+					t.Errorf("got status %d, want %d", resp.StatusCode, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"log"
+
+	"macos-systray-widget/clipboard"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// copyURL puts the address of the Foliage user interface on the clipboard.
+func copyURL() {
+	message := locale.Sprintf("Copied %s to the clipboard.", serverURL())
+	if err := clipboard.Copy(serverURL()); err != nil {
+		log.Printf("unable to copy URL to the clipboard: %v", err)
+		message = locale.Sprintf("Unable to copy the Foliage URL: %v", err)
+	}
+	notify.Post(notify.Notification{Message: message})
+}
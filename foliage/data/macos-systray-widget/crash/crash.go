@@ -0,0 +1,261 @@
+// Package crash records panics, so that a widget that disappears from the
+// system tray leaves something behind to say why.  Each panic is written to
+// a crash file, with the stack trace and the versions of the program and
+// the system.  If a Sentry DSN is configured, the crash is also sent to that
+// Sentry project, through Sentry's HTTP envelope API; the report holds the
+// panic message, the stack trace, the operating system, and the tags set
+// with SetTag, and nothing else.
+package crash
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExitStatus is the status the program exits with after a crash.
+const ExitStatus = 2
+
+// How long to wait for Sentry to take a report, and how many crash files to
+// keep.
+const (
+	sendTimeout = 10 * time.Second
+	keepFiles   = 10
+)
+
+// Config says where to record crashes.
+type Config struct {
+	Dir     string // Directory for crash files.
+	Prefix  string // Start of the names of crash files, such as "widget".
+	DSN     string // Sentry DSN to send crashes to, or "" to keep them here.
+	Release string // Version of the program, for reports.
+}
+
+var (
+	mu     sync.Mutex
+	config Config
+	tags   = map[string]string{}
+)
+
+// Setup sets where to record crashes.  Until it is called, panics are only
+// recorded in the standard error output, as Go normally does.
+func Setup(c Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	config = c
+}
+
+// SetTag records something to include in crash reports, such as the version
+// of a server the program talks to.
+func SetTag(key, value string) {
+	mu.Lock()
+	defer mu.Unlock()
+	tags[key] = value
+}
+
+// Recover records a panic, if there is one, and makes the program exit with
+// ExitStatus.  It has to be deferred, at the top of main and of each
+// goroutine to be covered; a panic in a goroutine without it still crashes
+// the program, but isn't recorded here.
+func Recover() {
+	v := recover()
+	if v == nil {
+		return
+	}
+	stack := debug.Stack()
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", v, stack)
+	Report(fmt.Sprint(v), stack, pcs)
+	os.Exit(ExitStatus)
+}
+
+// Report records a crash with the given message, text of the stack trace,
+// and program counters of the stack, writing it to a crash file and sending
+// it to Sentry if a DSN is configured.
+func Report(message string, stack []byte, pcs []uintptr) {
+	mu.Lock()
+	c := config
+	t := map[string]string{}
+	for k, v := range tags {
+		t[k] = v
+	}
+	mu.Unlock()
+	now := time.Now()
+	if c.Dir != "" {
+		if path, err := writeFile(c, now, message, stack, t); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to write crash file: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "crash recorded in %s\n", path)
+		}
+	}
+	if c.DSN != "" {
+		if err := send(c, now, message, pcs, t); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to send crash report: %v\n", err)
+		}
+	}
+}
+
+// writeFile writes a crash file, and removes the oldest ones beyond
+// keepFiles.  It returns the path of the file.
+func writeFile(c Config, now time.Time, message string, stack []byte, t map[string]string) (string, error) {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return "", err
+	}
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = filepath.Base(os.Args[0])
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Crash at %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Program: %s %s\n", prefix, c.Release)
+	fmt.Fprintf(&b, "System: %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	for _, k := range sortedKeys(t) {
+		fmt.Fprintf(&b, "%s: %s\n", k, t[k])
+	}
+	fmt.Fprintf(&b, "\npanic: %s\n\n%s", message, stack)
+	path := filepath.Join(c.Dir, prefix+"-crash-"+now.Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	old, _ := filepath.Glob(filepath.Join(c.Dir, prefix+"-crash-*.txt"))
+	sort.Strings(old)
+	for len(old) > keepFiles {
+		os.Remove(old[0])
+		old = old[1:]
+	}
+	return path, nil
+}
+
+// frame is a stack frame in a Sentry event.
+type frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// send sends the crash to Sentry, as an envelope holding one event.
+func send(c Config, now time.Time, message string, pcs []uintptr, t map[string]string) error {
+	endpoint, key, err := parseDSN(c.DSN)
+	if err != nil {
+		return err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	eventID := hex.EncodeToString(id)
+	main := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		main = info.Main.Path
+	}
+	// Sentry wants the oldest frame first; the runtime's own are left out.
+	var frames []frame
+	it := runtime.CallersFrames(pcs)
+	for {
+		f, more := it.Next()
+		if !strings.HasPrefix(f.Function, "runtime.") && f.Function != "" {
+			module, function := splitFunction(f.Function)
+			frames = append([]frame{{function, module, f.File, f.Line,
+				module == "main" || (main != "" && strings.HasPrefix(module, main))}}, frames...)
+		}
+		if !more {
+			break
+		}
+	}
+	event := map[string]interface{}{
+		"event_id":  eventID,
+		"timestamp": now.UTC().Format(time.RFC3339),
+		"platform":  "go",
+		"level":     "fatal",
+		"release":   c.Release,
+		"tags":      t,
+		"contexts": map[string]interface{}{
+			"os":      map[string]string{"name": runtime.GOOS},
+			"device":  map[string]string{"arch": runtime.GOARCH},
+			"runtime": map[string]string{"name": "go", "version": runtime.Version()},
+		},
+		"exception": map[string]interface{}{
+			"values": []interface{}{map[string]interface{}{
+				"type":       "panic",
+				"value":      message,
+				"stacktrace": map[string]interface{}{"frames": frames},
+			}},
+		},
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, item := range []interface{}{
+		map[string]string{"event_id": eventID, "dsn": c.DSN},
+		map[string]string{"type": "event"},
+		event,
+	} {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client=foliage-crash/1.0, sentry_key="+key)
+	resp, err := (&http.Client{Timeout: sendTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Sentry answered %s", resp.Status)
+	}
+	return nil
+}
+
+// parseDSN returns the envelope endpoint and public key of a Sentry DSN,
+// which has the form https://KEY@HOST/PROJECT (possibly with a path before
+// the project).
+func parseDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	key := u.User.Username()
+	i := strings.LastIndex(u.Path, "/")
+	if key == "" || u.Host == "" || i < 0 || u.Path[i+1:] == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN %q", dsn)
+	}
+	return fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, u.Path[:i], u.Path[i+1:]), key, nil
+}
+
+// splitFunction splits the name of a function, such as
+// "macos-systray-widget/menu.(*Item).Click", into its package and the rest.
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot], name[slash+2+dot:]
+	}
+	return "", name
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+	"runtime/debug"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/config"
+	"macos-systray-widget/crash"
+)
+
+// setUpCrashes makes the widget record a panic in a crash file in the log
+// directory, named widget-crash- and the time, before it exits, so that an
+// icon that vanishes from the tray leaves a stack trace behind; the agent
+// that keeps the widget running then starts it again.  If the user has
+// turned on FOLIAGE_CRASH_REPORTS (in Preferences, as "Send crash reports")
+// and there is a Sentry project to send them to, in FOLIAGE_SENTRY_DSN, the
+// crash is also sent there, with the versions of the widget, Foliage and
+// the operating system.
+func setUpCrashes() {
+	c := crash.Config{Prefix: "widget", Release: widgetVersion()}
+	if dir, err := appdirs.UserLogDir(); err == nil {
+		c.Dir = dir
+	} else {
+		log.Printf("unable to find where to record crashes: %v", err)
+	}
+	if config.Bool("FOLIAGE_CRASH_REPORTS", false) {
+		c.DSN = config.Get("FOLIAGE_SENTRY_DSN", "")
+	}
+	crash.Setup(c)
+}
+
+// widgetVersion returns the version of the widget's module, as the Go tools
+// recorded it when it was built.
+func widgetVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return "foliage-widget@" + info.Main.Version
+	}
+	return "foliage-widget@(devel)"
+}
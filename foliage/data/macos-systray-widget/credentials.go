@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"macos-systray-widget/keyring"
+)
+
+// Exit statuses of the keyring subcommand, besides 0 for success.
+const (
+	exitKeyringError    = 1
+	exitKeyringUsage    = 2
+	exitKeyringNotFound = 4
+)
+
+const keyringUsage = `usage: %[1]s keyring get SERVICE ACCOUNT
+       %[1]s keyring set SERVICE ACCOUNT < secret
+       %[1]s keyring delete SERVICE ACCOUNT
+`
+
+// runKeyring runs the keyring subcommand, which lets Foliage store its FOLIO
+// credentials in the system's credential store without needing a Python
+// keyring backend.  The subcommand "get" writes the secret to the standard
+// output, exactly as it was stored; "set" reads it from the standard input
+// (so that it doesn't appear in the list of processes), dropping a final
+// newline.  It returns the exit status.
+func runKeyring(args []string) int {
+	if len(args) != 3 {
+		fmt.Fprintf(os.Stderr, keyringUsage, programName())
+		return exitKeyringUsage
+	}
+	command, service, account := args[0], args[1], args[2]
+	var err error
+	switch command {
+	case "get":
+		var secret string
+		if secret, err = keyring.Get(service, account); err == nil {
+			_, err = io.WriteString(os.Stdout, secret)
+		}
+	case "set":
+		var input []byte
+		if input, err = io.ReadAll(os.Stdin); err == nil {
+			secret := strings.TrimSuffix(string(input), "\n")
+			err = keyring.Set(service, account, secret)
+		}
+	case "delete":
+		err = keyring.Delete(service, account)
+	default:
+		fmt.Fprintf(os.Stderr, keyringUsage, programName())
+		return exitKeyringUsage
+	}
+	if err == keyring.ErrNotFound {
+		return exitKeyringNotFound
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "keyring %s: %v\n", command, err)
+		return exitKeyringError
+	}
+	return 0
+}
@@ -0,0 +1,170 @@
+// Package cron reads schedules written the way cron's are, such as
+// "0 6 * * mon" for six in the morning every Monday, and works out when they
+// next come round.  A schedule has five fields, separated by spaces: the
+// minute (0–59), the hour (0–23), the day of the month (1–31), the month
+// (1–12, or jan–dec) and the day of the week (0–7, or sun–sat, where 0 and 7
+// are both Sunday).  Each field is "*" for any value, a value, a range of
+// values such as "1-5", or a list of them separated by commas, and "/n"
+// after "*" or a range takes every nth value from it.  As in cron, when
+// both the day of the month and the day of the week are given, either one
+// will do.  The shortcuts @yearly (or @annually), @monthly, @weekly, @daily
+// (or @midnight) and @hourly stand for the schedules they name.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a schedule in cron's form.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bit n is set if value n matches.
+
+	// Whether the day of the month and the day of the week are "*", which
+	// decides how they are combined.
+	anyDOM, anyDOW bool
+}
+
+var shortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	months = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	days   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// Parse reads a schedule.
+func Parse(expr string) (*Schedule, error) {
+	text := strings.TrimSpace(strings.ToLower(expr))
+	if s, ok := shortcuts[text]; ok {
+		text = s
+	}
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the schedule %q doesn't have five fields (minute, hour, day of the month, month, day of the week)", expr)
+	}
+	var s Schedule
+	var err error
+	if s.minute, err = field(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("the minute in the schedule %q: %w", expr, err)
+	}
+	if s.hour, err = field(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("the hour in the schedule %q: %w", expr, err)
+	}
+	if s.dom, err = field(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("the day of the month in the schedule %q: %w", expr, err)
+	}
+	if s.month, err = field(fields[3], 1, 12, months); err != nil {
+		return nil, fmt.Errorf("the month in the schedule %q: %w", expr, err)
+	}
+	if s.dow, err = field(fields[4], 0, 7, days); err != nil {
+		return nil, fmt.Errorf("the day of the week in the schedule %q: %w", expr, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too.
+	}
+	s.anyDOM, s.anyDOW = strings.HasPrefix(fields[2], "*"), strings.HasPrefix(fields[4], "*")
+	return &s, nil
+}
+
+// field reads a field whose values are from lo to hi, or, if there are
+// names, the names for them, starting at lo.
+func field(text string, lo, hi int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", part[i+1:])
+			}
+			step, part = n, part[:i]
+		}
+		first, last := lo, hi
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if first, err = value(bounds[0], lo, hi, names); err != nil {
+				return 0, err
+			}
+			last = first
+			if len(bounds) == 2 {
+				if last, err = value(bounds[1], lo, hi, names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				last = hi
+			}
+			if last < first {
+				return 0, fmt.Errorf("the range %q goes backwards", part)
+			}
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value reads a value of a field.
+func value(text string, lo, hi int, names []string) (int, error) {
+	for i, name := range names {
+		if text == name {
+			return lo + i, nil
+		}
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", text)
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%d isn't from %d to %d", n, lo, hi)
+	}
+	return n, nil
+}
+
+// Next returns the first time after t that the schedule comes round, in t's
+// time zone, or the zero time if it never does (as for February 30th).
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// day reports whether the schedule comes round on t's day.
+func (s *Schedule) day(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDOM && s.anyDOW:
+		return true
+	case s.anyDOM:
+		return dow
+	case s.anyDOW:
+		return dom
+	}
+	return dom || dow
+}
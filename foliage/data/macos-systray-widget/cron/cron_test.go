@@ -0,0 +1,84 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2024, time.May, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want []string // The next times, in order.
+	}{
+		{"*/15 * * * *", []string{"2024-05-15 10:15", "2024-05-15 10:30", "2024-05-15 10:45", "2024-05-15 11:00"}},
+		{"5/20 * * * *", []string{"2024-05-15 10:25", "2024-05-15 10:45", "2024-05-15 11:05"}},
+		{"0 9-17/4 * * *", []string{"2024-05-15 13:00", "2024-05-15 17:00", "2024-05-16 09:00"}},
+		{"30 6 * * 1-5", []string{"2024-05-16 06:30", "2024-05-17 06:30", "2024-05-20 06:30"}},
+		{"0 0 * * 0", []string{"2024-05-19 00:00", "2024-05-26 00:00"}},
+		{"0 0 * * 7", []string{"2024-05-19 00:00", "2024-05-26 00:00"}},
+		{"0 0 * * sun", []string{"2024-05-19 00:00"}},
+		{"0 0 * * 5-7", []string{"2024-05-17 00:00", "2024-05-18 00:00", "2024-05-19 00:00", "2024-05-24 00:00"}},
+		{"0 6 * * MON", []string{"2024-05-20 06:00"}},
+		{"0 0 1,15 * *", []string{"2024-06-01 00:00", "2024-06-15 00:00"}},
+		{"0 0 13 * fri", []string{"2024-05-17 00:00", "2024-05-24 00:00", "2024-05-31 00:00", "2024-06-07 00:00", "2024-06-13 00:00"}},
+		{"0 12 29 feb *", []string{"2028-02-29 12:00"}},
+		{"0 0 1 jan-mar/2 *", []string{"2025-01-01 00:00", "2025-03-01 00:00", "2026-01-01 00:00"}},
+		{"@hourly", []string{"2024-05-15 11:00", "2024-05-15 12:00"}},
+		{"@weekly", []string{"2024-05-19 00:00"}},
+		{"@monthly", []string{"2024-06-01 00:00"}},
+		{" @Yearly ", []string{"2025-01-01 00:00"}},
+		{"0 0 30 feb *", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := from
+			for _, want := range tt.want {
+				next = s.Next(next)
+				if got := next.Format("2006-01-02 15:04"); got != want {
+					t.Fatalf("got %s, want %s", got, want)
+				}
+			}
+			if tt.want == nil && !s.Next(from).IsZero() {
+				t.Errorf("got %s, want the zero time", s.Next(from))
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string // What the error says.
+	}{
+		{"", "doesn't have five fields"},
+		{"* * * *", "doesn't have five fields"},
+		{"* * * * * *", "doesn't have five fields"},
+		{"@often", "doesn't have five fields"},
+		{"60 * * * *", "the minute in the schedule \"60 * * * *\": 60 isn't from 0 to 59"},
+		{"* 24 * * *", "the hour in the schedule \"* 24 * * *\": 24 isn't from 0 to 23"},
+		{"* * 0 * *", "the day of the month in the schedule \"* * 0 * *\": 0 isn't from 1 to 31"},
+		{"* * * 13 *", "the month in the schedule \"* * * 13 *\": 13 isn't from 1 to 12"},
+		{"* * * * 8", "the day of the week in the schedule \"* * * * 8\": 8 isn't from 0 to 7"},
+		{"* * * * mo", "\"mo\" isn't a number"},
+		{"*/0 * * * *", "bad step \"0\""},
+		{"*/x * * * *", "bad step \"x\""},
+		{"17-5 * * * *", "the range \"17-5\" goes backwards"},
+		{"1-x * * * *", "\"x\" isn't a number"},
+		{"1,,2 * * * *", "\"\" isn't a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one with %q", err, tt.want)
+			}
+		})
+	}
+}
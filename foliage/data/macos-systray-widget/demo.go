@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log"
+
+	"fyne.io/systray"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// The "Demo Mode" menu items, which are checked while Foliage is in demo
+// mode.
+var demoItems []*systray.MenuItem
+
+// showDemoMode checks or unchecks the demo mode items.
+func showDemoMode(on bool) {
+	for _, mi := range demoItems {
+		if on {
+			mi.Check()
+		} else {
+			mi.Uncheck()
+		}
+	}
+}
+
+// toggleDemoMode asks Foliage to switch demo mode on if the item was
+// unchecked, or off if it was checked.  The items only change once Foliage
+// has agreed, so that they always show the mode Foliage is really in.
+func toggleDemoMode(mi *systray.MenuItem) {
+	on := !mi.Checked()
+	if err := jobs.SetDemoMode(serverURL(), controlToken(), on); err != nil {
+		log.Printf("unable to change demo mode: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to change demo mode: %v", err)})
+		return
+	}
+	showDemoMode(on)
+	message := locale.T("Demo mode is off. Foliage will change records in FOLIO.")
+	if on {
+		message = locale.T("Demo mode is on. Foliage will not change any records.")
+	}
+	notify.Post(notify.Notification{Message: message})
+	refreshSession()
+}
@@ -0,0 +1,112 @@
+// Package dialog shows simple native dialog boxes, such as the widget's
+// "About Foliage" window, and the system's dialogs for choosing files and
+// folders.
+package dialog
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotSupported is returned on systems where dialogs are not implemented,
+// or where no program for showing them is installed.
+var ErrNotSupported = errors.New("dialogs are not supported on this system")
+
+// Info shows an informational message with an OK button, and returns when
+// the user dismisses it.
+func Info(title, text string) error {
+	if title == "" {
+		title = "Foliage"
+	}
+	return info(title, text)
+}
+
+// Confirm asks the user a question, with a button labeled ok for agreeing
+// and another for declining, and reports whether the user agreed.  (On
+// Windows, the buttons are always labeled OK and Cancel.)
+func Confirm(title, text, ok string) (bool, error) {
+	if title == "" {
+		title = "Foliage"
+	}
+	if ok == "" {
+		ok = "OK"
+	}
+	return confirm(title, text, ok)
+}
+
+// Choose asks the user to pick one of the choices, and returns the index of
+// the one picked, or -1 if the user cancels.
+func Choose(title, text string, choices []string) (int, error) {
+	if title == "" {
+		title = "Foliage"
+	}
+	if len(choices) == 0 {
+		return -1, nil
+	}
+	return choose(title, text, choices)
+}
+
+// FileOptions describe a dialog for choosing a file or folder.
+type FileOptions struct {
+	Title     string   // The dialog's title, or its prompt on macOS.
+	Directory string   // The folder the dialog starts in, if not the system's choice.
+	Name      string   // The file name suggested for saving.
+	Types     []string // Extensions of the files that can be opened, such as ".csv".
+}
+
+// The kinds of file dialog.
+const (
+	openFile = iota
+	saveFile
+	chooseFolder
+)
+
+// OpenFile asks the user to choose an existing file, and returns its path,
+// or "" if the user cancels.
+func OpenFile(o FileOptions) (string, error) {
+	return chooseFile(openFile, fileDefaults(o, "Choose a File"))
+}
+
+// SaveFile asks the user where to save a file, and returns the path, or ""
+// if the user cancels.  The dialog asks before replacing an existing file.
+func SaveFile(o FileOptions) (string, error) {
+	return chooseFile(saveFile, fileDefaults(o, "Save As"))
+}
+
+// ChooseFolder asks the user to choose a folder, and returns its path, or
+// "" if the user cancels.
+func ChooseFolder(o FileOptions) (string, error) {
+	return chooseFile(chooseFolder, fileDefaults(o, "Choose a Folder"))
+}
+
+func fileDefaults(o FileOptions, title string) FileOptions {
+	if o.Title == "" {
+		o.Title = title
+	}
+	types := make([]string, len(o.Types))
+	for i, t := range o.Types {
+		types[i] = strings.TrimPrefix(t, ".")
+	}
+	o.Types = types
+	return o
+}
+
+// Field is one of the fields of a form.  It is a text field, unless Check
+// is set, in which case it is a checkbox, or Choices are given, in which
+// case it is a pop-up menu of them.
+type Field struct {
+	Label   string
+	Value   string // The value shown at first; "true" or "false" for a checkbox.
+	Check   bool
+	Choices []string
+}
+
+// Form asks the user to fill in the fields, and returns the values given
+// for them, in the same order, or nil if the user cancels.  The value of a
+// checkbox is "true" or "false".
+func Form(title, text string, fields []Field) ([]string, error) {
+	if title == "" {
+		title = "Foliage"
+	}
+	return form(title, text, fields)
+}
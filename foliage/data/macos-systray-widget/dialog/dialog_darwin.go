@@ -0,0 +1,167 @@
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+func info(title, text string) error {
+	script := "display dialog " + shellquote.AppleScript(text) +
+		" with title " + shellquote.AppleScript(title) +
+		` buttons {"OK"} default button "OK" with icon note`
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+func confirm(title, text, ok string) (bool, error) {
+	button := shellquote.AppleScript(ok)
+	script := "display dialog " + shellquote.AppleScript(text) +
+		" with title " + shellquote.AppleScript(title) +
+		` buttons {"Cancel", ` + button + `} default button ` + button +
+		` cancel button "Cancel" with icon caution`
+	return answered(exec.Command("osascript", "-e", script).Run())
+}
+
+// choose uses "choose from list", which answers "false" if the user
+// cancels.
+func choose(title, text string, choices []string) (int, error) {
+	quoted := make([]string, len(choices))
+	for i, choice := range choices {
+		quoted[i] = shellquote.AppleScript(choice)
+	}
+	script := "choose from list {" + strings.Join(quoted, ", ") + "} with title " +
+		shellquote.AppleScript(title) + " with prompt " + shellquote.AppleScript(text) +
+		" default items {" + quoted[0] + `} OK button name "Continue"`
+	out, err := exec.Command("osascript", "-e", script).Output()
+	return chosen(out, err, choices)
+}
+
+// chooseFile uses "choose file", "choose file name" or "choose folder",
+// which fail with status 1 if the user cancels.
+func chooseFile(kind int, o FileOptions) (string, error) {
+	script := map[int]string{
+		openFile:     "choose file",
+		saveFile:     "choose file name",
+		chooseFolder: "choose folder",
+	}[kind] + " with prompt " + shellquote.AppleScript(o.Title)
+	if o.Directory != "" {
+		script += " default location (POSIX file " + shellquote.AppleScript(o.Directory) + ")"
+	}
+	if kind == saveFile && o.Name != "" {
+		script += " default name " + shellquote.AppleScript(o.Name)
+	}
+	if kind == openFile && len(o.Types) > 0 {
+		quoted := make([]string, len(o.Types))
+		for i, t := range o.Types {
+			quoted[i] = shellquote.AppleScript(t)
+		}
+		script += " of type {" + strings.Join(quoted, ", ") + "}"
+	}
+	out, err := exec.Command("osascript", "-e", "POSIX path of ("+script+")").Output()
+	if ok, err := answered(err); !ok {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// formScript shows a form as an alert with the fields in its accessory
+// view, using AppleScript's JavaScript dialect, which can reach AppKit; plain
+// AppleScript can only ask for one thing at a time.  It is given the form as
+// JSON, and prints the values as a JSON list, or nothing if the user cancels.
+// The numbers are AppKit constants: NSSwitchButton, NSTextAlignmentRight,
+// and NSAlertFirstButtonReturn.
+const formScript = `ObjC.import('Cocoa');
+function run(argv) {
+	var form = JSON.parse(argv[0]);
+	var row = 30, width = 400, labelWidth = 150;
+	var height = row * form.fields.length;
+	var view = $.NSView.alloc.initWithFrame($.NSMakeRect(0, 0, width, height));
+	var controls = form.fields.map(function (f, i) {
+		var y = height - (i + 1) * row;
+		var frame = $.NSMakeRect(labelWidth + 8, y, width - labelWidth - 8, 24);
+		var c;
+		if (f.check) {
+			c = $.NSButton.alloc.initWithFrame(frame);
+			c.setButtonType(3);
+			c.title = '';
+			c.state = f.value === 'true' ? 1 : 0;
+		} else if (f.choices) {
+			c = $.NSPopUpButton.alloc.initWithFramePullsDown(frame, false);
+			f.choices.forEach(function (choice) { c.addItemWithTitle(choice); });
+			c.selectItemWithTitle(f.value);
+		} else {
+			c = $.NSTextField.alloc.initWithFrame(frame);
+			c.stringValue = f.value;
+		}
+		var label = $.NSTextField.labelWithString(f.label + ':');
+		label.frame = $.NSMakeRect(0, y + 3, labelWidth, 18);
+		label.alignment = 1;
+		// The label is beside the control, not part of it, so VoiceOver
+		// is told which control it names; checkboxes have no title of
+		// their own otherwise.
+		c.setAccessibilityLabel(f.label);
+		c.setAccessibilityTitleUIElement(label);
+		view.addSubview(label);
+		view.addSubview(c);
+		return {field: f, control: c};
+	});
+	var alert = $.NSAlert.alloc.init;
+	alert.messageText = form.title;
+	alert.informativeText = form.text;
+	alert.addButtonWithTitle('OK');
+	alert.addButtonWithTitle('Cancel');
+	alert.accessoryView = view;
+	alert.layout;
+	if (controls.length > 0) {
+		alert.window.initialFirstResponder = controls[0].control;
+	}
+	$.NSApplication.sharedApplication.activateIgnoringOtherApps(true);
+	if (alert.runModal != 1000) {
+		return '';
+	}
+	return JSON.stringify(controls.map(function (c) {
+		if (c.field.check) {
+			return c.control.state == 1 ? 'true' : 'false';
+		} else if (c.field.choices) {
+			return c.control.titleOfSelectedItem.js;
+		}
+		return c.control.stringValue.js;
+	}));
+}`
+
+func form(title, text string, fields []Field) ([]string, error) {
+	type jsField struct {
+		Label   string   `json:"label"`
+		Value   string   `json:"value"`
+		Check   bool     `json:"check"`
+		Choices []string `json:"choices,omitempty"`
+	}
+	spec := struct {
+		Title  string    `json:"title"`
+		Text   string    `json:"text"`
+		Fields []jsField `json:"fields"`
+	}{Title: title, Text: text}
+	for _, f := range fields {
+		spec.Fields = append(spec.Fields, jsField{f.Label, f.Value, f.Check, f.Choices})
+	}
+	arg, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("osascript", "-l", "JavaScript", "-e", formScript, string(arg)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to show the dialog: %v", err)
+	}
+	answer := strings.TrimSpace(string(out))
+	if answer == "" {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(answer), &values); err != nil || len(values) != len(fields) {
+		return nil, fmt.Errorf("unexpected answer from the dialog: %q", answer)
+	}
+	return values, nil
+}
@@ -0,0 +1,162 @@
+package dialog
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// There's no standard dialog facility on Linux desktops, so we use whichever
+// of the common helper programs is installed: zenity (GNOME and most other
+// desktops) or kdialog (KDE).
+func info(title, text string) error {
+	if path, err := exec.LookPath("zenity"); err == nil {
+		return exec.Command(path, "--info", "--title", title, "--text", text,
+			"--no-markup").Run()
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		return exec.Command(path, "--title", title, "--msgbox", text).Run()
+	}
+	return ErrNotSupported
+}
+
+func confirm(title, text, ok string) (bool, error) {
+	if path, err := exec.LookPath("zenity"); err == nil {
+		return answered(exec.Command(path, "--question", "--title", title, "--text", text,
+			"--ok-label", ok, "--cancel-label", "Cancel", "--no-markup").Run())
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		return answered(exec.Command(path, "--title", title, "--yes-label", ok,
+			"--no-label", "Cancel", "--warningyesno", text).Run())
+	}
+	return false, ErrNotSupported
+}
+
+func choose(title, text string, choices []string) (int, error) {
+	if path, err := exec.LookPath("zenity"); err == nil {
+		args := []string{"--list", "--title", title, "--text", text,
+			"--column", "Choice", "--hide-header"}
+		out, err := exec.Command(path, append(args, choices...)...).Output()
+		return chosen(out, err, choices)
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		// Each item of the menu is a tag, which kdialog prints when the
+		// item is picked, followed by its label.
+		args := []string{"--title", title, "--menu", text}
+		for _, choice := range choices {
+			args = append(args, choice, choice)
+		}
+		out, err := exec.Command(path, args...).Output()
+		return chosen(out, err, choices)
+	}
+	return -1, ErrNotSupported
+}
+
+func chooseFile(kind int, o FileOptions) (string, error) {
+	start := o.Directory
+	if kind == saveFile && o.Name != "" {
+		start = filepath.Join(start, o.Name)
+	} else if start != "" {
+		start += "/"
+	}
+	patterns := make([]string, len(o.Types))
+	for i, t := range o.Types {
+		patterns[i] = "*." + t
+	}
+	if path, err := exec.LookPath("zenity"); err == nil {
+		args := []string{"--file-selection", "--title", o.Title}
+		switch kind {
+		case saveFile:
+			args = append(args, "--save", "--confirm-overwrite")
+		case chooseFolder:
+			args = append(args, "--directory")
+		}
+		if start != "" {
+			args = append(args, "--filename", start)
+		}
+		if kind == openFile && len(patterns) > 0 {
+			args = append(args, "--file-filter", strings.Join(patterns, " "))
+		}
+		return chosenPath(exec.Command(path, args...).Output())
+	}
+	if path, err := exec.LookPath("kdialog"); err == nil {
+		if start == "" {
+			start = "."
+		}
+		args := []string{"--title", o.Title}
+		switch kind {
+		case openFile:
+			args = append(args, "--getopenfilename", start)
+			if len(patterns) > 0 {
+				args = append(args, strings.Join(patterns, " "))
+			}
+		case saveFile:
+			args = append(args, "--getsavefilename", start)
+		case chooseFolder:
+			args = append(args, "--getexistingdirectory", start)
+		}
+		return chosenPath(exec.Command(path, args...).Output())
+	}
+	return "", ErrNotSupported
+}
+
+// chosenPath interprets the outcome of running a program that prints the
+// path the user chose, or exits with status 1 if the user cancels.
+func chosenPath(out []byte, err error) (string, error) {
+	if ok, err := answered(err); !ok {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// form uses zenity's forms, which kdialog has nothing like.  Zenity can't
+// fill in a form's fields in advance, so the labels show the current values,
+// and fields left empty keep them.  Checkboxes are shown as Yes/No menus.
+func form(title, text string, fields []Field) ([]string, error) {
+	path, err := exec.LookPath("zenity")
+	if err != nil {
+		return nil, ErrNotSupported
+	}
+	if text != "" {
+		text += "\n\n"
+	}
+	text += "Fields left empty keep their current values."
+	args := []string{"--forms", "--title", title, "--text", text, "--separator", "\n"}
+	for _, f := range fields {
+		switch {
+		case f.Check:
+			args = append(args, "--add-combo", f.Label, "--combo-values", "Yes|No")
+		case len(f.Choices) > 0:
+			args = append(args, "--add-combo", f.Label,
+				"--combo-values", strings.Join(f.Choices, "|"))
+		default:
+			label := f.Label
+			if f.Value != "" {
+				label += " (" + f.Value + ")"
+			}
+			args = append(args, "--add-entry", label)
+		}
+	}
+	out, err := exec.Command(path, args...).Output()
+	if ok, err := answered(err); !ok {
+		return nil, err
+	}
+	answers := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(answers) != len(fields) {
+		return nil, fmt.Errorf("unexpected answer from zenity: %q", out)
+	}
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		answer := strings.TrimSpace(answers[i])
+		switch {
+		case answer == "":
+			values[i] = f.Value
+		case f.Check:
+			values[i] = fmt.Sprint(answer == "Yes")
+		default:
+			values[i] = answer
+		}
+	}
+	return values, nil
+}
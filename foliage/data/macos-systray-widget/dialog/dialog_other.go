@@ -0,0 +1,24 @@
+//go:build !darwin && !windows && !linux
+// +build !darwin,!windows,!linux
+
+package dialog
+
+func info(title, text string) error {
+	return ErrNotSupported
+}
+
+func confirm(title, text, ok string) (bool, error) {
+	return false, ErrNotSupported
+}
+
+func choose(title, text string, choices []string) (int, error) {
+	return -1, ErrNotSupported
+}
+
+func chooseFile(kind int, o FileOptions) (string, error) {
+	return "", ErrNotSupported
+}
+
+func form(title, text string, fields []Field) ([]string, error) {
+	return nil, ErrNotSupported
+}
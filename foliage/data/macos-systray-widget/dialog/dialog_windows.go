@@ -0,0 +1,263 @@
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+	"macos-systray-widget/internal/shellquote"
+)
+
+// Message box styles and results, from WinUser.h.
+const (
+	mbOK              = 0x00000000
+	mbOKCancel        = 0x00000001
+	mbIconWarning     = 0x00000030
+	mbIconInformation = 0x00000040
+	mbSetForeground   = 0x00010000
+	mbTopmost         = 0x00040000
+
+	idOK = 1
+)
+
+func info(title, text string) error {
+	_, err := messageBox(title, text, mbOK|mbIconInformation)
+	return err
+}
+
+func confirm(title, text, ok string) (bool, error) {
+	result, err := messageBox(title, text, mbOKCancel|mbIconWarning)
+	return result == idOK, err
+}
+
+// choose shows a small Windows Forms dialog with a list, through
+// PowerShell, since Windows has no standard dialog for choosing from a list.
+// The script prints the index of the choice, or -1 if the user cancels.
+func choose(title, text string, choices []string) (int, error) {
+	quoted := make([]string, len(choices))
+	for i, choice := range choices {
+		quoted[i] = shellquote.PowerShell(choice)
+	}
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$f = New-Object Windows.Forms.Form
+$f.Text = %s
+$f.TopMost = $true
+$f.StartPosition = 'CenterScreen'
+$f.FormBorderStyle = 'FixedDialog'
+$f.MinimizeBox = $false
+$f.MaximizeBox = $false
+$f.AutoSize = $true
+$f.AutoSizeMode = 'GrowAndShrink'
+$p = New-Object Windows.Forms.FlowLayoutPanel
+$p.FlowDirection = 'TopDown'
+$p.AutoSize = $true
+$p.Padding = 8
+$l = New-Object Windows.Forms.Label
+$l.Text = %s
+$l.AutoSize = $true
+$l.MaximumSize = '360,0'
+$b = New-Object Windows.Forms.ListBox
+$b.AccessibleName = $l.Text
+$b.Width = 360
+$b.Items.AddRange(@(%s))
+$b.Height = $b.ItemHeight * ($b.Items.Count + 1)
+$b.SelectedIndex = 0
+$ok = New-Object Windows.Forms.Button
+$ok.Text = 'OK'
+$ok.DialogResult = 'OK'
+$cancel = New-Object Windows.Forms.Button
+$cancel.Text = 'Cancel'
+$cancel.DialogResult = 'Cancel'
+$r = New-Object Windows.Forms.FlowLayoutPanel
+$r.FlowDirection = 'RightToLeft'
+$r.Width = 360
+$r.AutoSize = $true
+$r.Controls.AddRange(@($cancel, $ok))
+$p.Controls.AddRange(@($l, $b, $r))
+$f.Controls.Add($p)
+$f.AcceptButton = $ok
+$f.CancelButton = $cancel
+$b.Add_DoubleClick({ $f.DialogResult = 'OK' })
+if ($f.ShowDialog() -eq 'OK') { $b.SelectedIndex } else { -1 }`,
+		shellquote.PowerShell(title), shellquote.PowerShell(text), strings.Join(quoted, ", "))
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return -1, fmt.Errorf("unable to show the dialog: %v", err)
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil || i >= len(choices) {
+		return -1, fmt.Errorf("unexpected answer from the dialog: %q", out)
+	}
+	return i, nil
+}
+
+// chooseFile shows the Windows Forms file and folder dialogs, through
+// PowerShell.  The dialogs are given a hidden topmost window as their owner,
+// so that they don't open behind whatever the user is doing.  The script
+// prints the path chosen, or nothing if the user cancels.
+func chooseFile(kind int, o FileOptions) (string, error) {
+	var setup string
+	switch kind {
+	case chooseFolder:
+		setup = fmt.Sprintf(`$d = New-Object Windows.Forms.FolderBrowserDialog
+$d.Description = %s
+$d.ShowNewFolderButton = $true
+if (%s) { $d.SelectedPath = %s }`, shellquote.PowerShell(o.Title),
+			shellquote.PowerShell(o.Directory), shellquote.PowerShell(o.Directory))
+	default:
+		class := "OpenFileDialog"
+		if kind == saveFile {
+			class = "SaveFileDialog"
+		}
+		filter := "All files (*.*)|*.*"
+		if len(o.Types) > 0 {
+			patterns := make([]string, len(o.Types))
+			for i, t := range o.Types {
+				patterns[i] = "*." + t
+			}
+			list := strings.Join(patterns, ";")
+			filter = "Files (" + list + ")|" + list + "|" + filter
+		}
+		setup = fmt.Sprintf(`$d = New-Object Windows.Forms.%s
+$d.Title = %s
+$d.Filter = %s
+$d.FileName = %s
+if (%s) { $d.InitialDirectory = %s }`, class, shellquote.PowerShell(o.Title),
+			shellquote.PowerShell(filter), shellquote.PowerShell(o.Name),
+			shellquote.PowerShell(o.Directory), shellquote.PowerShell(o.Directory))
+	}
+	script := `Add-Type -AssemblyName System.Windows.Forms
+[Console]::OutputEncoding = New-Object Text.UTF8Encoding $false
+$o = New-Object Windows.Forms.Form
+$o.TopMost = $true
+` + setup + `
+if ($d.ShowDialog($o) -eq 'OK') { if ($d -is [Windows.Forms.FolderBrowserDialog]) { $d.SelectedPath } else { $d.FileName } }`
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-STA", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to show the dialog: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// form shows a Windows Forms dialog with a table of labels and fields,
+// through PowerShell.  The labels are separate controls, so each field is
+// also given its label as its accessible name, which Narrator and other UI
+// Automation clients read.  The script prints the values as a JSON list, or
+// nothing if the user cancels.
+func form(title, text string, fields []Field) ([]string, error) {
+	var controls strings.Builder
+	for _, f := range fields {
+		switch {
+		case f.Check:
+			controls.WriteString("$c = New-Object Windows.Forms.CheckBox\n")
+			fmt.Fprintf(&controls, "$c.Checked = $%v\n", f.Value == "true")
+		case len(f.Choices) > 0:
+			quoted := make([]string, len(f.Choices))
+			for i, choice := range f.Choices {
+				quoted[i] = shellquote.PowerShell(choice)
+			}
+			controls.WriteString("$c = New-Object Windows.Forms.ComboBox\n$c.DropDownStyle = 'DropDownList'\n$c.Width = 240\n")
+			fmt.Fprintf(&controls, "$c.Items.AddRange(@(%s))\n$c.SelectedItem = %s\n",
+				strings.Join(quoted, ", "), shellquote.PowerShell(f.Value))
+		default:
+			controls.WriteString("$c = New-Object Windows.Forms.TextBox\n$c.Width = 240\n")
+			fmt.Fprintf(&controls, "$c.Text = %s\n", shellquote.PowerShell(f.Value))
+		}
+		fmt.Fprintf(&controls, "Add-Field %s $c\n", shellquote.PowerShell(f.Label))
+	}
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+[Console]::OutputEncoding = New-Object Text.UTF8Encoding $false
+$f = New-Object Windows.Forms.Form
+$f.Text = %s
+$f.TopMost = $true
+$f.StartPosition = 'CenterScreen'
+$f.FormBorderStyle = 'FixedDialog'
+$f.MinimizeBox = $false
+$f.MaximizeBox = $false
+$f.AutoSize = $true
+$f.AutoSizeMode = 'GrowAndShrink'
+$p = New-Object Windows.Forms.FlowLayoutPanel
+$p.FlowDirection = 'TopDown'
+$p.AutoSize = $true
+$p.Padding = 8
+$l = New-Object Windows.Forms.Label
+$l.Text = %s
+$l.AutoSize = $true
+$l.MaximumSize = '400,0'
+$t = New-Object Windows.Forms.TableLayoutPanel
+$t.ColumnCount = 2
+$t.AutoSize = $true
+$script:controls = @()
+function Add-Field($label, $control) {
+	$n = New-Object Windows.Forms.Label
+	$n.Text = $label + ':'
+	$control.AccessibleName = $label
+	$n.AutoSize = $true
+	$n.Anchor = 'Right'
+	$t.Controls.Add($n)
+	$t.Controls.Add($control)
+	$script:controls += $control
+}
+%s$ok = New-Object Windows.Forms.Button
+$ok.Text = 'OK'
+$ok.DialogResult = 'OK'
+$cancel = New-Object Windows.Forms.Button
+$cancel.Text = 'Cancel'
+$cancel.DialogResult = 'Cancel'
+$r = New-Object Windows.Forms.FlowLayoutPanel
+$r.FlowDirection = 'RightToLeft'
+$r.Width = 400
+$r.AutoSize = $true
+$r.Controls.AddRange(@($cancel, $ok))
+$p.Controls.AddRange(@($l, $t, $r))
+$f.Controls.Add($p)
+$f.AcceptButton = $ok
+$f.CancelButton = $cancel
+if ($f.ShowDialog() -eq 'OK') {
+	$values = foreach ($c in $script:controls) {
+		if ($c -is [Windows.Forms.CheckBox]) { if ($c.Checked) { 'true' } else { 'false' } } else { [string]$c.Text }
+	}
+	ConvertTo-Json -InputObject @($values) -Compress
+}`, shellquote.PowerShell(title), shellquote.PowerShell(text), controls.String())
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to show the dialog: %v", err)
+	}
+	answer := strings.TrimSpace(string(out))
+	if answer == "" {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal([]byte(answer), &values); err != nil || len(values) != len(fields) {
+		return nil, fmt.Errorf("unexpected answer from the dialog: %q", answer)
+	}
+	return values, nil
+}
+
+func messageBox(title, text string, style uint32) (int32, error) {
+	t, err := windows.UTF16PtrFromString(text)
+	if err != nil {
+		return 0, err
+	}
+	c, err := windows.UTF16PtrFromString(title)
+	if err != nil {
+		return 0, err
+	}
+	// The widget has no window of its own, so the message box is made
+	// topmost; otherwise it can open behind whatever the user is doing.
+	result, err := windows.MessageBox(0, t, c, style|mbSetForeground|mbTopmost)
+	if result != 0 {
+		err = nil
+	}
+	return result, err
+}
@@ -0,0 +1,36 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package dialog
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// answered interprets the outcome of running a dialog program that exits
+// with status 1 if the user declines.
+func answered(err error) (bool, error) {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// chosen interprets the outcome of running a dialog program that prints the
+// choice the user picked, and exits with status 1 (or prints something
+// else) if the user cancels.
+func chosen(out []byte, err error, choices []string) (int, error) {
+	if ok, err := answered(err); !ok {
+		return -1, err
+	}
+	answer := strings.TrimSpace(string(out))
+	for i, choice := range choices {
+		if choice == answer {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
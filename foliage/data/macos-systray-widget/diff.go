@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"macos-systray-widget/backup"
+	"macos-systray-widget/foliolib"
+)
+
+// runDiff runs the diff subcommand, which compares two backups of a record
+// (see package backup), or a backup with the record as it is in FOLIO now,
+// and says how they differ, field by field.  Each is given as a backup file,
+// or as the record's id or backup folder, meaning its newest backup.  With
+// --json, the differences are printed as JSON, with their JSONPaths, for
+// scripts.  As with diff(1), it exits with status 0 if there are no
+// differences, 1 if there are and 2 if it couldn't tell.
+func runDiff(args []string) int {
+	fs := subcommandFlags("diff")
+	asJSON := fs.Bool("json", false, "print the differences as JSON")
+	all := fs.Bool("all", false, "include the fields FOLIO keeps up to date itself (_version and metadata)")
+	kind := fs.String("kind", "", "the kind of record, if it can't be told from the backup: instance, holdings, item, loan or user")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+	dir, err := backup.Dir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	oldPath, err := snapshotPath(dir, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	old, err := backup.Read(oldPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var newPath string
+	var new foliolib.Record
+	if fs.NArg() == 2 {
+		if newPath, err = snapshotPath(dir, fs.Arg(1)); err == nil {
+			new, err = backup.Read(newPath)
+		}
+	} else {
+		newPath = "FOLIO"
+		new, err = liveRecord(old, foliolib.Kind(*kind))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	var skip []string
+	if !*all {
+		skip = backup.Bookkeeping
+	}
+	diffs := backup.Diff(old, new, skip...)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if diffs == nil {
+			diffs = []backup.Difference{}
+		}
+		enc.Encode(struct {
+			ID          string              `json:"id"`
+			Old         string              `json:"old"`
+			New         string              `json:"new"`
+			Differences []backup.Difference `json:"differences"`
+		}{old.ID(), oldPath, newPath, diffs})
+	} else {
+		fmt.Printf("--- %s\n+++ %s\n", oldPath, newPath)
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+		switch len(diffs) {
+		case 0:
+			fmt.Println("no differences")
+		case 1:
+			fmt.Println("1 difference")
+		default:
+			fmt.Printf("%d differences\n", len(diffs))
+		}
+	}
+	if len(diffs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// snapshotPath returns the path of the backup that arg names: a backup
+// file, or else the newest backup of the record whose id or backup folder
+// it is.
+func snapshotPath(dir, arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		return arg, nil
+	}
+	id := filepath.Base(filepath.Clean(arg))
+	list, err := backup.List(dir, id)
+	if os.IsNotExist(err) || (err == nil && len(list) == 0) {
+		return "", fmt.Errorf("%s is neither a backup file nor the id of a record with backups in %s", arg, dir)
+	} else if err != nil {
+		return "", err
+	}
+	return list[len(list)-1].Path, nil
+}
+
+// liveRecord gets the record backed up as r from FOLIO, guessing its kind
+// if it isn't given.
+func liveRecord(r foliolib.Record, kind foliolib.Kind) (foliolib.Record, error) {
+	if kind == "" {
+		var ok bool
+		if kind, ok = backup.KindOf(r); !ok {
+			return nil, fmt.Errorf("unable to tell what kind of record %s is; give it with --kind", r.ID())
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	c, err := folioClient(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Record(ctx, kind, r.ID())
+}
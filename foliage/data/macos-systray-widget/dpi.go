@@ -0,0 +1,8 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+// setDPIAware does nothing except on Windows, where programs have to say
+// that they draw at the display's scale.  (See dpi_windows.go.)
+func setDPIAware() {}
@@ -0,0 +1,27 @@
+package main
+
+import "syscall"
+
+var (
+	user32                        = syscall.NewLazyDLL("user32.dll")
+	setProcessDpiAwarenessContext = user32.NewProc("SetProcessDpiAwarenessContext")
+	setProcessDPIAware            = user32.NewProc("SetProcessDPIAware")
+)
+
+// DPI_AWARENESS_CONTEXT_PER_MONITOR_AWARE_V2, which is the handle -4.
+const dpiPerMonitorAwareV2 = ^uintptr(3)
+
+// setDPIAware tells Windows that the widget draws at each display's own
+// scale, so that the tray gets the icon at the size the display needs.
+// Otherwise Windows hands the widget the sizes of a display at 100%, and
+// stretches the icon it gets, which is blurry at higher scales, such as on
+// 4K displays.  Windows 10 since version 1703 can do this for each display;
+// older versions only for the main one.
+func setDPIAware() {
+	if setProcessDpiAwarenessContext.Find() == nil {
+		if r, _, _ := setProcessDpiAwarenessContext.Call(dpiPerMonitorAwareV2); r != 0 {
+			return
+		}
+	}
+	setProcessDPIAware.Call()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/droptarget"
+	"macos-systray-widget/health"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// The operations offered for a dropped file, with the names Foliage's batch
+// endpoint knows them by.
+var dropOperations = []struct{ label, name string }{
+	{"Look up the records", "lookup"},
+	{"Change the records", "change"},
+	{"Delete the records", "delete"},
+}
+
+// The types of file Foliage can read identifiers from.
+var dropTypes = map[string]bool{".csv": true, ".txt": true, ".xlsx": true}
+
+// Serializes the handling of dropped files, so that dropping several files
+// asks about one at a time.
+var dropLock sync.Mutex
+
+// knownOperation reports whether Foliage's batch endpoint knows the
+// operation.
+func knownOperation(name string) bool {
+	for _, op := range dropOperations {
+		if op.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dropFiles hands each of the files to dropFile in turn, with the operation
+// given by --job, if any.
+func dropFiles(paths []string, op string) {
+	for _, path := range paths {
+		dropFile(path, op)
+	}
+}
+
+// dropFile asks the user what to do with the records listed in the file,
+// unless op says, and asks Foliage to start that batch job, starting Foliage
+// first if it isn't running and we know how.  Foliage reads the file itself,
+// so the list never has to be pasted into the browser.
+func dropFile(path, op string) {
+	dropLock.Lock()
+	defer dropLock.Unlock()
+	name := filepath.Base(path)
+	if !dropTypes[strings.ToLower(filepath.Ext(path))] {
+		notify.Post(notify.Notification{Message: locale.Sprintf("Foliage can only read identifiers from"+
+			" .csv, .txt and .xlsx files, not %s.", name)})
+		return
+	}
+	if op == "" {
+		labels := make([]string, len(dropOperations))
+		for i, op := range dropOperations {
+			labels[i] = locale.T(op.label)
+		}
+		i, err := dialog.Choose("Foliage",
+			locale.Sprintf("What should Foliage do with the records listed in %s?", name), labels)
+		if err != nil {
+			log.Printf("unable to ask what to do with %s: %v", path, err)
+			notify.Post(notify.Notification{Message: locale.Sprintf("Unable to ask what to do with %s: %v", name, err)})
+			return
+		}
+		if i < 0 {
+			debugf("nothing to do with %s", path)
+			return
+		}
+		op = dropOperations[i].name
+	}
+	if op == "delete" && !confirmDelete(name) {
+		debugf("deletion of the records in %s not confirmed", path)
+		return
+	}
+	if !health.NewChecker(serverURL()).Check() && foliageCommand != "" {
+		startFoliage(false)
+	}
+	if err := jobs.Batch(serverURL(), controlToken(), path, op, op == "delete"); err != nil {
+		log.Printf("unable to start a %s job on %s: %v", op, path, err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Foliage can't work on %s: %v", name, err)})
+		return
+	}
+	bringToFront()
+}
+
+// confirmDelete asks the user to confirm deleting the records listed in the
+// file with the given name, with the warning the Foliage page gives, since
+// Foliage doesn't ask again for deletions the widget asks for.
+func confirmDelete(name string) bool {
+	ok, err := dialog.Confirm("Foliage", locale.Sprintf("Delete the records listed in %s? If the"+
+		" deletions include holdings and/or instance records, all their associated items and"+
+		" holdings will also be deleted. Only do this if you have verified the implications"+
+		" first.", name), locale.T("Delete"))
+	if err != nil {
+		log.Printf("unable to ask for confirmation: %v", err)
+	}
+	return ok
+}
+
+// absDropFiles makes the paths of the files given with --drop or --job
+// absolute, both in o and in os.Args, in the copy of the widget that was
+// given them.  If another copy is running, it is handed os.Args, and its
+// working directory may be different.  The files are the last arguments.
+func absDropFiles(o *options) {
+	n := len(os.Args) - len(o.dropFiles)
+	for i, path := range o.dropFiles {
+		if abs, err := filepath.Abs(path); err == nil {
+			o.dropFiles[i] = abs
+			os.Args[n+i] = abs
+		}
+	}
+}
+
+// runDropTargetCommand carries out --install-drop-target or
+// --uninstall-drop-target and returns the exit status.
+func runDropTargetCommand(install bool) int {
+	var err error
+	if install {
+		var exe, path string
+		if exe, err = os.Executable(); err == nil {
+			if path, err = droptarget.Install(exe); err == nil {
+				fmt.Printf("Files of identifiers dropped on %s now go to Foliage.\n", path)
+			}
+		}
+	} else if err = droptarget.Uninstall(); err == nil {
+		fmt.Println("Removed the drop target.")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,25 @@
+// Package droptarget gives Foliage a place on the desktop where files of
+// identifiers can be dropped to start a batch job.  The tray icon itself
+// can't take drops on most systems, so each system gets what its users are
+// used to dropping files on: on macOS, a small droplet application that can
+// be kept in the Dock; on Windows, a "Send to" entry (its shortcut can also
+// be copied to the desktop); and on Linux, a desktop entry, whose launcher
+// file managers and docks accept drops on.  Each runs the widget with the
+// option --drop followed by the files.
+package droptarget
+
+import "errors"
+
+// ErrNotSupported is returned on systems where no drop target can be made.
+var ErrNotSupported = errors.New("drop targets are not supported on this system")
+
+// Install makes the drop target for the program exe, replacing any made
+// earlier, and returns its path.
+func Install(exe string) (string, error) {
+	return install(exe)
+}
+
+// Uninstall removes the drop target, if there is one.
+func Uninstall() error {
+	return uninstall()
+}
@@ -0,0 +1,92 @@
+package droptarget
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// lsregister tells Launch Services about the bundle, so that Finder and the
+// Dock know at once which files it accepts.
+const lsregister = "/System/Library/Frameworks/CoreServices.framework/Frameworks/" +
+	"LaunchServices.framework/Support/lsregister"
+
+// The types of file the droplet accepts: the ones Foliage can read.
+const documentTypes = `[{"CFBundleTypeName": "Identifier list", "CFBundleTypeRole": "Viewer",
+"LSItemContentTypes": ["public.comma-separated-values-text", "public.plain-text",
+"org.openxmlformats.spreadsheetml.sheet"]}]`
+
+// appPath returns the path of the droplet, in the user's own Applications
+// folder.
+func appPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Applications", "Foliage Droplet.app"), nil
+}
+
+// install makes an AppleScript droplet: Finder and the Dock hand files
+// dropped on an AppleScript application to its "open" handler, which runs
+// exe with them.  Opening the droplet by itself explains what it is for.
+func install(exe string) (string, error) {
+	app, err := appPath()
+	if err != nil {
+		return "", err
+	}
+	script := fmt.Sprintf(`on open theFiles
+	set args to ""
+	repeat with f in theFiles
+		set args to args & " " & quoted form of POSIX path of f
+	end repeat
+	do shell script quoted form of %s & " --drop" & args & " > /dev/null 2>&1 &"
+end open
+
+on run
+	display dialog "Drop a file of identifiers (.csv, .txt or .xlsx) on this icon to look up, change or delete the records in Foliage." with title "Foliage" buttons {"OK"} default button "OK"
+end run
+`, shellquote.AppleScript(exe))
+	if err := os.MkdirAll(filepath.Dir(app), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(app); err != nil {
+		return "", err
+	}
+	plist := filepath.Join(app, "Contents", "Info.plist")
+	for _, args := range [][]string{
+		{"osacompile", "-o", app, "-e", script},
+		{"plutil", "-replace", "CFBundleIdentifier", "-string", "org.caltechlibrary.foliage.droplet", plist},
+		{"plutil", "-replace", "CFBundleDocumentTypes", "-json", documentTypes, plist},
+		{lsregister, "-f", app},
+	} {
+		if err := run(args...); err != nil {
+			return "", err
+		}
+	}
+	return app, nil
+}
+
+func uninstall() error {
+	app, err := appPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(app); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	run(lsregister, "-u", app)
+	return os.RemoveAll(app)
+}
+
+func run(args ...string) error {
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v: %s", filepath.Base(args[0]), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package droptarget
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// shortcutPath returns the path of the shortcut in the user's SendTo
+// folder, whose entries make up the "Send to" menu of File Explorer.
+func shortcutPath() (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("APPDATA is not set")
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "SendTo", "Foliage.lnk"), nil
+}
+
+// install creates the shortcut.  Windows appends the files sent to it (or
+// dropped on it) to the shortcut's arguments.  As in the autostart package,
+// the shortcut is made through the Windows shell's COM interface, using
+// PowerShell.
+func install(exe string) (string, error) {
+	path, err := shortcutPath()
+	if err != nil {
+		return "", err
+	}
+	script := fmt.Sprintf(`$s = (New-Object -ComObject WScript.Shell).CreateShortcut(%s)
+$s.TargetPath = %s
+$s.Arguments = '--drop'
+$s.Description = 'Look up, change or delete the records listed in a file, in Foliage'
+$s.WorkingDirectory = %s
+$s.Save()`, shellquote.PowerShell(path), shellquote.PowerShell(exe),
+		shellquote.PowerShell(filepath.Dir(exe)))
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unable to create %s: %v: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return path, nil
+}
+
+func uninstall() error {
+	path, err := shortcutPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
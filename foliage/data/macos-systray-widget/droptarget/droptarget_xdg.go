@@ -0,0 +1,65 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package droptarget
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"macos-systray-widget/internal/shellquote"
+	"macos-systray-widget/internal/xdg"
+)
+
+// The name of the desktop entry's file in the applications directory.
+const desktopFile = "foliage-drop.desktop"
+
+// desktopPath returns the path of the desktop entry in the user's
+// applications directory.
+func desktopPath() (string, error) {
+	dir, err := xdg.DataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "applications", desktopFile), nil
+}
+
+// install writes a desktop entry that takes files of the types Foliage can
+// read.  Unlike the entry for foliage:// links, it is shown in the
+// applications menu, so that users can add it to their dock or desktop, and
+// it is not made the default for those types.
+func install(exe string) (string, error) {
+	path, err := desktopPath()
+	if err != nil {
+		return "", err
+	}
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Foliage Batch Job
+Comment=Look up, change or delete the records listed in a file, in Foliage
+Exec=%s --drop %%F
+MimeType=text/csv;text/plain;application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;
+Categories=Office;
+Terminal=false
+`, shellquote.DesktopExec(exe))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func uninstall() error {
+	path, err := desktopPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
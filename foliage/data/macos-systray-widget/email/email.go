@@ -0,0 +1,280 @@
+// Package email sends email through an SMTP server, such as the summary of
+// a batch job that ran overnight, with its results attached, for the person
+// who started it to find in the morning.  The server is given by settings
+// (see FromSettings), and is reached with STARTTLS (the default, usually on
+// port 587), TLS from the start (usually on port 465), or, for a relay on
+// the same machine or network, neither.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"macos-systray-widget/config"
+)
+
+// How long to wait for the server.
+const timeout = 30 * time.Second
+
+// The ways of securing the connection to the server.
+const (
+	StartTLS = "starttls"
+	TLS      = "tls"
+	None     = "none"
+)
+
+// Server is an SMTP server to send mail through.
+type Server struct {
+	Host     string
+	Port     int
+	User     string // For logging in, if the server needs it.
+	Password string
+	From     string // The sender's address, such as "Foliage <foliage@example.edu>".
+	Security string // StartTLS, TLS or None.
+}
+
+// FromSettings returns the server in the settings FOLIAGE_SMTP_HOST,
+// FOLIAGE_SMTP_PORT (by default, 465 for TLS and 587 otherwise),
+// FOLIAGE_SMTP_USER, FOLIAGE_SMTP_PASSWORD, FOLIAGE_SMTP_FROM (by default,
+// the user) and FOLIAGE_SMTP_SECURITY (starttls, the default, tls or none).
+func FromSettings() (Server, error) {
+	s := Server{
+		Host:     config.Get("FOLIAGE_SMTP_HOST", ""),
+		User:     config.Get("FOLIAGE_SMTP_USER", ""),
+		Password: config.Get("FOLIAGE_SMTP_PASSWORD", ""),
+		Security: strings.ToLower(config.Get("FOLIAGE_SMTP_SECURITY", StartTLS)),
+	}
+	s.From = config.Get("FOLIAGE_SMTP_FROM", s.User)
+	if s.Host == "" {
+		return s, errors.New("no SMTP server: set FOLIAGE_SMTP_HOST")
+	}
+	switch s.Security {
+	case StartTLS, None:
+		s.Port = 587
+	case TLS:
+		s.Port = 465
+	default:
+		return s, fmt.Errorf("unknown SMTP security %q; it can be starttls, tls or none", s.Security)
+	}
+	if port := config.Get("FOLIAGE_SMTP_PORT", ""); port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n <= 0 || n > 65535 {
+			return s, fmt.Errorf("FOLIAGE_SMTP_PORT %q isn't a port", port)
+		}
+		s.Port = n
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return s, fmt.Errorf("no sender's address: set FOLIAGE_SMTP_FROM (%v)", err)
+	}
+	return s, nil
+}
+
+// Message is an email message.
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string // Plain text.
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	Name string
+	Type string // The MIME type, such as "text/csv".
+	Data []byte
+}
+
+// Attach returns the file at path as an attachment, with the type its name
+// says.
+func Attach(path string) (Attachment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	t, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(path)))
+	switch {
+	case err == nil:
+	case strings.EqualFold(filepath.Ext(path), ".csv"):
+		t = "text/csv"
+	default:
+		t = "application/octet-stream"
+	}
+	return Attachment{Name: filepath.Base(path), Type: t, Data: data}, nil
+}
+
+// Send sends the message through the server.
+func (s Server) Send(ctx context.Context, m Message) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("the sender's address %q: %w", s.From, err)
+	}
+	var to []string
+	for _, a := range m.To {
+		addr, err := mail.ParseAddress(a)
+		if err != nil {
+			return fmt.Errorf("the address %q: %w", a, err)
+		}
+		to = append(to, addr.Address)
+	}
+	if len(to) == 0 {
+		return errors.New("the message has no one to send it to")
+	}
+	data, err := compose(from, m)
+	if err != nil {
+		return err
+	}
+	c, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to reach the SMTP server %s: %w", s.Host, err)
+	}
+	defer c.Close()
+	if s.User != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.User, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("the SMTP server %s didn't accept the login: %w", s.Host, err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("the SMTP server %s refused the sender: %w", s.Host, err)
+	}
+	for _, a := range to {
+		if err := c.Rcpt(a); err != nil {
+			return fmt.Errorf("the SMTP server %s refused %s: %w", s.Host, a, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("the SMTP server %s didn't take the message: %w", s.Host, err)
+	}
+	return c.Quit()
+}
+
+// dial connects to the server, securing the connection as the server says.
+func (s Server) dial(ctx context.Context) (*smtp.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	tlsConfig := &tls.Config{ServerName: s.Host}
+	if s.Security == TLS {
+		tc := tls.Client(conn, tlsConfig)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tc
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.Security == StartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// compose returns the message in the form it is sent in: plain text, or, if
+// there are attachments, a multipart message with the text first.
+func compose(from *mail.Address, m Message) ([]byte, error) {
+	var b bytes.Buffer
+	header := func(name, value string) { fmt.Fprintf(&b, "%s: %s\r\n", name, value) }
+	header("From", from.String())
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	body := text(m.Body)
+	if len(m.Attachments) == 0 {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		b.Write(body)
+		return b.Bytes(), nil
+	}
+	w := multipart.NewWriter(&b)
+	header("Content-Type", "multipart/mixed; boundary="+w.Boundary())
+	b.WriteString("\r\n")
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	part.Write(body)
+	for _, a := range m.Attachments {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.Type, map[string]string{"name": a.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// text returns the body in quoted-printable form, which any server takes,
+// with the lines ending in CRLF.
+func text(body string) []byte {
+	var b bytes.Buffer
+	w := quotedprintable.NewWriter(&b)
+	w.Write([]byte(strings.TrimRight(body, "\r\n") + "\n"))
+	w.Close()
+	return b.Bytes()
+}
+
+// messageID returns a new Message-ID, at the sender's domain.
+func messageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndexByte(from, '@'); i >= 0 {
+		domain = from[i+1:]
+	}
+	var r [12]byte
+	rand.Read(r[:])
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().Unix(), hex.EncodeToString(r[:]), domain)
+}
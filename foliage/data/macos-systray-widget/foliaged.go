@@ -0,0 +1,550 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/audit"
+	"macos-systray-widget/backup"
+	"macos-systray-widget/batch"
+	"macos-systray-widget/config"
+	"macos-systray-widget/email"
+	"macos-systray-widget/foliolib"
+	"macos-systray-widget/webhook"
+)
+
+// The keyring service under which foliaged keeps its FOLIO tokens, and how
+// often, by default, it looks for new jobs in its spool directory.
+const (
+	foliagedKeyring  = "org.caltechlibrary.foliage.foliaged"
+	foliagedInterval = 30 * time.Second
+)
+
+// runFoliaged runs foliaged, Foliage's batch daemon, which does Foliage's
+// batch operations directly against FOLIO, without Foliage or a browser,
+// for large jobs run from a server (see package batch).  It is the
+// subcommand "foliaged", and what the program does when it is named
+// foliaged.  "foliaged run JOB" runs the job in the job file JOB and exits;
+// "foliaged serve" runs the job files that appear in its spool directory,
+// one after another, until it is stopped.  Interrupting it, or SIGTERM,
+// stops the job that is running.  Each job that runs is given an id and a
+// journal (see batch.Journal), so that "foliaged resume ID" can go on with
+// it after it was stopped, or crashed; "foliaged jobs" lists them.  "foliaged
+// run --dry-run JOB" reports what the job would do, without doing it.
+// Every change it makes to FOLIO is kept in its audit log, which "foliaged
+// audit" exports or verifies.
+func runFoliaged(args []string) int {
+	usage := func() int {
+		name := foliagedName()
+		fmt.Fprintf(os.Stderr, "usage: %s run [--dry-run] [--workers N] JOB\n       %s resume [--workers N] ID\n       %s jobs\n"+
+			"       %s serve [--spool DIR] [--interval SECONDS] [--workers N] [--metrics ADDR]\n"+
+			"       %s validate [--folio] [--output FILE] JOB\n"+
+			"       %s audit export|verify ...\n       %s cache [clear [--stale]]\n       %s locks [list | serve [--listen ADDR]]\n",
+			name, name, name, name, name, name, name, name)
+		return 2
+	}
+	if len(args) == 0 {
+		return usage()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	switch args[0] {
+	case "run", "resume":
+		fs := subcommandFlags("foliaged")
+		dryRun := fs.Bool("dry-run", false, "report what the job would do, without changing anything in FOLIO")
+		workers := fs.Int("workers", settingInt("FOLIAGE_BATCH_WORKERS", batch.DefaultWorkers), "how many records to work on at once")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 1 || (*dryRun && args[0] == "resume") {
+			return usage()
+		}
+		if *dryRun {
+			return dryRunBatchJob(ctx, fs.Arg(0), *workers)
+		}
+		journal, err := batchJournal(args[0], fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		summary, err := runBatchJob(ctx, journal.Job, journal, *workers, true)
+		if summary.Stopped {
+			fmt.Fprintf(os.Stderr, "to go on with the job: %s resume %s\n", foliagedName(), journal.ID)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(summary)
+		if summary.Stopped || summary.Failed > 0 {
+			return 1
+		}
+		return 0
+	case "jobs":
+		if len(args) != 1 {
+			return usage()
+		}
+		if err := listBatchJobs(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	case "serve":
+		fs := subcommandFlags("foliaged")
+		spool := fs.String("spool", config.Get("FOLIAGE_BATCH_SPOOL", ""), "the directory to take job files from")
+		interval := fs.Int("interval", int(foliagedInterval/time.Second), "how often to look for jobs, in seconds")
+		workers := fs.Int("workers", settingInt("FOLIAGE_BATCH_WORKERS", batch.DefaultWorkers), "how many records to work on at once")
+		metricsAddr := fs.String("metrics", config.Get("FOLIAGE_METRICS_ADDR", ""), "the address to serve Prometheus metrics at, such as :9464")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if *metricsAddr != "" {
+			foliagedMetrics = newBatchMetrics()
+			if err := foliagedMetrics.serve(ctx, *metricsAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "unable to serve metrics: %v\n", err)
+				return 1
+			}
+		}
+		if *spool == "" {
+			dir, err := appdirs.UserDataDir()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			*spool = filepath.Join(dir, "foliaged", "spool")
+		}
+		if err := serveBatchJobs(ctx, *spool, time.Duration(*interval)*time.Second, *workers); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	case "audit":
+		return runAudit(args[1:])
+	case "cache":
+		return runCache(args[1:])
+	case "validate":
+		return runValidate(args[1:])
+	case "locks":
+		return runLocks(args[1:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return 0
+	}
+	return usage()
+}
+
+// foliagedName returns the name to give foliaged in messages.
+func foliagedName() string {
+	if name := programName(); name != "foliaged" {
+		return name + " foliaged"
+	}
+	return "foliaged"
+}
+
+// journalDir returns the directory that holds the journals of jobs: the
+// setting FOLIAGE_BATCH_JOURNAL, or else foliaged/journal in Foliage's data
+// directory.
+func journalDir() (string, error) {
+	if dir := config.Get("FOLIAGE_BATCH_JOURNAL", ""); dir != "" {
+		return dir, nil
+	}
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "foliaged", "journal"), nil
+}
+
+// batchJournal returns the journal for "run" of the job file arg, which is
+// a new one, or for "resume" of the job with the id arg.
+func batchJournal(command, arg string) (*batch.Journal, error) {
+	root, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	if command == "resume" {
+		return batch.OpenJournal(root, arg)
+	}
+	job, err := batch.LoadJob(arg)
+	if err != nil {
+		return nil, err
+	}
+	return batch.CreateJournal(root, arg, job)
+}
+
+// runBatchJob runs the job with the journal, working on as many records at
+// once as workers, logging its progress every so often, or, if verbose,
+// printing the result of each record too.  Dry runs have no journal, since
+// there is no harm in doing them again.  The job's webhooks are sent a
+// summary when it is over.
+func runBatchJob(ctx context.Context, job *batch.Job, journal *batch.Journal, workers int, verbose bool) (batch.Summary, error) {
+	id := job.Name
+	if journal != nil {
+		id = journal.ID
+	}
+	foliagedMetrics.started()
+	summary, err := doBatchJob(ctx, job, journal, workers, verbose)
+	foliagedMetrics.finished(summary, err)
+	announceBatchJob(job, id, summary, err)
+	return summary, err
+}
+
+// doBatchJob is runBatchJob, without the webhooks.
+func doBatchJob(ctx context.Context, job *batch.Job, journal *batch.Journal, workers int, verbose bool) (batch.Summary, error) {
+	id, verb, where := job.Name, "running", "results"
+	switch {
+	case journal != nil:
+		id = journal.ID
+		if journal.Resumed() {
+			verb = "resuming"
+		}
+	case job.DryRun:
+		verb, where = "dry-running", "a report"
+	}
+	c, err := batchClient(ctx)
+	var backups string
+	if err == nil {
+		backups, err = backup.Dir()
+	}
+	if err != nil {
+		if journal != nil {
+			journal.Finish(batch.Summary{Job: job.Name, Stopped: true})
+		}
+		return batch.Summary{}, err
+	}
+	log.Printf("%s job %s (%s %s records), with %s in %s", verb, id, job.Operation, job.Kind, where, job.OutputPath())
+	ctx = audit.WithJob(ctx, id)
+	last := time.Now()
+	opts := batch.Options{
+		Journal: journal,
+		Workers: workers,
+		Verify:  config.Bool("FOLIAGE_VERIFY_WRITES", false),
+		Backup: func(r foliolib.Record) error {
+			_, err := backup.Write(backups, r)
+			return err
+		},
+		Progress: func(r batch.Result, done, total int) {
+			foliagedMetrics.record(job, r)
+			if verbose {
+				fmt.Printf("%d/%d %s %s %s\n", done, total, r.Identifier, r.Status, r.Message)
+			} else if time.Since(last) > time.Minute || done == total {
+				log.Printf("job %s: %d of %d done", id, done, total)
+				last = time.Now()
+			}
+		},
+	}
+	release := lockRecords(ctx, job, id, &opts)
+	defer release()
+	return batch.Run(ctx, c, job, opts)
+}
+
+// The largest results file that is attached to email.
+const maxAttachment = 10 << 20
+
+// announceBatchJob sends the summary of the job with the id (or the error
+// that stopped it from running) to its webhooks and email addresses.
+func announceBatchJob(job *batch.Job, id string, s batch.Summary, err error) {
+	if len(job.Webhooks) == 0 && len(job.Email) == 0 {
+		return
+	}
+	m, results := batchJobMessage(job, id, s, err)
+	for _, hook := range job.Webhooks {
+		if err := webhook.Post(context.Background(), hook, m); err != nil {
+			log.Printf("unable to announce job %s: %v", id, err)
+		}
+	}
+	if len(job.Email) == 0 {
+		return
+	}
+	server, serr := email.FromSettings()
+	if serr != nil {
+		log.Printf("unable to email the summary of job %s: %v", id, serr)
+		return
+	}
+	mail := email.Message{To: job.Email, Subject: m.Title}
+	if info, serr := os.Stat(results); err == nil && serr == nil && info.Size() > maxAttachment {
+		m.Text += fmt.Sprintf("\n\nThe results, %d MB, are too large to attach.", info.Size()>>20)
+	} else if a, aerr := email.Attach(results); err == nil && aerr == nil {
+		mail.Attachments = []email.Attachment{a}
+	}
+	mail.Body = m.String()
+	if err := server.Send(context.Background(), mail); err != nil {
+		log.Printf("unable to email the summary of job %s: %v", id, err)
+	}
+}
+
+// batchJobMessage returns the summary of the job, for announcing it, and
+// the path of its results, or its report, for a dry run.  The summary links
+// to the results if the setting FOLIAGE_BATCH_RESULTS_URL says where the
+// results files can be found on the web.
+func batchJobMessage(job *batch.Job, id string, s batch.Summary, err error) (webhook.Message, string) {
+	m := webhook.Message{Title: fmt.Sprintf("Batch job %s finished", id)}
+	results := job.OutputPath()
+	switch {
+	case err != nil:
+		m.Title = fmt.Sprintf("Batch job %s failed", id)
+		m.Text = err.Error()
+	case s.DryRun:
+		m.Title = fmt.Sprintf("Dry run of batch job %s finished", id)
+		results = job.ReportPath()
+	case s.Stopped:
+		m.Title = fmt.Sprintf("Batch job %s was stopped", id)
+	}
+	done := "Succeeded"
+	if s.DryRun {
+		done = "Would be done"
+	}
+	m.Facts = []webhook.Fact{{Name: "Operation", Value: fmt.Sprintf("%s %s records", job.Operation, job.Kind)}}
+	if err == nil {
+		m.Facts = append(m.Facts,
+			webhook.Fact{Name: done, Value: fmt.Sprint(s.Succeeded)},
+			webhook.Fact{Name: "Failed", Value: fmt.Sprint(s.Failed)},
+			webhook.Fact{Name: "Skipped", Value: fmt.Sprint(s.Skipped)},
+			webhook.Fact{Name: "Records", Value: fmt.Sprint(s.Total)},
+			webhook.Fact{Name: "Took", Value: s.Finished.Sub(s.Started).Round(time.Second).String()})
+		if len(s.Failures) > 0 {
+			m.Text = "Failures:\n" + strings.Join(s.Failures, "\n")
+			if s.Failed > len(s.Failures) {
+				m.Text += fmt.Sprintf("\n… and %d more", s.Failed-len(s.Failures))
+			}
+		}
+		if base := config.Get("FOLIAGE_BATCH_RESULTS_URL", ""); base != "" {
+			m.Link = strings.TrimSuffix(base, "/") + "/" + url.PathEscape(filepath.Base(results))
+		} else {
+			m.Facts = append(m.Facts, webhook.Fact{Name: "Results", Value: results})
+		}
+	}
+	return m, results
+}
+
+// dryRunBatchJob does a dry run of the job in the job file at path, and
+// prints the report.
+func dryRunBatchJob(ctx context.Context, path string, workers int) int {
+	job, err := batch.LoadJob(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	job.DryRun = true
+	summary, err := runBatchJob(ctx, job, nil, workers, false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if report, err := os.ReadFile(job.ReportPath()); err == nil {
+		os.Stdout.Write(report)
+	}
+	fmt.Printf("\nThe report is in %s, and in CSV form in %s.\n", job.ReportPath(), job.OutputPath())
+	if summary.Stopped {
+		return 1
+	}
+	return 0
+}
+
+// listBatchJobs prints the jobs that have journals, oldest first, with how
+// far each got.
+func listBatchJobs() error {
+	root, err := journalDir()
+	if err != nil {
+		return err
+	}
+	jobs, err := batch.Jobs(root)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		state := "unfinished"
+		switch {
+		case j.Finished():
+			state = "finished"
+		case j.Last != nil && !j.Last.Stopped:
+			state = fmt.Sprintf("finished, %d failed", j.Failed)
+		case j.Last != nil:
+			state = "stopped"
+		}
+		fmt.Printf("%s\t%s\t%d done\t%s\t%s\n", j.ID, j.Created.Format("2006-01-02 15:04"), j.Done, state, j.Source)
+	}
+	return nil
+}
+
+// batchClient returns a FOLIO client for foliaged's jobs (see folioClient),
+// which keeps its metrics, its audit log and its cache of records.
+func batchClient(ctx context.Context) (*foliolib.Client, error) {
+	l, err := openAudit()
+	if err != nil {
+		return nil, err
+	}
+	return folioClient(ctx, func(c *foliolib.Client) {
+		if foliagedMetrics != nil {
+			c.Trace = foliagedMetrics.trace
+		}
+		if l != nil {
+			c.Audit = l
+		}
+		if store, err := openCache(c); err != nil {
+			log.Printf("not caching records: %v", err)
+		} else if store != nil {
+			c.Cache = store
+		}
+	})
+}
+
+// folioClient returns a FOLIO client for the tenant in the settings, set up
+// by setUp, if it isn't nil, before it is used, with a token: the one in
+// FOLIO_OKAPI_TOKEN, if there is one; otherwise the one foliaged last had,
+// kept in the keyring; otherwise a new one, for the user and password in
+// FOLIO_USER and FOLIO_PASSWORD.
+func folioClient(ctx context.Context, setUp func(*foliolib.Client)) (*foliolib.Client, error) {
+	c, err := foliolib.FromSettings()
+	if err != nil {
+		return nil, err
+	}
+	if setUp != nil {
+		setUp(c)
+	}
+	user, password := config.Get("FOLIO_USER", ""), config.Get("FOLIO_PASSWORD", "")
+	c.User = user
+	if c.Token() != "" {
+		return c, nil
+	}
+	c.Store = foliolib.KeyringStore{Service: foliagedKeyring, Account: user + "@" + c.Tenant + "@" + c.URL}
+	if err := c.Restore(ctx); err == nil {
+		return c, nil
+	}
+	if user == "" || password == "" {
+		return nil, errors.New("no FOLIO credentials: set FOLIO_OKAPI_TOKEN, or FOLIO_USER and FOLIO_PASSWORD")
+	}
+	if err := c.Login(ctx, user, password); err != nil {
+		return nil, fmt.Errorf("unable to log in to FOLIO: %w", err)
+	}
+	return c, nil
+}
+
+// serveBatchJobs runs the job files that appear in the spool directory,
+// oldest first, with the number of workers given, checking for new ones
+// every interval, until the context is done.  Once a job has run, its file
+// is moved into the subdirectory "done", or "failed" if it couldn't run; a
+// job that was stopped is left where it is, and resumed from its journal
+// the next time.  Jobs with schedules are left in the spool, and run each
+// time their schedule comes round (see schedules).
+func serveBatchJobs(ctx context.Context, spool string, interval time.Duration, workers int) error {
+	root, err := journalDir()
+	if err != nil {
+		return err
+	}
+	for _, dir := range []string{spool, filepath.Join(spool, "done"), filepath.Join(spool, "failed")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	sched := loadSchedules(root)
+	grace := interval + time.Minute
+	log.Printf("taking batch jobs from %s", spool)
+	for {
+		for _, path := range spooledJobs(spool) {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := serveBatchJob(ctx, root, spool, path, sched, grace, workers); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			log.Print("no longer taking batch jobs")
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// serveBatchJob runs the job in the job file at path in the spool, if it is
+// time to, and then moves the file out of the spool, unless the job is
+// scheduled to run again.  A job that was stopped is resumed.
+func serveBatchJob(ctx context.Context, root, spool, path string, sched *schedules, grace time.Duration, workers int) error {
+	job, err := batch.LoadJob(path)
+	var journal *batch.Journal
+	switch {
+	case err == nil && job.Schedule != "":
+		if id := sched.running(path); id != "" {
+			journal, err = batch.OpenJournal(root, id)
+		} else if !sched.due(path, job, time.Now(), grace) {
+			return nil
+		}
+	case err == nil:
+		if id, ok := batch.Unfinished(root, path); ok {
+			journal, err = batch.OpenJournal(root, id)
+		}
+	}
+	if err == nil && journal == nil && !job.DryRun {
+		if journal, err = batch.CreateJournal(root, path, job); err == nil && job.Schedule != "" {
+			sched.start(path, journal.ID, time.Now())
+		}
+	}
+	if journal != nil {
+		job = journal.Job
+	}
+	started := time.Now()
+	var summary batch.Summary
+	if err == nil {
+		summary, err = runBatchJob(ctx, job, journal, workers, false)
+	}
+	switch {
+	case err != nil:
+		log.Printf("unable to run job %s: %v", path, err)
+	case summary.Stopped:
+		log.Printf("stopped %s", summary)
+		return nil
+	default:
+		log.Print(summary)
+	}
+	if job != nil && job.Schedule != "" {
+		sched.finish(path, job, started)
+		return nil
+	}
+	dest := "done"
+	if err != nil {
+		dest = "failed"
+	}
+	if err := os.Rename(path, filepath.Join(spool, dest, filepath.Base(path))); err != nil {
+		return fmt.Errorf("unable to move job file %s: %w", path, err)
+	}
+	return nil
+}
+
+// spooledJobs returns the paths of the job files in the spool directory,
+// oldest first.
+func spooledJobs(spool string) []string {
+	entries, err := os.ReadDir(spool)
+	if err != nil {
+		log.Printf("unable to read %s: %v", spool, err)
+		return nil
+	}
+	type job struct {
+		path string
+		mod  time.Time
+	}
+	var jobs []job
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+			if info, err := e.Info(); err == nil && info.Mode().IsRegular() {
+				jobs = append(jobs, job{filepath.Join(spool, e.Name()), info.ModTime()})
+			}
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].mod.Before(jobs[j].mod) })
+	paths := make([]string, len(jobs))
+	for i, j := range jobs {
+		paths[i] = j.path
+	}
+	return paths
+}
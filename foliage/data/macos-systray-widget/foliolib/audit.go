@@ -0,0 +1,85 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// The actions an Auditor is told about.
+const (
+	Created = "create"
+	Updated = "update"
+	Deleted = "delete"
+)
+
+// Auditor is told about each record the client creates, updates or deletes,
+// once FOLIO has done it, such as for keeping an audit log (see package
+// audit).  The context is the request's, and so carries whatever the caller
+// put in it, such as the job the change is part of.
+type Auditor interface {
+	Audit(ctx context.Context, c Change) error
+}
+
+// Change is a change the client made to a record.
+type Change struct {
+	Action string // Created, Updated or Deleted.
+	Kind   Kind
+	ID     string
+	Before Record // The record before the change, or nil if it is new.
+	After  Record // The record after the change, or nil if it was deleted.
+	User   string // The FOLIO user the client is logged in as, if it is known.
+	Tenant string
+	URL    string
+}
+
+// before returns the record as it is in FOLIO, before it is changed, for the
+// auditor, or nil if the client has no auditor.  Getting it is an extra
+// request, but it shows what FOLIO had, which may not be what the caller
+// last read.
+func (c *Client) before(ctx context.Context, kind Kind, id string) (Record, error) {
+	if c.Audit == nil {
+		return nil, nil
+	}
+	r, err := c.Record(ctx, kind, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return r, err
+}
+
+// ErrNotAudited is matched with errors.Is by the errors of changes that were
+// made, but not recorded by the auditor.
+var ErrNotAudited = errors.New("not recorded in the audit log")
+
+// AuditError is the error of a change that FOLIO made but the auditor
+// didn't record.  Unlike other errors from the methods that change records,
+// it means the change is there.
+type AuditError struct {
+	Change Change
+	Err    error // The auditor's error.
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("the %s of %s record %s was made, but %v: %v", e.Change.Action, e.Change.Kind, e.Change.ID, ErrNotAudited, e.Err)
+}
+
+func (e *AuditError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is match ErrNotAudited.
+func (e *AuditError) Is(target error) bool { return target == ErrNotAudited }
+
+// audit tells the auditor, if there is one, about a change.  The change has
+// been made by the time it is called, so an error, an *AuditError, means
+// only that it wasn't recorded, which the caller is told, so that it can
+// say so, or stop.
+func (c *Client) audit(ctx context.Context, action string, kind Kind, id string, before, after Record) error {
+	if c.Audit == nil {
+		return nil
+	}
+	change := Change{action, kind, id, before, after, c.User, c.Tenant, c.URL}
+	if err := c.Audit.Audit(ctx, change); err != nil {
+		return &AuditError{change, err}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Auth is a way of logging in to FOLIO.  Login gets tokens for a user, and
+// Refresh gets new ones with the refresh token of the old ones.  The client
+// makes sure only one refresh is under way at a time.  Both should give up
+// when the context is done.
+type Auth interface {
+	Login(ctx context.Context, c *Client, user, password string) (Tokens, error)
+	Refresh(ctx context.Context, c *Client, t Tokens) (Tokens, error)
+}
+
+// FOLIO releases from Poppy on rotate refresh tokens: logging in gives a
+// short-lived access token and a refresh token, in cookies, and refreshing
+// gives new ones of both; each refresh token can be used only once.
+const (
+	loginPath     = "/authn/login-with-expiry"
+	refreshPath   = "/authn/refresh"
+	accessCookie  = "folioAccessToken"
+	refreshCookie = "folioRefreshToken"
+)
+
+// OkapiAuth logs in through the Okapi gateway, with FOLIO's own
+// authentication module: with refresh token rotation if the FOLIO release
+// has it, and otherwise with the older login, whose token lasts until it is
+// revoked.
+type OkapiAuth struct{}
+
+// Login logs in through Okapi.
+func (OkapiAuth) Login(ctx context.Context, c *Client, user, password string) (Tokens, error) {
+	body, err := json.Marshal(map[string]string{"tenant": c.Tenant, "username": user, "password": password})
+	if err != nil {
+		return Tokens{}, err
+	}
+	resp, err := c.send(ctx, http.MethodPost, loginPath, NotIdempotent, body, nil)
+	if errors.Is(err, ErrNotFound) {
+		return legacyLogin(ctx, c, body)
+	} else if err != nil {
+		return Tokens{}, err
+	}
+	return readTokens(loginPath, resp)
+}
+
+// Refresh refreshes the tokens through Okapi.
+func (OkapiAuth) Refresh(ctx context.Context, c *Client, t Tokens) (Tokens, error) {
+	header := http.Header{}
+	header.Set("Cookie", (&http.Cookie{Name: refreshCookie, Value: t.Refresh}).String())
+	resp, err := c.send(ctx, http.MethodPost, refreshPath, NotIdempotent, nil, header)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return readTokens(refreshPath, resp)
+}
+
+// legacyLogin logs in to a FOLIO release without refresh token rotation.
+func legacyLogin(ctx context.Context, c *Client, body []byte) (Tokens, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/authn/login", NotIdempotent, body, nil)
+	if err != nil {
+		return Tokens{}, err
+	}
+	defer resp.Body.Close()
+	token := resp.Header.Get("X-Okapi-Token")
+	if token == "" {
+		return Tokens{}, fmt.Errorf("POST /authn/login: FOLIO's answer has no token")
+	}
+	return Tokens{Access: token}, nil
+}
+
+// readTokens reads the tokens from FOLIO's answer to logging in or
+// refreshing: the tokens are in cookies, and when they expire is in the
+// body (and in the cookies, for releases that leave it out of the body).
+func readTokens(path string, resp *http.Response) (Tokens, error) {
+	defer resp.Body.Close()
+	var t Tokens
+	for _, ck := range resp.Cookies() {
+		expires := ck.Expires
+		if ck.MaxAge > 0 {
+			expires = time.Now().Add(time.Duration(ck.MaxAge) * time.Second)
+		}
+		switch ck.Name {
+		case accessCookie:
+			t.Access, t.AccessExpires = ck.Value, expires
+		case refreshCookie:
+			t.Refresh, t.RefreshExpires = ck.Value, expires
+		}
+	}
+	var body struct {
+		AccessTokenExpiration  time.Time `json:"accessTokenExpiration"`
+		RefreshTokenExpiration time.Time `json:"refreshTokenExpiration"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil {
+		if !body.AccessTokenExpiration.IsZero() {
+			t.AccessExpires = body.AccessTokenExpiration
+		}
+		if !body.RefreshTokenExpiration.IsZero() {
+			t.RefreshExpires = body.RefreshTokenExpiration
+		}
+	}
+	if t.Access == "" || t.Refresh == "" {
+		return t, fmt.Errorf("POST %s: FOLIO's answer has no tokens", path)
+	}
+	return t, nil
+}
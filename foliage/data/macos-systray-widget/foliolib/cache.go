@@ -0,0 +1,106 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// A Cache keeps records a client has got from FOLIO, so that looking them up
+// again needn't ask FOLIO (see Client.Lookup).  Records are found in it by
+// the kind and the value of one of their fields, the ones in CacheKeys.
+type Cache interface {
+	// Get returns the record of the kind whose field has the value, if
+	// the cache has it, and whether it is fresh enough to use.
+	Get(kind Kind, field, value string) (r Record, fresh, ok bool)
+
+	// Put adds the record to the cache, replacing any it had with the
+	// same id.
+	Put(kind Kind, r Record) error
+
+	// Invalidate removes the record of the kind with the id, which has
+	// been changed or deleted.
+	Invalidate(kind Kind, id string) error
+}
+
+// CacheKeys is the fields records can be looked up by in a cache: their id,
+// human-readable id (HRID), and barcode.
+var CacheKeys = []string{"id", "hrid", "barcode"}
+
+// isCacheKey reports whether the field is in CacheKeys.
+func isCacheKey(field string) bool {
+	for _, k := range CacheKeys {
+		if k == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the record of the kind whose field has the value; the
+// error matches ErrNotFound if there is none, and is also an error if
+// there is more than one.  With a cache, and a field in CacheKeys, a
+// record in the cache that is fresh is used rather than asking FOLIO, and
+// one that isn't is used if FOLIO can't be reached at all.  Records got
+// for changing should be got with Record or Search instead, which always
+// ask FOLIO.
+func (c *Client) Lookup(ctx context.Context, kind Kind, field, value string) (Record, error) {
+	cached := c.Cache != nil && isCacheKey(field)
+	var old Record
+	if cached {
+		r, fresh, ok := c.Cache.Get(kind, field, value)
+		if ok && fresh {
+			return r, nil
+		} else if ok {
+			old = r
+		}
+	}
+	r, err := c.lookUp(ctx, kind, field, value)
+	var ferr *Error
+	if err != nil && old != nil && ctx.Err() == nil && !errors.As(err, &ferr) && !errors.Is(err, ErrNotFound) {
+		return old, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if c.Cache != nil && field != "id" {
+		c.Cache.Put(kind, r) // Record puts the ones it gets.
+	}
+	return r, nil
+}
+
+// notFound is the error of a lookup that found nothing.
+type notFound string
+
+func (e notFound) Error() string { return string(e) }
+
+// Is makes errors.Is match it to ErrNotFound.
+func (e notFound) Is(target error) bool { return target == ErrNotFound }
+
+// lookUp is Lookup, without the cache.
+func (c *Client) lookUp(ctx context.Context, kind Kind, field, value string) (Record, error) {
+	if field == "id" {
+		r, err := c.Record(ctx, kind, value)
+		if errors.Is(err, ErrNotFound) {
+			return nil, notFound(fmt.Sprintf("no %s record has the id %s", kind, value))
+		}
+		return r, err
+	}
+	records, total, err := c.Search(ctx, kind, Exact(field, value), 0, 2)
+	switch {
+	case err != nil:
+		return nil, err
+	case total == 0 || len(records) == 0:
+		return nil, notFound(fmt.Sprintf("no %s record has the %s %s", kind, field, value))
+	case total > 1:
+		return nil, fmt.Errorf("%d %s records have the %s %s", total, kind, field, value)
+	}
+	return records[0], nil
+}
+
+// invalidate removes the record of the kind with the id from the cache, if
+// the client has one.
+func (c *Client) invalidate(kind Kind, id string) {
+	if c.Cache != nil {
+		c.Cache.Invalidate(kind, id)
+	}
+}
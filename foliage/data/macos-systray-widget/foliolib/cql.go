@@ -0,0 +1,54 @@
+package foliolib
+
+import "strings"
+
+// Quote returns value as a CQL string, in double quotes, with the characters
+// that CQL would otherwise treat as wildcards or anchors escaped, so that it
+// matches only itself.
+func Quote(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\', '"', '*', '?', '^':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Exact returns a CQL query for the records whose index (a field, such as
+// "barcode" or "hrid") is exactly value.
+func Exact(index, value string) string {
+	return index + "==" + Quote(value)
+}
+
+// And returns a CQL query for the records that match all the queries given.
+// Empty queries are left out.
+func And(queries ...string) string {
+	var parts []string
+	for _, q := range queries {
+		if q != "" {
+			parts = append(parts, q)
+		}
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return "(" + strings.Join(parts, ") and (") + ")"
+}
+
+// AnyOf returns a CQL query for the records whose index is exactly any of
+// the values, for looking many records up at once.
+func AnyOf(index string, values []string) string {
+	if len(values) == 1 {
+		return Exact(index, values[0])
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = Quote(v)
+	}
+	return index + "==(" + strings.Join(quoted, " or ") + ")"
+}
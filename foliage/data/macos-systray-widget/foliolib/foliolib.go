@@ -0,0 +1,387 @@
+// Package foliolib is a client for the FOLIO library services platform, by
+// way of its Okapi gateway: it logs in to get a token, and finds, reads,
+// creates, changes and deletes instances, holdings, items, loans and users,
+// the records Foliage works on.  It makes the same requests Foliage's own
+// Python code does (see folio.py), so that Go programs, such as the
+// helper's subcommands, see FOLIO the same way.
+//
+// A Client is safe for use by several goroutines at once.  Its requests
+// take a context, and give up as soon as it is done, so that a program can
+// stop a long batch run right away when the user cancels it or the program
+// is asked to exit.
+package foliolib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"macos-systray-widget/identifiers"
+	"macos-systray-widget/tlsconfig"
+)
+
+// Errors that FOLIO's answers are matched against with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found in FOLIO")
+	ErrUnauthorized = errors.New("not authorized by FOLIO")
+	ErrRateLimited  = errors.New("FOLIO's rate limit was exceeded")
+)
+
+// Error is an error answer from FOLIO.
+type Error struct {
+	Op      string // The request, such as "GET /users/123".
+	Status  int    // The HTTP status code.
+	Message string // What FOLIO said, from its answer.
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: FOLIO answered %d %s", e.Op, e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("%s: FOLIO answered %d: %s", e.Op, e.Status, e.Message)
+}
+
+// Is makes errors.Is match the errors above to the status codes they mean.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Try describes a try of a request.
+type Try struct {
+	Method   string
+	Path     string // With the query, if it has one.
+	Retry    int    // 1 for the first try, 2 for the second, and so on.
+	Status   int    // The HTTP status code, or 0 if there was no answer.
+	Duration time.Duration
+	Err      error // Why there was no answer, if there wasn't one.
+}
+
+// Default timings of a client.
+const (
+	DefaultTimeout = 30 * time.Second
+	retryFactor    = 2 * time.Second // Foliage's _RETRY_TIME_FACTOR.
+)
+
+// Client talks to one FOLIO tenant.
+type Client struct {
+	URL    string // The Okapi URL, such as https://okapi.example.edu.
+	Tenant string // The tenant ID.
+	User   string // The user the client logs in as, once it has.
+
+	// Retry says how often a request is tried again when it fails in a
+	// way that trying again could fix, waiting longer each time.  Which
+	// failures those are depends on the request (see Idempotency).
+	Retry RetryPolicy
+
+	// Auth says how to log in and refresh the tokens.  If it is nil, the
+	// client logs in through Okapi.
+	Auth Auth
+
+	// Store, if not nil, keeps the tokens FOLIO gives with refresh token
+	// rotation, so that a later run can go on using them (see Restore).
+	Store TokenStore
+
+	HTTP *http.Client // The client for making requests.
+
+	// Audit, if not nil, is told about each record the client creates,
+	// updates or deletes.  So that it knows what a record was before, the
+	// client gets it from FOLIO first.
+	Audit Auditor
+
+	// Identifiers is the kinds of identifiers the tenant's records have,
+	// for Resolve, or nil for identifiers.Default.  FromSettings takes them
+	// from the tenant's entry in the list of tenants.
+	Identifiers identifiers.Types
+
+	// Cache, if not nil, keeps the records the client gets, for Lookup.
+	// Records the client changes or deletes are taken out of it.
+	Cache Cache
+
+	// Trace, if not nil, is called after each try of each request, such as
+	// for keeping metrics.  It is called by the goroutine making the
+	// request, and shouldn't take long.
+	Trace func(Try)
+
+	mu      sync.Mutex // Guards tokens and the limits.
+	tokens  Tokens
+	limits  Limits
+	bucket  *bucket
+	slots   chan struct{}
+	renewMu sync.Mutex // Held while the tokens are being refreshed.
+}
+
+// New returns a client for the tenant at the Okapi URL, with the default
+// timings and limits.  It has no token until Login, Restore or SetToken is
+// called.
+func New(url, tenant string) *Client {
+	c := &Client{
+		URL:    strings.TrimSuffix(url, "/"),
+		Tenant: tenant,
+		Retry:  DefaultRetry,
+		HTTP:   &http.Client{Timeout: DefaultTimeout},
+	}
+	c.SetLimits(DefaultLimits)
+	return c
+}
+
+// Token returns the client's API (access) token, or "" if it has none.
+func (c *Client) Token() string {
+	return c.Tokens().Access
+}
+
+// Tokens returns the client's tokens.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// SetToken sets the client's API token, such as one Foliage already holds.
+// A token set this way isn't refreshed.
+func (c *Client) SetToken(token string) {
+	c.setTokens(Tokens{Access: token}, false)
+}
+
+// setTokens records new tokens, and saves them in the store if save is true
+// and there is one.
+func (c *Client) setTokens(t Tokens, save bool) error {
+	c.mu.Lock()
+	c.tokens = t
+	c.mu.Unlock()
+	if save && c.Store != nil {
+		return c.Store.Save(t)
+	}
+	return nil
+}
+
+// Login asks FOLIO for new tokens for the user, and uses them from then on.
+// Tokens that can be refreshed are saved in the store, if there is one, and
+// refreshed when they need to be.
+func (c *Client) Login(ctx context.Context, user, password string) error {
+	t, err := c.auth().Login(ctx, c, user, password)
+	if err != nil {
+		return err
+	}
+	c.User = user
+	return c.setTokens(t, t.Refresh != "")
+}
+
+// auth returns how the client logs in.
+func (c *Client) auth() Auth {
+	if c.Auth == nil {
+		return OkapiAuth{}
+	}
+	return c.Auth
+}
+
+// SetTLS makes the client connect with the TLS options, for a FOLIO whose
+// certificate isn't from a public certificate authority.  Turning off
+// checking the certificate is logged as a warning, since it exposes the
+// client's tokens.
+func (c *Client) SetTLS(o tlsconfig.Options) error {
+	t, err := o.Transport()
+	if err != nil {
+		return fmt.Errorf("TLS settings for %s: %w", c.URL, err)
+	}
+	if o.InsecureSkipVerify {
+		log.Printf("WARNING: not checking the TLS certificate of FOLIO at %s; "+
+			"anyone on the network can read and change what is sent to it, including tokens", c.URL)
+	}
+	c.HTTP.Transport = t
+	return nil
+}
+
+// Valid reports whether FOLIO accepts the client's token, by making the
+// smallest request Foliage knows of.
+func (c *Client) Valid(ctx context.Context) bool {
+	return c.Get(ctx, "/instance-statuses?limit=0", nil) == nil
+}
+
+// Get gets the JSON at path (which starts with a slash, and may have a
+// query) and decodes it into v, unless v is nil.
+func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
+	return c.Request(ctx, http.MethodGet, path, Idempotent, nil, v)
+}
+
+// Post posts the JSON form of body to path, and decodes the answer into v,
+// unless v is nil.  It is taken not to be idempotent; Request can say
+// otherwise.
+func (c *Client) Post(ctx context.Context, path string, body, v interface{}) error {
+	return c.Request(ctx, http.MethodPost, path, NotIdempotent, body, v)
+}
+
+// Put puts the JSON form of body at path.
+func (c *Client) Put(ctx context.Context, path string, body interface{}) error {
+	return c.Request(ctx, http.MethodPut, path, Idempotent, body, nil)
+}
+
+// Delete deletes what is at path.  If FOLIO says there is nothing there
+// after an earlier try timed out, the deletion succeeds (see DeleteOnce).
+func (c *Client) Delete(ctx context.Context, path string) error {
+	return c.Request(ctx, http.MethodDelete, path, DeleteOnce, nil, nil)
+}
+
+// Request makes a request with the JSON form of body, if it isn't nil, and
+// decodes the JSON answer into v, if it isn't nil.  The hint says which
+// failures the request is tried again after.
+func (c *Client) Request(ctx context.Context, method, path string, hint Idempotency, body, v interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	resp, err := c.do(ctx, method, path, hint, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s %s: unable to read FOLIO's answer: %w", method, path, err)
+	}
+	return nil
+}
+
+// do makes a request with the client's token.  The tokens are refreshed
+// first if they are about to expire, and again if FOLIO says the access
+// token is no good, in which case the request is made once more.
+func (c *Client) do(ctx context.Context, method, path string, hint Idempotency, body []byte) (*http.Response, error) {
+	t := c.Tokens()
+	if t.Refresh != "" && time.Until(t.AccessExpires) < refreshAhead {
+		if err := c.refresh(ctx, t.Access); err != nil {
+			return nil, err
+		}
+		t = c.Tokens()
+	}
+	resp, err := c.send(ctx, method, path, hint, body, authHeader(t))
+	if t.Refresh != "" && errors.Is(err, ErrUnauthorized) {
+		if rerr := c.refresh(ctx, t.Access); rerr != nil {
+			return nil, rerr
+		}
+		resp, err = c.send(ctx, method, path, hint, body, authHeader(c.Tokens()))
+	}
+	return resp, err
+}
+
+// authHeader returns the header that gives FOLIO the access token.
+func authHeader(t Tokens) http.Header {
+	h := http.Header{}
+	if t.Access != "" {
+		h.Set("X-Okapi-Token", t.Access)
+	}
+	return h
+}
+
+// send makes a request with the given extra headers, trying it again after
+// the failures the hint allows.  It returns an *Error for answers other than
+// success.
+func (c *Client) send(ctx context.Context, method, path string, hint Idempotency, body []byte, header http.Header) (*http.Response, error) {
+	op := method + " " + path
+	uncertain := false // Whether FOLIO may have acted on an earlier try.
+	for retry := 1; ; retry++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.URL+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("X-Okapi-Tenant", c.Tenant)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/plain")
+		done, err := c.throttle(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		start := time.Now()
+		resp, err := c.HTTP.Do(req)
+		done()
+		if c.Trace != nil {
+			t := Try{Method: method, Path: path, Retry: retry, Duration: time.Since(start), Err: err}
+			if resp != nil {
+				t.Status = resp.StatusCode
+			}
+			c.Trace(t)
+		}
+		// An answer that came before the context was done is kept: FOLIO
+		// has done what it was asked, and the caller needs to know.
+		if err != nil && ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+		if err != nil {
+			if again, _ := retryable(hint, err, 0); !again || retry > c.Retry.MaxRetries {
+				if timedOut(err) {
+					return nil, fmt.Errorf("%s: FOLIO didn't answer in time: %w", op, err)
+				}
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			uncertain = true
+			if err := sleep(ctx, c.Retry.wait(retry)); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			continue
+		}
+		if resp.StatusCode < 300 {
+			return resp, nil
+		}
+		if hint == DeleteOnce && uncertain && resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+		}
+		ferr := readError(op, resp)
+		again, maybe := retryable(hint, nil, resp.StatusCode)
+		if !again || retry > c.Retry.MaxRetries {
+			return nil, ferr
+		}
+		uncertain = uncertain || maybe
+		wait := c.Retry.wait(retry)
+		if d := retryAfter(resp); d > 0 {
+			wait = d
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+}
+
+// readError reads an error answer.  FOLIO says what went wrong in plain
+// text, or for validation errors (422), in a JSON list of errors.
+func readError(op string, resp *http.Response) error {
+	defer resp.Body.Close()
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	message := strings.TrimSpace(string(text))
+	var validation struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(text, &validation) == nil && len(validation.Errors) > 0 {
+		var messages []string
+		for _, e := range validation.Errors {
+			messages = append(messages, e.Message)
+		}
+		message = strings.Join(messages, "; ")
+	}
+	return &Error{Op: op, Status: resp.StatusCode, Message: message}
+}
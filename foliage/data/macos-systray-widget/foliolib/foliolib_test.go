@@ -0,0 +1,83 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testClient returns a client for a test server with the handler.  It
+// waits only a millisecond between tries, and has no limits, so that the
+// tests don't take long.
+func testClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c := New(srv.URL, "diku")
+	c.Retry = RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}
+	c.SetLimits(Limits{})
+	return c
+}
+
+func TestErrorAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		target  error
+		message string
+	}{
+		{"not found", http.StatusNotFound, "Not found", ErrNotFound, "Not found"},
+		{"unauthorized", http.StatusUnauthorized, "Invalid token", ErrUnauthorized, "Invalid token"},
+		{"forbidden", http.StatusForbidden, "Access requires permission", ErrUnauthorized, "Access requires permission"},
+		{"validation", http.StatusUnprocessableEntity,
+			`{"errors": [{"message": "barcode is taken"}, {"message": "no status"}]}`, nil, "barcode is taken; no status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			err := c.Get(context.Background(), "/items/1", nil)
+			var ferr *Error
+			if !errors.As(err, &ferr) {
+				t.Fatalf("got error %v, want an *Error", err)
+			}
+			if ferr.Status != tt.status || ferr.Message != tt.message {
+				t.Errorf("got status %d and message %q, want %d and %q", ferr.Status, ferr.Message, tt.status, tt.message)
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("error %v doesn't match %v", err, tt.target)
+			}
+		})
+	}
+}
+
+// TestAnswerAfterCancel checks that an answer FOLIO gave before the context
+// was done is kept, since FOLIO has done what it was asked.
+func TestAnswerAfterCancel(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNoContent, nil},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusServiceUnavailable, context.Canceled}, // Not tried again.
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			h, _ := answers(tt.status)
+			c := testClient(t, h)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c.Trace = func(Try) { cancel() }
+			if err := c.Delete(ctx, "/inventory/items/1"); !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package foliolib
+
+import (
+	"context"
+	"sync"
+)
+
+// Hierarchy is an instance, with its holdings records and their items.
+type Hierarchy struct {
+	Identifier string // As given to Expand.
+	Instance   Record
+	Holdings   []HoldingsTree
+
+	// Err is why the instance couldn't be expanded, if it couldn't; it
+	// matches ErrNotFound if there is no such instance.
+	Err error
+}
+
+// HoldingsTree is a holdings record, with its items.
+type HoldingsTree struct {
+	Holdings Record
+	Items    []Record
+}
+
+// Items returns the number of items in the hierarchy.
+func (h Hierarchy) Items() int {
+	n := 0
+	for _, t := range h.Holdings {
+		n += len(t.Items)
+	}
+	return n
+}
+
+// ExpandOptions change how Expand works.
+type ExpandOptions struct {
+	// Workers is how many requests Expand makes at once, or, if it is 0,
+	// as many as the client's limits let it (see Limits).
+	Workers int
+
+	// Progress, if not nil, is called after each instance is expanded,
+	// with its hierarchy and the number of instances done so far and in
+	// all.  Calls come one at a time, in the order the instances are
+	// done in.
+	Progress func(h Hierarchy, done, total int)
+}
+
+// expandBatch is how many holdings records' items Expand asks for at once.
+const expandBatch = 50
+
+// Expand gets the instances with the identifiers (ids, or any other kind of
+// identifier the tenant's instances have; see Resolve), with all their
+// holdings records and the items of those, and returns the hierarchies in
+// the order of the identifiers.  Rather than going through the records one
+// at a time, as Foliage does, it works on many instances at once, and asks
+// for the items of up to expandBatch holdings records at once, so that
+// instances with hundreds of items don't take hundreds of requests one
+// after another.  An instance that can't be expanded has the reason in its
+// Err; the error is for Expand being stopped, by the context.
+func (c *Client) Expand(ctx context.Context, identifiers []string, opts ExpandOptions) ([]Hierarchy, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = c.Limits().MaxInFlight
+	}
+	if workers <= 0 {
+		workers = DefaultLimits.MaxInFlight
+	}
+	slots := make(chan struct{}, workers)
+	run := func(f func()) bool {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+		defer func() { <-slots }()
+		f()
+		return true
+	}
+	hierarchies := make([]Hierarchy, len(identifiers))
+	var progress sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	for i, id := range identifiers {
+		wg.Add(1)
+		go func(h *Hierarchy, id string) {
+			defer wg.Done()
+			h.Identifier = id
+			h.Err = c.expand(ctx, h, id, run)
+			if opts.Progress != nil && ctx.Err() == nil {
+				progress.Lock()
+				done++
+				opts.Progress(*h, done, len(identifiers))
+				progress.Unlock()
+			}
+		}(&hierarchies[i], id)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return hierarchies, err
+	}
+	return hierarchies, nil
+}
+
+// expand fills in the hierarchy of the instance with the identifier, making
+// each request with run.
+func (c *Client) expand(ctx context.Context, h *Hierarchy, id string, run func(func()) bool) error {
+	var err error
+	if !run(func() {
+		if _, h.Instance, err = c.Resolve(ctx, id, Instance); err != nil {
+			return
+		}
+		err = c.SearchAll(ctx, Holdings, Exact("instanceId", h.Instance.ID())+" sortBy id", func(r Record) error {
+			h.Holdings = append(h.Holdings, HoldingsTree{Holdings: r})
+			return nil
+		})
+	}) {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(h.Holdings))
+	ids := make([]string, len(h.Holdings))
+	for i, t := range h.Holdings {
+		ids[i] = t.Holdings.ID()
+		index[ids[i]] = i
+	}
+	var items sync.Mutex
+	var wg sync.WaitGroup
+	for len(ids) > 0 {
+		n := expandBatch
+		if n > len(ids) {
+			n = len(ids)
+		}
+		batch := ids[:n]
+		ids = ids[n:]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var found []Record
+			var ferr error
+			if !run(func() {
+				ferr = c.SearchAll(ctx, Item, AnyOf("holdingsRecordId", batch)+" sortBy id", func(r Record) error {
+					found = append(found, r)
+					return nil
+				})
+			}) {
+				ferr = ctx.Err()
+			}
+			items.Lock()
+			defer items.Unlock()
+			if ferr != nil {
+				if err == nil {
+					err = ferr
+				}
+				return
+			}
+			for _, r := range found {
+				holdings, _ := r["holdingsRecordId"].(string)
+				if i, ok := index[holdings]; ok {
+					h.Holdings[i].Items = append(h.Holdings[i].Items, r)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return err
+}
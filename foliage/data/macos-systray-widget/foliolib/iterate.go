@@ -0,0 +1,165 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Paging is how an Iterator gets one page of records after another.
+type Paging int
+
+const (
+	// OffsetPaging asks for each page by its offset in the results.  It
+	// works with any query, but FOLIO takes longer the further in a page
+	// is, and some FOLIO modules refuse offsets past 10,000.
+	OffsetPaging Paging = iota
+
+	// CursorPaging sorts the records by id and asks for the records with
+	// ids after the last one of the previous page, which is as quick for
+	// the last page as for the first.  It needs a query without a sortBy
+	// clause of its own.
+	CursorPaging
+)
+
+// IterateOptions change how an Iterator gets the records.
+type IterateOptions struct {
+	Paging   Paging
+	PageSize int // How many records to ask for at a time; PageSize if 0.
+
+	// Progress, if not nil, is called after each page with the number of
+	// records got so far and the total number that match, for showing
+	// how far along a long run is.
+	Progress func(done, total int)
+}
+
+// An Iterator goes through the records that match a query, one page at a
+// time, so that only one page is in memory at once however many there are:
+//
+//	it := client.Iterate(ctx, foliolib.Item, query, foliolib.IterateOptions{Paging: foliolib.CursorPaging})
+//	for it.Next() {
+//		r := it.Record()
+//		…
+//	}
+//	if err := it.Err(); err != nil {
+//		…
+//	}
+//
+// Once the context is done, Next returns false and Err returns the
+// context's error.  An Iterator is for use by one goroutine.
+type Iterator struct {
+	ctx   context.Context
+	c     *Client
+	kind  Kind
+	query string
+	opts  IterateOptions
+
+	page   []Record
+	index  int
+	done   int // Records got so far.
+	total  int
+	offset int
+	last   string // Id of the last record, for cursor paging.
+	end    bool
+	err    error
+}
+
+// sortBy matches a CQL query with a sortBy clause.
+var sortBy = regexp.MustCompile(`(?i)\bsortby\b`)
+
+// Iterate returns an Iterator for the records of the kind that match the
+// CQL query.  A query of "" matches every record.
+func (c *Client) Iterate(ctx context.Context, kind Kind, query string, opts IterateOptions) *Iterator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = PageSize
+	}
+	it := &Iterator{ctx: ctx, c: c, kind: kind, query: query, opts: opts, index: -1}
+	if opts.Paging == CursorPaging && sortBy.MatchString(query) {
+		it.err, it.end = errors.New("cursor paging needs a query that doesn't sort the records"), true
+	}
+	return it
+}
+
+// Next moves to the next record, getting the next page if need be.  It
+// returns false when there are no more records, or there was an error.
+func (it *Iterator) Next() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err, it.end, it.page = err, true, nil
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.end {
+		return false
+	}
+	records, total, err := it.c.Search(it.ctx, it.kind, it.pageQuery(), it.offset, it.opts.PageSize)
+	if err != nil {
+		it.err, it.end = err, true
+		return false
+	}
+	if it.done == 0 {
+		// With cursor paging, later pages count only the records after
+		// them.
+		it.total = total
+	}
+	it.page, it.index = records, 0
+	it.done += len(records)
+	if it.opts.Paging == CursorPaging {
+		if len(records) > 0 {
+			it.last = records[len(records)-1].ID()
+		}
+	} else {
+		it.offset += len(records)
+	}
+	switch {
+	case len(records) < it.opts.PageSize:
+		it.end = true
+	case it.opts.Paging == CursorPaging:
+		// Without an id, there is no telling where the next page starts.
+		it.end = it.last == ""
+	default:
+		it.end = it.offset >= total
+	}
+	if it.done > it.total {
+		it.total = it.done
+	}
+	if it.opts.Progress != nil {
+		it.opts.Progress(it.done, it.total)
+	}
+	return len(records) > 0
+}
+
+// pageQuery returns the query for the next page.
+func (it *Iterator) pageQuery() string {
+	if it.opts.Paging != CursorPaging {
+		return it.query
+	}
+	q := it.query
+	if it.last != "" {
+		q = And(q, "id>"+Quote(it.last))
+	} else if q == "" {
+		q = "cql.allRecords=1"
+	}
+	return q + " sortBy id"
+}
+
+// Record returns the current record.
+func (it *Iterator) Record() Record {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+	return it.page[it.index]
+}
+
+// Total returns the number of records that match, as FOLIO counted them
+// when the first page was got, or the number got so far if that is more.
+func (it *Iterator) Total() int {
+	return it.total
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,137 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// after matches the cursor in the query of a page after the first.
+var after = regexp.MustCompile(`id>"([^"]*)"`)
+
+// itemServer returns a handler that serves n items, with ids in order, as
+// FOLIO does for offset and cursor paging, and a function that returns the
+// queries it was asked.
+func itemServer(t *testing.T, n int, cursor bool) (http.Handler, func() []string) {
+	var queries []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query()
+		query := v.Get("query")
+		queries = append(queries, query)
+		offset, _ := strconv.Atoi(v.Get("offset"))
+		limit, _ := strconv.Atoi(v.Get("limit"))
+		if cursor != strings.HasSuffix(query, " sortBy id") {
+			t.Errorf("query %q with cursor paging %v", query, cursor)
+		}
+		start, total := offset, n
+		if m := after.FindStringSubmatch(query); m != nil {
+			if offset != 0 {
+				t.Errorf("cursor query %q with offset %d", query, offset)
+			}
+			fmt.Sscanf(m[1], "item-%d", &start)
+			start++
+			total = n - start
+		}
+		var items []Record
+		for i := start; i < n && len(items) < limit; i++ {
+			items = append(items, Record{"id": fmt.Sprintf("item-%03d", i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items, "totalRecords": total})
+	})
+	return h, func() []string { return queries }
+}
+
+func TestIterate(t *testing.T) {
+	tests := []struct {
+		name     string
+		paging   Paging
+		query    string
+		records  int
+		pageSize int
+		pages    int
+	}{
+		{"offset", OffsetPaging, "", 250, 100, 3},
+		{"offset, full pages", OffsetPaging, "", 200, 100, 2},
+		{"offset, none", OffsetPaging, `status.name=="Missing"`, 0, 100, 1},
+		{"cursor", CursorPaging, "", 250, 100, 3},
+		// With full pages, only an empty page shows that there are no more.
+		{"cursor, full pages", CursorPaging, "", 200, 100, 3},
+		{"cursor with a query", CursorPaging, `status.name=="Missing"`, 25, 10, 3},
+		{"cursor, none", CursorPaging, "", 0, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, queries := itemServer(t, tt.records, tt.paging == CursorPaging)
+			c := testClient(t, h)
+			var done, total int
+			opts := IterateOptions{
+				Paging:   tt.paging,
+				PageSize: tt.pageSize,
+				Progress: func(d, n int) { done, total = d, n },
+			}
+			it := c.Iterate(context.Background(), Item, tt.query, opts)
+			n := 0
+			for it.Next() {
+				if want := fmt.Sprintf("item-%03d", n); it.Record().ID() != want {
+					t.Fatalf("record %d has id %q, want %q", n, it.Record().ID(), want)
+				}
+				n++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.records || it.Total() != tt.records {
+				t.Errorf("got %d records with a total of %d, want %d", n, it.Total(), tt.records)
+			}
+			if done != tt.records || total != tt.records {
+				t.Errorf("last progress was %d of %d, want %d of %d", done, total, tt.records, tt.records)
+			}
+			if got := queries(); len(got) != tt.pages {
+				t.Errorf("got %d pages, want %d (queries %q)", len(got), tt.pages, got)
+			} else if tt.query != "" && !strings.Contains(got[len(got)-1], tt.query) {
+				t.Errorf("the last query %q doesn't have the query %q", got[len(got)-1], tt.query)
+			}
+		})
+	}
+}
+
+func TestIterateCursorRefusesSort(t *testing.T) {
+	h, queries := itemServer(t, 10, true)
+	c := testClient(t, h)
+	it := c.Iterate(context.Background(), Item, "barcode=1* sortBy barcode", IterateOptions{Paging: CursorPaging})
+	if it.Next() {
+		t.Error("Next returned a record for a query that sorts")
+	}
+	if it.Err() == nil {
+		t.Error("cursor paging accepted a query that sorts")
+	}
+	if len(queries()) != 0 {
+		t.Errorf("asked FOLIO %q", queries())
+	}
+}
+
+func TestIterateStopsWithContext(t *testing.T) {
+	h, queries := itemServer(t, 250, false)
+	c := testClient(t, h)
+	ctx, cancel := context.WithCancel(context.Background())
+	it := c.Iterate(ctx, Item, "", IterateOptions{})
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	if it.Next() {
+		t.Error("Next returned a record after the context was canceled")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("got error %v, want the context's", it.Err())
+	}
+	if len(queries()) != 1 {
+		t.Errorf("got %d pages, want 1", len(queries()))
+	}
+}
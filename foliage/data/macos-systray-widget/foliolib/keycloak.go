@@ -0,0 +1,86 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// KeycloakAuth logs in with Keycloak, as FOLIO's Eureka platform does: the
+// client gets tokens from the realm's OpenID Connect token endpoint, with
+// the user's name and password, and refreshes them there.  FOLIO takes
+// the access token in the same header as an Okapi token.
+type KeycloakAuth struct {
+	URL          string // The Keycloak URL, such as https://keycloak.example.edu.
+	Realm        string // The realm, which in Eureka is named after the tenant.
+	ClientID     string // The OpenID Connect client, such as "diku-login-application".
+	ClientSecret string // The client's secret, if it has one.
+}
+
+// Login gets tokens for the user from Keycloak.
+func (k KeycloakAuth) Login(ctx context.Context, c *Client, user, password string) (Tokens, error) {
+	return k.token(ctx, c, url.Values{"grant_type": {"password"}, "username": {user}, "password": {password}})
+}
+
+// Refresh gets new tokens from Keycloak.
+func (k KeycloakAuth) Refresh(ctx context.Context, c *Client, t Tokens) (Tokens, error) {
+	return k.token(ctx, c, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {t.Refresh}})
+}
+
+// token asks the token endpoint for tokens.
+func (k KeycloakAuth) token(ctx context.Context, c *Client, form url.Values) (Tokens, error) {
+	endpoint := strings.TrimSuffix(k.URL, "/") + "/realms/" + url.PathEscape(k.Realm) + "/protocol/openid-connect/token"
+	op := "POST " + endpoint
+	form.Set("client_id", k.ClientID)
+	if k.ClientSecret != "" {
+		form.Set("client_secret", k.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Tokens{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return Tokens{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var answer struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		RefreshExpiresIn int    `json:"refresh_expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	jerr := json.Unmarshal(text, &answer)
+	if resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(text))
+		if answer.ErrorDescription != "" {
+			message = answer.ErrorDescription
+		} else if answer.Error != "" {
+			message = answer.Error
+		}
+		return Tokens{}, &Error{Op: op, Status: resp.StatusCode, Message: message}
+	}
+	if jerr != nil || answer.AccessToken == "" {
+		return Tokens{}, fmt.Errorf("%s: Keycloak's answer has no token", op)
+	}
+	now := time.Now()
+	t := Tokens{Access: answer.AccessToken, Refresh: answer.RefreshToken}
+	if answer.ExpiresIn > 0 {
+		t.AccessExpires = now.Add(time.Duration(answer.ExpiresIn) * time.Second)
+	}
+	// Keycloak gives 0 for refresh tokens that don't expire, such as
+	// offline tokens.
+	if answer.RefreshExpiresIn > 0 {
+		t.RefreshExpires = now.Add(time.Duration(answer.RefreshExpiresIn) * time.Second)
+	}
+	return t, nil
+}
@@ -0,0 +1,121 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeycloak(t *testing.T) {
+	tokens := map[string]interface{}{"access_token": "a", "expires_in": 300, "refresh_token": "r", "refresh_expires_in": 1800}
+	offline := map[string]interface{}{"access_token": "a", "expires_in": 300, "refresh_token": "r", "refresh_expires_in": 0}
+	tests := []struct {
+		name    string
+		secret  string
+		status  int
+		answer  map[string]interface{}
+		expires bool   // Whether the refresh token expires.
+		wantErr string // What the error should say, or "" for none.
+	}{
+		{"login", "", http.StatusOK, tokens, true, ""},
+		{"confidential client", "s3cret", http.StatusOK, tokens, true, ""},
+		{"offline token", "", http.StatusOK, offline, false, ""},
+		{"wrong password", "", http.StatusUnauthorized,
+			map[string]interface{}{"error": "invalid_grant", "error_description": "Invalid user credentials"},
+			false, "answered 401: Invalid user credentials"},
+		{"bad request", "", http.StatusBadRequest,
+			map[string]interface{}{"error": "unsupported_grant_type"}, false, "answered 400: unsupported_grant_type"},
+		{"no token", "", http.StatusOK, map[string]interface{}{}, false, "Keycloak's answer has no token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form url.Values
+			c := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/realms/diku/protocol/openid-connect/token" {
+					if r.Header.Get("X-Okapi-Token") != "a" {
+						w.WriteHeader(http.StatusUnauthorized)
+					}
+					return
+				}
+				r.ParseForm()
+				form = r.PostForm
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(tt.answer)
+			}))
+			c.Auth = KeycloakAuth{URL: c.URL + "/", Realm: "diku", ClientID: "diku-login-application", ClientSecret: tt.secret}
+			err := c.Login(context.Background(), "diku_admin", "admin")
+			want := url.Values{
+				"grant_type": {"password"},
+				"username":   {"diku_admin"},
+				"password":   {"admin"},
+				"client_id":  {"diku-login-application"},
+			}
+			if tt.secret != "" {
+				want.Set("client_secret", tt.secret)
+			}
+			if form.Encode() != want.Encode() {
+				t.Errorf("sent %v, want %v", form, want)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want one saying %q", err, tt.wantErr)
+				}
+				if tt.status == http.StatusUnauthorized && !errors.Is(err, ErrUnauthorized) {
+					t.Errorf("error %v doesn't match ErrUnauthorized", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := c.Tokens()
+			if got.Access != "a" || got.Refresh != "r" {
+				t.Errorf("got tokens %+v", got)
+			}
+			if d := time.Until(got.AccessExpires); d < 4*time.Minute || d > 5*time.Minute {
+				t.Errorf("the access token expires in %v, want 5m", d)
+			}
+			if got.RefreshExpires.IsZero() == tt.expires {
+				t.Errorf("the refresh token expires at %v", got.RefreshExpires)
+			}
+			if !c.Valid(context.Background()) {
+				t.Error("FOLIO doesn't take the access token")
+			}
+		})
+	}
+}
+
+func TestKeycloakRefresh(t *testing.T) {
+	var form url.Values
+	c := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/realms/diku/protocol/openid-connect/token" {
+			r.ParseForm()
+			form = r.PostForm
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "a2", "expires_in": 300, "refresh_token": "r2", "refresh_expires_in": 1800,
+			})
+			return
+		}
+		if r.Header.Get("X-Okapi-Token") != "a2" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	c.Auth = KeycloakAuth{URL: c.URL, Realm: "diku", ClientID: "diku-login-application"}
+	store := &memStore{}
+	c.Store = store
+	c.setTokens(Tokens{Access: "a1", AccessExpires: time.Now().Add(time.Hour), Refresh: "r1"}, false)
+	if err := c.Get(context.Background(), "/instance-statuses?limit=0", nil); err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "r1" {
+		t.Errorf("refreshed with %v", form)
+	}
+	if store.tokens.Access != "a2" || store.tokens.Refresh != "r2" {
+		t.Errorf("saved tokens %+v", store.tokens)
+	}
+}
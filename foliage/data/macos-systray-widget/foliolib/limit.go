@@ -0,0 +1,103 @@
+package foliolib
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limits keep a client from making requests faster than FOLIO, and the other
+// people using the tenant, can bear, which a long batch run can otherwise
+// do.  A zero Rate or MaxInFlight means no limit.
+type Limits struct {
+	Rate        float64 // The most requests a second, on average.
+	Burst       int     // How many requests can be made at once after a pause.
+	MaxInFlight int     // The most requests waiting for an answer at once.
+}
+
+// DefaultLimits are the limits of new clients.
+var DefaultLimits = Limits{Rate: 10, Burst: 20, MaxInFlight: 5}
+
+// SetLimits changes the client's limits.  Requests already waiting for an
+// answer count against the old limits until they get one.
+func (c *Client) SetLimits(l Limits) {
+	var b *bucket
+	if l.Rate > 0 {
+		burst := float64(l.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		b = &bucket{rate: l.Rate, burst: burst, tokens: burst, last: time.Now()}
+	}
+	var slots chan struct{}
+	if l.MaxInFlight > 0 {
+		slots = make(chan struct{}, l.MaxInFlight)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limits, c.bucket, c.slots = l, b, slots
+}
+
+// Limits returns the client's limits.
+func (c *Client) Limits() Limits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limits
+}
+
+// throttle waits until the limits allow another request, and returns the
+// function to call once it has been answered.  It returns the context's
+// error if the context is done first.
+func (c *Client) throttle(ctx context.Context) (func(), error) {
+	c.mu.Lock()
+	b, slots := c.bucket, c.slots
+	c.mu.Unlock()
+	if b != nil {
+		if err := b.take(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// A bucket is a token bucket: it fills at rate tokens a second, up to
+// burst, and each request takes one.
+type bucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take waits until there is a token, and takes it, unless the context is
+// done first.
+func (b *bucket) take(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		requests int
+		atLeast  time.Duration
+		atMost   time.Duration
+	}{
+		// The first Burst requests are made at once, and the rest at the rate.
+		{"burst", Limits{Rate: 1, Burst: 3}, 3, 0, 500 * time.Millisecond},
+		{"rate", Limits{Rate: 20, Burst: 2}, 6, 150 * time.Millisecond, 2 * time.Second},
+		{"no burst", Limits{Rate: 20}, 5, 150 * time.Millisecond, 2 * time.Second},
+		{"no limit", Limits{}, 20, 0, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, tries := answers(200)
+			c := testClient(t, h)
+			c.SetLimits(tt.limits)
+			start := time.Now()
+			for i := 0; i < tt.requests; i++ {
+				if err := c.Get(context.Background(), "/items/1", nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			took := time.Since(start)
+			if took < tt.atLeast || took > tt.atMost {
+				t.Errorf("%d requests took %v, want between %v and %v", tt.requests, took, tt.atLeast, tt.atMost)
+			}
+			if tries() != tt.requests {
+				t.Errorf("made %d requests, want %d", tries(), tt.requests)
+			}
+		})
+	}
+}
+
+func TestRateLimitStopsWithContext(t *testing.T) {
+	h, _ := answers(200)
+	c := testClient(t, h)
+	c.SetLimits(Limits{Rate: 0.1, Burst: 1})
+	if err := c.Get(context.Background(), "/items/1", nil); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.Get(ctx, "/items/1", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context's", err)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var now, most int32
+	c := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&now, 1)
+		defer atomic.AddInt32(&now, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	c.SetLimits(Limits{MaxInFlight: 2})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Get(context.Background(), "/items/1", nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if most > 2 {
+		t.Errorf("%d requests were in flight at once, want at most 2", most)
+	}
+}
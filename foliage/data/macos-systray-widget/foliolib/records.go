@@ -0,0 +1,220 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Kind is a kind of FOLIO record.
+type Kind string
+
+// The kinds of records Foliage works on.
+const (
+	Instance Kind = "instance"
+	Holdings Kind = "holdings"
+	Item     Kind = "item"
+	Loan     Kind = "loan"
+	User     Kind = "user"
+
+	// Locations are where holdings and items are shelved; batch jobs don't
+	// work on them, but look them up.
+	Location Kind = "location"
+)
+
+// Where each kind of record is kept.  Instances and items are deleted
+// through the inventory module, as Foliage does, so that FOLIO checks that
+// nothing still refers to them; the rest are deleted from storage.
+var endpoints = map[Kind]struct{ storage, delete, list string }{
+	Instance: {"/instance-storage/instances", "/inventory/instances", "instances"},
+	Holdings: {"/holdings-storage/holdings", "/holdings-storage/holdings", "holdingsRecords"},
+	Item:     {"/item-storage/items", "/inventory/items", "items"},
+	Loan:     {"/loan-storage/loans", "/loan-storage/loans", "loans"},
+	User:     {"/users", "/users", "users"},
+	Location: {"/locations", "/locations", "locations"},
+}
+
+// PageSize is the number of records SearchAll and Iterate ask for at a
+// time, unless told otherwise.
+const PageSize = 100
+
+// Record is a FOLIO record, as FOLIO's JSON has it.
+type Record map[string]interface{}
+
+// ID returns the record's id, or "" if it has none.
+func (r Record) ID() string {
+	id, _ := r["id"].(string)
+	return id
+}
+
+// Copy returns a copy of the record, which can be changed without changing
+// the record.
+func (r Record) Copy() Record {
+	if r == nil {
+		return nil
+	}
+	return Record(copyValue(map[string]interface{}(r)).(map[string]interface{}))
+}
+
+// copyValue returns a copy of a value decoded from JSON.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			m[k] = copyValue(x)
+		}
+		return m
+	case Record:
+		return copyValue(map[string]interface{}(v))
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, x := range v {
+			s[i] = copyValue(x)
+		}
+		return s
+	}
+	return v
+}
+
+// endpoint returns where records of the kind are kept.
+func endpoint(kind Kind) (struct{ storage, delete, list string }, error) {
+	e, ok := endpoints[kind]
+	if !ok {
+		return e, fmt.Errorf("unknown kind of FOLIO record %q", kind)
+	}
+	return e, nil
+}
+
+// Record gets the record of the kind with the given id; the error matches
+// ErrNotFound if there is none.
+func (c *Client) Record(ctx context.Context, kind Kind, id string) (Record, error) {
+	e, err := endpoint(kind)
+	if err != nil {
+		return nil, err
+	}
+	var r Record
+	if err := c.Get(ctx, e.storage+"/"+url.PathEscape(id), &r); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			c.invalidate(kind, id)
+		}
+		return nil, err
+	}
+	if c.Cache != nil {
+		c.Cache.Put(kind, r)
+	}
+	return r, nil
+}
+
+// Search returns the records of the kind that match the CQL query (see
+// Exact and Quote), from the offset'th one, at most limit of them, along
+// with the total number that match.  A query of "" matches every record.
+func (c *Client) Search(ctx context.Context, kind Kind, query string, offset, limit int) ([]Record, int, error) {
+	e, err := endpoint(kind)
+	if err != nil {
+		return nil, 0, err
+	}
+	v := url.Values{}
+	if query != "" {
+		v.Set("query", query)
+	}
+	v.Set("offset", fmt.Sprint(offset))
+	v.Set("limit", fmt.Sprint(limit))
+	var answer map[string]interface{}
+	if err := c.Get(ctx, e.storage+"?"+v.Encode(), &answer); err != nil {
+		return nil, 0, err
+	}
+	list, _ := answer[e.list].([]interface{})
+	records := make([]Record, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			records = append(records, Record(m))
+		}
+	}
+	total := len(records)
+	if n, ok := answer["totalRecords"].(float64); ok {
+		total = int(n)
+	}
+	return records, total, nil
+}
+
+// SearchAll calls each for every record of the kind that matches the query,
+// getting them PageSize at a time.  It stops at the first error from each.
+// The query should sort the records (with "sortBy id", for instance) if
+// they may change while it runs, so that pages don't overlap; Iterate can
+// page through large result sets more quickly.
+func (c *Client) SearchAll(ctx context.Context, kind Kind, query string, each func(Record) error) error {
+	it := c.Iterate(ctx, kind, query, IterateOptions{})
+	for it.Next() {
+		if err := each(it.Record()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Create makes a new record of the kind, and returns its id.  FOLIO makes
+// up the id if the record has none.
+func (c *Client) Create(ctx context.Context, kind Kind, r Record) (string, error) {
+	e, err := endpoint(kind)
+	if err != nil {
+		return "", err
+	}
+	var created Record
+	if err := c.Post(ctx, e.storage, r, &created); err != nil {
+		return "", err
+	}
+	id := created.ID()
+	if id == "" {
+		id = r.ID()
+	}
+	if id == "" {
+		return "", fmt.Errorf("POST %s: FOLIO's answer has no id", e.storage)
+	}
+	if created == nil {
+		created = r
+	}
+	return id, c.audit(ctx, Created, kind, id, nil, created)
+}
+
+// Update replaces the record of the kind that has r's id with r.  FOLIO
+// refuses the change if r's version (_version) is out of date, when
+// optimistic locking is turned on.
+func (c *Client) Update(ctx context.Context, kind Kind, r Record) error {
+	e, err := endpoint(kind)
+	if err != nil {
+		return err
+	}
+	if r.ID() == "" {
+		return fmt.Errorf("unable to update a FOLIO %s record without an id", kind)
+	}
+	before, err := c.before(ctx, kind, r.ID())
+	if err != nil {
+		return err
+	}
+	err = c.Put(ctx, e.storage+"/"+url.PathEscape(r.ID()), r)
+	c.invalidate(kind, r.ID())
+	if err != nil {
+		return err
+	}
+	return c.audit(ctx, Updated, kind, r.ID(), before, r)
+}
+
+// DeleteRecord deletes the record of the kind with the given id.
+func (c *Client) DeleteRecord(ctx context.Context, kind Kind, id string) error {
+	e, err := endpoint(kind)
+	if err != nil {
+		return err
+	}
+	before, err := c.before(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	err = c.Delete(ctx, e.delete+"/"+url.PathEscape(id))
+	c.invalidate(kind, id)
+	if err != nil {
+		return err
+	}
+	return c.audit(ctx, Deleted, kind, id, before, nil)
+}
@@ -0,0 +1,56 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"macos-systray-widget/identifiers"
+)
+
+// anyKind is the order the kinds of records are tried in for identifiers
+// that can be of any kind, such as record ids, as Foliage tries them.
+var anyKind = []Kind{Item, Instance, Holdings, Loan, User}
+
+// Resolve finds the record the identifier is of, and returns its kind and
+// the record, by trying the kinds of identifiers the identifier looks like,
+// in order (see Client.Identifiers); with kinds, it looks only for records
+// of those kinds.  The error matches ErrNotFound if no record has it.
+// Records are looked up with Lookup, so may come from the cache.
+func (c *Client) Resolve(ctx context.Context, id string, kinds ...Kind) (Kind, Record, error) {
+	types := c.Identifiers
+	if types == nil {
+		types = identifiers.Default()
+	}
+	wanted := func(k Kind) bool {
+		for _, w := range kinds {
+			if w == k {
+				return true
+			}
+		}
+		return len(kinds) == 0
+	}
+	matched := false
+	for _, t := range types.Matching(id) {
+		try := anyKind
+		if t.Kind != "" {
+			try = []Kind{Kind(t.Kind)}
+		}
+		for _, kind := range try {
+			if !wanted(kind) {
+				continue
+			}
+			matched = true
+			r, err := c.Lookup(ctx, kind, t.Index, id)
+			if err == nil {
+				return kind, r, nil
+			} else if !errors.Is(err, ErrNotFound) {
+				return "", nil, err
+			}
+		}
+	}
+	if !matched {
+		return "", nil, notFound(fmt.Sprintf("%s is not any kind of identifier this tenant has", id))
+	}
+	return "", nil, notFound(fmt.Sprintf("no record has the identifier %s", id))
+}
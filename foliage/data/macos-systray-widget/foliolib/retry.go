@@ -0,0 +1,116 @@
+package foliolib
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy says when and how often a request is tried again.  The wait
+// before the nth retry is Backoff doubled n-1 times, up to MaxBackoff,
+// unless FOLIO says how long to wait.
+type RetryPolicy struct {
+	MaxRetries int           // How many times a request is tried again.
+	Backoff    time.Duration // The wait before the first retry.
+	MaxBackoff time.Duration // The longest wait, or 0 for no limit.
+}
+
+// DefaultRetry is the retry policy of new clients.
+var DefaultRetry = RetryPolicy{MaxRetries: 8, Backoff: retryFactor, MaxBackoff: time.Minute}
+
+// wait returns how long to wait before the retry'th retry (counting from 1).
+func (p RetryPolicy) wait(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// Idempotency is a hint about what trying a request again does, which says
+// which failures it is retried after.
+type Idempotency int
+
+const (
+	// NotIdempotent requests, such as creating a record, could be carried out
+	// twice if they were tried again after FOLIO may have acted on them, so
+	// they are only retried when FOLIO refused them because of its rate
+	// limit.
+	NotIdempotent Idempotency = iota
+
+	// Idempotent requests, such as getting or replacing a record, are also
+	// retried after a timeout, a network error or an error from a gateway
+	// (502, 503 or 504), since doing them twice is the same as doing them
+	// once.
+	Idempotent
+
+	// DeleteOnce requests are Idempotent deletions.  If a retry is answered
+	// with 404 (not found) after FOLIO may have acted on an earlier try,
+	// the earlier try is taken to have deleted the record, and the request
+	// succeeds.  Without this, a deletion that times out after FOLIO has
+	// carried it out is reported as a failure.
+	DeleteOnce
+)
+
+// retryable reports whether a request with the hint should be tried again
+// after it failed with err (a network error) or was answered with status,
+// and whether FOLIO may have acted on it.
+func retryable(hint Idempotency, err error, status int) (retry, uncertain bool) {
+	if err != nil {
+		return hint != NotIdempotent && !badCertificate(err), true
+	}
+	switch status {
+	case http.StatusTooManyRequests:
+		return true, false
+	case http.StatusServiceUnavailable:
+		return hint != NotIdempotent, false
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return hint != NotIdempotent, true
+	}
+	return false, false
+}
+
+// retryAfter returns how long FOLIO's answer says to wait, or 0.
+func retryAfter(resp *http.Response) time.Duration {
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 0
+}
+
+// sleep waits for d, or until the context is done, in which case it returns
+// the context's error.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// timedOut reports whether err is a timeout.
+func timedOut(err error) bool {
+	var nerr net.Error
+	return errors.As(err, &nerr) && nerr.Timeout()
+}
+
+// badCertificate reports whether err is a problem with the server's TLS
+// certificate, which trying again won't fix.
+func badCertificate(err error) bool {
+	var (
+		unknown  x509.UnknownAuthorityError
+		invalid  x509.CertificateInvalidError
+		hostname x509.HostnameError
+	)
+	return errors.As(err, &unknown) || errors.As(err, &invalid) || errors.As(err, &hostname)
+}
@@ -0,0 +1,147 @@
+package foliolib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// answers returns a handler that answers the requests it gets with the
+// statuses in turn, repeating the last one, and a function that returns how
+// many requests it has had.
+func answers(statuses ...int) (http.Handler, func() int) {
+	var mu sync.Mutex
+	n := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := statuses[len(statuses)-1]
+		if n < len(statuses) {
+			status = statuses[n]
+		}
+		n++
+		mu.Unlock()
+		w.WriteHeader(status)
+	})
+	return h, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return n
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		hint     Idempotency
+		statuses []int
+		tries    int
+		want     error // nil for success, or what the error should match.
+	}{
+		{"get after unavailable", http.MethodGet, Idempotent, []int{503, 200}, 2, nil},
+		{"get after bad gateway", http.MethodGet, Idempotent, []int{502, 504, 200}, 3, nil},
+		{"get after rate limit", http.MethodGet, Idempotent, []int{429, 200}, 2, nil},
+		{"get gives up", http.MethodGet, Idempotent, []int{503}, 4, &Error{}},
+		{"get not found", http.MethodGet, Idempotent, []int{404}, 1, ErrNotFound},
+		{"post after rate limit", http.MethodPost, NotIdempotent, []int{429, 201}, 2, nil},
+		{"post not after unavailable", http.MethodPost, NotIdempotent, []int{503, 201}, 1, &Error{}},
+		{"post not after gateway timeout", http.MethodPost, NotIdempotent, []int{504, 201}, 1, &Error{}},
+		{"delete after gateway timeout", http.MethodDelete, DeleteOnce, []int{504, 204}, 2, nil},
+		{"delete already done", http.MethodDelete, DeleteOnce, []int{504, 404}, 2, nil},
+		{"delete unavailable, then not found", http.MethodDelete, DeleteOnce, []int{503, 404}, 2, ErrNotFound},
+		{"delete rate limited, then not found", http.MethodDelete, DeleteOnce, []int{429, 404}, 2, ErrNotFound},
+		{"delete not found", http.MethodDelete, DeleteOnce, []int{404}, 1, ErrNotFound},
+		{"idempotent delete not found", http.MethodDelete, Idempotent, []int{504, 404}, 2, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, tries := answers(tt.statuses...)
+			c := testClient(t, h)
+			err := c.Request(context.Background(), tt.method, "/items/1", tt.hint, nil, nil)
+			var ferr *Error
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("got error %v, want success", err)
+			case tt.want == nil:
+			case errors.As(tt.want, &ferr):
+				if !errors.As(err, &ferr) {
+					t.Errorf("got error %v, want an *Error", err)
+				}
+			case !errors.Is(err, tt.want):
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+			if n := tries(); n != tt.tries {
+				t.Errorf("made %d tries, want %d", n, tt.tries)
+			}
+		})
+	}
+}
+
+// TestDeleteOnceAfterTimeout checks the case DeleteOnce is for: FOLIO
+// deletes the record, but answers too late, so that the retry finds nothing
+// to delete.
+func TestDeleteOnceAfterTimeout(t *testing.T) {
+	var mu sync.Mutex
+	deleted := false
+	c := testClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		already := deleted
+		deleted = true
+		mu.Unlock()
+		if already {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	c.HTTP.Timeout = 50 * time.Millisecond
+	if err := c.Delete(context.Background(), "/inventory/items/1"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+
+	mu.Lock()
+	deleted = false
+	mu.Unlock()
+	err := c.Request(context.Background(), http.MethodDelete, "/inventory/items/1", Idempotent, nil, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Idempotent delete: got %v, want an error matching ErrNotFound", err)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{9, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.wait(tt.retry); got != tt.want {
+			t.Errorf("wait(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	h, tries := answers(503)
+	c := testClient(t, h)
+	c.Retry = RetryPolicy{MaxRetries: 8, Backoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := c.Get(ctx, "/items/1", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context's", err)
+	}
+	if tries() != 1 {
+		t.Errorf("made %d tries, want 1", tries())
+	}
+}
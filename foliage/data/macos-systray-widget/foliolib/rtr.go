@@ -0,0 +1,106 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"macos-systray-widget/keyring"
+)
+
+// How long before the access token expires the client refreshes it.
+const refreshAhead = time.Minute
+
+// ErrSessionExpired is returned when the refresh token has expired or been
+// used up, so that the user has to log in again.
+var ErrSessionExpired = errors.New("the FOLIO session has expired; log in again")
+
+// Tokens are the tokens the client uses.  Without refresh token rotation,
+// there is only an access token, which doesn't expire.
+type Tokens struct {
+	Access         string    `json:"access"`
+	AccessExpires  time.Time `json:"access_expires"`
+	Refresh        string    `json:"refresh,omitempty"`
+	RefreshExpires time.Time `json:"refresh_expires"`
+}
+
+// TokenStore keeps a client's tokens between runs.  Save is called each time
+// they change, which with refresh token rotation is every few minutes.
+type TokenStore interface {
+	Load() (Tokens, error)
+	Save(Tokens) error
+}
+
+// Restore makes the client use the tokens in its store, refreshing them if
+// the access token has expired.  It returns ErrSessionExpired if the
+// refresh token has expired too.
+func (c *Client) Restore(ctx context.Context) error {
+	if c.Store == nil {
+		return errors.New("the FOLIO client has nowhere to keep tokens")
+	}
+	t, err := c.Store.Load()
+	if err != nil {
+		return err
+	}
+	if t.Refresh != "" && !t.RefreshExpires.IsZero() && time.Now().After(t.RefreshExpires) {
+		return ErrSessionExpired
+	}
+	c.setTokens(t, false)
+	if t.Refresh != "" && time.Until(t.AccessExpires) < refreshAhead {
+		return c.refresh(ctx, t.Access)
+	}
+	return nil
+}
+
+// refresh gets new tokens with the refresh token, unless another goroutine
+// already has since the access token old was in use.
+func (c *Client) refresh(ctx context.Context, old string) error {
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+	t := c.Tokens()
+	if t.Access != old {
+		return nil
+	}
+	if !t.RefreshExpires.IsZero() && time.Now().After(t.RefreshExpires) {
+		return ErrSessionExpired
+	}
+	nt, err := c.auth().Refresh(ctx, c, t)
+	var ferr *Error
+	if errors.As(err, &ferr) && ferr.Status >= 400 && ferr.Status < 500 && ferr.Status != http.StatusTooManyRequests {
+		return fmt.Errorf("%w (%v)", ErrSessionExpired, err)
+	} else if err != nil {
+		return err
+	}
+	return c.setTokens(nt, true)
+}
+
+// KeyringStore keeps tokens in the system's credential store (see package
+// keyring), as JSON in the secret for the service and account.  The
+// account should say which FOLIO, tenant and user the tokens are for.
+type KeyringStore struct {
+	Service string
+	Account string
+}
+
+// Load reads the tokens from the credential store.
+func (s KeyringStore) Load() (Tokens, error) {
+	var t Tokens
+	secret, err := keyring.Get(s.Service, s.Account)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal([]byte(secret), &t)
+	return t, err
+}
+
+// Save writes the tokens in the credential store.
+func (s KeyringStore) Save(t Tokens) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return keyring.Set(s.Service, s.Account, string(data))
+}
@@ -0,0 +1,226 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStore is a TokenStore that keeps the tokens in memory.
+type memStore struct {
+	mu     sync.Mutex
+	tokens Tokens
+	saves  int
+}
+
+func (s *memStore) Load() (Tokens, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens, nil
+}
+
+func (s *memStore) Save(t Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = t
+	s.saves++
+	return nil
+}
+
+// okapi is a test Okapi with refresh token rotation: each refresh token can
+// be used once, and gives the next access token and refresh token.  If
+// legacy is true, it has only the older login, without expiry.
+type okapi struct {
+	mu        sync.Mutex
+	legacy    bool
+	access    string          // The access token it accepts.
+	used      map[string]bool // Refresh tokens used up.
+	refreshes int
+	generated int
+}
+
+// issue sets cookies with new tokens.
+func (o *okapi) issue(w http.ResponseWriter, ttl time.Duration) {
+	o.generated++
+	o.access = fmt.Sprintf("access-%d", o.generated)
+	http.SetCookie(w, &http.Cookie{Name: accessCookie, Value: o.access, MaxAge: int(ttl.Seconds())})
+	http.SetCookie(w, &http.Cookie{Name: refreshCookie, Value: fmt.Sprintf("refresh-%d", o.generated), MaxAge: 3600})
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"accessTokenExpiration":  time.Now().Add(ttl).UTC().Format(time.RFC3339),
+		"refreshTokenExpiration": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+func (o *okapi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch r.URL.Path {
+	case loginPath:
+		if o.legacy {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		o.issue(w, 10*time.Minute)
+	case "/authn/login":
+		o.access = "legacy"
+		w.Header().Set("X-Okapi-Token", o.access)
+		w.WriteHeader(http.StatusCreated)
+	case refreshPath:
+		ck, err := r.Cookie(refreshCookie)
+		if err != nil || o.used[ck.Value] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if o.used == nil {
+			o.used = map[string]bool{}
+		}
+		o.used[ck.Value] = true
+		o.refreshes++
+		o.issue(w, 10*time.Minute)
+	default:
+		if r.Header.Get("X-Okapi-Token") != o.access {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}
+}
+
+func TestOkapiLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		legacy  bool
+		refresh bool
+	}{
+		{"with expiry", false, true},
+		{"legacy", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &okapi{legacy: tt.legacy}
+			c := testClient(t, o)
+			store := &memStore{}
+			c.Store = store
+			if err := c.Login(context.Background(), "diku_admin", "admin"); err != nil {
+				t.Fatal(err)
+			}
+			tokens := c.Tokens()
+			if tokens.Access != o.access || (tokens.Refresh != "") != tt.refresh {
+				t.Errorf("got tokens %+v", tokens)
+			}
+			if tt.refresh && time.Until(tokens.AccessExpires) < 9*time.Minute {
+				t.Errorf("the access token expires at %v", tokens.AccessExpires)
+			}
+			// Only tokens that can be refreshed are worth keeping.
+			if want := map[bool]int{true: 1, false: 0}[tt.refresh]; store.saves != want {
+				t.Errorf("saved the tokens %d times, want %d", store.saves, want)
+			}
+			if !c.Valid(context.Background()) {
+				t.Error("the client's token isn't valid")
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name      string
+		expires   time.Duration // When the client's access token expires.
+		stale     bool          // Whether FOLIO no longer accepts it.
+		used      bool          // Whether its refresh token has been used.
+		refreshes int
+		want      error
+	}{
+		{"fresh", time.Hour, false, false, 0, nil},
+		{"about to expire", 10 * time.Second, false, false, 1, nil},
+		{"expired", -time.Minute, true, false, 1, nil},
+		{"refused", time.Hour, true, false, 1, nil},
+		{"used up", time.Hour, true, true, 0, ErrSessionExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &okapi{access: "access-0", used: map[string]bool{}}
+			if tt.stale {
+				o.access = "newer"
+			}
+			o.used["refresh-0"] = tt.used
+			c := testClient(t, o)
+			store := &memStore{}
+			c.Store = store
+			c.setTokens(Tokens{
+				Access:         "access-0",
+				AccessExpires:  time.Now().Add(tt.expires),
+				Refresh:        "refresh-0",
+				RefreshExpires: time.Now().Add(time.Hour),
+			}, false)
+			err := c.Get(context.Background(), "/instance-statuses?limit=0", nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if o.refreshes != tt.refreshes {
+				t.Errorf("refreshed %d times, want %d", o.refreshes, tt.refreshes)
+			}
+			if tt.refreshes > 0 && (store.tokens.Access != o.access || c.Token() != o.access) {
+				t.Errorf("the client has %q and saved %q, want %q", c.Token(), store.tokens.Access, o.access)
+			}
+		})
+	}
+}
+
+// TestRefreshOnce checks that requests that find the token about to expire
+// at the same time refresh it only once, since each refresh token can only
+// be used once.
+func TestRefreshOnce(t *testing.T) {
+	o := &okapi{access: "access-0"}
+	c := testClient(t, o)
+	c.setTokens(Tokens{Access: "access-0", AccessExpires: time.Now(), Refresh: "refresh-0"}, false)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Get(context.Background(), "/instance-statuses?limit=0", nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if o.refreshes != 1 {
+		t.Errorf("refreshed %d times, want 1", o.refreshes)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    Tokens
+		refreshes int
+		want      error
+	}{
+		{"fresh", Tokens{Access: "access-0", AccessExpires: time.Now().Add(time.Hour), Refresh: "refresh-0"}, 0, nil},
+		{"access expired", Tokens{Access: "access-0", AccessExpires: time.Now().Add(-time.Hour), Refresh: "refresh-0",
+			RefreshExpires: time.Now().Add(time.Hour)}, 1, nil},
+		{"refresh expired", Tokens{Access: "access-0", AccessExpires: time.Now().Add(-time.Hour), Refresh: "refresh-0",
+			RefreshExpires: time.Now().Add(-time.Minute)}, 0, ErrSessionExpired},
+		{"legacy", Tokens{Access: "access-0"}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &okapi{access: "access-0"}
+			c := testClient(t, o)
+			c.Store = &memStore{tokens: tt.tokens}
+			err := c.Restore(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if o.refreshes != tt.refreshes {
+				t.Errorf("refreshed %d times, want %d", o.refreshes, tt.refreshes)
+			}
+		})
+	}
+}
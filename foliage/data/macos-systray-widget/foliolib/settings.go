@@ -0,0 +1,94 @@
+package foliolib
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/config"
+	"macos-systray-widget/proxy"
+	"macos-systray-widget/tenants"
+)
+
+// FromSettings returns a client for the FOLIO that Foliage's settings name,
+// with FOLIO_OKAPI_URL and FOLIO_OKAPI_TENANT_ID, and the token in
+// FOLIO_OKAPI_TOKEN, if there is one.  The setting FOLIO_AUTH says how it
+// logs in: "okapi" (the default), or "keycloak" for FOLIO's Eureka
+// platform, with the Keycloak URL in FOLIO_KEYCLOAK_URL, and the realm,
+// client and client secret in FOLIO_KEYCLOAK_REALM (by default, the tenant),
+// FOLIO_KEYCLOAK_CLIENT_ID (by default, the tenant followed by
+// "-login-application") and FOLIO_KEYCLOAK_CLIENT_SECRET.
+//
+// If the tenant is in the list of tenants the widget offers (see package
+// tenants), the limits given there replace the DefaultLimits, and the
+// client uses the TLS settings given there.  The client's requests go
+// through the proxy the settings give (see package proxy).
+func FromSettings() (*Client, error) {
+	url, tenant := config.Get("FOLIO_OKAPI_URL", ""), config.Get("FOLIO_OKAPI_TENANT_ID", "")
+	if url == "" || tenant == "" {
+		return nil, fmt.Errorf("FOLIO_OKAPI_URL and FOLIO_OKAPI_TENANT_ID need to be set")
+	}
+	if err := proxy.Configure(); err != nil {
+		return nil, err
+	}
+	c := New(url, tenant)
+	switch kind := strings.ToLower(config.Get("FOLIO_AUTH", "")); kind {
+	case "", "okapi":
+	case "keycloak", "eureka":
+		k := KeycloakAuth{
+			URL:          config.Get("FOLIO_KEYCLOAK_URL", ""),
+			Realm:        config.Get("FOLIO_KEYCLOAK_REALM", ""),
+			ClientID:     config.Get("FOLIO_KEYCLOAK_CLIENT_ID", ""),
+			ClientSecret: config.Get("FOLIO_KEYCLOAK_CLIENT_SECRET", ""),
+		}
+		if k.URL == "" {
+			return nil, fmt.Errorf("FOLIO_KEYCLOAK_URL needs to be set when FOLIO_AUTH is %q", kind)
+		}
+		if k.Realm == "" {
+			k.Realm = tenant
+		}
+		if k.ClientID == "" {
+			k.ClientID = tenant + "-login-application"
+		}
+		c.Auth = k
+	default:
+		return nil, fmt.Errorf("unknown FOLIO_AUTH %q; it can be okapi or keycloak", kind)
+	}
+	if err := c.useProfile(); err != nil {
+		return nil, err
+	}
+	if token := config.Get("FOLIO_OKAPI_TOKEN", ""); token != "" {
+		c.SetToken(token)
+	}
+	return c, nil
+}
+
+// useProfile sets the limits, TLS settings and kinds of identifiers from the
+// client's entry in the list of tenants.
+func (c *Client) useProfile() error {
+	path, err := tenants.ConfiguredPath()
+	if err != nil {
+		return err
+	}
+	list, err := tenants.Load(path)
+	if err != nil {
+		return err
+	}
+	t, ok := tenants.Find(list, c.URL, c.Tenant)
+	if !ok {
+		return nil
+	}
+	l := c.Limits()
+	if t.RateLimit > 0 {
+		l.Rate, l.Burst = t.RateLimit, int(t.RateLimit) // A second's worth.
+	}
+	if t.RateBurst > 0 {
+		l.Burst = t.RateBurst
+	}
+	if t.MaxInFlight > 0 {
+		l.MaxInFlight = t.MaxInFlight
+	}
+	c.SetLimits(l)
+	c.Identifiers = t.Identifiers
+	return c.SetTLS(t.TLS(filepath.Dir(path)))
+}
@@ -0,0 +1,76 @@
+package foliolib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"macos-systray-widget/marc"
+)
+
+// SourceRecord gets the MARC record that Source Record Storage (SRS) keeps
+// for the instance with the id.  The error matches ErrNotFound if the
+// instance has none, as instances catalogued in FOLIO itself don't, or if
+// its record has been deleted.
+func (c *Client) SourceRecord(ctx context.Context, instanceID string) (*marc.Record, error) {
+	var answer struct {
+		RecordType   string `json:"recordType"`
+		State        string `json:"state"`
+		Deleted      bool   `json:"deleted"`
+		ParsedRecord struct {
+			Content json.RawMessage `json:"content"`
+		} `json:"parsedRecord"`
+	}
+	path := "/source-storage/records/" + url.PathEscape(instanceID) + "/formatted?idType=INSTANCE"
+	if err := c.Get(ctx, path, &answer); err != nil {
+		return nil, err
+	}
+	if answer.Deleted || answer.State == "DELETED" {
+		return nil, notFound(fmt.Sprintf("the MARC record of instance %s has been deleted", instanceID))
+	}
+	if answer.RecordType != "" && answer.RecordType != "MARC_BIB" {
+		return nil, fmt.Errorf("the source record of instance %s is %s, not MARC", instanceID, answer.RecordType)
+	}
+	content := []byte(answer.ParsedRecord.Content)
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		content = []byte(text) // Some releases give the content as a string of JSON.
+	}
+	if len(content) == 0 {
+		return nil, errors.New("SRS's answer has no MARC record for instance " + instanceID)
+	}
+	return marc.Parse(content)
+}
+
+// InstanceOf returns the id of the instance the record of the kind with the
+// id belongs to: the record itself, for an instance, or the instance of a
+// holdings record or of an item's holdings record.
+func (c *Client) InstanceOf(ctx context.Context, kind Kind, id string) (string, error) {
+	switch kind {
+	case Instance:
+		return id, nil
+	case Item:
+		r, err := c.Record(ctx, Item, id)
+		if err != nil {
+			return "", err
+		}
+		holdings, _ := r["holdingsRecordId"].(string)
+		if holdings == "" {
+			return "", fmt.Errorf("item %s has no holdings record", id)
+		}
+		return c.InstanceOf(ctx, Holdings, holdings)
+	case Holdings:
+		r, err := c.Record(ctx, Holdings, id)
+		if err != nil {
+			return "", err
+		}
+		instance, _ := r["instanceId"].(string)
+		if instance == "" {
+			return "", fmt.Errorf("holdings record %s has no instance", id)
+		}
+		return instance, nil
+	}
+	return "", fmt.Errorf("%s records don't belong to instances", kind)
+}
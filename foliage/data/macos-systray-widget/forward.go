@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+
+	"macos-systray-widget/crash"
+)
+
+// handleForwarded applies the arguments of a later copy of the widget, which
+// has found us running and exited in our favor.  Usually the later copy was
+// started by a new Foliage process, so we watch that process from now on.
+// If the new Foliage is at a different URL, it is another instance rather
+// than a replacement for ours, and it gets a submenu of its own.  A
+// different menu can't be adopted while we run, so that is noted and
+// otherwise ignored.  With --open, the later copy was started to bring
+// Foliage to the front, as a desktop keyboard shortcut can do; with
+// --open-url, to open a foliage:// link; with --look-up, to look up text
+// selected in another application; with --drop, to start a batch job on
+// files dropped on the widget's drop target; and with --job, to start a
+// batch job for a script.
+func handleForwarded(args []string) error {
+	defer crash.Recover()
+	o, err := parseFlags(os.Args[0], args, flag.ContinueOnError)
+	if err != nil {
+		return err
+	}
+	log.Printf("received arguments from another copy of the widget: %q", args)
+	// Only a URL given on the command line names another instance; the
+	// settings can't tell a copy run by hand which Foliage we're watching.
+	if url := resolveURL(o.url, o.port); (o.url != "" || o.port > 0) && url != serverURL() {
+		// Another Foliage instance, such as one using a test tenant.
+		if !adoptInstance(url, o.pid) {
+			log.Printf("no room in the menu for the Foliage at %s", url)
+		}
+		return nil
+	}
+	if o.menu != "" {
+		log.Printf("ignoring menu file %s", o.menu)
+	}
+	if o.command != "" {
+		foliageCommand = o.command
+	}
+	if o.openURL != "" {
+		go openLink(o.openURL)
+	} else if o.lookUp != "" {
+		go lookUpSelection(o.lookUp)
+	} else if len(o.dropFiles) > 0 {
+		go dropFiles(o.dropFiles, o.job)
+	} else if o.open {
+		go bringToFront()
+	}
+	if o.pid != 0 && o.pid != serverPid() {
+		if !processAlive(o.pid) {
+			return fmt.Errorf("process %d is not running", o.pid)
+		}
+		log.Printf("watching Foliage process %d", o.pid)
+		setServerPid(o.pid)
+		atomic.StoreInt32(&stopped, 0)
+		showItems(restartItems, false)
+		go watchProcess(o.pid)
+	}
+	return nil
+}
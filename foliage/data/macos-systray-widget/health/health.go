@@ -0,0 +1,123 @@
+// Package health polls the Foliage web server to find out whether it is up.
+package health
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// State describes what we know about the Foliage server.
+type State int
+
+const (
+	// Starting means the server has not responded yet, but it has not been
+	// long enough since we started watching it to conclude it's not coming.
+	Starting State = iota
+	// Running means the server responded to the most recent check.
+	Running
+	// NotResponding means the server failed to respond, either after having
+	// been running or after the startup grace period ran out.
+	NotResponding
+)
+
+func (s State) String() string {
+	switch s {
+	case Starting:
+		return "starting"
+	case Running:
+		return "running"
+	case NotResponding:
+		return "not responding"
+	}
+	return "unknown"
+}
+
+// Default values for Checker fields.
+const (
+	DefaultInterval     = 5 * time.Second
+	DefaultTimeout      = 3 * time.Second
+	DefaultStartupGrace = 60 * time.Second
+)
+
+// Checker periodically polls an HTTP endpoint of the Foliage server.
+type Checker struct {
+	URL          string        // Address to poll.
+	Interval     time.Duration // Time between checks.
+	Timeout      time.Duration // Time allowed for the server to respond.
+	StartupGrace time.Duration // How long to wait for the first response.
+
+	mu     sync.Mutex // Guards URL once Run has started.
+	client *http.Client
+}
+
+// NewChecker returns a Checker for url that uses the default timings.
+func NewChecker(url string) *Checker {
+	return &Checker{
+		URL:          url,
+		Interval:     DefaultInterval,
+		Timeout:      DefaultTimeout,
+		StartupGrace: DefaultStartupGrace,
+	}
+}
+
+// Check makes a single request to the server and returns true if the
+// server answered with a non-error HTTP status code.
+func (c *Checker) Check() bool {
+	c.mu.Lock()
+	if c.client == nil {
+		c.client = &http.Client{Timeout: c.Timeout}
+	}
+	url := c.URL
+	c.mu.Unlock()
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < 400
+}
+
+// SetURL changes the address to poll, for a server that has moved to another
+// port.  Unlike setting URL, it can be done while Run is running.
+func (c *Checker) SetURL(url string) {
+	c.mu.Lock()
+	c.URL = url
+	c.mu.Unlock()
+}
+
+// Run polls the server forever, sending the new state on the changes
+// channel every time the state changes.  The initial state, Starting, is
+// sent before the first check is made.
+func (c *Checker) Run(changes chan<- State) {
+	state := Starting
+	changes <- state
+	started := time.Now()
+	for {
+		next := state
+		if c.Check() {
+			next = Running
+		} else if state == Running || time.Since(started) > c.StartupGrace {
+			next = NotResponding
+		}
+		if next != state {
+			state = next
+			changes <- state
+		}
+		time.Sleep(c.Interval)
+	}
+}
+
+// Reachable returns nil if a web server answers at url, whatever it answers,
+// or else the reason it couldn't be reached.  It is for finding out whether
+// the network connection to another server, such as FOLIO's, is working.
+// The transport, if not nil, is for servers that need TLS settings of their
+// own.
+func Reachable(url string, timeout time.Duration, transport http.RoundTripper) error {
+	resp, err := (&http.Client{Timeout: timeout, Transport: transport}).Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
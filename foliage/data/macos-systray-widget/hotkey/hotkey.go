@@ -0,0 +1,97 @@
+// Package hotkey registers system-wide keyboard shortcuts, which work
+// whichever application is in front.  Shortcuts are written the way menus
+// show them, such as "Ctrl+Shift+F"; see Parse.
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrNotSupported is returned on systems where shortcuts can't be registered.
+var ErrNotSupported = errors.New("global keyboard shortcuts are not supported on this system")
+
+// Modifier keys.
+const (
+	Ctrl = 1 << iota
+	Shift
+	Alt // Option on macOS
+	Cmd // the Windows key on Windows
+)
+
+// Key is a keyboard shortcut: a key and the modifier keys held down with it.
+type Key struct {
+	Mods int
+	// Name is an upper-case letter, a digit, or F1 to F12.
+	Name string
+}
+
+// String returns the shortcut in the form Parse accepts.
+func (k Key) String() string {
+	var parts []string
+	for _, m := range []struct {
+		mod  int
+		name string
+	}{{Ctrl, "Ctrl"}, {Alt, "Alt"}, {Shift, "Shift"}, {Cmd, "Cmd"}} {
+		if k.Mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, k.Name), "+")
+}
+
+// Parse reads a shortcut such as "Ctrl+Shift+F".  The names of the modifiers
+// are not case-sensitive; they are Ctrl (or Control), Shift, Alt (or
+// Option), Cmd (or Command, Win, or Super), and CmdOrCtrl, which means Cmd
+// on macOS and Ctrl elsewhere.  At least one modifier is required, so that
+// the shortcut doesn't take a key away from every other application.
+func Parse(s string) (Key, error) {
+	var k Key
+	parts := strings.Split(s, "+")
+	for _, p := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "ctrl", "control":
+			k.Mods |= Ctrl
+		case "shift":
+			k.Mods |= Shift
+		case "alt", "option", "opt":
+			k.Mods |= Alt
+		case "cmd", "command", "win", "super":
+			k.Mods |= Cmd
+		case "cmdorctrl":
+			if runtime.GOOS == "darwin" {
+				k.Mods |= Cmd
+			} else {
+				k.Mods |= Ctrl
+			}
+		default:
+			return Key{}, fmt.Errorf("unknown modifier %q in shortcut %q", p, s)
+		}
+	}
+	k.Name = strings.ToUpper(strings.TrimSpace(parts[len(parts)-1]))
+	if _, ok := keyCode(k.Name); !ok {
+		return Key{}, fmt.Errorf("unknown key %q in shortcut %q", k.Name, s)
+	}
+	if k.Mods == 0 {
+		return Key{}, fmt.Errorf("shortcut %q needs at least one modifier key", s)
+	}
+	return k, nil
+}
+
+// Register calls pressed each time the user presses the shortcut k.  It
+// fails if another application has already registered the same shortcut.
+func Register(k Key, pressed func()) error {
+	code, ok := keyCode(k.Name)
+	if !ok {
+		return fmt.Errorf("unknown key %q", k.Name)
+	}
+	return register(k.Mods, code, pressed)
+}
+
+// keyCode returns the system's code for the named key.
+func keyCode(name string) (uint32, bool) {
+	code, ok := keyCodes[name]
+	return code, ok
+}
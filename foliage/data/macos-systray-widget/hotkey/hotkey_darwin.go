@@ -0,0 +1,109 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package hotkey
+
+/*
+#cgo LDFLAGS: -framework Carbon
+#include <Carbon/Carbon.h>
+#include <dispatch/dispatch.h>
+#include <pthread.h>
+
+extern void hotkeyPressed(UInt32 id);
+
+static OSStatus handleHotKey(EventHandlerCallRef next, EventRef event, void *data) {
+	EventHotKeyID hk;
+	OSStatus err = GetEventParameter(event, kEventParamDirectObject, typeEventHotKeyID,
+		NULL, sizeof(hk), NULL, &hk);
+	if (err == noErr) {
+		hotkeyPressed(hk.id);
+	}
+	return err;
+}
+
+// registerHotKey registers the shortcut with Carbon, which is still the only
+// API for global shortcuts that doesn't need the user to grant the program
+// accessibility access.  Carbon events are delivered on the main thread, so
+// that is where the handler is installed.
+static OSStatus registerHotKey(UInt32 code, UInt32 mods, UInt32 id) {
+	__block OSStatus err = noErr;
+	void (^block)(void) = ^{
+		static int installed = 0;
+		if (!installed) {
+			EventTypeSpec spec = {kEventClassKeyboard, kEventHotKeyPressed};
+			err = InstallApplicationEventHandler(NewEventHandlerUPP(handleHotKey), 1, &spec, NULL, NULL);
+			if (err != noErr) {
+				return;
+			}
+			installed = 1;
+		}
+		EventHotKeyID hk = {'FOLI', id};
+		EventHotKeyRef ref;
+		err = RegisterEventHotKey(code, mods, hk, GetApplicationEventTarget(), 0, &ref);
+	};
+	if (pthread_main_np()) {
+		block();
+	} else {
+		dispatch_sync(dispatch_get_main_queue(), block);
+	}
+	return err;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Carbon's virtual key codes, which are positions on the ANSI keyboard
+// rather than characters.
+var keyCodes = map[string]uint32{
+	"A": 0x00, "S": 0x01, "D": 0x02, "F": 0x03, "H": 0x04, "G": 0x05, "Z": 0x06,
+	"X": 0x07, "C": 0x08, "V": 0x09, "B": 0x0B, "Q": 0x0C, "W": 0x0D, "E": 0x0E,
+	"R": 0x0F, "Y": 0x10, "T": 0x11, "1": 0x12, "2": 0x13, "3": 0x14, "4": 0x15,
+	"6": 0x16, "5": 0x17, "9": 0x19, "7": 0x1A, "8": 0x1C, "0": 0x1D, "O": 0x1F,
+	"U": 0x20, "I": 0x22, "P": 0x23, "L": 0x25, "J": 0x26, "K": 0x28, "N": 0x2D,
+	"M":  0x2E,
+	"F1": 0x7A, "F2": 0x78, "F3": 0x63, "F4": 0x76, "F5": 0x60, "F6": 0x61,
+	"F7": 0x62, "F8": 0x64, "F9": 0x65, "F10": 0x6D, "F11": 0x67, "F12": 0x6F,
+}
+
+var (
+	handlersMu sync.Mutex
+	handlers   = map[uint32]func(){}
+)
+
+//export hotkeyPressed
+func hotkeyPressed(id C.UInt32) {
+	handlersMu.Lock()
+	pressed := handlers[uint32(id)]
+	handlersMu.Unlock()
+	if pressed != nil {
+		// Don't hold up the main thread.
+		go pressed()
+	}
+}
+
+func register(mods int, code uint32, pressed func()) error {
+	var flags C.UInt32
+	for _, m := range []struct {
+		mod  int
+		flag C.UInt32
+	}{{Ctrl, C.controlKey}, {Shift, C.shiftKey}, {Alt, C.optionKey}, {Cmd, C.cmdKey}} {
+		if mods&m.mod != 0 {
+			flags |= m.flag
+		}
+	}
+	handlersMu.Lock()
+	id := uint32(len(handlers) + 1)
+	handlers[id] = pressed
+	handlersMu.Unlock()
+	if status := C.registerHotKey(C.UInt32(code), flags, C.UInt32(id)); status != C.noErr {
+		handlersMu.Lock()
+		delete(handlers, id)
+		handlersMu.Unlock()
+		return fmt.Errorf("unable to register the shortcut (error %d)", int(status))
+	}
+	return nil
+}
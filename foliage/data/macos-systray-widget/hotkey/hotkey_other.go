@@ -0,0 +1,21 @@
+//go:build !windows && (!darwin || !cgo)
+// +build !windows
+// +build !darwin !cgo
+
+package hotkey
+
+// Parse still checks the names of keys, so accept the usual ones.
+var keyCodes = map[string]uint32{}
+
+func init() {
+	for _, c := range "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789" {
+		keyCodes[string(c)] = 0
+	}
+	for _, f := range []string{"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12"} {
+		keyCodes[f] = 0
+	}
+}
+
+func register(mods int, code uint32, pressed func()) error {
+	return ErrNotSupported
+}
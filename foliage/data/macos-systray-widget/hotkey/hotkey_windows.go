@@ -0,0 +1,88 @@
+package hotkey
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32             = syscall.NewLazyDLL("user32.dll")
+	procRegisterHotKey = user32.NewProc("RegisterHotKey")
+	procGetMessageW    = user32.NewProc("GetMessageW")
+)
+
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modWin      = 0x0008
+	modNoRepeat = 0x4000
+	wmHotkey    = 0x0312
+)
+
+// The virtual-key codes of the keys we accept.  Those of letters and digits
+// are their ASCII codes.
+var keyCodes = map[string]uint32{}
+
+func init() {
+	for c := 'A'; c <= 'Z'; c++ {
+		keyCodes[string(c)] = uint32(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		keyCodes[string(c)] = uint32(c)
+	}
+	for i := 1; i <= 12; i++ {
+		keyCodes["F"+strconv.Itoa(i)] = uint32(0x70 + i - 1) // VK_F1 onward
+	}
+}
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// Each shortcut gets a thread of its own: WM_HOTKEY messages go to the
+// message queue of the thread that registered the shortcut, so that thread
+// has to be the one that waits for them.
+func register(mods int, code uint32, pressed func()) error {
+	var flags uint32 = modNoRepeat
+	for _, m := range []struct {
+		mod  int
+		flag uint32
+	}{{Ctrl, modControl}, {Shift, modShift}, {Alt, modAlt}, {Cmd, modWin}} {
+		if mods&m.mod != 0 {
+			flags |= m.flag
+		}
+	}
+	result := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		r, _, err := procRegisterHotKey.Call(0, 1, uintptr(flags), uintptr(code))
+		if r == 0 {
+			if err == syscall.Errno(0) {
+				err = errors.New("RegisterHotKey failed")
+			}
+			result <- err
+			return
+		}
+		result <- nil
+		var m msg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(r) <= 0 {
+				return
+			}
+			if m.message == wmHotkey {
+				go pressed()
+			}
+		}
+	}()
+	return <-result
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/localcert"
+)
+
+// runHTTPS runs the https subcommand, which serves Foliage over HTTPS on
+// this computer, for sites whose security policies forbid unencrypted web
+// applications even on localhost.  It makes a certificate authority of the
+// user's own and a certificate for localhost signed by it (see package
+// localcert), in the directory "certificates" in Foliage's user data
+// directory, and then passes requests, including PyWebIO's WebSocket
+// connections, to Foliage at its usual URL.  With --trust, it first adds the
+// certificate authority to the user's trusted certificates.  The setting
+// FOLIAGE_HTTPS_PORT gives the port to listen on, 8443 by default.  It runs
+// until it is interrupted.
+func runHTTPS(args []string) int {
+	fs := subcommandFlags("https")
+	listen := fs.Int("listen", settingInt("FOLIAGE_HTTPS_PORT", 8443), "the port to serve HTTPS on")
+	urlFlag := fs.String("url", "", "the URL of Foliage, over HTTP")
+	port := fs.Int("port", 0, "the port of Foliage on this computer")
+	trust := fs.Bool("trust", false, "add the certificate authority to the trusted certificates")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	backend, err := foliageBackend(*urlFlag, *port)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	files, err := certificates(*trust)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	server := &http.Server{
+		Addr:              net.JoinHostPort("127.0.0.1", strconv.Itoa(*listen)),
+		Handler:           foliageProxy(backend, "https"),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Serving Foliage (%s) at https://localhost:%d/\n", backend, *listen)
+	if err := server.ListenAndServeTLS(files.Cert, files.Key); err != nil {
+		log.Printf("unable to serve HTTPS: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// foliageBackend returns the URL of Foliage for the https and share
+// subcommands to pass requests to, from their options or the settings.
+func foliageBackend(urlFlag string, port int) (*url.URL, error) {
+	raw := resolveURL(urlFlag, port)
+	backend, err := url.Parse(raw)
+	if err != nil || backend.Scheme != "http" {
+		return nil, fmt.Errorf("Foliage's URL should start with http://, not %q", raw)
+	}
+	return backend, nil
+}
+
+// certificates makes the certificates for serving Foliage over HTTPS, if
+// need be, for localhost and the extra hosts given, and if trust is true,
+// adds the certificate authority to the user's trusted certificates.
+func certificates(trust bool, extra ...string) (localcert.Files, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return localcert.Files{}, fmt.Errorf("unable to find where to keep certificates: %w", err)
+	}
+	files, err := localcert.Ensure(filepath.Join(dir, "certificates"), extra...)
+	if err != nil {
+		return files, err
+	}
+	if files.Created {
+		fmt.Printf("Made a certificate authority for this computer: %s\n", files.CA)
+	}
+	if trust {
+		if err := localcert.Trust(files.CA); err != nil {
+			return files, fmt.Errorf("unable to trust %s: %w", files.CA, err)
+		}
+		fmt.Println("The certificate authority is now trusted.")
+	} else if files.Created {
+		fmt.Printf("Run \"%s https --trust\" to make browsers on this computer trust it.\n", programName())
+	}
+	return files, nil
+}
+
+// foliageProxy returns a handler that passes requests on to Foliage at
+// backend, including the WebSocket connections PyWebIO makes, telling it
+// that they came by the given protocol ("http" or "https").  The Host
+// header is passed on as it is, because PyWebIO only accepts WebSocket
+// connections from pages whose origin matches it.
+func foliageProxy(backend *url.URL, proto string) *httputil.ReverseProxy {
+	proxy := httputil.NewSingleHostReverseProxy(backend)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		r.Header.Set("X-Forwarded-Proto", proto)
+	}
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		debugf("unable to reach Foliage at %s: %v", backend, err)
+		http.Error(w, "Foliage is not running at "+backend.String()+".", http.StatusBadGateway)
+	}
+	return proxy
+}
@@ -0,0 +1,28 @@
+package icon
+
+import _ "embed"
+
+// The built-in icon, drawn at several sizes.
+var (
+	//go:embed icon-32.png
+	icon32 []byte
+	//go:embed icon-64.png
+	icon64 []byte
+	//go:embed icon-128.png
+	icon128 []byte
+	//go:embed icon-256.png
+	icon256 []byte
+)
+
+// Data is the built-in tray icon, at the sizes and in the format the system
+// tray needs on this platform (see Make).
+var Data = mustMake(icon32, icon64, icon128, icon256)
+
+// mustMake returns Make of the images, which are known to be good.
+func mustMake(images ...[]byte) []byte {
+	data, err := Make(images...)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
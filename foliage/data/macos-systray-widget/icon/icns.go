@@ -0,0 +1,69 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The macOS menu bar is given icons in .icns format, so that it can choose
+// between images at different sizes: a 16-pixel image on ordinary displays,
+// and a 32-pixel one on Retina displays.  An .icns file is a list of
+// entries, each a four-letter type, its length and its data; the types used
+// here hold PNG images, one for each size.
+
+// The types of .icns entries with PNG images, by size.
+var icnsTypes = map[int]string{
+	16:   "icp4",
+	32:   "icp5",
+	64:   "icp6",
+	128:  "ic07",
+	256:  "ic08",
+	512:  "ic09",
+	1024: "ic10",
+}
+
+// isICNS returns true if data starts with an .icns file header.
+func isICNS(data []byte) bool {
+	return len(data) >= 8 && bytes.Equal(data[:4], []byte("icns"))
+}
+
+// pngsFromICNS returns the PNG-format images stored in the .icns data.
+// Entries of other kinds are left out.
+func pngsFromICNS(data []byte) [][]byte {
+	if !isICNS(data) {
+		return nil
+	}
+	var images [][]byte
+	for rest := data[8:]; len(rest) >= 8; {
+		length := int(binary.BigEndian.Uint32(rest[4:8]))
+		if length < 8 || length > len(rest) {
+			break
+		}
+		if img := rest[8:length]; bytes.HasPrefix(img, pngSignature) {
+			images = append(images, img)
+		}
+		rest = rest[length:]
+	}
+	return images
+}
+
+// icnsFromPNGs returns .icns data containing the PNG images given, which are
+// square, with the sizes given.  Images of sizes .icns files have no type
+// for are left out.
+func icnsFromPNGs(images [][]byte, sizes []int) []byte {
+	var entries bytes.Buffer
+	for i, img := range images {
+		kind, ok := icnsTypes[sizes[i]]
+		if !ok {
+			continue
+		}
+		entries.WriteString(kind)
+		binary.Write(&entries, binary.BigEndian, uint32(8+len(img)))
+		entries.Write(img)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("icns")
+	binary.Write(&buf, binary.BigEndian, uint32(8+entries.Len()))
+	buf.Write(entries.Bytes())
+	return buf.Bytes()
+}
@@ -0,0 +1,68 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The Windows tray needs icons in .ico format, whereas everything else uses
+// PNG (or, on macOS, .icns; see icns.go).  An .ico file is a directory of
+// images at different sizes, and since Windows Vista, the images can be
+// stored in PNG format.  The functions here let the rest of this package
+// work with PNG images whether the icon data is in .ico or .png format.
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// isICO returns true if data starts with an .ico file header.
+func isICO(data []byte) bool {
+	return len(data) >= 6 && bytes.Equal(data[:4], []byte{0, 0, 1, 0})
+}
+
+// pngsFromICO returns the PNG-format images stored in the .ico data.  Images
+// in the older bitmap format are left out.
+func pngsFromICO(data []byte) [][]byte {
+	if !isICO(data) {
+		return nil
+	}
+	var images [][]byte
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	for i := 0; i < count; i++ {
+		entry := 6 + 16*i
+		if entry+16 > len(data) {
+			break
+		}
+		length := int(binary.LittleEndian.Uint32(data[entry+8:]))
+		offset := int(binary.LittleEndian.Uint32(data[entry+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			continue
+		}
+		if img := data[offset : offset+length]; bytes.HasPrefix(img, pngSignature) {
+			images = append(images, img)
+		}
+	}
+	return images
+}
+
+// icoFromPNGs returns .ico data containing the PNG images given, which are
+// square, with the sizes given.
+func icoFromPNGs(images [][]byte, sizes []int) []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	binary.Write(&buf, le, [3]uint16{0, 1, uint16(len(images))})
+	offset := 6 + 16*len(images)
+	for i, img := range images {
+		// Sizes of 256 or more are stored as 0 in the directory entry.
+		size := byte(sizes[i])
+		if sizes[i] >= 256 {
+			size = 0
+		}
+		buf.Write([]byte{size, size, 0, 0})
+		binary.Write(&buf, le, [2]uint16{1, 32})
+		binary.Write(&buf, le, [2]uint32{uint32(len(img)), uint32(offset)})
+		offset += len(img)
+	}
+	for _, img := range images {
+		buf.Write(img)
+	}
+	return buf.Bytes()
+}
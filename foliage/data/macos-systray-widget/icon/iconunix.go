@@ -1,616 +0,0 @@
-//+build linux darwin
-
-// File generated by 2goarray v0.1.0 (http://github.com/cratonica/2goarray)
-
-package icon
-
-var Data []byte = []byte{
-	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 
-	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x40, 
-	0x08, 0x06, 0x00, 0x00, 0x00, 0xaa, 0x69, 0x71, 0xde, 0x00, 0x00, 0x00, 
-	0x04, 0x67, 0x41, 0x4d, 0x41, 0x00, 0x00, 0xb1, 0x8f, 0x0b, 0xfc, 0x61, 
-	0x05, 0x00, 0x00, 0x00, 0x20, 0x63, 0x48, 0x52, 0x4d, 0x00, 0x00, 0x7a, 
-	0x26, 0x00, 0x00, 0x80, 0x84, 0x00, 0x00, 0xfa, 0x00, 0x00, 0x00, 0x80, 
-	0xe8, 0x00, 0x00, 0x75, 0x30, 0x00, 0x00, 0xea, 0x60, 0x00, 0x00, 0x3a, 
-	0x98, 0x00, 0x00, 0x17, 0x70, 0x9c, 0xba, 0x51, 0x3c, 0x00, 0x00, 0x00, 
-	0x09, 0x70, 0x48, 0x59, 0x73, 0x00, 0x00, 0x0b, 0x13, 0x00, 0x00, 0x0b, 
-	0x13, 0x01, 0x00, 0x9a, 0x9c, 0x18, 0x00, 0x00, 0x01, 0x59, 0x69, 0x54, 
-	0x58, 0x74, 0x58, 0x4d, 0x4c, 0x3a, 0x63, 0x6f, 0x6d, 0x2e, 0x61, 0x64, 
-	0x6f, 0x62, 0x65, 0x2e, 0x78, 0x6d, 0x70, 0x00, 0x00, 0x00, 0x00, 0x00, 
-	0x3c, 0x78, 0x3a, 0x78, 0x6d, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x20, 0x78, 
-	0x6d, 0x6c, 0x6e, 0x73, 0x3a, 0x78, 0x3d, 0x22, 0x61, 0x64, 0x6f, 0x62, 
-	0x65, 0x3a, 0x6e, 0x73, 0x3a, 0x6d, 0x65, 0x74, 0x61, 0x2f, 0x22, 0x20, 
-	0x78, 0x3a, 0x78, 0x6d, 0x70, 0x74, 0x6b, 0x3d, 0x22, 0x58, 0x4d, 0x50, 
-	0x20, 0x43, 0x6f, 0x72, 0x65, 0x20, 0x35, 0x2e, 0x34, 0x2e, 0x30, 0x22, 
-	0x3e, 0x0a, 0x20, 0x20, 0x20, 0x3c, 0x72, 0x64, 0x66, 0x3a, 0x52, 0x44, 
-	0x46, 0x20, 0x78, 0x6d, 0x6c, 0x6e, 0x73, 0x3a, 0x72, 0x64, 0x66, 0x3d, 
-	0x22, 0x68, 0x74, 0x74, 0x70, 0x3a, 0x2f, 0x2f, 0x77, 0x77, 0x77, 0x2e, 
-	0x77, 0x33, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x31, 0x39, 0x39, 0x39, 0x2f, 
-	0x30, 0x32, 0x2f, 0x32, 0x32, 0x2d, 0x72, 0x64, 0x66, 0x2d, 0x73, 0x79, 
-	0x6e, 0x74, 0x61, 0x78, 0x2d, 0x6e, 0x73, 0x23, 0x22, 0x3e, 0x0a, 0x20, 
-	0x20, 0x20, 0x20, 0x20, 0x20, 0x3c, 0x72, 0x64, 0x66, 0x3a, 0x44, 0x65, 
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x20, 0x72, 0x64, 
-	0x66, 0x3a, 0x61, 0x62, 0x6f, 0x75, 0x74, 0x3d, 0x22, 0x22, 0x0a, 0x20, 
-	0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x20, 0x78, 
-	0x6d, 0x6c, 0x6e, 0x73, 0x3a, 0x74, 0x69, 0x66, 0x66, 0x3d, 0x22, 0x68, 
-	0x74, 0x74, 0x70, 0x3a, 0x2f, 0x2f, 0x6e, 0x73, 0x2e, 0x61, 0x64, 0x6f, 
-	0x62, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x66, 0x66, 0x2f, 
-	0x31, 0x2e, 0x30, 0x2f, 0x22, 0x3e, 0x0a, 0x20, 0x20, 0x20, 0x20, 0x20, 
-	0x20, 0x20, 0x20, 0x20, 0x3c, 0x74, 0x69, 0x66, 0x66, 0x3a, 0x4f, 0x72, 
-	0x69, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x3e, 0x31, 0x3c, 
-	0x2f, 0x74, 0x69, 0x66, 0x66, 0x3a, 0x4f, 0x72, 0x69, 0x65, 0x6e, 0x74, 
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x3e, 0x0a, 0x20, 0x20, 0x20, 0x20, 0x20, 
-	0x20, 0x3c, 0x2f, 0x72, 0x64, 0x66, 0x3a, 0x44, 0x65, 0x73, 0x63, 0x72, 
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x3e, 0x0a, 0x20, 0x20, 0x20, 0x3c, 
-	0x2f, 0x72, 0x64, 0x66, 0x3a, 0x52, 0x44, 0x46, 0x3e, 0x0a, 0x3c, 0x2f, 
-	0x78, 0x3a, 0x78, 0x6d, 0x70, 0x6d, 0x65, 0x74, 0x61, 0x3e, 0x0a, 0x4c, 
-	0xc2, 0x27, 0x59, 0x00, 0x00, 0x1a, 0x7a, 0x49, 0x44, 0x41, 0x54, 0x78, 
-	0x01, 0xe5, 0x9b, 0x07, 0x98, 0x55, 0xd5, 0xb5, 0x80, 0xd7, 0x3e, 0xf7, 
-	0xde, 0x29, 0x14, 0x05, 0x84, 0x41, 0x81, 0xb9, 0x83, 0x88, 0x18, 0x44, 
-	0x11, 0xc5, 0x1a, 0x0d, 0x60, 0x4f, 0x62, 0x8b, 0x8d, 0x67, 0x89, 0x82, 
-	0x18, 0x35, 0xa2, 0x33, 0x03, 0x16, 0x2c, 0xef, 0x25, 0x4c, 0xa2, 0x46, 
-	0x6c, 0x61, 0x00, 0x25, 0xd8, 0x10, 0x34, 0x6a, 0x08, 0xc6, 0x9a, 0x58, 
-	0x22, 0xbe, 0x60, 0xef, 0xa2, 0x89, 0x60, 0x8c, 0x02, 0x53, 0x10, 0x91, 
-	0x5e, 0x94, 0x29, 0xf7, 0xde, 0xb3, 0xdf, 0xbf, 0xd6, 0xbd, 0x67, 0x18, 
-	0xc6, 0x01, 0xc1, 0xe0, 0xcb, 0x8b, 0xef, 0xf0, 0xcd, 0x3d, 0xe7, 0xec, 
-	0xbd, 0xf6, 0xde, 0xab, 0xaf, 0xb5, 0xd7, 0x3e, 0x88, 0x70, 0xf9, 0x53, 
-	0x25, 0xe6, 0x2b, 0x86, 0xc4, 0xbd, 0x88, 0xd3, 0xf7, 0xe6, 0x97, 0xaf, 
-	0x90, 0x78, 0xf4, 0xee, 0x4b, 0x93, 0xa7, 0xf0, 0x77, 0x81, 0xbe, 0xfb, 
-	0x53, 0xfb, 0xe5, 0x45, 0xed, 0xad, 0xdd, 0xa3, 0xb9, 0xfc, 0xe8, 0x1e, 
-	0xdd, 0xfd, 0x45, 0xdd, 0x77, 0xb0, 0x31, 0xad, 0xcc, 0xdf, 0xda, 0xd8, 
-	0xff, 0xd5, 0x36, 0xef, 0x37, 0x26, 0x5a, 0x99, 0x11, 0x21, 0x10, 0x11, 
-	0xef, 0xcb, 0x77, 0xee, 0xea, 0xcb, 0x8a, 0x1f, 0xf6, 0x97, 0xf7, 0xf4, 
-	0x30, 0xe0, 0x33, 0x25, 0xca, 0x08, 0x6a, 0x06, 0x1b, 0x8d, 0x89, 0xee, 
-	0xbe, 0xb4, 0x77, 0xbe, 0xc1, 0x94, 0x27, 0x7f, 0xef, 0xcb, 0x92, 0xd3, 
-	0xec, 0xb9, 0x19, 0x33, 0x23, 0xb8, 0x4d, 0xdd, 0x59, 0x3b, 0xb0, 0xbf, 
-	0x16, 0xf8, 0x6d, 0x0a, 0xfe, 0xeb, 0xb6, 0x9b, 0xc4, 0xfd, 0xa8, 0x92, 
-	0x83, 0x24, 0xf4, 0xfb, 0x4a, 0x2c, 0xbc, 0xcb, 0x8d, 0x5f, 0x54, 0xc7, 
-	0xc2, 0x2a, 0xf5, 0xd0, 0x55, 0xd0, 0x5a, 0x96, 0x3c, 0x9c, 0xe7, 0x3f, 
-	0x49, 0x41, 0x90, 0x2f, 0xf5, 0xfe, 0x79, 0x29, 0x74, 0x83, 0xa5, 0x2e, 
-	0xfc, 0x44, 0xea, 0xd3, 0x7d, 0xdc, 0x1d, 0x8b, 0xd7, 0xab, 0xa4, 0x99, 
-	0x84, 0xdb, 0x86, 0x4b, 0x99, 0xe8, 0x66, 0x4a, 0xc6, 0x8f, 0xec, 0xd1, 
-	0x5b, 0xe2, 0xc1, 0x1b, 0x74, 0x3f, 0xe5, 0x26, 0xd6, 0x9e, 0xe9, 0xcf, 
-	0x1f, 0x98, 0x70, 0x77, 0xbc, 0x9d, 0xda, 0x00, 0xb9, 0x65, 0x4f, 0xca, 
-	0x08, 0xc5, 0x65, 0xcb, 0xa0, 0xb7, 0x0e, 0x2a, 0xf0, 0x23, 0xbb, 0xb4, 
-	0x13, 0xef, 0x1f, 0x97, 0xf6, 0xf1, 0x89, 0x92, 0x09, 0x16, 0xf8, 0xd2, 
-	0x9e, 0x07, 0xb2, 0x58, 0x3a, 0x4b, 0x7c, 0xc9, 0xa1, 0x92, 0x1f, 0xcc, 
-	0x62, 0xca, 0x7c, 0x69, 0x90, 0x03, 0x81, 0xbb, 0x45, 0xea, 0xc3, 0x69, 
-	0x90, 0xdb, 0x4e, 0x0a, 0xe2, 0x63, 0x6d, 0xa9, 0x8a, 0x21, 0xa6, 0x31, 
-	0x66, 0x46, 0x30, 0xa3, 0x89, 0xf8, 0x51, 0x25, 0xfb, 0x40, 0xfc, 0x47, 
-	0x30, 0xae, 0x23, 0xcd, 0x7d, 0x15, 0x76, 0x4b, 0x88, 0x57, 0x62, 0x15, 
-	0xd6, 0x97, 0x95, 0xfc, 0x1a, 0xe6, 0x3f, 0xaf, 0xf8, 0x19, 0x2e, 0xb9, 
-	0x76, 0xed, 0xdb, 0x96, 0x57, 0x20, 0xe9, 0x64, 0x03, 0x04, 0x35, 0xc8, 
-	0xba, 0xf4, 0x67, 0x4c, 0xdc, 0x51, 0x0a, 0xe4, 0x55, 0x3f, 0xaa, 0xe7, 
-	0x00, 0x5b, 0xc4, 0xfb, 0x5f, 0x98, 0x81, 0x78, 0xe9, 0xe7, 0x26, 0x54, 
-	0xbd, 0x8e, 0xac, 0x93, 0xe8, 0xc5, 0x3c, 0xfa, 0xce, 0xe2, 0xef, 0x64, 
-	0x83, 0x91, 0xd9, 0x61, 0x44, 0xf4, 0x46, 0x9a, 0x10, 0xfa, 0x29, 0x92, 
-	0xa0, 0xa5, 0xc1, 0xaf, 0x83, 0x09, 0x7b, 0xfb, 0x51, 0xc5, 0xc3, 0x15, 
-	0x1e, 0x02, 0x9b, 0x7c, 0x4a, 0x76, 0xfc, 0x86, 0xdf, 0x48, 0xd2, 0xac, 
-	0xdf, 0x13, 0x56, 0x8e, 0x96, 0x4e, 0x89, 0x41, 0x92, 0x28, 0x7c, 0x68, 
-	0x03, 0xc4, 0xb6, 0x7f, 0x0a, 0x4c, 0x2a, 0x81, 0x3b, 0x1b, 0x26, 0x14, 
-	0x31, 0xfd, 0xf5, 0xd2, 0x10, 0xbe, 0x0f, 0x9a, 0x73, 0xfc, 0xa5, 0xdd, 
-	0x3a, 0xf3, 0xde, 0x01, 0x89, 0xcf, 0x87, 0xf0, 0x0e, 0x7e, 0x64, 0xb7, 
-	0x62, 0x71, 0xe1, 0xc7, 0x58, 0xe6, 0x60, 0x37, 0xa9, 0xe6, 0x09, 0x18, 
-	0xb3, 0x0a, 0xd3, 0xd9, 0x49, 0xa5, 0x63, 0xea, 0x5e, 0x56, 0xbc, 0x07, 
-	0xbe, 0xa1, 0x93, 0x3d, 0xe3, 0x2c, 0x65, 0xfb, 0xf8, 0x7e, 0x92, 0xf6, 
-	0xb7, 0x4b, 0x46, 0x46, 0x1b, 0x23, 0x42, 0x77, 0xb1, 0xa1, 0x5f, 0x41, 
-	0xcb, 0x26, 0xaf, 0x21, 0x26, 0x7d, 0x09, 0x33, 0x23, 0xc0, 0xa7, 0x5a, 
-	0x56, 0xa4, 0x4e, 0x45, 0x03, 0x8f, 0x46, 0x13, 0xae, 0x37, 0x2d, 0xc0, 
-	0x84, 0x36, 0x39, 0xf4, 0x6b, 0x76, 0xe0, 0x68, 0x86, 0xc4, 0x5d, 0x65, 
-	0xf5, 0x7f, 0xa3, 0x78, 0x57, 0x63, 0xb9, 0x15, 0x10, 0xf9, 0x13, 0x2c, 
-	0x7f, 0x92, 0xa4, 0x63, 0x03, 0x21, 0xf2, 0x29, 0xe6, 0x45, 0x62, 0xfe, 
-	0x28, 0x89, 0xc7, 0xa7, 0xf3, 0x58, 0x05, 0x62, 0x25, 0xb6, 0x96, 0x97, 
-	0x0f, 0xb0, 0xca, 0xbd, 0xf5, 0xd9, 0x97, 0x27, 0x7f, 0x2c, 0xed, 0xe2, 
-	0x7f, 0x93, 0x30, 0x76, 0xb7, 0xf5, 0x39, 0xf7, 0x33, 0xfc, 0x84, 0x60, 
-	0x52, 0x95, 0xee, 0xd6, 0xea, 0xbb, 0x65, 0x5d, 0xa6, 0x4a, 0xf2, 0x82, 
-	0x81, 0xfe, 0xa2, 0x92, 0x7d, 0x54, 0x4b, 0x54, 0x63, 0x0c, 0xae, 0xe5, 
-	0x4f, 0xc5, 0xec, 0x1c, 0x73, 0xdc, 0x50, 0x70, 0x29, 0x91, 0x86, 0xf4, 
-	0x6c, 0xa9, 0x4b, 0x0d, 0xe0, 0xf9, 0x4a, 0x7f, 0x51, 0xf1, 0x20, 0x15, 
-	0xd6, 0xe6, 0x34, 0xa8, 0xe5, 0x74, 0x5b, 0xf2, 0x0e, 0xc7, 0x51, 0x61, 
-	0xd4, 0xd2, 0x4d, 0xa8, 0x19, 0x27, 0x99, 0xf0, 0x01, 0x49, 0xc4, 0x5f, 
-	0x13, 0xdf, 0x78, 0x8d, 0x9b, 0x50, 0xfb, 0x0c, 0x8c, 0x98, 0x89, 0xfb, 
-	0x29, 0x11, 0xef, 0xfe, 0xca, 0x64, 0xbb, 0x42, 0xd1, 0x7e, 0x30, 0x25, 
-	0xe7, 0xf0, 0xdc, 0x32, 0xf1, 0x61, 0x09, 0xea, 0xda, 0x41, 0x12, 0xc1, 
-	0x7d, 0xf2, 0x79, 0xe6, 0x23, 0x48, 0x3b, 0x02, 0x69, 0x8d, 0x95, 0x84, 
-	0xf4, 0x97, 0xc6, 0xf0, 0xcf, 0xee, 0xd6, 0xaa, 0xbf, 0x1b, 0x12, 0x4e, 
-	0xee, 0x95, 0x36, 0x2c, 0x15, 0xf3, 0xdf, 0xb3, 0xf7, 0x7e, 0x43, 0xe0, 
-	0xc3, 0xc6, 0x97, 0x99, 0x91, 0x32, 0xa7, 0xb4, 0xe4, 0x18, 0x7a, 0x16, 
-	0xe3, 0x42, 0x3f, 0x94, 0xc2, 0xd8, 0x59, 0x6e, 0xd2, 0x27, 0xef, 0x49, 
-	0x18, 0x9e, 0x06, 0x13, 0xa6, 0xb3, 0xb0, 0x43, 0x44, 0x69, 0xbd, 0x6f, 
-	0x3c, 0xfa, 0xeb, 0xbf, 0x99, 0x77, 0xb5, 0x49, 0x47, 0x27, 0x7b, 0xa9, 
-	0xa7, 0x06, 0xf1, 0x59, 0xe2, 0xf2, 0x3e, 0xf1, 0xa5, 0xdd, 0x7b, 0xb8, 
-	0x5b, 0x6b, 0xdf, 0x82, 0x09, 0x6f, 0xb3, 0xdc, 0x18, 0xc8, 0x7e, 0x07, 
-	0xf4, 0xae, 0xe3, 0x5e, 0xe4, 0x2b, 0x2c, 0x07, 0xa8, 0xa3, 0xbd, 0x3b, 
-	0xea, 0x7a, 0x89, 0x2d, 0x9f, 0xc9, 0x1c, 0x44, 0xdf, 0x7b, 0xb0, 0xb2, 
-	0x02, 0x64, 0x89, 0x21, 0xfe, 0x37, 0x39, 0x47, 0x36, 0x46, 0x52, 0xc1, 
-	0x23, 0x59, 0x1f, 0xee, 0xf6, 0x32, 0xd8, 0xc5, 0xeb, 0xcc, 0x59, 0xaa, 
-	0xcd, 0x6b, 0x64, 0xd0, 0xbb, 0xf4, 0xcb, 0x11, 0xe5, 0xc2, 0x73, 0x99, 
-	0xe7, 0x57, 0xbc, 0x3d, 0x0e, 0xe3, 0xb7, 0x53, 0x78, 0x84, 0x31, 0x83, 
-	0xdf, 0xd7, 0xa4, 0x2c, 0x79, 0xa5, 0x8d, 0xcf, 0x39, 0x5e, 0x7b, 0xfe, 
-	0x27, 0x7f, 0x02, 0x7f, 0x45, 0xaf, 0xed, 0x91, 0xda, 0xeb, 0x52, 0x10, 
-	0x9b, 0xcf, 0x7d, 0x21, 0xf3, 0x7d, 0xc0, 0xe2, 0x09, 0x71, 0xb1, 0x37, 
-	0x2d, 0xde, 0xbb, 0x70, 0x84, 0x14, 0xb8, 0x03, 0x68, 0xdb, 0x07, 0x49, 
-	0x27, 0x21, 0xb0, 0xab, 0x2c, 0x5f, 0xab, 0xfe, 0x61, 0x35, 0x48, 0x25, 
-	0x41, 0xf2, 0x30, 0xfc, 0xc6, 0xe3, 0xee, 0xb6, 0x4f, 0x56, 0x00, 0xf3, 
-	0x22, 0xfd, 0x82, 0xdf, 0x48, 0x4b, 0xc2, 0xbf, 0xc0, 0xfb, 0xde, 0x10, 
-	0x33, 0x48, 0x62, 0xf1, 0x05, 0xc0, 0x30, 0xc4, 0x77, 0x57, 0x7c, 0x55, 
-	0x95, 0xd5, 0x57, 0xc0, 0xaa, 0xd0, 0x9e, 0xf5, 0xae, 0x92, 0xc5, 0xa7, 
-	0xd0, 0xdb, 0x01, 0x1f, 0x33, 0x8b, 0x71, 0x73, 0x01, 0xb5, 0x04, 0x4a, 
-	0xc7, 0x60, 0x4e, 0x10, 0xef, 0x8e, 0xd6, 0x47, 0x57, 0x31, 0x3b, 0xad, 
-	0xf7, 0x6d, 0x71, 0x05, 0xd8, 0xd8, 0xaf, 0x41, 0x74, 0x7f, 0xf9, 0x3c, 
-	0x7d, 0x2e, 0x13, 0x7e, 0x8c, 0xd3, 0x29, 0x35, 0x22, 0xe2, 0x6e, 0x47, 
-	0x16, 0xad, 0x11, 0x1f, 0xdb, 0x5f, 0xea, 0x33, 0x53, 0xa5, 0x6d, 0xac, 
-	0x87, 0x34, 0xca, 0x3f, 0x30, 0x09, 0xe8, 0x08, 0x4a, 0x80, 0x85, 0x01, 
-	0xb2, 0x23, 0xb2, 0xeb, 0x40, 0xdb, 0xe3, 0x86, 0x8c, 0x03, 0x69, 0xed, 
-	0x17, 0xa9, 0x26, 0x9f, 0x58, 0x89, 0xc6, 0x64, 0x98, 0x3b, 0x21, 0xf1, 
-	0xfa, 0x14, 0x2a, 0xad, 0xed, 0x6d, 0xf4, 0xc7, 0x57, 0xf4, 0x2c, 0x20, 
-	0xa1, 0xba, 0x07, 0x0d, 0x99, 0x0d, 0xd3, 0xef, 0xf4, 0xe5, 0xc5, 0xfb, 
-	0x69, 0x3b, 0x26, 0x75, 0x1a, 0xbf, 0xaf, 0xd9, 0x73, 0x4c, 0x35, 0x51, 
-	0x9e, 0xb0, 0x66, 0x34, 0xce, 0xdd, 0xb6, 0xb0, 0x9a, 0x67, 0xc2, 0x74, 
-	0xb1, 0x99, 0x91, 0x69, 0x8d, 0x01, 0xfe, 0x73, 0x3f, 0x18, 0x26, 0x89, 
-	0x8d, 0xc8, 0x4d, 0x6e, 0x52, 0xed, 0x54, 0x37, 0xb1, 0xe6, 0x48, 0x1c, 
-	0x4f, 0x1f, 0x69, 0xc4, 0x7b, 0xa7, 0x7d, 0x03, 0xe1, 0x2b, 0x20, 0xf1, 
-	0xb9, 0x53, 0x24, 0xf8, 0xad, 0x7c, 0x91, 0x7e, 0x8f, 0xd0, 0xa4, 0x39, 
-	0x83, 0xba, 0x45, 0x9c, 0x5f, 0xf8, 0x39, 0x52, 0x22, 0xc6, 0xab, 0x72, 
-	0x63, 0xaf, 0x7a, 0x65, 0xfc, 0xe7, 0x30, 0x44, 0xaf, 0x85, 0xf6, 0xeb, 
-	0x44, 0x25, 0xd5, 0x46, 0xc2, 0xc4, 0x76, 0x68, 0x0f, 0x60, 0xf2, 0x89, 
-	0xf9, 0x8c, 0x95, 0xe1, 0x47, 0x52, 0x18, 0x0c, 0x97, 0x7c, 0x5b, 0x1b, 
-	0x95, 0x77, 0xaf, 0x69, 0xfe, 0x01, 0x2e, 0x3d, 0x81, 0x1f, 0x8c, 0x53, 
-	0x1d, 0x07, 0x03, 0x3f, 0xc1, 0x2f, 0x3d, 0x6b, 0x99, 0xea, 0xdc, 0xb9, 
-	0x39, 0xe7, 0x28, 0xaf, 0x88, 0x0b, 0x0e, 0x00, 0x46, 0xaf, 0xec, 0x4a, 
-	0xd9, 0xe7, 0xaf, 0xfd, 0xab, 0x93, 0xcc, 0x84, 0x84, 0xa1, 0xd1, 0x0c, 
-	0x6e, 0xe2, 0xa2, 0x8f, 0x50, 0xc1, 0x9f, 0xa2, 0x94, 0x7d, 0xc9, 0xfc, 
-	0xee, 0x31, 0xc4, 0x9d, 0xdf, 0x13, 0x07, 0x79, 0x38, 0xe1, 0xac, 0x5b, 
-	0x56, 0xc2, 0x7e, 0x10, 0xc8, 0xe6, 0xc3, 0x90, 0x34, 0xc8, 0x93, 0xac, 
-	0x06, 0xd9, 0xd8, 0xee, 0x82, 0xb6, 0x39, 0x0d, 0x68, 0xb4, 0xf9, 0xbc, 
-	0x5b, 0xc5, 0x3d, 0x0f, 0xc6, 0xf4, 0xb5, 0x50, 0x28, 0xf2, 0x19, 0x0e, 
-	0x4d, 0x13, 0xab, 0xee, 0x98, 0x49, 0x1d, 0x66, 0xb1, 0x5c, 0xd2, 0x75, 
-	0xd8, 0xb9, 0xbf, 0x88, 0x54, 0xeb, 0x55, 0xf0, 0x78, 0x9f, 0xf9, 0x26, 
-	0x13, 0x31, 0xae, 0xf0, 0xa5, 0x3b, 0x76, 0xd1, 0x39, 0x48, 0x33, 0x4d, 
-	0x75, 0xf4, 0x99, 0xf5, 0xfe, 0x0e, 0x6c, 0xd2, 0x9e, 0xb7, 0xd1, 0x4f, 
-	0x80, 0xd4, 0xaf, 0x42, 0xd5, 0xd6, 0xa1, 0x8a, 0x6b, 0xe1, 0xfc, 0x21, 
-	0xd1, 0xbc, 0x84, 0xaf, 0x85, 0x6e, 0x62, 0xf5, 0x08, 0x59, 0xdb, 0xf8, 
-	0x03, 0x88, 0x9a, 0x00, 0x91, 0x47, 0x60, 0x02, 0x27, 0x63, 0x22, 0x2a, 
-	0xc9, 0xe3, 0x50, 0x57, 0x7c, 0x45, 0xe2, 0x3f, 0x78, 0xc1, 0x1f, 0x64, 
-	0x7a, 0xda, 0x38, 0x17, 0x1e, 0x26, 0x29, 0xc3, 0xb7, 0x7f, 0xf6, 0xdd, 
-	0xab, 0xda, 0x16, 0x49, 0x10, 0x9e, 0x0e, 0xc1, 0xc8, 0xcc, 0xef, 0xca, 
-	0xfb, 0x77, 0xa0, 0xea, 0xc7, 0xcc, 0x53, 0xc8, 0xf3, 0xc3, 0x6e, 0xf2, 
-	0xb2, 0xcf, 0x71, 0xbe, 0x53, 0x24, 0x15, 0xbe, 0x0c, 0x85, 0x5d, 0xdd, 
-	0xa4, 0xea, 0xfb, 0x61, 0xcc, 0x62, 0xe6, 0xae, 0xf4, 0x17, 0x97, 0xec, 
-	0xec, 0xc7, 0xec, 0xd6, 0xde, 0xe6, 0xd2, 0x9f, 0xd0, 0x7f, 0x0a, 0xae, 
-	0xd9, 0xf7, 0xb9, 0xcd, 0x18, 0xd3, 0x04, 0xb0, 0xf5, 0x0f, 0x59, 0x35, 
-	0x8a, 0x85, 0xfb, 0x33, 0xf4, 0x6f, 0xa4, 0xc3, 0x2f, 0xc2, 0x88, 0x59, 
-	0x6a, 0x93, 0xb6, 0x78, 0x59, 0xf2, 0x59, 0x7c, 0x40, 0x0c, 0x4f, 0xbf, 
-	0x17, 0x4c, 0xb8, 0x01, 0x09, 0xc4, 0x65, 0x4d, 0xea, 0x0c, 0x69, 0x17, 
-	0x6b, 0x83, 0x93, 0x3c, 0xde, 0x4d, 0x58, 0x50, 0xc3, 0x38, 0x87, 0xaf, 
-	0xd8, 0x15, 0x9b, 0x3e, 0x42, 0xf2, 0x63, 0x23, 0xa4, 0x2e, 0x9d, 0x56, 
-	0x7f, 0xc1, 0xe6, 0xe9, 0x1c, 0xa4, 0xf9, 0x02, 0x92, 0xef, 0x09, 0xcc, 
-	0x79, 0x44, 0x97, 0x39, 0xbc, 0xaf, 0xe3, 0xb9, 0x4e, 0x52, 0xa9, 0x17, 
-	0xcd, 0x27, 0x78, 0xf7, 0x31, 0xef, 0xd9, 0xcb, 0xe3, 0x4f, 0x9c, 0x3f, 
-	0xdb, 0x5e, 0x02, 0xb7, 0x2f, 0xd3, 0xbe, 0xc1, 0xfb, 0x3c, 0x69, 0x58, 
-	0xd7, 0x31, 0x9b, 0x68, 0x25, 0x87, 0xc2, 0x38, 0xc2, 0xb1, 0xb7, 0xc8, 
-	0x10, 0x0d, 0x6b, 0x79, 0x57, 0x93, 0xc1, 0x3f, 0xc4, 0x37, 0x99, 0x6b, 
-	0xb4, 0x18, 0x60, 0x80, 0xba, 0x01, 0xa2, 0xfd, 0x60, 0x88, 0x2f, 0xe7, 
-	0x5e, 0x61, 0x8b, 0xc7, 0xfc, 0x52, 0xe2, 0xb0, 0x66, 0x87, 0x47, 0xc8, 
-	0x7a, 0x0f, 0xf2, 0x9e, 0xec, 0xcf, 0xcd, 0xc0, 0xfe, 0x07, 0xc9, 0xda, 
-	0xd4, 0xa1, 0xd2, 0x36, 0xfe, 0x17, 0x5f, 0xde, 0xeb, 0x0e, 0xa4, 0x3f, 
-	0x1b, 0xa4, 0x86, 0x01, 0xf7, 0x03, 0x58, 0xc1, 0xa3, 0xab, 0x92, 0xf5, 
-	0x99, 0xd5, 0xec, 0x03, 0xa6, 0x42, 0xe4, 0x34, 0xc1, 0xfd, 0x49, 0x1b, 
-	0x98, 0xf8, 0x45, 0xfa, 0x56, 0xc6, 0xf7, 0x26, 0x44, 0x0e, 0x95, 0x74, 
-	0x6a, 0x95, 0x64, 0xe2, 0xb5, 0xc0, 0xaf, 0x61, 0x04, 0x89, 0x14, 0x4e, 
-	0x30, 0x94, 0xf5, 0xbc, 0xf7, 0x03, 0x87, 0xc3, 0x49, 0xcc, 0x9e, 0x43, 
-	0x1b, 0x17, 0xd3, 0x55, 0xe5, 0x26, 0x2c, 0xae, 0xc1, 0xf1, 0x4d, 0x42, 
-	0x63, 0x2e, 0x46, 0x33, 0xa6, 0x33, 0xbf, 0x3a, 0xdf, 0x56, 0x2f, 0xcb, 
-	0x25, 0x10, 0x07, 0x9d, 0x69, 0x05, 0x50, 0x66, 0x6c, 0x64, 0x42, 0xad, 
-	0x8c, 0x0a, 0x8c, 0xbb, 0xb9, 0x14, 0x13, 0x73, 0x98, 0xc0, 0x02, 0x77, 
-	0x83, 0xb0, 0x82, 0x16, 0x41, 0x48, 0x35, 0x7f, 0x6b, 0x59, 0x7c, 0x6f, 
-	0x08, 0xba, 0x18, 0xa5, 0xcb, 0x48, 0x2c, 0xf6, 0x82, 0x04, 0x41, 0x4c, 
-	0xd6, 0x87, 0x84, 0xa5, 0xf4, 0x73, 0xa8, 0x65, 0x8a, 0xfe, 0xee, 0xc0, 
-	0xef, 0x65, 0x52, 0x15, 0x59, 0x26, 0x0d, 0x89, 0x63, 0x70, 0xa2, 0x9f, 
-	0xa1, 0x51, 0xc3, 0x71, 0x55, 0xc0, 0x66, 0x60, 0x43, 0xfd, 0xef, 0xc1, 
-	0xe8, 0x63, 0x8b, 0x30, 0xbe, 0x30, 0x01, 0x3c, 0x89, 0x94, 0xfb, 0x42, 
-	0x17, 0x22, 0xaa, 0x0c, 0x86, 0xe9, 0x95, 0x3c, 0xad, 0xe5, 0xef, 0x09, 
-	0x7f, 0x59, 0xd7, 0xb6, 0x30, 0xa4, 0x17, 0xeb, 0xad, 0x80, 0x21, 0xcf, 
-	0x10, 0xa2, 0x2f, 0xc6, 0x44, 0x0e, 0xc1, 0x5f, 0x80, 0x03, 0x82, 0x69, 
-	0xe5, 0x52, 0xa9, 0x1b, 0x2d, 0xa4, 0xe3, 0x8c, 0x79, 0x9e, 0x84, 0x6a, 
-	0x84, 0x12, 0xff, 0x55, 0x9a, 0x10, 0x18, 0xd7, 0x34, 0xc5, 0x2c, 0x2d, 
-	0xde, 0x85, 0xbf, 0x0f, 0x65, 0xfb, 0xd8, 0xa5, 0xf2, 0x79, 0x78, 0x8b, 
-	0x2c, 0xae, 0x89, 0x4b, 0xa7, 0x9a, 0x5e, 0x38, 0xad, 0x43, 0xe0, 0xfc, 
-	0x93, 0x86, 0x78, 0x0c, 0x62, 0x42, 0x6c, 0xbc, 0x20, 0x98, 0x05, 0x0e, 
-	0x7b, 0x83, 0x64, 0x17, 0xda, 0xcf, 0xa6, 0x5f, 0x39, 0x9e, 0x31, 0x07, 
-	0xe8, 0x64, 0x81, 0x9b, 0x32, 0x7f, 0xa9, 0xa4, 0x83, 0x01, 0x68, 0xca, 
-	0xe9, 0xc0, 0xab, 0x99, 0x34, 0x4a, 0xd1, 0xb2, 0xf5, 0xdc, 0x9f, 0x37, 
-	0x2d, 0x89, 0x85, 0x7b, 0x41, 0x1c, 0x76, 0x0e, 0xf3, 0xec, 0x0a, 0xbb, 
-	0x32, 0xf3, 0x6a, 0xfa, 0xf0, 0x03, 0xb4, 0x37, 0xe4, 0xcf, 0x45, 0x76, 
-	0x03, 0xa4, 0x43, 0xfc, 0x60, 0xde, 0xfb, 0x48, 0x3a, 0xdd, 0xc3, 0x55, 
-	0xd6, 0xbc, 0x2c, 0x89, 0xfc, 0x7e, 0xac, 0xa1, 0xf3, 0x6d, 0x74, 0x19, 
-	0x0d, 0x9a, 0x47, 0x68, 0x0d, 0x22, 0x13, 0xbc, 0x05, 0x7e, 0x83, 0x98, 
-	0x7b, 0x8a, 0x3f, 0xbf, 0x5b, 0x67, 0x63, 0xca, 0x66, 0x6a, 0x0a, 0x59, 
-	0x0d, 0x20, 0xeb, 0x63, 0xc1, 0x77, 0x51, 0xcf, 0x3e, 0xb2, 0x36, 0x7d, 
-	0x35, 0x22, 0x79, 0x5a, 0x76, 0x4a, 0xde, 0x2d, 0x2b, 0x93, 0xef, 0x23, 
-	0xed, 0x47, 0x90, 0xce, 0x0e, 0xd8, 0x30, 0xb9, 0x3e, 0xe1, 0x2d, 0xed, 
-	0xe7, 0xe3, 0xd0, 0x16, 0x80, 0xec, 0x10, 0xc6, 0x34, 0x1a, 0x43, 0xb2, 
-	0x21, 0x29, 0xcc, 0xb9, 0x25, 0x0d, 0x8d, 0xc8, 0xb7, 0x6a, 0x19, 0xce, 
-	0xed, 0x77, 0x48, 0x79, 0x32, 0x9a, 0xd0, 0x56, 0x56, 0x24, 0x0f, 0x23, 
-	0xd4, 0xea, 0xd8, 0xf7, 0x25, 0x16, 0xfc, 0x0c, 0x04, 0x75, 0xac, 0x1a, 
-	0x0d, 0x97, 0xd3, 0xb1, 0x1d, 0x91, 0xd7, 0x3b, 0xf8, 0x96, 0x83, 0xf1, 
-	0x1b, 0x25, 0x24, 0x5f, 0xa7, 0xc9, 0x9a, 0xf4, 0x7d, 0x68, 0xe5, 0xce, 
-	0x1a, 0x12, 0x73, 0x70, 0x5d, 0xb9, 0x57, 0xd9, 0x73, 0xbf, 0xec, 0x6a, 
-	0x48, 0xde, 0x68, 0xb0, 0x36, 0xd7, 0x38, 0x07, 0xbc, 0x9e, 0x77, 0x37, 
-	0x56, 0xe9, 0xbc, 0x2b, 0xa5, 0x30, 0xf1, 0x4b, 0x6b, 0xbf, 0x60, 0xe0, 
-	0x26, 0x77, 0xa0, 0x41, 0x76, 0x60, 0xfc, 0x17, 0xa8, 0x7d, 0x3b, 0x08, 
-	0x7c, 0x93, 0xf7, 0x53, 0x99, 0x04, 0x5b, 0xc3, 0x3e, 0x43, 0x9c, 0x57, 
-	0xba, 0x6e, 0x00, 0xc8, 0x4e, 0xe6, 0xfd, 0x61, 0xe9, 0xd4, 0xab, 0x8f, 
-	0x74, 0x0a, 0xf6, 0x00, 0xa9, 0x5d, 0xf8, 0xdb, 0x11, 0x46, 0xdd, 0x63, 
-	0x9a, 0x11, 0x77, 0x01, 0xa1, 0x2b, 0x1f, 0x35, 0xd5, 0xe9, 0x0e, 0x8d, 
-	0x76, 0x85, 0x36, 0x77, 0x86, 0x24, 0x29, 0x4d, 0xbb, 0x93, 0xcb, 0xed, 
-	0x5d, 0xc2, 0xcb, 0x61, 0xf4, 0x61, 0x30, 0xe6, 0x24, 0x09, 0x62, 0x6b, 
-	0xf0, 0x23, 0x64, 0x93, 0xca, 0x0c, 0xe9, 0x4b, 0xa4, 0x59, 0xea, 0x26, 
-	0x2c, 0xfc, 0x0c, 0x5d, 0x1a, 0x63, 0xda, 0xe4, 0x7d, 0x31, 0xea, 0x3c, 
-	0xcb, 0x5f, 0x52, 0xe2, 0xf1, 0x09, 0x57, 0x02, 0xd3, 0x19, 0x27, 0x4b, 
-	0x28, 0x64, 0x3a, 0xb2, 0x47, 0x74, 0x51, 0xf7, 0x06, 0xb6, 0xa8, 0x26, 
-	0x55, 0xe0, 0xf3, 0x10, 0xb9, 0xc3, 0x39, 0xa8, 0x3f, 0x19, 0x28, 0x0e, 
-	0xd5, 0xfb, 0xf3, 0xfd, 0xc8, 0x9e, 0x3b, 0xe6, 0x36, 0x51, 0x59, 0x5a, 
-	0xb3, 0x48, 0x34, 0xfd, 0x46, 0x8d, 0x39, 0x49, 0x68, 0xba, 0xeb, 0x50, 
-	0x6d, 0x7f, 0x21, 0xb9, 0x40, 0x39, 0x6a, 0xe9, 0x24, 0x5e, 0x50, 0x09, 
-	0x73, 0xa6, 0x33, 0xe2, 0x5c, 0x4d, 0x41, 0x5d, 0x45, 0x55, 0x3d, 0x04, 
-	0xee, 0x89, 0xb9, 0xdc, 0x4c, 0x32, 0x33, 0x06, 0x8d, 0xa8, 0x94, 0x4c, 
-	0xac, 0x04, 0xe2, 0xc9, 0xdd, 0x71, 0x79, 0x71, 0x57, 0x88, 0xfa, 0x9f, 
-	0xa7, 0x2b, 0x18, 0x82, 0x93, 0xab, 0x3f, 0x40, 0xea, 0x95, 0x6c, 0x8f, 
-	0x8f, 0x82, 0x88, 0x23, 0xc9, 0x33, 0x9e, 0x26, 0x54, 0xce, 0x60, 0xaf, 
-	0x0f, 0x44, 0x98, 0x94, 0xa0, 0x31, 0x0f, 0x32, 0x88, 0x00, 0xb2, 0xaf, 
-	0x84, 0xe9, 0x47, 0x75, 0x9c, 0x9b, 0x50, 0x7d, 0x93, 0xac, 0x4f, 0xbf, 
-	0x06, 0x13, 0x86, 0xd0, 0x5e, 0xc4, 0xdc, 0xb7, 0x42, 0xe6, 0x2a, 0xb4, 
-	0xb1, 0x0d, 0x55, 0xa8, 0xe5, 0x6a, 0xef, 0x0a, 0x87, 0xbb, 0x36, 0x67, 
-	0xc5, 0xbc, 0xf7, 0xb0, 0xda, 0xbb, 0x68, 0xdc, 0xcf, 0x79, 0x46, 0x80, 
-	0xfe, 0x25, 0xb4, 0xed, 0xcf, 0x40, 0x20, 0xa0, 0xf0, 0x46, 0x83, 0x5d, 
-	0x3c, 0xd0, 0x60, 0xed, 0xb9, 0xd9, 0x4f, 0x96, 0x01, 0xbe, 0xe1, 0x0a, 
-	0xf9, 0x22, 0x33, 0x17, 0xdb, 0xc9, 0x02, 0x05, 0xee, 0x31, 0x38, 0xdf, 
-	0x48, 0x14, 0x78, 0x51, 0x3a, 0xe6, 0x9d, 0x4b, 0xdf, 0x3c, 0xca, 0x65, 
-	0x7d, 0x74, 0x83, 0x44, 0xfb, 0x43, 0xd8, 0xd9, 0x7b, 0xd2, 0x31, 0x71, 
-	0x29, 0x5b, 0xde, 0x2a, 0x49, 0x67, 0xae, 0xc5, 0xfc, 0xf7, 0x83, 0xda, 
-	0x81, 0x8c, 0x67, 0x0f, 0xc1, 0xec, 0x31, 0x19, 0x07, 0x22, 0xd3, 0xe5, 
-	0xc2, 0x64, 0x07, 0x5d, 0x0b, 0xa9, 0x8c, 0x46, 0x9d, 0x9f, 0x04, 0xc6, 
-	0x08, 0xc4, 0x47, 0x5f, 0x28, 0x2b, 0x53, 0x6a, 0xff, 0x63, 0x99, 0xeb, 
-	0x18, 0xc6, 0x1c, 0x0d, 0xd2, 0xfd, 0x18, 0x78, 0x4c, 0x6e, 0xa3, 0xa5, 
-	0xa3, 0xd8, 0x8d, 0xba, 0x63, 0x19, 0xdb, 0x1f, 0x62, 0x4a, 0x61, 0x52, 
-	0x7f, 0xfc, 0xd1, 0x53, 0x3a, 0x1f, 0x84, 0x67, 0xac, 0xbc, 0xa6, 0x76, 
-	0x5f, 0x56, 0x72, 0x11, 0x02, 0xeb, 0x8a, 0x46, 0x8e, 0xb2, 0xbe, 0xd0, 
-	0x9d, 0x01, 0xec, 0x63, 0xf6, 0xbc, 0x96, 0xb4, 0x9e, 0x62, 0x8e, 0xd7, 
-	0x8d, 0x5e, 0x76, 0x2b, 0x1d, 0x09, 0xdc, 0xba, 0xf5, 0xc7, 0x0a, 0x8f, 
-	0x6e, 0xd2, 0x12, 0x3c, 0x77, 0x97, 0xbd, 0x71, 0x66, 0x67, 0x21, 0x1d, 
-	0x75, 0x70, 0x82, 0x26, 0x24, 0xf0, 0xde, 0xf3, 0x65, 0x55, 0x6a, 0x14, 
-	0x93, 0xf7, 0x03, 0x51, 0xe2, 0x70, 0xac, 0x9a, 0xf6, 0x93, 0x91, 0xbc, 
-	0x93, 0xd5, 0xa9, 0xa7, 0x31, 0xdd, 0x13, 0x50, 0xe3, 0xdf, 0x4b, 0x9e, 
-	0x7b, 0x08, 0x22, 0xfe, 0x08, 0x43, 0xca, 0x6d, 0xbc, 0xee, 0x06, 0xf3, 
-	0x82, 0xb3, 0xd9, 0x05, 0xcc, 0x33, 0x04, 0x99, 0x8e, 0x39, 0x94, 0xd0, 
-	0x79, 0xe4, 0x0b, 0x6f, 0xba, 0xdf, 0xd4, 0x68, 0x86, 0x78, 0x2e, 0xf9, 
-	0x42, 0x37, 0x10, 0x1c, 0xcf, 0x33, 0xc5, 0x16, 0xd4, 0xbb, 0x4b, 0x62, 
-	0x8a, 0xac, 0x5c, 0xf7, 0x00, 0xef, 0x7a, 0xad, 0xe0, 0x4f, 0x6d, 0x5e, 
-	0xeb, 0x0d, 0x53, 0x60, 0xd0, 0x72, 0xf5, 0x21, 0x4a, 0x38, 0x0c, 0x70, 
-	0x46, 0x90, 0xd6, 0x1c, 0xc5, 0x13, 0x8d, 0xf2, 0x4f, 0x54, 0x38, 0xbb, 
-	0x9c, 0x26, 0x66, 0x1e, 0xbf, 0xc5, 0x9a, 0xd3, 0xaa, 0xea, 0x99, 0x7f, 
-	0x1c, 0x0c, 0x1a, 0x9d, 0xed, 0x1c, 0xf2, 0x25, 0x06, 0x98, 0xea, 0x9b, 
-	0x23, 0xc9, 0xd9, 0x92, 0x02, 0xb2, 0x2b, 0xeb, 0x4b, 0xea, 0xdb, 0x59, 
-	0x76, 0xa8, 0x7d, 0x15, 0x1b, 0x4b, 0xfb, 0xf2, 0x92, 0xc9, 0xe4, 0xed, 
-	0x17, 0xa2, 0xca, 0x21, 0x52, 0x0e, 0x60, 0xd4, 0x9d, 0x4c, 0x3c, 0x8b, 
-	0x10, 0x37, 0x83, 0x7b, 0x23, 0x92, 0xeb, 0x8f, 0xed, 0x66, 0xf7, 0x03, 
-	0x3a, 0xbe, 0xb4, 0xe4, 0x3a, 0xe0, 0xaf, 0x96, 0xc6, 0xcc, 0x7b, 0xf0, 
-	0xf8, 0x65, 0x90, 0x2f, 0x61, 0xef, 0x57, 0xaa, 0xd9, 0x25, 0x0c, 0xa8, 
-	0x81, 0xd8, 0xbf, 0xc3, 0x10, 0x4c, 0xa2, 0xf8, 0x0f, 0x92, 0x17, 0x3b, 
-	0x09, 0x07, 0x8b, 0x35, 0xf8, 0x73, 0x2c, 0xbc, 0x7a, 0x7f, 0x1b, 0xf0, 
-	0x57, 0xe1, 0x23, 0x5e, 0x60, 0xa6, 0x13, 0xb2, 0x88, 0xbb, 0xe3, 0x80, 
-	0xdf, 0xdb, 0x4c, 0x8a, 0x46, 0xf3, 0xfa, 0x5a, 0x74, 0x2d, 0x4b, 0x3e, 
-	0x0a, 0x3e, 0x27, 0x80, 0xcf, 0x50, 0x34, 0x65, 0xa6, 0x99, 0xc6, 0xca, 
-	0xe4, 0x5d, 0xe2, 0xf3, 0x2e, 0x60, 0x03, 0x16, 0xc8, 0xf6, 0xbd, 0x53, 
-	0x6a, 0xb6, 0xc0, 0xdd, 0x8b, 0x36, 0x8d, 0xb5, 0xf5, 0x5b, 0xe4, 0x06, 
-	0xc6, 0x00, 0x23, 0x1a, 0x86, 0xc1, 0xd9, 0x18, 0xfb, 0x39, 0xaf, 0xa1, 
-	0x23, 0xbb, 0xb0, 0x12, 0x93, 0x7c, 0x8c, 0x70, 0x74, 0x3c, 0x66, 0x00, 
-	0x2d, 0x80, 0x37, 0x86, 0xa3, 0x40, 0x76, 0x09, 0x36, 0xfd, 0x3b, 0x2a, 
-	0x3d, 0x8b, 0x31, 0x8d, 0x3d, 0x6d, 0xe7, 0x67, 0x84, 0xf7, 0xee, 0x42, 
-	0xd6, 0x48, 0x96, 0x98, 0xda, 0x0e, 0x62, 0x5e, 0x26, 0x3f, 0x68, 0x0f, 
-	0xd3, 0x8e, 0x67, 0x96, 0xa3, 0x90, 0xd9, 0x48, 0x56, 0x18, 0x4d, 0x5a, 
-	0xfc, 0x5b, 0xb4, 0x69, 0x21, 0xcb, 0x3d, 0x27, 0x9d, 0x50, 0xd7, 0x95, 
-	0xa1, 0xd6, 0x22, 0x03, 0x90, 0xee, 0xec, 0x26, 0x7d, 0xdc, 0x60, 0x12, 
-	0x2e, 0x5c, 0xaa, 0xd1, 0x43, 0x9d, 0xc4, 0xc1, 0x86, 0x47, 0x4a, 0x7a, 
-	0xab, 0xd6, 0xa8, 0xa0, 0xc0, 0xcf, 0x59, 0x68, 0x2b, 0x4d, 0x1e, 0xc7, 
-	0x7c, 0xcc, 0x4d, 0x76, 0xd9, 0x2e, 0x18, 0x4d, 0xa2, 0xb5, 0x3f, 0x75, 
-	0x83, 0x37, 0x21, 0x76, 0x2c, 0x63, 0x70, 0xd0, 0x72, 0xa0, 0x4c, 0xac, 
-	0xc9, 0x56, 0xa0, 0xca, 0x93, 0xc7, 0xea, 0xde, 0x86, 0x34, 0xfb, 0x26, 
-	0x65, 0x92, 0x0a, 0xd5, 0xe6, 0xe5, 0xa7, 0x89, 0x01, 0x51, 0x83, 0xde, 
-	0x6d, 0x21, 0x25, 0x37, 0x6b, 0x63, 0xb7, 0xd0, 0xf2, 0x7d, 0x24, 0xbd, 
-	0x10, 0xef, 0xfd, 0x73, 0x90, 0xcf, 0xc8, 0xf6, 0x84, 0xcc, 0x35, 0xe9, 
-	0xb9, 0x38, 0xca, 0x3d, 0x0c, 0x5e, 0xed, 0x50, 0x90, 0xa0, 0xc3, 0x0f, 
-	0x28, 0x93, 0x32, 0x6c, 0x9b, 0xc5, 0xcf, 0x46, 0x56, 0xa8, 0xb6, 0xdb, 
-	0x1d, 0x98, 0x02, 0xc6, 0x76, 0x85, 0x69, 0x04, 0xa7, 0xd4, 0x87, 0xc0, 
-	0x3d, 0x83, 0x7f, 0x29, 0x93, 0xba, 0xcc, 0x34, 0xe4, 0xf8, 0x08, 0x49, 
-	0xd6, 0x63, 0x98, 0xd6, 0x4e, 0xee, 0xa6, 0xaa, 0x25, 0x3a, 0x9f, 0x5e, 
-	0x10, 0x32, 0x87, 0x94, 0x9b, 0x5c, 0xc2, 0xf7, 0xd5, 0xca, 0x92, 0xfa, 
-	0x06, 0x57, 0x31, 0xb7, 0xb1, 0x99, 0xf4, 0x7f, 0x8b, 0x49, 0x52, 0xb9, 
-	0x5a, 0xf8, 0x21, 0x26, 0xb2, 0x1a, 0xfc, 0x62, 0x24, 0x54, 0x47, 0x62, 
-	0x96, 0x33, 0x09, 0xbb, 0x3d, 0x10, 0xce, 0x49, 0xec, 0x65, 0x1e, 0x89, 
-	0x68, 0x91, 0x55, 0xc9, 0x5b, 0xa4, 0xae, 0xcb, 0x65, 0x6a, 0x3a, 0xd9, 
-	0x15, 0xb2, 0xbf, 0x5f, 0xb2, 0x09, 0x6d, 0x86, 0x70, 0x2b, 0x50, 0xd8, 
-	0xf3, 0xc4, 0xea, 0x4b, 0xd5, 0x07, 0x40, 0xec, 0xb1, 0xa4, 0xa8, 0xc4, 
-	0xe9, 0xcc, 0x2c, 0x88, 0x7f, 0x55, 0x89, 0xf7, 0x17, 0x17, 0xef, 0x8b, 
-	0x8d, 0x2f, 0x25, 0x79, 0xba, 0x15, 0xd8, 0x3d, 0x40, 0xe2, 0x3e, 0x0c, 
-	0x06, 0x4f, 0x5f, 0xbd, 0x1b, 0x8c, 0x78, 0x94, 0xb0, 0x46, 0x4e, 0x2f, 
-	0x93, 0x19, 0xbf, 0x23, 0x12, 0x3d, 0x14, 0xe2, 0xc9, 0x31, 0x80, 0xca, 
-	0x0b, 0xca, 0x50, 0x5b, 0x4d, 0xa8, 0x86, 0x93, 0x13, 0x7c, 0x1f, 0x8d, 
-	0x9a, 0x29, 0x0d, 0x99, 0xff, 0xd2, 0xf5, 0xfc, 0xf0, 0x9e, 0x05, 0x7a, 
-	0x57, 0x95, 0xa7, 0xcc, 0xf6, 0x67, 0x34, 0x66, 0x3a, 0x51, 0xa7, 0xd0, 
-	0x88, 0x47, 0x03, 0x4c, 0xfa, 0xa3, 0xcd, 0xf6, 0x3f, 0x95, 0xba, 0x86, 
-	0x5a, 0x85, 0xc5, 0x7e, 0x8e, 0xc6, 0x2f, 0xb5, 0x83, 0xf8, 0x17, 0x60, 
-	0x1a, 0xc4, 0xa7, 0xef, 0x52, 0xe2, 0xad, 0x6b, 0x6e, 0xbf, 0xac, 0xc4, 
-	0x43, 0x76, 0x92, 0xf9, 0x2b, 0x06, 0x6a, 0x5b, 0x8e, 0x29, 0xd6, 0xdd, 
-	0x2a, 0x03, 0xac, 0x27, 0xf7, 0xa3, 0x1c, 0x37, 0xb5, 0xd4, 0x81, 0xa5, 
-	0xc9, 0x5f, 0x20, 0x29, 0x9c, 0x8c, 0xfc, 0xc4, 0xba, 0x03, 0x62, 0xb9, 
-	0xb0, 0x73, 0x5b, 0x93, 0x39, 0x89, 0x7c, 0xa1, 0x33, 0x48, 0x9f, 0x0d, 
-	0x63, 0x66, 0x21, 0xbd, 0xc3, 0x51, 0xff, 0x27, 0xa9, 0x25, 0xe8, 0x96, 
-	0xf6, 0x6d, 0x85, 0x75, 0x95, 0x55, 0xb3, 0xe9, 0xbf, 0x9e, 0xbf, 0xbe, 
-	0x10, 0x4f, 0xc8, 0x23, 0x22, 0x34, 0x62, 0xd5, 0x85, 0xc1, 0x85, 0xd8, 
-	0x7c, 0x3b, 0x20, 0x46, 0xc0, 0xcc, 0xcb, 0xd4, 0x71, 0x29, 0xc1, 0x36, 
-	0xbf, 0xf7, 0xd7, 0xa2, 0x2d, 0x85, 0xf2, 0x45, 0xd7, 0xac, 0xca, 0x46, 
-	0xa1, 0x2c, 0x8c, 0x0d, 0x36, 0x1f, 0x91, 0x1f, 0x3b, 0x8d, 0xb5, 0xd6, 
-	0xba, 0x09, 0x8b, 0x5e, 0xc7, 0xd4, 0x08, 0xdb, 0x98, 0xcd, 0x17, 0x99, 
-	0x5f, 0xb2, 0xc6, 0x79, 0xbe, 0x74, 0xe7, 0xbd, 0x98, 0xef, 0xbb, 0x6e, 
-	0xe6, 0x5c, 0x7c, 0x94, 0x5e, 0x5a, 0xda, 0x0b, 0x77, 0xcb, 0x3e, 0x63, 
-	0x4a, 0x2d, 0x1f, 0xa2, 0x86, 0x96, 0x77, 0xf3, 0x07, 0xab, 0xde, 0xb6, 
-	0x64, 0x83, 0x98, 0x31, 0xcf, 0xbc, 0x43, 0xc2, 0xcd, 0x65, 0xb7, 0x77, 
-	0x2b, 0x0b, 0x5d, 0x4d, 0xec, 0x1d, 0xa0, 0xdc, 0xd6, 0x6d, 0xad, 0x8e, 
-	0x25, 0xfd, 0x6c, 0x83, 0x8c, 0xef, 0x41, 0xaa, 0x5a, 0x1a, 0x1b, 0x06, 
-	0xcc, 0x73, 0x5f, 0x9a, 0x73, 0x62, 0xcd, 0xdb, 0xc4, 0xfa, 0x13, 0xc9, 
-	0x1a, 0x7e, 0x48, 0xbc, 0xaf, 0x91, 0x36, 0xf1, 0x1f, 0x00, 0x13, 0xc3, 
-	0x71, 0xde, 0x04, 0x41, 0x63, 0x72, 0x9b, 0x33, 0x4c, 0xa9, 0x7e, 0x0e, 
-	0x8c, 0xfa, 0x8b, 0xdc, 0xfe, 0x76, 0x96, 0x01, 0xdd, 0xde, 0x66, 0x52, 
-	0x2e, 0x42, 0x1b, 0x0c, 0x48, 0x13, 0x15, 0xa6, 0xf2, 0xa6, 0x55, 0xa5, 
-	0xb9, 0x38, 0x41, 0x0e, 0x76, 0xc2, 0x4b, 0x65, 0x4d, 0x70, 0x3d, 0xb8, 
-	0xfd, 0x54, 0x82, 0xcc, 0xbb, 0x08, 0xc7, 0x6a, 0x0a, 0x26, 0xf1, 0xc2, 
-	0xb6, 0x5a, 0xea, 0x8b, 0xce, 0x33, 0xb3, 0xf4, 0x30, 0xb8, 0x55, 0x1f, 
-	0xa0, 0x6b, 0xb4, 0xbc, 0x74, 0x12, 0x35, 0x0d, 0xce, 0x09, 0x77, 0x13, 
-	0x9f, 0xb9, 0x97, 0x3c, 0x60, 0x7f, 0x59, 0x95, 0xfe, 0x2b, 0x61, 0x6f, 
-	0x88, 0x74, 0xad, 0x59, 0x43, 0x44, 0xf7, 0xb6, 0xf9, 0xe0, 0x0c, 0x11, 
-	0xfb, 0x3e, 0x91, 0xcd, 0x52, 0x35, 0x73, 0x5c, 0xc7, 0x5f, 0x5b, 0x96, 
-	0x61, 0x93, 0x13, 0x2e, 0x62, 0x93, 0x4a, 0x6d, 0xb0, 0x61, 0x89, 0x32, 
-	0x2b, 0xb2, 0x65, 0x5d, 0x07, 0x84, 0xef, 0x67, 0xc3, 0x73, 0x86, 0x45, 
-	0x03, 0x0d, 0xa1, 0x29, 0x3f, 0x41, 0x32, 0x99, 0x6b, 0xb4, 0xce, 0x48, 
-	0xdf, 0x25, 0x80, 0xcc, 0x87, 0xd1, 0x8f, 0x69, 0xae, 0x6f, 0x8e, 0xb2, 
-	0x2c, 0x39, 0x03, 0xcc, 0x35, 0xeb, 0x9b, 0x69, 0xe3, 0x4b, 0x93, 0xaf, 
-	0xf0, 0xfe, 0x06, 0x3e, 0xe7, 0x49, 0xee, 0x85, 0x14, 0x71, 0x1e, 0x85, 
-	0xb9, 0x07, 0x23, 0x98, 0x57, 0xb4, 0x3f, 0xba, 0x48, 0xde, 0x86, 0x4b, 
-	0x43, 0xd1, 0xfd, 0xcd, 0xfd, 0x40, 0x93, 0x2a, 0x44, 0x40, 0x9b, 0xba, 
-	0x1b, 0xf1, 0xea, 0x41, 0x71, 0x3a, 0x48, 0xf5, 0x00, 0x59, 0xdd, 0x78, 
-	0x2d, 0xb6, 0xdf, 0x9f, 0x9c, 0xec, 0x4f, 0xe6, 0x33, 0x74, 0xe7, 0xa5, 
-	0x61, 0x2d, 0x1f, 0xe2, 0xeb, 0xc2, 0x14, 0x88, 0x94, 0xa0, 0xde, 0x77, 
-	0xf0, 0x37, 0x9e, 0xbc, 0xfe, 0x6e, 0x90, 0x7a, 0x86, 0x8c, 0xf1, 0x0f, 
-	0x12, 0x2f, 0x7c, 0x14, 0x89, 0xd5, 0xb2, 0xd7, 0xd0, 0x22, 0xec, 0x2c, 
-	0x5f, 0xda, 0x63, 0x30, 0xc4, 0x9d, 0x89, 0x0f, 0x38, 0x01, 0x69, 0x2f, 
-	0x81, 0xf8, 0x10, 0x1f, 0x51, 0x4e, 0x7e, 0xa1, 0xfd, 0x24, 0x4e, 0x6e, 
-	0x05, 0x8e, 0x74, 0x88, 0xe1, 0xb5, 0x2e, 0x9d, 0x15, 0x18, 0x6b, 0xf1, 
-	0xfe, 0xdd, 0x26, 0x5c, 0x1b, 0xd2, 0x47, 0xf0, 0x3c, 0x0c, 0x9f, 0x73, 
-	0xb5, 0xa4, 0x08, 0x9f, 0x3e, 0x95, 0x54, 0xe2, 0x95, 0x79, 0xcc, 0x31, 
-	0x1f, 0xd3, 0x3d, 0xce, 0x60, 0x9d, 0xcb, 0x93, 0x36, 0x2b, 0x10, 0xc8, 
-	0x86, 0x6b, 0x8b, 0x19, 0xa0, 0x43, 0x20, 0x34, 0x6d, 0x3e, 0x81, 0x47, 
-	0x42, 0xce, 0xcf, 0xb0, 0xfd, 0xf3, 0xf0, 0x09, 0x07, 0xb1, 0x90, 0xaa, 
-	0xee, 0xb3, 0x48, 0xf1, 0x24, 0xec, 0x5b, 0xbd, 0xac, 0x66, 0x94, 0x29, 
-	0x4c, 0xa0, 0x9e, 0xbf, 0x94, 0x39, 0xbc, 0xac, 0xf2, 0xee, 0x04, 0x9b, 
-	0x54, 0x15, 0x47, 0xd0, 0x1f, 0x97, 0xf6, 0xb1, 0xc3, 0xc9, 0x03, 0x66, 
-	0x23, 0x99, 0x49, 0x4c, 0x59, 0xc7, 0xac, 0x8f, 0x40, 0x7c, 0x80, 0x26, 
-	0x34, 0x02, 0xd3, 0x86, 0xe7, 0x13, 0xf0, 0x0f, 0x4b, 0xd0, 0xbb, 0xb8, 
-	0x2f, 0xef, 0xd1, 0xdf, 0x12, 0x1b, 0x06, 0xf2, 0xfe, 0x26, 0xfa, 0x76, 
-	0xa6, 0x3e, 0x5a, 0x74, 0xe0, 0x90, 0x16, 0x96, 0x8c, 0x61, 0xd5, 0xc1, 
-	0xb2, 0xbc, 0x66, 0xad, 0xfb, 0xf5, 0xe2, 0x5a, 0xf0, 0x99, 0x21, 0x1d, 
-	0x12, 0x44, 0x30, 0x52, 0x69, 0x47, 0xad, 0x41, 0x2f, 0xe7, 0x8a, 0xa4, 
-	0x3e, 0x66, 0x66, 0x00, 0x3a, 0xc6, 0xcc, 0xad, 0x62, 0x80, 0xcd, 0xa1, 
-	0x39, 0xc2, 0xa9, 0x78, 0x03, 0x55, 0xc7, 0x89, 0xd5, 0x77, 0x11, 0x51, 
-	0x9f, 0xe6, 0x54, 0xe8, 0x32, 0x1c, 0xd0, 0x11, 0x48, 0xbe, 0x01, 0x18, 
-	0x2a, 0x00, 0x6c, 0x8e, 0x34, 0x2d, 0xce, 0x0f, 0x34, 0xfc, 0x25, 0x68, 
-	0x5b, 0xc1, 0xa8, 0xd7, 0x41, 0xb0, 0x0b, 0x48, 0xfc, 0x04, 0xe4, 0x07, 
-	0xd3, 0xb6, 0x40, 0xd6, 0x66, 0x3e, 0xc6, 0x11, 0xae, 0xb1, 0xfd, 0xbe, 
-	0x97, 0x1b, 0x60, 0xce, 0x1e, 0xbc, 0xff, 0x03, 0xc2, 0x41, 0xd2, 0xaf, 
-	0xe6, 0x08, 0xb6, 0xd0, 0x0e, 0x68, 0xc4, 0x41, 0x70, 0x60, 0x04, 0x2b, 
-	0x0e, 0xd4, 0x24, 0x48, 0xa0, 0x5c, 0x17, 0x88, 0x1c, 0xaa, 0xd1, 0xc1, 
-	0xda, 0x32, 0xee, 0x25, 0x7c, 0xc0, 0x64, 0x8b, 0x12, 0x65, 0xc5, 0xef, 
-	0x42, 0xde, 0x50, 0xb2, 0xd5, 0x0b, 0xe5, 0xd3, 0x9a, 0x0e, 0xea, 0x87, 
-	0xf4, 0x04, 0x0c, 0x26, 0xed, 0x24, 0x85, 0x0d, 0x5a, 0x95, 0x52, 0xea, 
-	0x4d, 0x24, 0x5b, 0xc5, 0x00, 0xf5, 0x03, 0xca, 0x39, 0x5d, 0xc4, 0x6c, 
-	0x51, 0x77, 0x68, 0x05, 0xee, 0xfb, 0x84, 0xab, 0x3a, 0xe2, 0x79, 0x03, 
-	0xda, 0x90, 0x0f, 0xf2, 0x31, 0x10, 0xa9, 0x81, 0x19, 0x77, 0x22, 0xc9, 
-	0xb3, 0x58, 0xa6, 0x9b, 0x34, 0x26, 0x76, 0xa7, 0x86, 0xf8, 0x26, 0x30, 
-	0xd8, 0x24, 0x69, 0x69, 0x26, 0xf3, 0x84, 0xe4, 0x25, 0x4e, 0x21, 0x62, 
-	0xec, 0x8a, 0x84, 0x6f, 0x52, 0xdb, 0x07, 0xc9, 0x7d, 0xf8, 0x1b, 0x04, 
-	0x53, 0x77, 0x83, 0x09, 0xc7, 0xb0, 0x4c, 0x5a, 0x56, 0x66, 0x6e, 0x36, 
-	0xe2, 0xe2, 0xc4, 0x76, 0xef, 0xf7, 0xb7, 0x8a, 0xb2, 0x22, 0x5f, 0x59, 
-	0x55, 0x45, 0x96, 0xa9, 0x61, 0xee, 0x4e, 0xab, 0x1a, 0x2b, 0x50, 0xc2, 
-	0xb3, 0xa5, 0x0f, 0xde, 0x81, 0x29, 0xb3, 0xf0, 0x41, 0x14, 0x67, 0x9c, 
-	0x1e, 0xf4, 0x4c, 0x51, 0x77, 0xa9, 0xdd, 0xb2, 0xe2, 0xa3, 0x7e, 0xac, 
-	0x9d, 0xa7, 0x0e, 0x56, 0xb5, 0xd8, 0xda, 0xf8, 0xd9, 0x2a, 0x06, 0x98, 
-	0xad, 0x33, 0x8b, 0xff, 0xe9, 0x2e, 0x45, 0xa8, 0x3d, 0x95, 0x63, 0xb9, 
-	0x12, 0x15, 0xd7, 0x05, 0xf2, 0x91, 0x38, 0xdb, 0x61, 0x4a, 0x67, 0xe9, 
-	0xcc, 0x89, 0x92, 0xd7, 0xb8, 0x3b, 0xc4, 0x9d, 0x8f, 0x93, 0x22, 0xeb, 
-	0x0b, 0xf7, 0x94, 0xbc, 0xd4, 0x6c, 0xd8, 0xb6, 0x8e, 0x02, 0x8b, 0xda, 
-	0xfb, 0x14, 0x77, 0xdb, 0xa2, 0xd7, 0xdd, 0xcd, 0xf3, 0x97, 0x92, 0x62, 
-	0x9f, 0x4c, 0x1e, 0x70, 0x2d, 0x28, 0x56, 0x28, 0x42, 0x51, 0xb8, 0x85, 
-	0x09, 0x4f, 0xc2, 0x0c, 0xf6, 0x09, 0xc1, 0x1a, 0xd6, 0xb9, 0xdb, 0xa2, 
-	0x82, 0x0b, 0xe6, 0xc0, 0xc0, 0xec, 0x01, 0xab, 0x02, 0xa7, 0xdc, 0xb9, 
-	0xfc, 0x6e, 0xc7, 0x69, 0xd1, 0x83, 0xfa, 0x2a, 0x61, 0xd0, 0x19, 0xcc, 
-	0xd2, 0x88, 0x76, 0x1a, 0x91, 0x65, 0x88, 0xa5, 0xbd, 0x7a, 0x82, 0xb5, 
-	0xa2, 0xb7, 0x6a, 0x20, 0x94, 0xc6, 0x46, 0x60, 0x3a, 0xb3, 0xec, 0xb9, 
-	0xd9, 0x4f, 0xd6, 0xa9, 0x34, 0x6b, 0x68, 0xed, 0x51, 0x25, 0xaf, 0xc4, 
-	0xdb, 0x9e, 0x40, 0xfc, 0xb1, 0x20, 0xdc, 0x16, 0x82, 0x3b, 0x99, 0xd7, 
-	0xd6, 0x7a, 0xbf, 0x9e, 0xd6, 0x85, 0xfe, 0x2a, 0x08, 0x1e, 0x17, 0x8d, 
-	0xb7, 0x70, 0x98, 0x1f, 0x7f, 0x10, 0x09, 0x73, 0x64, 0xe6, 0x8f, 0x75, 
-	0x93, 0x16, 0xbd, 0x11, 0xf5, 0xe9, 0x9d, 0x18, 0x3d, 0x15, 0x39, 0x9c, 
-	0x03, 0x51, 0xc3, 0x21, 0x76, 0x7a, 0xf3, 0xfa, 0x1d, 0xeb, 0xc5, 0x61, 
-	0x49, 0x46, 0xd5, 0x54, 0x73, 0x03, 0x6e, 0x3b, 0x48, 0x7d, 0x7c, 0x9c, 
-	0x14, 0xa4, 0xdf, 0xe1, 0xbe, 0x8f, 0xbb, 0x63, 0xc1, 0x1a, 0x9b, 0x43, 
-	0x0b, 0xb1, 0xdb, 0xc5, 0x9f, 0x25, 0xf1, 0xd1, 0x9d, 0x20, 0x3e, 0x44, 
-	0x16, 0x83, 0xc3, 0x1f, 0xad, 0x4f, 0xa5, 0xdc, 0xaf, 0x5f, 0xcc, 0x12, 
-	0x28, 0xfd, 0x56, 0x21, 0xe3, 0xc7, 0x22, 0x94, 0x13, 0x9a, 0xaf, 0xa3, 
-	0x70, 0x5b, 0xa5, 0x01, 0x10, 0xb9, 0x04, 0xe2, 0xdb, 0xb3, 0x50, 0x27, 
-	0x54, 0xbc, 0x1e, 0xbb, 0xae, 0x47, 0x5d, 0x1f, 0xc4, 0x0f, 0xec, 0xa3, 
-	0xc4, 0x47, 0x5b, 0x59, 0x9c, 0xda, 0xf7, 0xf8, 0x80, 0xe2, 0x33, 0xe0, 
-	0x8a, 0x91, 0x78, 0x51, 0x73, 0xe2, 0xd9, 0x28, 0x9d, 0x89, 0x9a, 0xae, 
-	0x84, 0xa8, 0x01, 0x04, 0xfa, 0x1e, 0x46, 0xbc, 0x46, 0x97, 0xac, 0x67, 
-	0x57, 0x9c, 0xcc, 0xd9, 0xea, 0x5d, 0xbf, 0x43, 0x42, 0x1b, 0x6e, 0x86, 
-	0x0d, 0x35, 0x92, 0x9f, 0x39, 0x0b, 0x2d, 0x7a, 0x04, 0x26, 0x3c, 0x60, 
-	0x7d, 0xe4, 0x1b, 0x9a, 0x74, 0x41, 0xfc, 0xf5, 0x08, 0xa3, 0x92, 0xb6, 
-	0x5f, 0xa1, 0x05, 0xcb, 0xa3, 0x3e, 0xbd, 0x1b, 0xf1, 0x5a, 0xed, 0x0a, 
-	0xfd, 0x8d, 0x9c, 0x5c, 0x97, 0x69, 0x9b, 0x64, 0xbf, 0x78, 0xb0, 0x47, 
-	0xfd, 0xd9, 0x22, 0x0d, 0x50, 0xc0, 0x28, 0x6e, 0xfb, 0xf2, 0x9e, 0x07, 
-	0x60, 0xf7, 0x1c, 0x5f, 0x21, 0xf5, 0xba, 0x70, 0x1e, 0xd5, 0x9e, 0x13, 
-	0xa4, 0x68, 0xd1, 0x02, 0x24, 0xa6, 0xf5, 0x2d, 0x95, 0xd8, 0x77, 0x09, 
-	0x7d, 0x2f, 0x73, 0x9c, 0xf6, 0x96, 0x74, 0x24, 0x5c, 0xae, 0xef, 0xdc, 
-	0x56, 0xea, 0x0a, 0xa8, 0xf6, 0xc4, 0x8e, 0xd4, 0x5e, 0x58, 0x5e, 0xc4, 
-	0xd0, 0x32, 0x08, 0xc7, 0xf3, 0x33, 0x40, 0x53, 0xdf, 0x9e, 0x55, 0xd9, 
-	0x44, 0x47, 0x1b, 0xec, 0x1a, 0xc2, 0x2f, 0x56, 0xa3, 0x51, 0x27, 0xda, 
-	0x03, 0xe0, 0xf0, 0xc0, 0x56, 0x2d, 0xfa, 0x02, 0x3a, 0xaf, 0x6b, 0x1a, 
-	0x7f, 0xe9, 0xce, 0x14, 0x63, 0x32, 0x59, 0xc7, 0xd9, 0xe8, 0x37, 0x8a, 
-	0xfd, 0xea, 0x24, 0x61, 0xda, 0xe9, 0xc8, 0xf9, 0x4a, 0xdb, 0x33, 0x28, 
-	0xa3, 0x99, 0xd3, 0x96, 0xc8, 0xfd, 0x6c, 0x31, 0x03, 0x14, 0xde, 0x9c, 
-	0x87, 0x9e, 0x23, 0x95, 0x17, 0x5f, 0x08, 0xb9, 0xa7, 0x80, 0x4c, 0x2f, 
-	0x90, 0xd2, 0x6a, 0x8e, 0xaa, 0xa4, 0xee, 0xea, 0x6a, 0x68, 0xdb, 0x9d, 
-	0x0a, 0xd2, 0xce, 0x56, 0x4d, 0xc6, 0x38, 0x68, 0x53, 0x8d, 0x49, 0x30, 
-	0x0a, 0x18, 0x7f, 0x1f, 0x45, 0x8d, 0x0a, 0x4b, 0x70, 0x50, 0x51, 0xcb, 
-	0x32, 0x01, 0xd8, 0xd4, 0xe5, 0x47, 0x74, 0x6e, 0xef, 0xa6, 0x2e, 0x5f, 
-	0xd7, 0x2c, 0x01, 0x3a, 0x1c, 0xd8, 0xdb, 0x71, 0xb6, 0xbb, 0xa0, 0x79, 
-	0x47, 0xaa, 0x06, 0x90, 0x36, 0xf7, 0x66, 0x83, 0x36, 0x07, 0xc6, 0xb6, 
-	0x63, 0xed, 0x61, 0x30, 0x79, 0x4d, 0x96, 0x51, 0x9c, 0x6c, 0x79, 0xf9, 
-	0x48, 0x76, 0x68, 0x3f, 0x2e, 0xb7, 0x8f, 0xf8, 0x12, 0xf1, 0xba, 0xee, 
-	0x56, 0x31, 0xa0, 0x25, 0xa2, 0xc6, 0x90, 0xa2, 0xee, 0x1c, 0x8e, 0xc6, 
-	0x28, 0x5c, 0x90, 0x76, 0xf2, 0x25, 0x09, 0x33, 0xaa, 0x53, 0xd4, 0x70, 
-	0x98, 0x6f, 0x77, 0xe7, 0x3e, 0x87, 0xe8, 0xa5, 0x32, 0x61, 0xd1, 0xc7, 
-	0xcd, 0xd5, 0x5c, 0xe7, 0xb2, 0xac, 0x32, 0x93, 0xee, 0x8b, 0xc5, 0x77, 
-	0x80, 0x41, 0x05, 0x8c, 0x2d, 0xe4, 0xde, 0x89, 0x79, 0x74, 0xbf, 0xd1, 
-	0x9d, 0x3f, 0x6a, 0x87, 0xfe, 0xa4, 0x5c, 0x79, 0x4b, 0x87, 0xe8, 0x2e, 
-	0xf1, 0x2c, 0xc2, 0xeb, 0xbd, 0xe4, 0x16, 0x21, 0xaa, 0xfd, 0x43, 0x8e, 
-	0xf0, 0x9f, 0xd1, 0x43, 0x1c, 0x36, 0x4d, 0x44, 0x05, 0x8a, 0xb4, 0x22, 
-	0xf3, 0x18, 0x4f, 0xdc, 0xf7, 0x47, 0xe2, 0x45, 0xca, 0x70, 0x86, 0x4f, 
-	0xa9, 0x73, 0x6d, 0x9e, 0xfd, 0xd9, 0x44, 0xb9, 0x9f, 0xaf, 0xc5, 0x00, 
-	0x73, 0x52, 0x2d, 0xea, 0x06, 0xcd, 0x27, 0xdd, 0xdc, 0xb3, 0x6e, 0x52, 
-	0xd8, 0x94, 0x0c, 0x03, 0x41, 0xc2, 0x92, 0xd3, 0x13, 0xe4, 0x77, 0x71, 
-	0xb1, 0xb5, 0x10, 0xb3, 0x14, 0xb7, 0x55, 0x07, 0xfb, 0xd4, 0x92, 0x38, 
-	0x17, 0xb0, 0x23, 0xf9, 0x73, 0x20, 0xb6, 0x27, 0x21, 0xef, 0x61, 0xf2, 
-	0xa6, 0x91, 0xa8, 0xb1, 0x6a, 0x99, 0x6e, 0xca, 0x46, 0x91, 0x44, 0x8d, 
-	0xa7, 0x7c, 0xbf, 0x8c, 0xb1, 0x7d, 0x08, 0x8b, 0xab, 0xd9, 0x12, 0x5f, 
-	0x21, 0x8b, 0xdb, 0x8f, 0xd7, 0xcd, 0x0f, 0x3e, 0xe8, 0x01, 0x72, 0x93, 
-	0xd3, 0xd9, 0x18, 0xad, 0xa6, 0x88, 0xcb, 0x67, 0x3c, 0x6c, 0xb0, 0x58, 
-	0x4c, 0x27, 0x6e, 0x89, 0xdb, 0xd7, 0x62, 0x40, 0x34, 0x89, 0x4e, 0x6a, 
-	0x53, 0xaa, 0x63, 0xa1, 0x50, 0x11, 0xb5, 0xdb, 0xc7, 0x0e, 0x7a, 0x76, 
-	0xd7, 0x71, 0x60, 0x20, 0xab, 0xea, 0xc9, 0x1b, 0x40, 0xca, 0x54, 0x35, 
-	0x36, 0x89, 0x7c, 0xa0, 0x17, 0xea, 0x3a, 0x5d, 0xd2, 0xf1, 0xfb, 0xf5, 
-	0xc8, 0x1b, 0xf5, 0xde, 0x8e, 0x9c, 0x42, 0x0f, 0x44, 0xec, 0x22, 0xd6, 
-	0x0f, 0x41, 0x63, 0x34, 0x32, 0x0c, 0x67, 0xbb, 0xfd, 0x23, 0x08, 0x1c, 
-	0x4f, 0x87, 0x6a, 0x44, 0x01, 0xcf, 0x07, 0xd8, 0x96, 0x9c, 0x17, 0x0d, 
-	0x8f, 0x2c, 0xbf, 0x03, 0x70, 0x3f, 0xd2, 0x28, 0x25, 0x2b, 0x4b, 0x6e, 
-	0x96, 0xfa, 0xce, 0x57, 0x48, 0xe1, 0xb2, 0xa3, 0x31, 0xc1, 0x27, 0x2c, 
-	0xfb, 0x4c, 0xfb, 0x17, 0xe8, 0x1f, 0xdc, 0xd2, 0xf3, 0xdb, 0x42, 0xb9, 
-	0x9f, 0x0d, 0x48, 0x37, 0x6f, 0xdd, 0x06, 0xcf, 0x8a, 0x94, 0x86, 0x4e, 
-	0x9d, 0x8a, 0x3d, 0xc2, 0xd5, 0xf8, 0x8c, 0x9f, 0x83, 0x30, 0xce, 0xab, 
-	0xfa, 0x9a, 0x68, 0x7a, 0x8b, 0xfb, 0x05, 0xcb, 0x6a, 0x79, 0xff, 0x88, 
-	0xc4, 0xf7, 0x78, 0x37, 0x8e, 0xaa, 0x8f, 0x6a, 0x88, 0xee, 0xe4, 0xbc, 
-	0xbc, 0x44, 0xbb, 0x3a, 0x4a, 0x92, 0xa8, 0xcc, 0x5d, 0xd2, 0x26, 0x51, 
-	0x6b, 0x99, 0x66, 0x10, 0x7c, 0xc7, 0x12, 0x21, 0x3a, 0x30, 0x87, 0x69, 
-	0x84, 0xd9, 0x07, 0x35, 0x5b, 0x24, 0x44, 0x3f, 0xc0, 0xb8, 0x73, 0xf1, 
-	0x07, 0x7f, 0xa2, 0x2b, 0xc9, 0x1f, 0x79, 0x84, 0xbf, 0x14, 0xf3, 0xf9, 
-	0x8d, 0x7d, 0x07, 0xb5, 0x89, 0x8f, 0x2a, 0xb6, 0x2e, 0x0c, 0x32, 0xeb, 
-	0x96, 0x5c, 0xc6, 0xf1, 0x88, 0xf8, 0xd2, 0x62, 0xaa, 0xc1, 0xee, 0x72, 
-	0xd4, 0x7b, 0x77, 0x25, 0x5e, 0x6d, 0x18, 0x15, 0xdd, 0x45, 0xe7, 0xc9, 
-	0xda, 0xa5, 0xbb, 0x4b, 0x8a, 0xf2, 0x0e, 0x21, 0x5b, 0xff, 0x1b, 0x5a, 
-	0xb2, 0xa7, 0x9b, 0xb4, 0xf0, 0x3d, 0xa9, 0xac, 0x01, 0x2f, 0xbf, 0x8c, 
-	0xac, 0x72, 0x06, 0x50, 0x07, 0xf0, 0x9d, 0xd0, 0x22, 0xfa, 0x0f, 0x42, 
-	0xb2, 0xf9, 0x10, 0x35, 0x26, 0xc2, 0x41, 0xb5, 0x04, 0x0f, 0x3f, 0xcc, 
-	0x5f, 0xc2, 0x17, 0x6c, 0x9e, 0x7f, 0xa1, 0xa3, 0x44, 0x46, 0xba, 0x1b, 
-	0xfa, 0xff, 0xe4, 0xad, 0x51, 0x82, 0x38, 0xa6, 0xc3, 0x35, 0x76, 0x76, 
-	0xc6, 0xee, 0xad, 0xfc, 0x6c, 0x73, 0x0d, 0x68, 0x6e, 0x6b, 0x84, 0xc4, 
-	0xd7, 0x40, 0xb8, 0x9b, 0xec, 0x50, 0xd3, 0xcb, 0x42, 0x1a, 0x15, 0x24, 
-	0xd2, 0x54, 0xf2, 0x7a, 0x30, 0x49, 0x79, 0x76, 0x7a, 0xe1, 0xaf, 0xb2, 
-	0x75, 0x3c, 0x4b, 0x76, 0x6e, 0x80, 0x60, 0x2d, 0xb8, 0x8e, 0xa5, 0x42, 
-	0x7d, 0xbd, 0x32, 0x07, 0x5b, 0xbf, 0x80, 0xca, 0xf1, 0x14, 0x22, 0xca, 
-	0xa7, 0x8c, 0xb9, 0x91, 0x44, 0x9c, 0x4d, 0x52, 0x8c, 0x12, 0x7d, 0xe3, 
-	0xfb, 0x98, 0xd0, 0x81, 0xcc, 0xa2, 0x55, 0x28, 0x76, 0x81, 0x6c, 0xbc, 
-	0x3c, 0x39, 0x89, 0xc8, 0xce, 0x68, 0xc4, 0xef, 0x60, 0x5a, 0x1a, 0xdd, 
-	0x1b, 0x44, 0x94, 0x18, 0x18, 0x85, 0xef, 0x56, 0x68, 0xb7, 0xa6, 0x6d, 
-	0xcf, 0x80, 0x5c, 0xd5, 0xd5, 0x5f, 0xb6, 0x0b, 0x07, 0x1a, 0xa9, 0xa9, 
-	0x78, 0xe7, 0xb3, 0xb5, 0x68, 0xda, 0x14, 0xcf, 0xb3, 0x9f, 0xcf, 0x9e, 
-	0xc9, 0xea, 0xa7, 0x19, 0xc2, 0x7a, 0x5e, 0x18, 0x72, 0x30, 0xea, 0x32, 
-	0x6c, 0x7b, 0x83, 0x33, 0x20, 0xb8, 0x9c, 0xbd, 0x85, 0xba, 0xc2, 0x07, 
-	0x20, 0xa6, 0x13, 0xda, 0x73, 0x14, 0x70, 0x4b, 0x31, 0xa8, 0x9d, 0x88, 
-	0xe2, 0x5a, 0x94, 0xad, 0xe6, 0x9d, 0xa3, 0x33, 0x94, 0x44, 0xdd, 0x9a, 
-	0x9e, 0x46, 0x29, 0x15, 0x6a, 0x6c, 0xaa, 0xcf, 0x29, 0xea, 0x91, 0xce, 
-	0xf7, 0xe1, 0x89, 0xd8, 0x5f, 0x73, 0xc3, 0xe6, 0xd4, 0x1f, 0x98, 0x66, 
-	0x8e, 0x4b, 0xdf, 0xbe, 0xa1, 0x2b, 0xf2, 0x07, 0xd1, 0x5d, 0x97, 0xd1, 
-	0xef, 0x84, 0xa8, 0x08, 0x8f, 0xa5, 0x86, 0x77, 0xa5, 0x11, 0xb2, 0xce, 
-	0xbe, 0x7e, 0x23, 0x93, 0xe3, 0x08, 0x9c, 0xb2, 0x32, 0x04, 0x5e, 0x45, 
-	0xfa, 0x0a, 0xc1, 0x64, 0x99, 0x93, 0xaa, 0xe7, 0x60, 0x1e, 0x9d, 0x60, 
-	0xd4, 0x30, 0x08, 0x7d, 0x8b, 0xfe, 0x8b, 0xc0, 0x5c, 0x19, 0xa3, 0xa1, 
-	0x36, 0x8f, 0x67, 0xa2, 0x09, 0x5e, 0x24, 0x70, 0x1c, 0xef, 0xf9, 0xc5, 
-	0xd6, 0x26, 0x72, 0x2d, 0x26, 0x32, 0x81, 0x35, 0x5b, 0x8d, 0xff, 0xc0, 
-	0xd8, 0xa5, 0xbc, 0xfb, 0x46, 0xae, 0xc8, 0x14, 0xec, 0x6b, 0xd2, 0xca, 
-	0xea, 0x4f, 0x23, 0x55, 0x24, 0x5c, 0x9d, 0x0d, 0x11, 0xe5, 0x10, 0x5d, 
-	0x0c, 0xb1, 0xf3, 0x41, 0xfc, 0x21, 0x36, 0x44, 0xaf, 0xe0, 0xf9, 0xd9, 
-	0xb7, 0x07, 0x9d, 0x29, 0x95, 0xe1, 0xdd, 0xcd, 0xc1, 0x0d, 0xe4, 0x76, 
-	0x15, 0x44, 0x9c, 0xd2, 0x1a, 0x82, 0xfa, 0x75, 0x1b, 0x9f, 0xf0, 0x90, 
-	0x84, 0x85, 0x7c, 0xa6, 0x55, 0xfd, 0x21, 0x07, 0xb9, 0xb3, 0x30, 0xa1, 
-	0x43, 0xd1, 0x10, 0x0e, 0x43, 0x60, 0xd0, 0x92, 0xea, 0xe9, 0xb6, 0x35, 
-	0x86, 0x5b, 0x10, 0xa9, 0x46, 0xd7, 0xea, 0x15, 0x6f, 0xb5, 0xf5, 0x9f, 
-	0x6c, 0x8c, 0x24, 0xad, 0x47, 0x69, 0x48, 0xa7, 0x16, 0xa2, 0x37, 0x6c, 
-	0x94, 0x42, 0x3e, 0x93, 0x71, 0x9c, 0x24, 0xc5, 0xfc, 0x0c, 0x37, 0xbe, 
-	0x76, 0x41, 0xcb, 0xa5, 0x34, 0x35, 0xb6, 0xc2, 0x87, 0x1e, 0x8f, 0x8b, 
-	0x9f, 0xa6, 0xfd, 0x2a, 0x45, 0x3d, 0x44, 0x23, 0x8f, 0xb7, 0x73, 0x8b, 
-	0xdc, 0x26, 0x47, 0xb3, 0xcf, 0xec, 0xa6, 0x28, 0x5b, 0x44, 0xed, 0x62, 
-	0x9b, 0xb3, 0xb6, 0xb1, 0x02, 0xe2, 0xff, 0x48, 0x88, 0x9f, 0xba, 0xb9, 
-	0x04, 0x48, 0xe7, 0xd5, 0xeb, 0x1b, 0xd1, 0x80, 0x26, 0xe9, 0xeb, 0x8e, 
-	0x6c, 0xa7, 0xe2, 0x97, 0xa8, 0xd3, 0x1f, 0x48, 0xf1, 0xe3, 0x76, 0x24, 
-	0x3d, 0x2e, 0x0a, 0x61, 0xba, 0xb8, 0x32, 0xca, 0xb6, 0xab, 0x4b, 0xf2, 
-	0x29, 0x73, 0xce, 0x6d, 0x2a, 0xc5, 0xc3, 0xb0, 0xf3, 0x50, 0xe5, 0xdd, 
-	0x08, 0x61, 0x97, 0x45, 0xcc, 0x6c, 0x82, 0x57, 0xa4, 0x2b, 0xe8, 0xd5, 
-	0xaf, 0xdc, 0x13, 0x85, 0x67, 0x22, 0xdb, 0x93, 0xf8, 0x1b, 0x40, 0x7a, 
-	0x5c, 0x44, 0x72, 0xad, 0x85, 0x58, 0xdd, 0x75, 0xb2, 0x69, 0x0b, 0xae, 
-	0xc8, 0x9e, 0x27, 0xfc, 0xab, 0x4c, 0x20, 0x97, 0x07, 0x68, 0x1e, 0x4f, 
-	0x66, 0x7c, 0x3a, 0x52, 0x3f, 0x15, 0xd4, 0x7b, 0x40, 0xf6, 0x7b, 0xfc, 
-	0x3d, 0xc9, 0x47, 0x50, 0xaf, 0xe4, 0xbe, 0x31, 0x52, 0xda, 0x54, 0x47, 
-	0x9d, 0x94, 0x96, 0x0c, 0xc0, 0x81, 0x9d, 0xcf, 0xeb, 0x2a, 0x54, 0xff, 
-	0xea, 0xa8, 0x5d, 0x55, 0x78, 0x23, 0x46, 0x28, 0x83, 0x3c, 0x3a, 0x91, 
-	0xc7, 0x97, 0x29, 0xea, 0x18, 0x55, 0xc1, 0xeb, 0x32, 0x1f, 0x00, 0x75, 
-	0x0d, 0x4c, 0x7b, 0xd0, 0xc6, 0x95, 0x27, 0x6f, 0x94, 0x54, 0xdd, 0x2f, 
-	0xad, 0x00, 0x9b, 0xc3, 0x45, 0xdb, 0x5b, 0x5e, 0xdf, 0x88, 0x06, 0x44, 
-	0x8b, 0xb4, 0xcc, 0xc0, 0x2c, 0xf1, 0xc9, 0x5f, 0x4a, 0xf8, 0xe2, 0x08, 
-	0x5e, 0x0f, 0x31, 0x7d, 0x40, 0x58, 0xd3, 0xd0, 0xa6, 0x95, 0x63, 0x3c, 
-	0xbe, 0x85, 0x2f, 0xff, 0x30, 0x44, 0xbc, 0xa8, 0x73, 0x34, 0x69, 0x52, 
-	0xc4, 0x4c, 0x8d, 0x0d, 0xfc, 0x0f, 0x14, 0x76, 0x9b, 0x59, 0xbf, 0xa0, 
-	0x5f, 0xa4, 0xa5, 0xfd, 0xfb, 0x80, 0xde, 0x0c, 0xc3, 0xa6, 0xdb, 0x98, 
-	0x9c, 0xd3, 0x23, 0x53, 0x24, 0x44, 0xba, 0xf3, 0x08, 0x85, 0xe5, 0xcd, 
-	0x99, 0xa7, 0x30, 0xcd, 0xaf, 0x6f, 0x94, 0x01, 0xba, 0x90, 0x49, 0xb6, 
-	0x02, 0x53, 0xe0, 0x6a, 0xb9, 0x15, 0xcd, 0x6a, 0x47, 0x5d, 0x17, 0xca, 
-	0x59, 0xa4, 0xd4, 0xc1, 0xf2, 0xe8, 0x3c, 0x20, 0x72, 0x98, 0xd1, 0x78, 
-	0x95, 0xb1, 0xc1, 0xea, 0x17, 0x20, 0x1d, 0x13, 0x7d, 0x39, 0xb1, 0xd6, 
-	0x22, 0x27, 0x5f, 0xb8, 0xfb, 0xbb, 0x5d, 0x65, 0x2d, 0xf9, 0x44, 0xf6, 
-	0x82, 0x50, 0xf3, 0xf8, 0x91, 0xed, 0xc3, 0x84, 0x73, 0x60, 0xc2, 0xf6, 
-	0x30, 0xa1, 0x32, 0x6a, 0x8b, 0x60, 0xff, 0x25, 0x77, 0x65, 0x86, 0x4a, 
-	0x43, 0x11, 0xb5, 0x9d, 0x64, 0x0b, 0x2c, 0xac, 0x9d, 0xfe, 0xe6, 0xcd, 
-	0xc6, 0x40, 0x1a, 0xfc, 0xf9, 0xf6, 0x4d, 0xf3, 0xbd, 0x9a, 0x56, 0xb3, 
-	0x5f, 0xe8, 0xb9, 0x11, 0x8c, 0x3a, 0xc9, 0x16, 0x57, 0x34, 0x3f, 0x99, 
-	0xe7, 0x9d, 0x56, 0xa3, 0xa0, 0x3f, 0x6a, 0x6b, 0x01, 0xfa, 0xaf, 0x7d, 
-	0x35, 0xa6, 0xc0, 0x98, 0xcd, 0x61, 0x11, 0x31, 0xa1, 0x39, 0x8c, 0x12, 
-	0xa3, 0x0c, 0x6b, 0xde, 0xd6, 0xfc, 0x59, 0x19, 0xad, 0xef, 0xfa, 0xc5, 
-	0x29, 0x9a, 0xf0, 0x70, 0xd3, 0xfb, 0x57, 0xac, 0xd5, 0x7c, 0x8e, 0xff, 
-	0x53, 0xcf, 0x39, 0xed, 0x89, 0xb7, 0xa6, 0x25, 0x9b, 0x42, 0xd4, 0x7c, 
-	0x8e, 0x32, 0xa1, 0x2c, 0x39, 0x8c, 0xc8, 0x62, 0x9f, 0xca, 0x44, 0x6d, 
-	0x9b, 0x1a, 0xf3, 0xad, 0x6b, 0x8f, 0xd4, 0x1e, 0x26, 0xcc, 0xe0, 0x14, 
-	0x6a, 0x7f, 0x25, 0x30, 0x6a, 0xd3, 0xe7, 0x8d, 0xec, 0x4d, 0x1b, 0xbe, 
-	0x75, 0x57, 0x74, 0x2e, 0xe0, 0xb5, 0x1c, 0xca, 0xe7, 0x79, 0x7a, 0x45, 
-	0x6d, 0xdf, 0x3a, 0x62, 0x37, 0x41, 0x50, 0xa4, 0xf6, 0x98, 0xc1, 0x78, 
-	0xea, 0x06, 0x97, 0x2b, 0x58, 0x54, 0xc1, 0xde, 0xac, 0xf3, 0xd9, 0xc4, 
-	0x7c, 0xff, 0x76, 0xcd, 0xea, 0x3f, 0x2c, 0x94, 0x6a, 0xca, 0x9c, 0x0e, 
-	0xfe, 0x4a, 0xad, 0xf0, 0x78, 0xc7, 0xe7, 0x7b, 0x9b, 0x73, 0xa2, 0xff, 
-	0x76, 0x44, 0x7e, 0x15, 0xc2, 0x4d, 0x5a, 0x50, 0x96, 0x3c, 0x1f, 0x4d, 
-	0x58, 0xf4, 0x55, 0xf0, 0xdf, 0xca, 0xfe, 0xc8, 0xf9, 0x91, 0x17, 0xbc, 
-	0x8b, 0x53, 0xb4, 0x6c, 0x73, 0x93, 0x71, 0xf4, 0x5b, 0xc9, 0x81, 0x88, 
-	0xa8, 0x4c, 0x7a, 0xa8, 0x74, 0xcc, 0xff, 0x50, 0x0b, 0xab, 0xff, 0x2f, 
-	0x7c, 0x40, 0x44, 0xb7, 0xde, 0x55, 0x0b, 0xac, 0x4e, 0x50, 0x5e, 0x32, 
-	0x92, 0xb7, 0xcc, 0xff, 0x00, 0x5a, 0xe6, 0x77, 0x73, 0x8a, 0x6b, 0x18, 
-	0x83, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 
-	0x82, 
-}
-
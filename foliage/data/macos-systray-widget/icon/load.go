@@ -0,0 +1,34 @@
+package icon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Load reads an icon from a PNG, .ico or .icns file, for use in place of the
+// built-in icon.  For a PNG file, such as foliage.png, versions of it for
+// high-resolution displays beside it, named foliage@2x.png and
+// foliage@3x.png, are used too.  It returns the icon at the sizes and in the
+// format the system tray needs on this platform (see Make).
+func Load(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	images := unpack(data)
+	if formatOf(data) == formatPNG {
+		ext := filepath.Ext(path)
+		for _, scale := range []string{"@2x", "@3x"} {
+			if more, err := os.ReadFile(strings.TrimSuffix(path, ext) + scale + ext); err == nil {
+				images = append(images, more)
+			}
+		}
+	}
+	icon, err := Make(images...)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a PNG image or an .ico or .icns file containing one", path)
+	}
+	return icon, nil
+}
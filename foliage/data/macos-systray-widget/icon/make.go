@@ -0,0 +1,181 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+)
+
+// The formats icon data can be in.
+type format int
+
+const (
+	formatPNG  format = iota // One image.
+	formatICO                // Images at several sizes, for Windows.
+	formatICNS               // Images at several sizes, for macOS.
+)
+
+// formatOf returns the format of the icon data.
+func formatOf(data []byte) format {
+	switch {
+	case isICO(data):
+		return formatICO
+	case isICNS(data):
+		return formatICNS
+	}
+	return formatPNG
+}
+
+// unpack returns the PNG images in the icon data.
+func unpack(data []byte) [][]byte {
+	switch formatOf(data) {
+	case formatICO:
+		return pngsFromICO(data)
+	case formatICNS:
+		return pngsFromICNS(data)
+	}
+	return [][]byte{data}
+}
+
+// pack returns icon data in the format, holding the PNG images, which are
+// square.  Data in PNG format holds only the largest.
+func pack(f format, images [][]byte) []byte {
+	sorted := append([][]byte(nil), images...)
+	sort.SliceStable(sorted, func(i, j int) bool { return pngSize(sorted[i]) < pngSize(sorted[j]) })
+	sizes := make([]int, len(sorted))
+	for i, img := range sorted {
+		sizes[i] = pngSize(img)
+	}
+	switch f {
+	case formatICO:
+		return icoFromPNGs(sorted, sizes)
+	case formatICNS:
+		return icnsFromPNGs(sorted, sizes)
+	}
+	return sorted[len(sorted)-1]
+}
+
+// pngSize returns the width of the PNG image, from its header, or 0 if it
+// doesn't have one.
+func pngSize(data []byte) int {
+	if len(data) < 24 || !bytes.HasPrefix(data, pngSignature) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(data[16:20]))
+}
+
+// Make returns an icon for the system tray from PNG images of it, at any
+// sizes, such as a drawing made for small sizes and a more detailed one.
+// The images are resized to each of the sizes the tray wants on this system
+// (see Sizes), each from the smallest image at least that big, and put
+// together in the format the tray needs: .ico on Windows, .icns on macOS,
+// and elsewhere, PNG, which holds only one image.  The system then shows
+// the image that suits the display's scale, rather than scaling one image
+// itself, so that the icon isn't blurry.  No sizes bigger than the largest
+// image are made, unless it is smaller than all of them.
+func Make(images ...[]byte) ([]byte, error) {
+	type source struct {
+		data []byte
+		img  image.Image
+	}
+	var sources []source
+	for _, data := range images {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source{data, img})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no images")
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].img.Bounds().Dx() < sources[j].img.Bounds().Dx()
+	})
+	largest := sources[len(sources)-1]
+	var made [][]byte
+	for _, size := range Sizes() {
+		if size > largest.img.Bounds().Dx() && len(made) > 0 {
+			break
+		}
+		src := largest
+		for _, s := range sources {
+			if s.img.Bounds().Dx() >= size {
+				src = s
+				break
+			}
+		}
+		if b := src.img.Bounds(); b.Dx() == size && b.Dy() == size {
+			made = append(made, src.data)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resize(src.img, size)); err != nil {
+			return nil, err
+		}
+		made = append(made, buf.Bytes())
+	}
+	return pack(trayFormat, made), nil
+}
+
+// resize returns the image scaled to size by size pixels.  Each new pixel is
+// the average of the part of the image it covers, so that small sizes stay
+// smooth, where picking the nearest pixels would make them jagged.
+func resize(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	in := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+	xs, ys := spans(b.Dx(), size), spans(b.Dy(), size)
+	out := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y, ry := range ys {
+		for x, rx := range xs {
+			// The colors are premultiplied by their alpha, so that
+			// transparent pixels don't darken the edges.
+			var sum [4]float64
+			var total float64
+			for _, sy := range ry {
+				for _, sx := range rx {
+					w := sx.weight * sy.weight
+					i := in.PixOffset(sx.i, sy.i)
+					for c := range sum {
+						sum[c] += w * float64(in.Pix[i+c])
+					}
+					total += w
+				}
+			}
+			o := out.PixOffset(x, y)
+			for c := range sum {
+				out.Pix[o+c] = uint8(math.Min(255, sum[c]/total+0.5))
+			}
+		}
+	}
+	return out
+}
+
+// A span is a pixel of a row of an image, and how much of it a pixel of the
+// resized row covers.
+type span struct {
+	i      int
+	weight float64
+}
+
+// spans returns, for each pixel of a row of an image resized from length m
+// to n, the pixels of the original row it covers.
+func spans(m, n int) [][]span {
+	scale := float64(m) / float64(n)
+	rows := make([][]span, n)
+	for j := range rows {
+		lo, hi := float64(j)*scale, float64(j+1)*scale
+		for i := int(lo); i < m && float64(i) < hi; i++ {
+			if w := math.Min(hi, float64(i+1)) - math.Max(lo, float64(i)); w > 0 {
+				rows[j] = append(rows[j], span{i, w})
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,11 @@
+package icon
+
+// The format of icons for the macOS menu bar.
+const trayFormat = formatICNS
+
+// Sizes returns the sizes, in pixels, that icons for the system tray are
+// made in.  The menu bar shows the icon at 16 points, which is 16 pixels on
+// ordinary displays and 32 on Retina displays; 64 pixels is for zooming.
+func Sizes() []int {
+	return []int{16, 32, 64}
+}
@@ -0,0 +1,28 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package icon
+
+import (
+	"math"
+	"os"
+	"strconv"
+)
+
+// The format of icons for the system tray.  The tray on Linux desktops takes
+// a single image, which it scales to fit the panel.
+const trayFormat = formatPNG
+
+// Sizes returns the sizes, in pixels, that icons for the system tray are
+// made in: 64 pixels, times the desktop's scale factor, as the environment
+// variable GDK_SCALE or QT_SCALE_FACTOR gives it, up to 4.
+func Sizes() []int {
+	scale := 1.0
+	for _, name := range []string{"GDK_SCALE", "QT_SCALE_FACTOR"} {
+		if f, err := strconv.ParseFloat(os.Getenv(name), 64); err == nil && f >= 1 {
+			scale = math.Min(f, 4)
+			break
+		}
+	}
+	return []int{int(math.Ceil(64 * scale))}
+}
@@ -0,0 +1,13 @@
+package icon
+
+// The format of icons for the Windows tray.
+const trayFormat = formatICO
+
+// Sizes returns the sizes, in pixels, that icons for the system tray are
+// made in.  Windows shows tray icons at 16 pixels at 100% scale, up to 64 at
+// 400%, with the sizes in between for the scales in between; the tray loads
+// the icon at twice that size, and shrinks it, which the larger sizes are
+// for.
+func Sizes() []int {
+	return []int{16, 20, 24, 32, 40, 48, 64, 96, 128, 256}
+}
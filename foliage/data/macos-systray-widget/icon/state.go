@@ -0,0 +1,117 @@
+package icon
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// Colors used for the status badges drawn on top of the icon.
+var (
+	Green  = color.RGBA{0x2e, 0xb8, 0x42, 0xff}
+	Yellow = color.RGBA{0xf5, 0xb7, 0x00, 0xff}
+	Orange = color.RGBA{0xf2, 0x6b, 0x1d, 0xff}
+	Red    = color.RGBA{0xd9, 0x2b, 0x2b, 0xff}
+	Gray   = color.RGBA{0x8e, 0x8e, 0x93, 0xff}
+)
+
+// Colors of the monochrome icons for light and dark taskbars, matching the
+// system icons on Windows.
+var (
+	OnLight = color.RGBA{0x1f, 0x1f, 0x1f, 0xff}
+	OnDark  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Badged returns a copy of the icon in data with a filled circle of the
+// given color drawn in the lower right corner of each of its images.  The
+// data can be in PNG, .ico or .icns format, and the result is in the same
+// format.  If data cannot be decoded, it is returned unchanged.
+func Badged(data []byte, c color.Color) []byte {
+	return transform(data, func(src image.Image) image.Image {
+		b := src.Bounds()
+		dst := image.NewRGBA(b)
+		draw.Draw(dst, b, src, b.Min, draw.Src)
+
+		// The badge is a dot 3/8 the width of the icon, with a
+		// transparent ring around it so that it stands apart from the
+		// underlying image.
+		r := b.Dx() * 3 / 16
+		cx, cy := b.Max.X-r-1, b.Max.Y-r-1
+		ring := r + b.Dx()/32 + 1
+		for y := cy - ring; y <= cy+ring; y++ {
+			for x := cx - ring; x <= cx+ring; x++ {
+				d := (x-cx)*(x-cx) + (y-cy)*(y-cy)
+				if d <= r*r {
+					dst.Set(x, y, c)
+				} else if d <= ring*ring {
+					dst.Set(x, y, color.Transparent)
+				}
+			}
+		}
+		return dst
+	})
+}
+
+// Dimmed returns a copy of the icon in data with its opacity scaled by the
+// given fraction (0 to 1).  The data can be in PNG, .ico or .icns format,
+// and the result is in the same format.  If data cannot be decoded, it is
+// returned unchanged.
+func Dimmed(data []byte, fraction float64) []byte {
+	return transform(data, func(src image.Image) image.Image {
+		b := src.Bounds()
+		dst := image.NewNRGBA(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+				c.A = uint8(float64(c.A) * fraction)
+				dst.SetNRGBA(x, y, c)
+			}
+		}
+		return dst
+	})
+}
+
+// Tinted returns a monochrome copy of the icon in data, in the given color,
+// keeping only the shape (the alpha channel) of the original.  This is what
+// macOS does with template icons.  The data can be in PNG, .ico or .icns
+// format, and the result is in the same format.  If data cannot be decoded,
+// it is returned unchanged.
+func Tinted(data []byte, c color.Color) []byte {
+	tint := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return transform(data, func(src image.Image) image.Image {
+		b := src.Bounds()
+		dst := image.NewNRGBA(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				_, _, _, a := src.At(x, y).RGBA()
+				p := tint
+				p.A = uint8(uint32(tint.A) * a / 0xffff)
+				dst.SetNRGBA(x, y, p)
+			}
+		}
+		return dst
+	})
+}
+
+// transform returns the icon in data with f applied to each of its images,
+// in the same format, or data itself if none of them can be decoded.
+func transform(data []byte, f func(image.Image) image.Image) []byte {
+	var images [][]byte
+	for _, p := range unpack(data) {
+		img, err := png.Decode(bytes.NewReader(p))
+		if err != nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, f(img)); err != nil {
+			continue
+		}
+		images = append(images, buf.Bytes())
+	}
+	if len(images) == 0 {
+		return data
+	}
+	return pack(formatOf(data), images)
+}
@@ -0,0 +1,146 @@
+// Package identifiers says what the identifiers of FOLIO records look like
+// at a site, so that the widget and Go programs can tell what kind of
+// record an identifier is of, and how to look it up, without guessing the
+// way one library's conventions would.  A site lists its kinds of
+// identifiers in its entry in the list of tenants (see package tenants),
+// in order, each with a regular expression and where to look it up:
+//
+//	identifiers:
+//	  - name: item barcode
+//	    pattern: '350[0-9]+|nobarcode[0-9]*|(?i:temp)-.+'
+//	    kind: item
+//	    index: barcode
+//	  - name: user barcode
+//	    pattern: '000[0-9]+'
+//	    kind: user
+//	    index: barcode
+//	  - name: instance HRID
+//	    pattern: 'in[0-9]+'
+//	    kind: instance
+//	    index: hrid
+//
+// The pattern must match the whole identifier; an identifier of no kind in
+// the list is no identifier at all.  The kind (instance, holdings, item,
+// loan or user) is the kind of record the identifier is of, or empty for
+// any kind, which are tried in turn; the index is the field (the CQL
+// index) it is looked up by, by default the name.  Without a list, Default
+// is used.
+package identifiers
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"macos-systray-widget/config"
+)
+
+// Type is a kind of identifier.
+type Type struct {
+	Name    string `json:"name" yaml:"name"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Kind    string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Index   string `json:"index,omitempty" yaml:"index,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Types is a list of kinds of identifiers, in the order they are tried.
+type Types []Type
+
+// UUID is the pattern of FOLIO's record ids.
+const UUID = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+
+// Default returns the kinds of identifiers used when a tenant has none of
+// its own: FOLIO's record ids, of any kind of record; item barcodes of 8 to
+// 14 digits, or as the setting FOLIAGE_BARCODE_PATTERN has them; and HRIDs
+// with FOLIO's own prefixes, "it", "ho" and "in", followed by digits.
+func Default() Types {
+	barcode := config.Get("FOLIAGE_BARCODE_PATTERN", "[0-9]{8,14}")
+	types := Types{
+		{Name: "id", Pattern: UUID, Index: "id"},
+		{Name: "item barcode", Pattern: barcode, Kind: "item", Index: "barcode"},
+		{Name: "item HRID", Pattern: "it[0-9]+", Kind: "item", Index: "hrid"},
+		{Name: "holdings HRID", Pattern: "ho[0-9]+", Kind: "holdings", Index: "hrid"},
+		{Name: "instance HRID", Pattern: "in[0-9]+", Kind: "instance", Index: "hrid"},
+	}
+	if err := types.Check(); err != nil {
+		log.Printf("ignoring FOLIAGE_BARCODE_PATTERN: %v", err)
+		types[1].Pattern = "[0-9]{8,14}"
+		types.Check()
+	}
+	return types
+}
+
+// Check reports what is wrong with the types, and fills in the defaults.
+func (ts Types) Check() error {
+	for i := range ts {
+		t := &ts[i]
+		if t.Name == "" {
+			return fmt.Errorf("kind of identifier %d has no name", i+1)
+		}
+		if t.Pattern == "" {
+			return fmt.Errorf("the kind of identifier %q has no pattern", t.Name)
+		}
+		if t.Index == "" {
+			t.Index = t.Name
+		}
+		switch t.Kind {
+		case "", "instance", "holdings", "item", "loan", "user":
+		default:
+			return fmt.Errorf("the kind of identifier %q is of an unknown kind of record, %q", t.Name, t.Kind)
+		}
+		re, err := regexp.Compile("^(?:" + t.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("the pattern of the kind of identifier %q: %w", t.Name, err)
+		}
+		t.re = re
+	}
+	return nil
+}
+
+// Matches reports whether the identifier is of the type.
+func (t Type) Matches(id string) bool {
+	re := t.re
+	if re == nil {
+		var err error
+		if re, err = regexp.Compile("^(?:" + t.Pattern + ")$"); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(id)
+}
+
+// Matching returns the types the identifier is of, in order.
+func (ts Types) Matching(id string) Types {
+	var matching Types
+	for _, t := range ts {
+		if t.Matches(id) {
+			matching = append(matching, t)
+		}
+	}
+	return matching
+}
+
+// Of returns the types of identifiers of records of the kind, including
+// those of any kind, in order.
+func (ts Types) Of(kind string) Types {
+	var of Types
+	for _, t := range ts {
+		if t.Kind == "" || t.Kind == kind {
+			of = append(of, t)
+		}
+	}
+	return of
+}
+
+// Index returns the types whose index is the one given, in order.
+func (ts Types) Index(index string) Types {
+	var with Types
+	for _, t := range ts {
+		if t.Index == index {
+			with = append(with, t)
+		}
+	}
+	return with
+}
@@ -0,0 +1,195 @@
+// Package instance makes sure that only one copy of the widget runs at a time
+// for a given user.  The first copy to start writes a small state file
+// recording its process id and the port on localhost where it listens for
+// messages from later copies.  A later copy finds the state file, sends its
+// command-line arguments to the first copy, and exits, so that the user
+// doesn't end up with two icons in the system tray.
+//
+// The message protocol is a single line of JSON in each direction: a request
+// containing the arguments, and a reply saying whether they were accepted.
+// A request with no arguments serves to test whether the first copy is still
+// running.  If the state file is left behind by a copy that has exited
+// without removing it, the next copy to start replaces it.
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ErrRunning is returned by Acquire when another copy is already running.
+var ErrRunning = errors.New("another copy of the widget is already running")
+
+// How long to wait for the running copy to answer.
+const timeout = 5 * time.Second
+
+// State is the content of the state file.
+type State struct {
+	Pid         int `json:"pid"`
+	Port        int `json:"port"`
+	ControlPort int `json:"control_port,omitempty"`
+}
+
+type request struct {
+	Args []string `json:"args"`
+}
+
+type reply struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Instance represents the right to be the only running copy of the widget.
+type Instance struct {
+	path     string
+	listener net.Listener
+}
+
+// Path returns the default location of the state file, in the user's cache
+// directory.
+func Path() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "Foliage", "widget.json"), nil
+}
+
+// Acquire makes the caller the running copy of the widget, by writing a
+// state file at path.  controlPort is recorded in the state file for the
+// benefit of other programs; it may be 0.  If another copy is running,
+// Acquire returns ErrRunning.
+func Acquire(path string, controlPort int) (*Instance, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	state := State{
+		Pid:         os.Getpid(),
+		Port:        listener.Addr().(*net.TCPAddr).Port,
+		ControlPort: controlPort,
+	}
+	// Try twice: the second time after removing a state file left behind
+	// by a copy that is no longer running.
+	for attempt := 0; attempt < 2; attempt++ {
+		err = create(path, state)
+		if !os.IsExist(err) {
+			break
+		}
+		if Forward(path, nil) == nil {
+			listener.Close()
+			return nil, ErrRunning
+		}
+		os.Remove(path)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &Instance{path: path, listener: listener}, nil
+}
+
+// create writes the state file at path, failing if it already exists.  The
+// content is written to a temporary file first and then linked into place,
+// so that another copy never reads a partly written file.
+func create(path string, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := path + "." + strconv.Itoa(state.Pid)
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	return os.Link(tmp, path)
+}
+
+// Read returns the content of the state file at path.
+func Read(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Forward sends args to the running copy recorded in the state file at path.
+// It returns an error if there is no running copy or it rejects the args.
+func Forward(path string, args []string) error {
+	state, err := Read(path)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", state.Port), timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	if args == nil {
+		args = []string{}
+	}
+	if err := json.NewEncoder(conn).Encode(request{Args: args}); err != nil {
+		return err
+	}
+	var r reply
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&r); err != nil {
+		return err
+	}
+	if !r.OK {
+		return errors.New(r.Error)
+	}
+	return nil
+}
+
+// Serve accepts messages from later copies of the widget and calls handle
+// with the arguments from each one that has any.  If handle returns an
+// error, it is passed back to the sender.  Serve returns when the Instance
+// is released.
+func (in *Instance) Serve(handle func(args []string) error) {
+	for {
+		conn, err := in.listener.Accept()
+		if err != nil {
+			return
+		}
+		go serveConn(conn, handle)
+	}
+}
+
+func serveConn(conn net.Conn, handle func(args []string) error) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	var req request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+	r := reply{OK: true}
+	if len(req.Args) > 0 {
+		if err := handle(req.Args); err != nil {
+			r = reply{Error: err.Error()}
+		}
+	}
+	json.NewEncoder(conn).Encode(r)
+}
+
+// Release stops listening for later copies and removes the state file.
+func (in *Instance) Release() error {
+	in.listener.Close()
+	return os.Remove(in.path)
+}
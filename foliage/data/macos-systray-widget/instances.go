@@ -0,0 +1,212 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/menu"
+	"macos-systray-widget/status"
+)
+
+// The number of other Foliage instances the menu has room for, and how often
+// to check on each of them.
+const (
+	instanceSlots    = 4
+	instanceInterval = 10 * time.Second
+)
+
+// Other Foliage instances are ones at a different URL from ours, such as a
+// second Foliage using a test tenant.  When one of them starts, its copy of
+// the widget finds us running and hands its arguments to us (see
+// forward.go), and we show that instance as a submenu of its own instead of
+// ignoring it.  As with the slots for items added by Foliage, the submenus
+// are created in advance and hidden until they are needed.
+type instanceSlot struct {
+	menu, statusItem, openItem, quitItem *systray.MenuItem
+
+	// The instance shown in the slot; url is empty while the slot is free.
+	// gen changes each time the slot is reused, so that the watcher for an
+	// earlier instance knows to stop.
+	url string
+	pid int
+	gen int
+}
+
+var (
+	instancesMu sync.Mutex
+	instances   []*instanceSlot
+)
+
+// addInstanceSlots creates the submenus for other instances at the position
+// of the manifest item.
+func addInstanceSlots(parent *systray.MenuItem, item menu.Item) {
+	for i := 0; i < instanceSlots; i++ {
+		s := &instanceSlot{menu: addItem(parent, item, "", false)}
+		s.statusItem = s.menu.AddSubMenuItem("", "")
+		s.statusItem.Disable()
+		s.openItem = s.menu.AddSubMenuItem(locale.T("Open"), locale.T("Open this Foliage in a web browser"))
+		s.quitItem = s.menu.AddSubMenuItem(locale.T("Quit"), locale.T("Quit this Foliage"))
+		s.menu.Hide()
+		instancesMu.Lock()
+		instances = append(instances, s)
+		instancesMu.Unlock()
+		go s.handleClicks()
+	}
+}
+
+// adoptInstance shows the Foliage instance at the given URL in a submenu of
+// its own, watching the process pid if it isn't 0.  It returns false if
+// there is no room in the menu for another instance.
+func adoptInstance(u string, pid int) bool {
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	var free *instanceSlot
+	for _, s := range instances {
+		if s.url == u {
+			// We know this one already; it may have a new process.
+			if pid != 0 {
+				s.pid = pid
+			}
+			return true
+		}
+		if s.url == "" && free == nil {
+			free = s
+		}
+	}
+	if free == nil {
+		return false
+	}
+	log.Printf("showing the Foliage at %s in its own submenu", u)
+	free.url, free.pid = u, pid
+	free.gen++
+	free.menu.SetTitle(locale.Sprintf("Foliage at %s", hostOf(u)))
+	free.statusItem.SetTitle(locale.T("Checking…"))
+	free.quitItem.Hide()
+	if pid != 0 {
+		free.quitItem.Show()
+	}
+	free.menu.Show()
+	go free.watch(free.gen)
+	return true
+}
+
+// watch keeps the submenu up to date with the state of the instance, until
+// its process exits or the slot is reused.  An instance with no known
+// process is dropped once it has stopped responding for a minute.
+func (s *instanceSlot) watch(gen int) {
+	var silentSince time.Time
+	for {
+		instancesMu.Lock()
+		u, pid, current := s.url, s.pid, s.gen == gen
+		instancesMu.Unlock()
+		if !current {
+			return
+		}
+		if pid != 0 && !processAlive(pid) {
+			log.Printf("the Foliage at %s has exited", u)
+			s.release(gen)
+			return
+		}
+		info, err := status.Fetch(u)
+		if err != nil {
+			debugf("the Foliage at %s is not responding: %v", u, err)
+			if silentSince.IsZero() {
+				silentSince = time.Now()
+			} else if pid == 0 && time.Since(silentSince) > time.Minute {
+				s.release(gen)
+				return
+			}
+			s.statusItem.SetTitle(locale.T("Not responding"))
+		} else {
+			silentSince = time.Time{}
+			text := sessionText(info, time.Now())
+			s.statusItem.SetTitle(text)
+			if info.TenantID != "" {
+				s.menu.SetTitle(locale.Sprintf("Foliage — %s (%s)", info.TenantID, hostOf(u)))
+			}
+		}
+		time.Sleep(instanceInterval)
+	}
+}
+
+// release frees the slot, unless it has already been reused.
+func (s *instanceSlot) release(gen int) {
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	if s.gen == gen {
+		s.url, s.pid = "", 0
+		s.gen++
+		s.menu.Hide()
+	}
+}
+
+func (s *instanceSlot) handleClicks() {
+	for {
+		select {
+		case <-s.openItem.ClickedCh:
+			instancesMu.Lock()
+			u := s.url
+			instancesMu.Unlock()
+			if u != "" {
+				open(u)
+			}
+		case <-s.quitItem.ClickedCh:
+			instancesMu.Lock()
+			u, pid, gen := s.url, s.pid, s.gen
+			instancesMu.Unlock()
+			if pid != 0 && confirmQuitInstance(u) {
+				quitInstance(pid)
+				s.release(gen)
+			}
+		}
+	}
+}
+
+// confirmQuitInstance asks whether to quit the Foliage at u.  Unlike our own
+// Foliage, another instance is quit from a menu that looks much like ours,
+// so the user always confirms, to be sure of quitting the right one.
+func confirmQuitInstance(u string) bool {
+	ok, err := dialog.Confirm(locale.T("Quit Foliage"),
+		locale.Sprintf("Quit the Foliage at %s? Any operation it is running will be"+
+			" left half done.", hostOf(u)), locale.T("Quit"))
+	if err != nil {
+		log.Printf("unable to ask for confirmation: %v", err)
+		return false
+	}
+	return ok
+}
+
+// quitInstance stops another Foliage process.  On Windows, Foliage quits
+// when its own widget exits, but this one's widget has already handed over
+// to us, so the process can only be ended.
+func quitInstance(pid int) {
+	if runtime.GOOS == "windows" {
+		p, err := os.FindProcess(pid)
+		if err == nil {
+			log.Printf("ending Foliage process %d", pid)
+			err = p.Kill()
+		}
+		if err != nil {
+			log.Printf("unable to end Foliage process %d: %v", pid, err)
+		}
+		return
+	}
+	if err := shutdownServer(pid, shutdownTimeout); err != nil {
+		log.Print(err)
+	}
+}
+
+// hostOf returns the host and port of the URL u, for naming an instance.
+func hostOf(u string) string {
+	if parsed, err := url.Parse(u); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return u
+}
@@ -0,0 +1,38 @@
+// Package shellquote quotes strings for the scripts and files the helper
+// generates: AppleScript, PowerShell and POSIX shell commands, and the Exec
+// key of freedesktop.org desktop entries.
+package shellquote
+
+import "strings"
+
+// AppleScript quotes s as an AppleScript string literal.
+func AppleScript(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// PowerShell quotes s as a PowerShell string literal.  Within single
+// quotes, only the quote itself is special, and it is doubled.
+func PowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Shell quotes s for the POSIX shell.
+func Shell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// DesktopExec quotes an argument for the Exec key of a desktop entry.  The
+// specification reserves a number of characters, which require the argument
+// to be quoted, and within quotes, a few need backslashes.  Percent signs
+// start field codes, so literal ones are doubled.  Since the value of a key
+// is itself a string with escapes, backslashes are then doubled again.
+func DesktopExec(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`") {
+		return arg
+	}
+	r := strings.NewReplacer(`"`, `\"`, "`", "\\`", `$`, `\$`, `\`, `\\`)
+	quoted := `"` + r.Replace(arg) + `"`
+	return strings.ReplaceAll(quoted, `\`, `\\`)
+}
@@ -0,0 +1,33 @@
+package shellquote
+
+import "testing"
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote func(string) string
+		in    string
+		want  string
+	}{
+		{"applescript", AppleScript, `Foliage`, `"Foliage"`},
+		{"applescript quotes", AppleScript, `say "hi"`, `"say \"hi\""`},
+		{"applescript backslash", AppleScript, `C:\x`, `"C:\\x"`},
+		{"powershell", PowerShell, `C:\Program Files\Foliage`, `'C:\Program Files\Foliage'`},
+		{"powershell quote", PowerShell, `it's`, `'it''s'`},
+		{"shell", Shell, `/Applications/Foliage.app`, `'/Applications/Foliage.app'`},
+		{"shell quote", Shell, `it's`, `'it'\''s'`},
+		{"exec plain", DesktopExec, `/usr/bin/foliage`, `/usr/bin/foliage`},
+		{"exec empty", DesktopExec, ``, `""`},
+		{"exec percent", DesktopExec, `100%`, `100%%`},
+		{"exec space", DesktopExec, `/opt/my apps/foliage`, `"/opt/my apps/foliage"`},
+		{"exec dollar", DesktopExec, `$HOME`, `"\\$HOME"`},
+		{"exec backslash", DesktopExec, `a\b`, `"a\\\\b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quote(tt.in); got != tt.want {
+				t.Errorf("quoting %q gave %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package xdg finds the user's directories under the XDG Base Directory
+// specification, which Linux and other freedesktop.org desktops follow.
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DataHome returns the directory for the user's data files, such as desktop
+// entries in its applications subdirectory.  It honors XDG_DATA_HOME.
+func DataHome() (string, error) {
+	return dir("XDG_DATA_HOME", ".local", "share")
+}
+
+// ConfigHome returns the directory for the user's configuration files, such
+// as desktop entries in its autostart subdirectory.  It honors
+// XDG_CONFIG_HOME.
+func ConfigHome() (string, error) {
+	return dir("XDG_CONFIG_HOME", ".config")
+}
+
+// dir returns the value of the environment variable, or if it isn't set,
+// the default path under the home directory.
+func dir(envVar string, def ...string) (string, error) {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{home}, def...)...), nil
+}
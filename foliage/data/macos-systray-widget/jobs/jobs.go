@@ -0,0 +1,140 @@
+// Package jobs asks the Foliage server to pause, resume, or cancel the batch
+// operation it is running, using the server's job endpoints (/job/pause,
+// /job/resume and /job/cancel).  It can also turn Foliage's demo mode, in
+// which operations don't change any records, on or off (/demo-mode/on and
+// /demo-mode/off), hand it a lookup to do for a foliage:// link (/lookup)
+// or a file of identifiers to work on (/batch), and switch it to another
+// FOLIO tenant (/tenant).  The endpoints take the
+// same token as the widget's control API, and answer in the same form, plus
+// a description of the operation: a request Foliage refuses gets an HTTP
+// status of 400 or more, with the reason.
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"macos-systray-widget/control"
+)
+
+// How long to wait for the server to answer.
+const timeout = 5 * time.Second
+
+// Pause asks the Foliage server at url to pause its batch operation.
+func Pause(url, token string) error {
+	_, err := post(url, "job/pause", token, nil)
+	return err
+}
+
+// Resume asks the Foliage server at url to resume its paused operation.
+func Resume(url, token string) error {
+	_, err := post(url, "job/resume", token, nil)
+	return err
+}
+
+// Cancel asks the Foliage server at url to stop its batch operation, and
+// returns the progress the operation had made when it was stopped.
+func Cancel(url, token string) (*control.Progress, error) {
+	return post(url, "job/cancel", token, nil)
+}
+
+// SetDemoMode asks the Foliage server at url to turn demo mode on or off.
+// The server refuses while a batch operation is running.
+func SetDemoMode(url, token string, on bool) error {
+	path := "demo-mode/off"
+	if on {
+		path = "demo-mode/on"
+	}
+	_, err := post(url, path, token, nil)
+	return err
+}
+
+// Lookup asks the Foliage server at url to look up the records with the
+// given identifiers.  The kind of record to retrieve ("item", "instance",
+// and so on) may be empty, to use the one selected in the lookup tab.  If
+// notify is true, Foliage reports the outcome through the control API's
+// notify command.
+func Lookup(url, token string, identifiers []string, kind string, notify bool) error {
+	body, err := json.Marshal(struct {
+		Identifiers []string `json:"identifiers"`
+		Kind        string   `json:"kind,omitempty"`
+		Notify      bool     `json:"notify,omitempty"`
+	}{identifiers, kind, notify})
+	if err != nil {
+		return err
+	}
+	_, err = post(url, "lookup", token, body)
+	return err
+}
+
+// Batch asks the Foliage server at url to start a batch operation on the
+// identifiers in the file at path, which must be an absolute path on this
+// computer.  The operation is "lookup", "change" or "delete"; Foliage asks
+// the user to choose the change to make before changing any records, and
+// refuses a deletion unless confirmed says the user has confirmed it.  The
+// server refuses while another operation is running.
+func Batch(url, token, path, operation string, confirmed bool) error {
+	body, err := json.Marshal(struct {
+		File      string `json:"file"`
+		Operation string `json:"operation"`
+		Confirmed bool   `json:"confirmed,omitempty"`
+	}{path, operation, confirmed})
+	if err != nil {
+		return err
+	}
+	_, err = post(url, "batch", token, body)
+	return err
+}
+
+// SwitchTenant asks the Foliage server at url to use the FOLIO tenant with
+// the id tenantID at the OKAPI URL folioURL.  The server refuses while a
+// batch operation is running.
+func SwitchTenant(url, token, folioURL, tenantID string) error {
+	body, err := json.Marshal(struct {
+		URL      string `json:"url"`
+		TenantID string `json:"tenant_id"`
+	}{folioURL, tenantID})
+	if err != nil {
+		return err
+	}
+	_, err = post(url, "tenant", token, body)
+	return err
+}
+
+func post(url, path, token string, body []byte) (*control.Progress, error) {
+	req, err := http.NewRequest("POST", strings.TrimSuffix(url, "/")+"/"+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set(control.TokenHeader, token)
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Foliage refuses a request with an HTTP status saying so, and the
+	// reason in the answer's "error".
+	var r struct {
+		Error string            `json:"error"`
+		Job   *control.Progress `json:"job"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	switch {
+	case resp.StatusCode != http.StatusOK && err == nil && r.Error != "":
+		return nil, errors.New(r.Error)
+	case resp.StatusCode != http.StatusOK || err != nil:
+		return nil, fmt.Errorf("unexpected response to /%s request: %s", path, resp.Status)
+	}
+	return r.Job, nil
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"macos-systray-widget/control"
+)
+
+func TestCancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string // "" if Cancel should succeed.
+	}{
+		{"cancelled", http.StatusOK, `{"ok": true, "job": {"operation": "delete", "done": 3, "total": 10}}`, ""},
+		{"no job", http.StatusConflict, `{"ok": false, "error": "no job is running"}`, "no job is running"},
+		{"no token", http.StatusForbidden, `{"ok": false, "error": "invalid or missing token"}`, "invalid or missing token"},
+		{"not JSON", http.StatusInternalServerError, `<html>Internal Server Error</html>`,
+			"unexpected response to /job/cancel request: 500 Internal Server Error"},
+		{"no reason", http.StatusBadRequest, `{}`, "unexpected response to /job/cancel request: 400 Bad Request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/job/cancel" || r.Header.Get(control.TokenHeader) != "token" {
+					t.Errorf("got %s %s with token %q", r.Method, r.URL.Path, r.Header.Get(control.TokenHeader))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			job, err := Cancel(srv.URL+"/", "token")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Cancel: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Fatalf("Cancel: got error %v, want %q", err, tt.wantErr)
+			case tt.wantErr == "" && (job == nil || job.Operation != "delete" || job.Done != 3 || job.Total != 10):
+				t.Errorf("Cancel: got %+v", job)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/config"
+)
+
+// The setting holding the passphrase for the encrypted file, and the name of
+// the file in Foliage's data directory.
+const (
+	passphraseKey = "FOLIAGE_KEYRING_PASSPHRASE"
+	fileName      = "keyring.json"
+)
+
+// Parameters for deriving the encryption key from the passphrase with scrypt,
+// as recommended by the scrypt package for interactive logins in 2017.
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+	keySize = 32 // AES-256.
+)
+
+// encryptedFile is the content of the file.  The secrets are kept as a JSON
+// object mapping service names to objects mapping account names to secrets,
+// encrypted with AES-GCM.
+type encryptedFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+type secrets map[string]map[string]string
+
+func fileGet(service, account string) (string, error) {
+	all, err := readFile()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := all[service][account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func fileSet(service, account, secret string) error {
+	all, err := readFile()
+	if err != nil {
+		return err
+	}
+	if all[service] == nil {
+		all[service] = map[string]string{}
+	}
+	all[service][account] = secret
+	return writeFile(all)
+}
+
+func fileDelete(service, account string) error {
+	all, err := readFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[service][account]; !ok {
+		return ErrNotFound
+	}
+	delete(all[service], account)
+	if len(all[service]) == 0 {
+		delete(all, service)
+	}
+	return writeFile(all)
+}
+
+// filePath returns the path of the encrypted file.
+func filePath() (string, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// passphrase returns the passphrase for the file.
+func passphrase() ([]byte, error) {
+	p := config.Get(passphraseKey, "")
+	if p == "" {
+		return nil, fmt.Errorf("no Secret Service is available, and %s is not set", passphraseKey)
+	}
+	return []byte(p), nil
+}
+
+// readFile decrypts the secrets in the file.  If there is no file, there are
+// no secrets.
+func readFile() (secrets, error) {
+	pass, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+	path, err := filePath()
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var f encryptedFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	aead, err := newAEAD(pass, f.Salt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := aead.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %s: wrong passphrase, or the file is damaged", path)
+	}
+	all := secrets{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return all, nil
+}
+
+// writeFile encrypts the secrets and replaces the file with them, using a
+// new salt and nonce each time.
+func writeFile(all secrets) error {
+	pass, err := passphrase()
+	if err != nil {
+		return err
+	}
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	f := encryptedFile{Salt: make([]byte, 16)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+	aead, err := newAEAD(pass, f.Salt)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Data = aead.Seal(nil, f.Nonce, data, nil)
+	content, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename it, so that a failure partway
+	// through never leaves a damaged file.
+	tmp, err := os.CreateTemp(filepath.Dir(path), fileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// newAEAD returns the AES-GCM cipher for the passphrase and salt.
+func newAEAD(pass, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(pass, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
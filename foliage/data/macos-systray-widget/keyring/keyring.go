@@ -0,0 +1,35 @@
+// Package keyring stores secrets, such as Foliage's FOLIO credentials, in the
+// system's own credential store.  On macOS, that is the Keychain, which is
+// used through the Security framework, and on Windows, it is the Credential
+// Manager.
+//
+// Each secret is identified by a service name and an account name, like the
+// entries kept by the Python keyring package, so that Foliage can find the
+// entries it stored before it used the widget for this.
+package keyring
+
+import "errors"
+
+// ErrNotFound is returned when there is no secret for the given service and
+// account.
+var ErrNotFound = errors.New("secret not found")
+
+// ErrNotSupported is returned on systems where no credential store is
+// supported.
+var ErrNotSupported = errors.New("no credential store is supported on this system")
+
+// Get returns the secret stored for the service and account.
+func Get(service, account string) (string, error) {
+	return get(service, account)
+}
+
+// Set stores the secret for the service and account, replacing any secret
+// already stored for them.
+func Set(service, account, secret string) error {
+	return set(service, account, secret)
+}
+
+// Delete removes the secret stored for the service and account.
+func Delete(service, account string) error {
+	return del(service, account)
+}
@@ -0,0 +1,141 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package keyring
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework Security
+
+#include <stdlib.h>
+#include <string.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <Security/Security.h>
+
+// query returns a new query for the generic password item with the given
+// service and account.  The caller must release it.
+static CFMutableDictionaryRef query(const char *service, const char *account) {
+	CFMutableDictionaryRef q = CFDictionaryCreateMutable(NULL, 0,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	CFStringRef s = CFStringCreateWithCString(NULL, service, kCFStringEncodingUTF8);
+	CFStringRef a = CFStringCreateWithCString(NULL, account, kCFStringEncodingUTF8);
+	CFDictionarySetValue(q, kSecClass, kSecClassGenericPassword);
+	CFDictionarySetValue(q, kSecAttrService, s);
+	CFDictionarySetValue(q, kSecAttrAccount, a);
+	CFRelease(s);
+	CFRelease(a);
+	return q;
+}
+
+// keychainGet copies the item's data into memory allocated with malloc.
+static OSStatus keychainGet(const char *service, const char *account,
+                            void **data, size_t *length) {
+	CFMutableDictionaryRef q = query(service, account);
+	CFDictionarySetValue(q, kSecReturnData, kCFBooleanTrue);
+	CFDictionarySetValue(q, kSecMatchLimit, kSecMatchLimitOne);
+	CFTypeRef result = NULL;
+	OSStatus status = SecItemCopyMatching(q, &result);
+	CFRelease(q);
+	if (status != errSecSuccess) {
+		return status;
+	}
+	*length = CFDataGetLength((CFDataRef) result);
+	*data = malloc(*length > 0 ? *length : 1);
+	memcpy(*data, CFDataGetBytePtr((CFDataRef) result), *length);
+	CFRelease(result);
+	return errSecSuccess;
+}
+
+// keychainSet updates the item, or adds it if it doesn't exist.
+static OSStatus keychainSet(const char *service, const char *account,
+                            const void *data, size_t length) {
+	CFMutableDictionaryRef q = query(service, account);
+	CFDataRef value = CFDataCreate(NULL, (const UInt8 *) data, length);
+	CFMutableDictionaryRef attrs = CFDictionaryCreateMutable(NULL, 0,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	CFDictionarySetValue(attrs, kSecValueData, value);
+	OSStatus status = SecItemUpdate(q, attrs);
+	if (status == errSecItemNotFound) {
+		CFDictionarySetValue(q, kSecValueData, value);
+		status = SecItemAdd(q, NULL);
+	}
+	CFRelease(attrs);
+	CFRelease(value);
+	CFRelease(q);
+	return status;
+}
+
+static OSStatus keychainDelete(const char *service, const char *account) {
+	CFMutableDictionaryRef q = query(service, account);
+	OSStatus status = SecItemDelete(q);
+	CFRelease(q);
+	return status;
+}
+
+// statusMessage returns a description of the status, in memory allocated
+// with malloc, or NULL if there is none.
+static char *statusMessage(OSStatus status) {
+	CFStringRef s = SecCopyErrorMessageString(status, NULL);
+	if (s == NULL) {
+		return NULL;
+	}
+	CFIndex size = CFStringGetMaximumSizeForEncoding(CFStringGetLength(s),
+		kCFStringEncodingUTF8) + 1;
+	char *message = malloc(size);
+	if (!CFStringGetCString(s, message, size, kCFStringEncodingUTF8)) {
+		message[0] = '\0';
+	}
+	CFRelease(s);
+	return message;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+func get(service, account string) (string, error) {
+	s, a := C.CString(service), C.CString(account)
+	defer C.free(unsafe.Pointer(s))
+	defer C.free(unsafe.Pointer(a))
+	var data unsafe.Pointer
+	var length C.size_t
+	if err := statusError(C.keychainGet(s, a, &data, &length)); err != nil {
+		return "", err
+	}
+	defer C.free(data)
+	return C.GoStringN((*C.char)(data), C.int(length)), nil
+}
+
+func set(service, account, secret string) error {
+	s, a := C.CString(service), C.CString(account)
+	defer C.free(unsafe.Pointer(s))
+	defer C.free(unsafe.Pointer(a))
+	data := C.CBytes([]byte(secret))
+	defer C.free(data)
+	return statusError(C.keychainSet(s, a, data, C.size_t(len(secret))))
+}
+
+func del(service, account string) error {
+	s, a := C.CString(service), C.CString(account)
+	defer C.free(unsafe.Pointer(s))
+	defer C.free(unsafe.Pointer(a))
+	return statusError(C.keychainDelete(s, a))
+}
+
+// statusError turns a Security framework status code into an error.
+func statusError(status C.OSStatus) error {
+	switch status {
+	case C.errSecSuccess:
+		return nil
+	case C.errSecItemNotFound:
+		return ErrNotFound
+	}
+	message := C.statusMessage(status)
+	if message == nil {
+		return fmt.Errorf("keychain error %d", int(status))
+	}
+	defer C.free(unsafe.Pointer(message))
+	return fmt.Errorf("keychain error %d: %s", int(status), C.GoString(message))
+}
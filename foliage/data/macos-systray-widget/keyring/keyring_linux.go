@@ -0,0 +1,37 @@
+package keyring
+
+import "log"
+
+// On Linux, secrets are kept by the desktop's Secret Service (GNOME Keyring
+// or KWallet) if there is one.  Headless systems usually have none, and then
+// secrets are kept in an encrypted file instead.
+
+func get(service, account string) (string, error) {
+	s, err := openSecretService()
+	if err != nil {
+		log.Printf("using encrypted file: %v", err)
+		return fileGet(service, account)
+	}
+	defer s.close()
+	return s.get(service, account)
+}
+
+func set(service, account, secret string) error {
+	s, err := openSecretService()
+	if err != nil {
+		log.Printf("using encrypted file: %v", err)
+		return fileSet(service, account, secret)
+	}
+	defer s.close()
+	return s.set(service, account, secret)
+}
+
+func del(service, account string) error {
+	s, err := openSecretService()
+	if err != nil {
+		log.Printf("using encrypted file: %v", err)
+		return fileDelete(service, account)
+	}
+	defer s.close()
+	return s.del(service, account)
+}
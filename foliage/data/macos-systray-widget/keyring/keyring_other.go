@@ -0,0 +1,18 @@
+//go:build !windows && !linux && (!darwin || !cgo)
+// +build !windows
+// +build !linux
+// +build !darwin !cgo
+
+package keyring
+
+func get(service, account string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func set(service, account, secret string) error {
+	return ErrNotSupported
+}
+
+func del(service, account string) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,154 @@
+package keyring
+
+import (
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Credential types and persistence, from WinCred.h.
+const (
+	credTypeGeneric       = 1
+	credPersistEnterprise = 3
+)
+
+// credential is the CREDENTIALW structure from WinCred.h.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// Secrets are kept in the Windows Credential Manager as generic credentials,
+// the way the Python keyring package keeps them: the target name is the
+// service, and the user name is the account, unless the service already has
+// a credential for another account, in which case the target name is
+// "account@service".  The secret is stored as UTF-16.
+
+func get(service, account string) (string, error) {
+	secret, user, err := read(service)
+	if err == nil && user == account {
+		return secret, nil
+	} else if err != nil && err != ErrNotFound {
+		return "", err
+	}
+	secret, _, err = read(account + "@" + service)
+	return secret, err
+}
+
+func set(service, account, secret string) error {
+	target := service
+	if _, user, err := read(service); err == nil && user != account {
+		target = account + "@" + service
+	} else if err != nil && err != ErrNotFound {
+		return err
+	}
+	return write(target, account, secret)
+}
+
+func del(service, account string) error {
+	err := remove(account + "@" + service)
+	if err != ErrNotFound {
+		return err
+	}
+	if _, user, err := read(service); err != nil {
+		return err
+	} else if user != account {
+		return ErrNotFound
+	}
+	return remove(service)
+}
+
+// read returns the secret and user name of the generic credential with the
+// given target name.
+func read(target string) (secret, user string, err error) {
+	t, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return "", "", err
+	}
+	var cred *credential
+	r, _, e := procCredRead.Call(uintptr(unsafe.Pointer(t)), credTypeGeneric, 0,
+		uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		return "", "", credError(e)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	blob := unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)
+	chars := make([]uint16, len(blob)/2)
+	for i := range chars {
+		chars[i] = uint16(blob[2*i]) | uint16(blob[2*i+1])<<8
+	}
+	return string(utf16.Decode(chars)), windows.UTF16PtrToString(cred.UserName), nil
+}
+
+// write creates or replaces the generic credential with the given target.
+func write(target, user, secret string) error {
+	t, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	u, err := windows.UTF16PtrFromString(user)
+	if err != nil {
+		return err
+	}
+	chars := utf16.Encode([]rune(secret))
+	blob := make([]byte, 2*len(chars))
+	for i, c := range chars {
+		blob[2*i], blob[2*i+1] = byte(c), byte(c>>8)
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         t,
+		UserName:           u,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistEnterprise,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	r, _, e := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return credError(e)
+	}
+	return nil
+}
+
+// remove deletes the generic credential with the given target name.
+func remove(target string) error {
+	t, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	r, _, e := procCredDelete.Call(uintptr(unsafe.Pointer(t)), credTypeGeneric, 0)
+	if r == 0 {
+		return credError(e)
+	}
+	return nil
+}
+
+// credError turns the error from a failed Cred call into ErrNotFound, if
+// that's what it means.
+func credError(err error) error {
+	if err == windows.ERROR_NOT_FOUND {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Names used by the freedesktop.org Secret Service API.
+const (
+	ssBusName    = "org.freedesktop.secrets"
+	ssPath       = "/org/freedesktop/secrets"
+	ssService    = "org.freedesktop.Secret.Service"
+	ssCollection = "org.freedesktop.Secret.Collection"
+	ssItem       = "org.freedesktop.Secret.Item"
+	ssPrompt     = "org.freedesktop.Secret.Prompt"
+
+	defaultCollection = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	noPrompt          = dbus.ObjectPath("/")
+)
+
+// ssSecret is the Secret structure of the Secret Service API.
+type ssSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretService is a connection to the Secret Service, with a session that
+// transfers secrets unencrypted (which is fine over the private connection
+// to the session bus).
+type secretService struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+// openSecretService connects to the Secret Service on the session bus.  Items
+// are found by the attributes "service" and "username", like those created
+// by the Python keyring package.
+func openSecretService() (*secretService, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("no D-Bus session bus: %w", err)
+	}
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = conn.Object(ssBusName, ssPath).Call(ssService+".OpenSession", 0,
+		"plain", dbus.MakeVariant("")).Store(&output, &session)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("no Secret Service available: %w", err)
+	}
+	return &secretService{conn, session}, nil
+}
+
+func (s *secretService) close() {
+	s.conn.Object(ssBusName, s.session).Call("org.freedesktop.Secret.Session.Close", 0)
+	s.conn.Close()
+}
+
+func (s *secretService) get(service, account string) (string, error) {
+	item, err := s.find(service, account)
+	if err != nil {
+		return "", err
+	}
+	var secret ssSecret
+	err = s.conn.Object(ssBusName, item).Call(ssItem+".GetSecret", 0, s.session).Store(&secret)
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Value), nil
+}
+
+func (s *secretService) set(service, account, secret string) error {
+	if err := s.unlock([]dbus.ObjectPath{defaultCollection}); err != nil {
+		return err
+	}
+	props := map[string]dbus.Variant{
+		ssItem + ".Label": dbus.MakeVariant(fmt.Sprintf("Password for '%s' on '%s'", account, service)),
+		ssItem + ".Attributes": dbus.MakeVariant(map[string]string{
+			"application": "Foliage",
+			"service":     service,
+			"username":    account,
+		}),
+	}
+	value := ssSecret{s.session, []byte{}, []byte(secret), "text/plain"}
+	var item, prompt dbus.ObjectPath
+	err := s.conn.Object(ssBusName, defaultCollection).Call(ssCollection+".CreateItem", 0,
+		props, value, true).Store(&item, &prompt)
+	if err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+func (s *secretService) del(service, account string) error {
+	item, err := s.find(service, account)
+	if err != nil {
+		return err
+	}
+	var prompt dbus.ObjectPath
+	if err := s.conn.Object(ssBusName, item).Call(ssItem+".Delete", 0).Store(&prompt); err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+// find returns the item for the service and account, unlocking it if need be.
+func (s *secretService) find(service, account string) (dbus.ObjectPath, error) {
+	attrs := map[string]string{"service": service, "username": account}
+	var unlocked, locked []dbus.ObjectPath
+	err := s.conn.Object(ssBusName, ssPath).Call(ssService+".SearchItems", 0, attrs).Store(&unlocked, &locked)
+	if err != nil {
+		return "", err
+	}
+	if len(unlocked) > 0 {
+		return unlocked[0], nil
+	}
+	if len(locked) > 0 {
+		return locked[0], s.unlock(locked[:1])
+	}
+	return "", ErrNotFound
+}
+
+// unlock unlocks the items or collections, which may mean asking the user
+// for their keyring password.
+func (s *secretService) unlock(paths []dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := s.conn.Object(ssBusName, ssPath).Call(ssService+".Unlock", 0, paths).Store(&unlocked, &prompt)
+	if err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+// prompt shows the Secret Service's prompt, if there is one, and waits for
+// the user to respond.
+func (s *secretService) prompt(path dbus.ObjectPath) error {
+	if path == noPrompt || path == "" {
+		return nil
+	}
+	err := s.conn.AddMatchSignal(dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(ssPrompt), dbus.WithMatchMember("Completed"))
+	if err != nil {
+		return err
+	}
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+	if err := s.conn.Object(ssBusName, path).Call(ssPrompt+".Prompt", 0, "").Err; err != nil {
+		return err
+	}
+	for sig := range signals {
+		if sig.Path != path || sig.Name != ssPrompt+".Completed" || len(sig.Body) < 2 {
+			continue
+		}
+		if dismissed, _ := sig.Body[0].(bool); dismissed {
+			return errors.New("the request to the keyring was dismissed")
+		}
+		return nil
+	}
+	return errors.New("lost the connection to the Secret Service")
+}
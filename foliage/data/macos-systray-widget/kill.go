@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"macos-systray-widget/instance"
+	"macos-systray-widget/procs"
+)
+
+// How long a process found by the kill subcommand is given to exit after
+// being interrupted, before it is killed.
+const killTimeout = 5 * time.Second
+
+// A process the kill subcommand found.
+type stuckProcess struct {
+	procs.Process
+	kind   string // "Foliage", "PyWebIO" or "widget".
+	reason string // Why it looks stuck, or "".
+}
+
+// runKill runs the kill subcommand, which finds what Foliage has left behind
+// when it didn't exit cleanly: Foliage processes (including ones run with
+// Python, and PyWebIO processes), copies of the widget or other
+// foliage-helper programs, whatever is listening on Foliage's port, and a
+// widget state file left by a copy that has exited.  It lists them, and
+// once the user confirms (or with --yes), it interrupts each process, kills
+// it if it is still running a few seconds later, and removes the state
+// file.  With --list, it only lists them.
+func runKill(args []string) int {
+	fs := subcommandFlags("kill")
+	yes := fs.Bool("yes", false, "stop the processes without asking")
+	listOnly := fs.Bool("list", false, "only list the processes")
+	urlFlag := fs.String("url", "", "the URL of Foliage, for finding what is listening on its port")
+	port := fs.Int("port", 0, "the port of Foliage on this computer")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	found, err := findStuckProcesses(resolveURL(*urlFlag, *port))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to list processes: %v\n", err)
+		return 1
+	}
+	statePath, stale := staleStateFile()
+	if len(found) == 0 && stale == "" {
+		fmt.Println("No Foliage processes are running.")
+		return 0
+	}
+	for _, p := range found {
+		line := fmt.Sprintf("%6d  %-8s  %s", p.Pid, p.kind, p.Name)
+		if len(p.Args) > 1 {
+			line += " " + strings.Join(p.Args[1:], " ")
+		}
+		if p.reason != "" {
+			line += "  (" + p.reason + ")"
+		}
+		fmt.Println(line)
+	}
+	if stale != "" {
+		fmt.Printf("Stale widget state file %s (%s)\n", statePath, stale)
+	}
+	if *listOnly || (!*yes && !confirmKill(len(found))) {
+		return 0
+	}
+	status := 0
+	for _, p := range found {
+		if err := stopProcess(p.Pid); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+		} else {
+			fmt.Printf("Stopped %s process %d.\n", p.kind, p.Pid)
+		}
+	}
+	if stale != "" {
+		if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "unable to remove %s: %v\n", statePath, err)
+			status = 1
+		} else {
+			fmt.Printf("Removed %s.\n", statePath)
+		}
+	}
+	return status
+}
+
+// findStuckProcesses returns the processes that belong to Foliage, other
+// than this one, with the one listening on the port of rawurl (if it is
+// on this computer) even if it doesn't look like Foliage.
+func findStuckProcesses(rawurl string) ([]stuckProcess, error) {
+	all, err := procs.List()
+	if err != nil {
+		return nil, err
+	}
+	byPid := map[int]procs.Process{}
+	for _, p := range all {
+		byPid[p.Pid] = p
+	}
+	var found []stuckProcess
+	seen := map[int]bool{os.Getpid(): true}
+	for _, p := range all {
+		if kind := processKind(p); kind != "" && !seen[p.Pid] {
+			seen[p.Pid] = true
+			found = append(found, stuckProcess{p, kind, stuckReason(p, kind, byPid)})
+		}
+	}
+	if port := localPort(rawurl); port > 0 {
+		if pid, err := procs.Listening(port); err == nil && pid > 0 && !seen[pid] {
+			p, ok := byPid[pid]
+			if !ok {
+				p = procs.Process{Pid: pid, Name: "?"}
+			}
+			found = append(found, stuckProcess{p, "port", fmt.Sprintf("listening on port %d", port)})
+		}
+	}
+	return found, nil
+}
+
+// processKind returns what sort of Foliage process p is, or "" if it isn't
+// one.  Foliage runs as a program named Foliage when it is built as an
+// application, and otherwise as a Python program whose arguments name it.
+func processKind(p procs.Process) string {
+	name := strings.ToLower(p.Name)
+	switch {
+	case name == "foliage-helper" || name == "macos-systray-widget":
+		return "widget"
+	case name == "foliage":
+		return "Foliage"
+	case strings.HasPrefix(name, "python"):
+		for i, arg := range p.Args {
+			if i == 0 {
+				continue
+			}
+			arg = strings.ToLower(arg)
+			if strings.Contains(arg, "pywebio") {
+				return "PyWebIO"
+			}
+			if arg == "foliage" || strings.HasSuffix(arg, "/foliage") || strings.HasSuffix(arg, `\foliage`) {
+				return "Foliage"
+			}
+		}
+	}
+	return ""
+}
+
+// stuckReason returns why p looks like it was left behind: a widget whose
+// Foliage process (given by --pid) has exited, or a Foliage whose parent has
+// exited.
+func stuckReason(p procs.Process, kind string, byPid map[int]procs.Process) string {
+	if kind == "widget" {
+		for i, arg := range p.Args {
+			value := ""
+			if strings.HasPrefix(arg, "--pid=") || strings.HasPrefix(arg, "-pid=") {
+				value = arg[strings.Index(arg, "=")+1:]
+			} else if (arg == "--pid" || arg == "-pid") && i+1 < len(p.Args) {
+				value = p.Args[i+1]
+			}
+			if pid, err := strconv.Atoi(value); err == nil && pid > 0 && !processAlive(pid) {
+				return fmt.Sprintf("its Foliage process %d has exited", pid)
+			}
+		}
+		return ""
+	}
+	if _, ok := byPid[p.PPid]; p.PPid > 1 && !ok {
+		return fmt.Sprintf("the process that started it, %d, has exited", p.PPid)
+	}
+	return ""
+}
+
+// staleStateFile returns the path of the widget's state file, and a
+// description of why it is stale if it was left by a copy that has exited,
+// or "" if it isn't.
+func staleStateFile() (string, string) {
+	path, err := instance.Path()
+	if err != nil {
+		return "", ""
+	}
+	state, err := instance.Read(path)
+	if err != nil || processAlive(state.Pid) {
+		return path, ""
+	}
+	return path, fmt.Sprintf("the widget process %d has exited", state.Pid)
+}
+
+// confirmKill asks the user whether to stop n processes, if the standard
+// input is a terminal; otherwise, it doesn't stop them.
+func confirmKill(n int) bool {
+	if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		fmt.Println("Run again with --yes to stop them.")
+		return false
+	}
+	question := "Stop these processes?"
+	if n == 0 {
+		question = "Remove the state file?"
+	} else if n == 1 {
+		question = "Stop this process?"
+	}
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		fmt.Println()
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// stopProcess asks the process with the given pid to exit, the way the
+// widget asks Foliage, and kills it if it hasn't after killTimeout.  On
+// Windows, which can't ask, it is killed right away.
+func stopProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("unable to find process %d: %w", pid, err)
+	}
+	if err := interruptProcess(p); err == nil {
+		for deadline := time.Now().Add(killTimeout); time.Now().Before(deadline); time.Sleep(shutdownInterval) {
+			if !processAlive(pid) {
+				return nil
+			}
+		}
+	}
+	if err := p.Kill(); err != nil && processAlive(pid) {
+		return fmt.Errorf("unable to kill process %d: %w", pid, err)
+	}
+	return nil
+}
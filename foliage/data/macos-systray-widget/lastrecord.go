@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/control"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/tenants"
+)
+
+// The "Open Last Record in FOLIO" menu items, which are shown once Foliage
+// has reported a record that can be opened.
+var lastRecordItems []*systray.MenuItem
+
+// The record Foliage last showed or changed.
+var (
+	lastRecordMu sync.Mutex
+	lastRecord   *control.Record
+)
+
+// folioUIURL returns the address of the FOLIO staff interface of the tenant
+// Foliage is using: the ui_url of its entry in the list of tenants, if it
+// has one, or else the setting FOLIO_UI_URL.  It is "" if neither is set.
+func folioUIURL() string {
+	t, ok := tenants.Find(loadTenants(), config.Get("FOLIO_OKAPI_URL", ""), config.Get("FOLIO_OKAPI_TENANT_ID", ""))
+	if ok && t.UIURL != "" {
+		return strings.TrimSuffix(t.UIURL, "/")
+	}
+	return strings.TrimSuffix(config.Get("FOLIO_UI_URL", ""), "/")
+}
+
+// recordURL returns the address of the record in the FOLIO staff interface
+// at ui.  Holdings records and items without the ids of the records above
+// them are searched for in the inventory, rather than shown directly.
+func recordURL(ui string, r control.Record) string {
+	id := url.PathEscape(r.ID)
+	search := func(segment string) string {
+		q := url.Values{"segment": {segment}, "qindex": {"querySearch"}, "query": {fmt.Sprintf("id==%q", r.ID)}}
+		return ui + "/inventory?" + q.Encode()
+	}
+	switch r.Kind {
+	case "instance":
+		return ui + "/inventory/view/" + id
+	case "holdings":
+		if r.InstanceID == "" {
+			return search("holdings")
+		}
+		return ui + "/inventory/view/" + url.PathEscape(r.InstanceID) + "/" + id
+	case "item":
+		if r.InstanceID == "" || r.HoldingsID == "" {
+			return search("items")
+		}
+		return ui + "/inventory/view/" + url.PathEscape(r.InstanceID) + "/" + url.PathEscape(r.HoldingsID) + "/" + id
+	case "user":
+		return ui + "/users/preview/" + id
+	case "loan":
+		if r.UserID == "" {
+			return ""
+		}
+		return ui + "/users/" + url.PathEscape(r.UserID) + "/loans/view/" + id
+	}
+	return ""
+}
+
+// describeRecord returns how the record is shown in the menu item's tooltip.
+func describeRecord(r control.Record) string {
+	name := r.Title
+	if name == "" {
+		name = r.ID
+	}
+	switch r.Kind {
+	case "instance":
+		return locale.Sprintf("Open instance %s in FOLIO", name)
+	case "holdings":
+		return locale.Sprintf("Open holdings record %s in FOLIO", name)
+	case "item":
+		return locale.Sprintf("Open item %s in FOLIO", name)
+	case "user":
+		return locale.Sprintf("Open user %s in FOLIO", name)
+	}
+	return locale.Sprintf("Open loan %s in FOLIO", name)
+}
+
+// setLastRecord remembers the record Foliage last showed or changed, and
+// shows the "Open Last Record in FOLIO" items if it can be opened.
+func setLastRecord(r control.Record) {
+	debugf("last record: %s %s", r.Kind, r.ID)
+	lastRecordMu.Lock()
+	lastRecord = &r
+	lastRecordMu.Unlock()
+	ok := folioUIURL() != "" && recordURL("ui", r) != ""
+	if ok {
+		for _, mi := range lastRecordItems {
+			mi.SetTooltip(describeRecord(r))
+		}
+	}
+	showItems(lastRecordItems, ok)
+}
+
+// openLastRecord opens the record Foliage last showed or changed in the
+// FOLIO staff interface.
+func openLastRecord() {
+	lastRecordMu.Lock()
+	r := lastRecord
+	lastRecordMu.Unlock()
+	if r == nil {
+		return
+	}
+	ui := folioUIURL()
+	if ui == "" {
+		log.Printf("unable to open %s %s: the FOLIO interface's address isn't known; set FOLIO_UI_URL", r.Kind, r.ID)
+		return
+	}
+	if link := recordURL(ui, *r); link != "" {
+		open(link)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/health"
+)
+
+// How long to wait for a newly started Foliage to begin answering requests.
+const startTimeout = 2 * time.Minute
+
+// Foliage looks for this environment variable to find out whether it was
+// started by the widget.  If it was, it doesn't start a widget of its own
+// or open a browser window, because the widget takes care of both, and it
+// exits when the widget process (whose id the variable gives) does.
+const widgetEnvVar = "FOLIAGE_WIDGET_PID"
+
+// Menu items for starting and restarting Foliage, which are hidden until
+// needed.
+var startItems, restartItems []*systray.MenuItem
+
+// Set while Foliage is being started, to avoid starting it twice at once.
+// Accessed atomically.
+var launching int32
+
+// Process id of the last Foliage the widget started itself, or 0 if it
+// hasn't started one.  Accessed atomically.
+var startedPid int64
+
+// showStartItems shows the items for starting Foliage if the server is not
+// responding and we know how to start it, and hides them otherwise.
+func showStartItems(state health.State) {
+	show := state == health.NotResponding && foliageCommand != ""
+	if pid := serverPid(); pid != 0 && processAlive(pid) {
+		show = false
+	}
+	showItems(startItems, show)
+}
+
+// startFoliage runs the configured command for starting Foliage, watches the
+// new process, waits for the server to start answering, and then (if
+// openWhenReady is true) opens the Foliage interface in the browser.
+func startFoliage(openWhenReady bool) {
+	if foliageCommand == "" {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&launching, 0, 1) {
+		log.Print("Foliage is already being started")
+		return
+	}
+	defer atomic.StoreInt32(&launching, 0)
+
+	showItems(startItems, false)
+	showItems(restartItems, false)
+	log.Printf("starting Foliage using %q", foliageCommand)
+	cmd := shellCommand(foliageCommand)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", widgetEnvVar, os.Getpid()))
+	if port := choosePort(serverURL()); port > 0 {
+		if busy := localPort(serverURL()); port != busy {
+			logEvent(logInfo, "port in use; starting Foliage on another", "busy_port", busy, "port", port)
+			setServerURL(localURL(port))
+		}
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PORT=%d", port))
+	}
+	// Tell Foliage the token for both control APIs, and how to reach ours;
+	// see control.go.
+	cmd.Env = append(cmd.Env, "FOLIAGE_CONTROL_TOKEN="+controlToken())
+	if controlPort > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("FOLIAGE_CONTROL_PORT=%d", controlPort))
+	}
+	if err := cmd.Start(); err != nil {
+		log.Printf("unable to start Foliage: %v", err)
+		return
+	}
+	pid := cmd.Process.Pid
+	setServerPid(pid)
+	atomic.StoreInt64(&startedPid, int64(pid))
+	atomic.StoreInt32(&stopped, 0)
+	showState("starting")
+	// Waiting on the process lets the system clean up after it when it
+	// exits; until then, it would still appear to be running.
+	go cmd.Wait()
+	go watchProcess(pid)
+
+	checker := health.NewChecker(serverURL())
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) && processAlive(pid) {
+		if checker.Check() {
+			log.Printf("Foliage is answering at %s", serverURL())
+			if openWhenReady {
+				open(serverURL())
+			}
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	log.Printf("Foliage did not start answering at %s", serverURL())
+}
+
+// choosePort returns the port for a Foliage the widget starts: the port of
+// url, unless something else is listening on it (usually a Foliage that
+// didn't exit cleanly, which would make the new one fail with "Address
+// already in use"), in which case it is a free port the system picks.  The
+// widget then uses the new port for everything.  It returns 0 if url isn't
+// on this computer.
+func choosePort(url string) int {
+	port := localPort(url)
+	if port == 0 {
+		return 0
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err == nil {
+		l.Close()
+		return port
+	}
+	if l, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return port
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// shellCommand returns a command that runs the given shell command line.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("/bin/sh", "-c", command)
+}
+
+// startIfNotRunning starts Foliage, without opening it in the browser, unless
+// it is already answering at its URL.  It is used at launch when the widget
+// is run with --start, as it is by the agent made with --install-agent.
+func startIfNotRunning() {
+	if health.NewChecker(serverURL()).Check() {
+		return
+	}
+	startFoliage(false)
+}
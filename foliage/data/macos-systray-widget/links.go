@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"macos-systray-widget/health"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/urlscheme"
+)
+
+// The query parameters of a lookup link that give identifiers.  Foliage
+// works out what kind of identifier each one is, so they are all treated
+// alike; the different names just make links easier to read.
+var linkIdentifierParams = []string{"barcode", "id", "hrid", "identifier"}
+
+// The kinds of record a lookup link can ask for, as Foliage names them.
+var linkKinds = map[string]bool{
+	"item": true, "holdings": true, "instance": true, "loan": true, "user": true,
+}
+
+// parseLink reads a foliage:// link such as
+// foliage://lookup?barcode=350110123456&kind=item, and returns the
+// identifiers to look up and the kind of record, which may be empty.
+func parseLink(link string) ([]string, string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, "", err
+	}
+	if u.Scheme != urlscheme.Scheme {
+		return nil, "", fmt.Errorf("not a %s:// link: %s", urlscheme.Scheme, link)
+	}
+	// Accept foliage:lookup?... as well as foliage://lookup?...
+	action := u.Host
+	if action == "" {
+		action = u.Opaque
+	}
+	if strings.Trim(action+u.Path, "/") != "lookup" {
+		return nil, "", fmt.Errorf("unknown kind of link: %s", link)
+	}
+	q := u.Query()
+	var ids []string
+	for _, name := range linkIdentifierParams {
+		for _, value := range q[name] {
+			ids = append(ids, strings.Fields(value)...)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, "", fmt.Errorf("the link has nothing to look up: %s", link)
+	}
+	kind := strings.ToLower(q.Get("kind"))
+	if kind != "" && !linkKinds[kind] {
+		return nil, "", fmt.Errorf("unknown kind of record %q in link", kind)
+	}
+	return ids, kind, nil
+}
+
+// openLink hands the lookup in a foliage:// link to Foliage.
+func openLink(link string) {
+	ids, kind, err := parseLink(link)
+	if err != nil {
+		log.Print(err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Foliage can't open this link: %v", err)})
+		return
+	}
+	debugf("opening link %s", link)
+	if err := lookUp(ids, kind); err != nil {
+		log.Printf("unable to hand the link to Foliage: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to open the link in Foliage: %v", err)})
+	}
+}
+
+// lookUp asks Foliage to look up the records with the given identifiers,
+// and brings Foliage to the front to show them.  If Foliage isn't running
+// and we know how to start it, it is started first.
+func lookUp(ids []string, kind string) error {
+	if !health.NewChecker(serverURL()).Check() && foliageCommand != "" {
+		startFoliage(false)
+	}
+	if err := jobs.Lookup(serverURL(), controlToken(), ids, kind, false); err != nil {
+		return err
+	}
+	bringToFront()
+	return nil
+}
+
+// runURLSchemeCommand carries out --register-url-scheme or
+// --unregister-url-scheme and returns the exit status.
+func runURLSchemeCommand(register bool) int {
+	var err error
+	if register {
+		var exe string
+		if exe, err = os.Executable(); err == nil {
+			if err = urlscheme.Register(exe); err == nil {
+				fmt.Printf("Registered the widget to open %s:// links.\n", urlscheme.Scheme)
+			}
+		}
+	} else if err = urlscheme.Unregister(); err == nil {
+		fmt.Printf("Removed the handler for %s:// links.\n", urlscheme.Scheme)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
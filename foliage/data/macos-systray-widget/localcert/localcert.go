@@ -0,0 +1,205 @@
+// Package localcert makes the certificates for serving Foliage over HTTPS
+// on this computer, the way mkcert does: a certificate authority of the
+// user's own, which can be added to the certificates the system trusts, and
+// a certificate for localhost signed by it.  Browsers then accept the
+// certificate without warnings, and nothing but this computer trusts it.
+package localcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotSupported is returned by Trust on systems where there is no store
+// of trusted certificates that a user can add to.
+var ErrNotSupported = errors.New("trusting a certificate is not supported on this system; " +
+	"add the certificate authority to the browser's certificates instead")
+
+// How long the certificate authority and the certificate are valid.  macOS
+// refuses server certificates valid for more than 825 days.
+const (
+	caLifetime   = 10 * 365 * 24 * time.Hour
+	certLifetime = 800 * 24 * time.Hour
+	renewBefore  = 30 * 24 * time.Hour
+)
+
+// Hosts are the names and addresses the certificate is always for.
+var Hosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// Files are the paths of the certificate files in a directory.
+type Files struct {
+	CA      string // The certificate authority's certificate.
+	CAKey   string // Its private key.
+	Cert    string // The certificate for localhost.
+	Key     string // Its private key.
+	Created bool   // Whether the certificate authority was just made.
+}
+
+// Ensure makes the certificate authority and the certificate in dir, if
+// they aren't already there, and makes a new certificate if the one there
+// expires soon or isn't for all of Hosts and the extra hosts given, such as
+// this computer's name and addresses on the local network.  The private keys
+// can only be read by the user.
+func Ensure(dir string, extra ...string) (Files, error) {
+	f := Files{
+		CA:    filepath.Join(dir, "foliage-ca.pem"),
+		CAKey: filepath.Join(dir, "foliage-ca-key.pem"),
+		Cert:  filepath.Join(dir, "localhost.pem"),
+		Key:   filepath.Join(dir, "localhost-key.pem"),
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return f, err
+	}
+	ca, caKey, err := load(f.CA, f.CAKey)
+	if err != nil {
+		if ca, caKey, err = makeCA(f.CA, f.CAKey); err != nil {
+			return f, fmt.Errorf("unable to make a certificate authority: %w", err)
+		}
+		f.Created = true
+	}
+	hosts := append(append([]string{}, Hosts...), extra...)
+	if cert, _, err := load(f.Cert, f.Key); err == nil && !f.Created &&
+		time.Until(cert.NotAfter) > renewBefore && cert.CheckSignatureFrom(ca) == nil && covers(cert, hosts) {
+		return f, nil
+	}
+	if err := makeCert(f.Cert, f.Key, hosts, ca, caKey); err != nil {
+		return f, fmt.Errorf("unable to make a certificate: %w", err)
+	}
+	return f, nil
+}
+
+// Trust adds the certificate authority in the file at path to the user's
+// trusted certificates.  The system may ask the user to confirm it.
+func Trust(path string) error {
+	return trust(path)
+}
+
+// makeCA makes a certificate authority, and writes its certificate and key.
+func makeCA(certPath, keyPath string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	name := "Foliage local CA"
+	if u, err := user.Current(); err == nil {
+		host, _ := os.Hostname()
+		name += " " + u.Username + "@" + host
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"Foliage local CA"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := write(certPath, keyPath, der, key); err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+// covers reports whether cert is for all of hosts.
+func covers(cert *x509.Certificate, hosts []string) bool {
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// makeCert makes a certificate for hosts signed by the certificate
+// authority, and writes it and its key.
+func makeCert(certPath, keyPath string, hosts []string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{CommonName: hosts[0], Organization: []string{"Foliage"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	return write(certPath, keyPath, der, key)
+}
+
+// load reads a certificate and its key.
+func load(certPath, keyPath string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, fmt.Errorf("%s or %s is not a PEM file", certPath, keyPath)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// write writes a certificate and its key as PEM files.
+func write(certPath, keyPath string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+// serialNumber returns a random serial number for a certificate.
+func serialNumber() *big.Int {
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return n
+}
@@ -0,0 +1,24 @@
+package localcert
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// trust adds the certificate to the user's login keychain as a trusted root.
+// macOS asks for the user's password to allow it.
+func trust(path string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	keychain := filepath.Join(home, "Library", "Keychains", "login.keychain-db")
+	out, err := exec.Command("security", "add-trusted-cert", "-r", "trustRoot", "-k", keychain, path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("security add-trusted-cert failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
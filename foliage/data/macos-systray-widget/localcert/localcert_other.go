@@ -0,0 +1,11 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package localcert
+
+// trust returns ErrNotSupported: Linux systems keep trusted certificates in
+// system directories, which differ between distributions and need root,
+// and many browsers have stores of their own.
+func trust(path string) error {
+	return ErrNotSupported
+}
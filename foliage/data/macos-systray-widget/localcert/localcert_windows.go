@@ -0,0 +1,17 @@
+package localcert
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// trust adds the certificate to the user's Trusted Root Certification
+// Authorities.  Windows asks the user to confirm it.
+func trust(path string) error {
+	out, err := exec.Command("certutil", "-user", "-addstore", "-f", "Root", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("certutil failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Package locale translates the text the widget shows people: its menu,
+// tooltips, notifications and dialogs.  Messages are written in English in
+// the code, and looked up by that text in a locale file for the language
+// the widget is using, which gives the translation of each:
+//
+//	{
+//	  "language": "Français",
+//	  "messages": {
+//	    "Foliage has stopped.": "Foliage s'est arrêté.",
+//	    "Unable to pause the job: %v": "Impossible de suspendre la tâche : %v"
+//	  }
+//	}
+//
+// Messages with verbs, such as %v, are formats for fmt.Sprintf; a
+// translation that needs the values in a different order can number them
+// (%[2]s).  Messages a locale file leaves out, or leaves empty, are shown in
+// English.
+//
+// The locale files built into the widget are in the messages folder, named
+// after the language's tag, such as fr.json or pt-BR.json; en.json has
+// every message, and is the one to copy to start a translation.  Files in
+// the locales folder of Foliage's data directory (see Dir) are used in
+// place of the built-in ones, so translations can be tried out, or added,
+// without building the widget.
+package locale
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"macos-systray-widget/appdirs"
+)
+
+// Default is the language of the messages in the code.
+const Default = "en"
+
+// Catalog is the contents of a locale file.
+type Catalog struct {
+	Language string            `json:"language"` // The language's name, in the language.
+	Messages map[string]string `json:"messages"` // The translations, by English message.
+}
+
+//go:embed messages/*.json
+var builtIn embed.FS
+
+// The language in use, and its messages.
+var (
+	mu       sync.RWMutex
+	current  = Default
+	messages map[string]string
+)
+
+// Dir returns the folder where locale files are looked for before the
+// built-in ones.
+func Dir() (string, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "locales"), nil
+}
+
+// Select makes the widget use the language with the tag, such as "fr" or
+// "pt-BR", or, if the tag is "", the first of the system's preferred
+// languages (see System) that there is a locale file for.  A language with
+// a region falls back to the language alone ("pt-BR" to "pt").  It returns
+// the language now in use, which is Default if there is no locale file for
+// any of them, and an error if a locale file can't be read.
+func Select(tag string) (string, error) {
+	tags := []string{tag}
+	if tag == "" {
+		tags = System()
+	}
+	var problem error
+	for _, t := range tags {
+		for _, name := range candidates(t) {
+			c, err := load(name)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			} else if err != nil {
+				if problem == nil {
+					problem = err
+				}
+				continue
+			}
+			use(name, c.Messages)
+			return name, problem
+		}
+	}
+	use(Default, nil)
+	return Default, problem
+}
+
+// use makes the language with the messages the one in use.
+func use(lang string, m map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current, messages = lang, m
+}
+
+// Current returns the tag of the language in use.
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the translation of the message into the language in use, or the
+// message itself if it has none.
+func T(message string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t := messages[message]; t != "" {
+		return t
+	}
+	return message
+}
+
+// Sprintf formats the values with the translation of the format.
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// load reads the locale file for the language with the tag, from Dir if it
+// has one, or else from the built-in files.  The error matches
+// fs.ErrNotExist if there is neither.
+func load(tag string) (*Catalog, error) {
+	name := tag + ".json"
+	var path string
+	var data []byte
+	if dir, err := Dir(); err == nil {
+		path = filepath.Join(dir, name)
+		if data, err = os.ReadFile(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if data == nil {
+		path = "messages/" + name
+		var err error
+		if data, err = builtIn.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	c := &Catalog{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// candidates returns the names of the locale files that could have the
+// language with the tag, best first.  Tags may be written as POSIX locales
+// are, as in "pt_BR.UTF-8"; "C" and "POSIX" are no language at all.
+func candidates(tag string) []string {
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if parts[0] == "" || parts[0] == "C" || parts[0] == "POSIX" {
+		return nil
+	}
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	var names []string
+	for n := len(parts); n > 0; n-- {
+		names = append(names, strings.Join(parts[:n], "-"))
+	}
+	return names
+}
+
+// System returns the tags of the languages the user prefers, best first,
+// as the system gives them.
+func System() []string {
+	return system()
+}
+
+// envLanguages returns the languages named by the environment variables
+// that POSIX systems use, in the order gettext consults them.
+func envLanguages() []string {
+	var tags []string
+	for _, v := range strings.Split(os.Getenv("LANGUAGE"), ":") {
+		if v != "" {
+			tags = append(tags, v)
+		}
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			tags = append(tags, v)
+			break
+		}
+	}
+	return tags
+}
@@ -0,0 +1,22 @@
+package locale
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// system returns the languages in the Language & Region settings, followed
+// by those of the environment, which Terminal sets from them (but which
+// programs started from the Dock don't have).
+func system() []string {
+	var tags []string
+	if out, err := exec.Command("defaults", "read", "-g", "AppleLanguages").Output(); err == nil {
+		// The value is a property list array, such as ( "en-US", fr ).
+		for _, field := range strings.FieldsFunc(string(out), func(r rune) bool {
+			return strings.ContainsRune("(),\" \t\r\n", r)
+		}) {
+			tags = append(tags, field)
+		}
+	}
+	return append(tags, envLanguages()...)
+}
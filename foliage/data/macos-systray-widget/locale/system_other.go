@@ -0,0 +1,8 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package locale
+
+func system() []string {
+	return envLanguages()
+}
@@ -0,0 +1,12 @@
+package locale
+
+import "golang.org/x/sys/windows"
+
+// system returns the display languages in the user's Language settings.
+func system() []string {
+	tags, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil {
+		return envLanguages()
+	}
+	return tags
+}
@@ -0,0 +1,331 @@
+// Package locks is a small lock service for FOLIO records, so that people
+// running batch jobs against the same tenant at the same time find out
+// when they are about to change the same records.  The locks are advisory:
+// a job that wants a record another job has locked learns which job has
+// it, and by whom, and decides for itself what to do.
+//
+// One program serves the locks (see Table, which is an http.Handler), and
+// jobs on any computer that can reach it take them with a Client:
+//
+//	GET    /locks                      the locks held, as a JSON list
+//	POST   /locks        {"job": "...", "owner": "...", "ids": ["..."]}
+//	                                   lock the records for the job; the
+//	                                   answer's "conflicts" are the locks
+//	                                   other jobs already hold on them
+//	POST   /locks/renew  {"job": "..."}
+//	DELETE /locks?job=...              release the job's locks
+//
+// Locks last TTL after they are taken or last renewed, so that a job that
+// stops without releasing its locks doesn't hold them for ever; a job's
+// Client renews them while it runs.  If the server is given a token,
+// requests must have it as a bearer token.
+package locks
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lock is a lock on a record, held by a job.
+type Lock struct {
+	ID      string    `json:"id"`    // The record's id.
+	Job     string    `json:"job"`   // The id of the job that holds the lock.
+	Owner   string    `json:"owner"` // Who runs the job, as user@computer.
+	Since   time.Time `json:"since"`
+	Expires time.Time `json:"expires"`
+}
+
+func (l Lock) String() string {
+	return fmt.Sprintf("job %s of %s has had it locked since %s", l.Job, l.Owner, l.Since.Local().Format("2006-01-02 15:04"))
+}
+
+// DefaultTTL is how long locks last without being renewed, by default.
+const DefaultTTL = 10 * time.Minute
+
+// Table is the locks a lock server holds, in memory.  It serves the lock
+// service's HTTP requests.
+type Table struct {
+	TTL   time.Duration // How long locks last; DefaultTTL if it is 0.
+	Token string        // The bearer token requests must have, if any.
+
+	mu    sync.Mutex
+	locks map[string]*Lock // By record id.
+}
+
+// ttl returns how long locks last.
+func (t *Table) ttl() time.Duration {
+	if t.TTL <= 0 {
+		return DefaultTTL
+	}
+	return t.TTL
+}
+
+// expire drops the locks that have expired.  t.mu must be held.
+func (t *Table) expire(now time.Time) {
+	for id, l := range t.locks {
+		if !now.Before(l.Expires) {
+			delete(t.locks, id)
+		}
+	}
+}
+
+// Acquire locks the records with the ids for the job, and returns the locks
+// other jobs hold on any of them, which it leaves alone.  Records the job
+// has locked already have their locks renewed.
+func (t *Table) Acquire(job, owner string, ids []string) []Lock {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.expire(now)
+	if t.locks == nil {
+		t.locks = map[string]*Lock{}
+	}
+	var conflicts []Lock
+	for _, id := range ids {
+		switch l, held := t.locks[id]; {
+		case !held:
+			t.locks[id] = &Lock{ID: id, Job: job, Owner: owner, Since: now, Expires: now.Add(t.ttl())}
+		case l.Job == job:
+			l.Expires = now.Add(t.ttl())
+		default:
+			conflicts = append(conflicts, *l)
+		}
+	}
+	return conflicts
+}
+
+// Renew renews the job's locks, and returns how many it has.
+func (t *Table) Renew(job string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.expire(now)
+	n := 0
+	for _, l := range t.locks {
+		if l.Job == job {
+			l.Expires = now.Add(t.ttl())
+			n++
+		}
+	}
+	return n
+}
+
+// Release releases the job's locks, and returns how many it had.
+func (t *Table) Release(job string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for id, l := range t.locks {
+		if l.Job == job {
+			delete(t.locks, id)
+			n++
+		}
+	}
+	return n
+}
+
+// List returns the locks held, by job and then by record.
+func (t *Table) List() []Lock {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(time.Now())
+	list := make([]Lock, 0, len(t.locks))
+	for _, l := range t.locks {
+		list = append(list, *l)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Job != list[j].Job {
+			return list[i].Job < list[j].Job
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// request is the body of a request to lock records or renew locks.
+type request struct {
+	Job   string   `json:"job"`
+	Owner string   `json:"owner,omitempty"`
+	IDs   []string `json:"ids,omitempty"`
+}
+
+// answer is the answer to a request.
+type answer struct {
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	Conflicts []Lock `json:"conflicts,omitempty"`
+	Count     int    `json:"count"`
+}
+
+// The most record ids a request to lock records can have.
+const maxIDs = 1000
+
+func (t *Table) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if t.Token != "" {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(t.Token)) != 1 {
+			reply(w, http.StatusUnauthorized, answer{Error: "a token is needed"})
+			return
+		}
+	}
+	switch {
+	case r.URL.Path == "/locks" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(t.List())
+	case r.URL.Path == "/locks" && r.Method == http.MethodDelete:
+		job := r.URL.Query().Get("job")
+		if job == "" {
+			reply(w, http.StatusBadRequest, answer{Error: "no job given"})
+			return
+		}
+		reply(w, http.StatusOK, answer{OK: true, Count: t.Release(job)})
+	case (r.URL.Path == "/locks" || r.URL.Path == "/locks/renew") && r.Method == http.MethodPost:
+		var req request
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil || req.Job == "" {
+			reply(w, http.StatusBadRequest, answer{Error: "the request needs a job"})
+			return
+		}
+		if r.URL.Path == "/locks/renew" {
+			reply(w, http.StatusOK, answer{OK: true, Count: t.Renew(req.Job)})
+			return
+		}
+		if len(req.IDs) > maxIDs {
+			reply(w, http.StatusBadRequest, answer{Error: fmt.Sprintf("at most %d records can be locked at once", maxIDs)})
+			return
+		}
+		conflicts := t.Acquire(req.Job, req.Owner, req.IDs)
+		reply(w, http.StatusOK, answer{OK: true, Conflicts: conflicts, Count: len(req.IDs) - len(conflicts)})
+	case r.URL.Path == "/locks" || r.URL.Path == "/locks/renew":
+		reply(w, http.StatusMethodNotAllowed, answer{Error: r.Method + " is not allowed"})
+	default:
+		reply(w, http.StatusNotFound, answer{Error: "not found"})
+	}
+}
+
+// reply sends the answer.
+func reply(w http.ResponseWriter, status int, a answer) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(a)
+}
+
+// Client takes locks for a job from a lock server.
+type Client struct {
+	URL   string // Of the server, such as http://folio-tools.example.edu:8737.
+	Token string
+	Job   string
+	Owner string
+	HTTP  *http.Client // http.DefaultClient, if nil.
+}
+
+// Acquire locks the records with the ids for the job, and returns the locks
+// other jobs hold on any of them.
+func (c *Client) Acquire(ctx context.Context, ids ...string) ([]Lock, error) {
+	a, err := c.do(ctx, http.MethodPost, "/locks", request{Job: c.Job, Owner: c.Owner, IDs: ids})
+	return a.Conflicts, err
+}
+
+// Renew renews the job's locks.
+func (c *Client) Renew(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, "/locks/renew", request{Job: c.Job})
+	return err
+}
+
+// Release releases the job's locks.
+func (c *Client) Release(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodDelete, "/locks?job="+url.QueryEscape(c.Job), nil)
+	return err
+}
+
+// List returns the locks the server holds.
+func (c *Client) List(ctx context.Context) ([]Lock, error) {
+	var list []Lock
+	err := c.send(ctx, http.MethodGet, "/locks", nil, &list)
+	return list, err
+}
+
+// KeepAlive renews the job's locks every third of ttl, until the context is
+// done.  Failures are left for the next time.
+func (c *Client) KeepAlive(ctx context.Context, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	tick := time.NewTicker(ttl / 3)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			c.Renew(ctx)
+		}
+	}
+}
+
+// do sends a request with the body, and returns the answer.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (answer, error) {
+	var a answer
+	if err := c.send(ctx, method, path, body, &a); err != nil {
+		return a, err
+	}
+	if !a.OK {
+		return a, fmt.Errorf("the lock server says: %s", a.Error)
+	}
+	return a, nil
+}
+
+// send sends a request with the body, if it isn't nil, and decodes the
+// answer into v.
+func (c *Client) send(ctx context.Context, method, path string, body, v interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.URL, "/")+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach the lock server: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("unable to read the lock server's answer: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("the lock server answered %s", resp.Status)
+		}
+		return fmt.Errorf("unable to read the lock server's answer: %w", err)
+	}
+	if a, ok := v.(*answer); ok && resp.StatusCode != http.StatusOK && a.Error == "" {
+		a.Error = resp.Status
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/browser"
+	"macos-systray-widget/config"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// logFile returns the path of Foliage's log file.  Foliage tells us where it
+// is writing its log using the setting LOG_FILE (which it sets to "-" when
+// it logs to the terminal instead).  If that isn't set, the log is in its
+// default location, the file log.txt in the user's log directory.
+func logFile() (string, error) {
+	if path := config.Get("LOG_FILE", ""); path != "" && path != "-" {
+		return path, nil
+	}
+	dir, err := appdirs.UserLogDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "log.txt"), nil
+}
+
+// openLog opens Foliage's log file using the system's default viewer for
+// text files.
+func openLog() {
+	path, err := logFile()
+	if err == nil {
+		_, err = os.Stat(path)
+	}
+	if err != nil {
+		log.Printf("unable to find the Foliage log file: %v", err)
+		notify.Post(notify.Notification{Message: locale.T("The Foliage log file could not be found.")})
+		return
+	}
+	// The same programs that open URLs in the browser also open files in
+	// whatever application is registered for them.
+	if err := browser.Open(path); err != nil {
+		log.Printf("unable to open %s: %v", path, err)
+	}
+}
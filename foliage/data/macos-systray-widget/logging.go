@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"macos-systray-widget/rotate"
+)
+
+// Log levels, set by the option --log-level.  At the default level, the
+// widget logs problems and notable events; at "debug", it also logs what it
+// is doing, such as each change in the server's state; at "off", it logs
+// nothing.
+const (
+	logOff = iota
+	logInfo
+	logDebug
+)
+
+// The forms of log entries, set by the option --log-format.  Plain text is
+// for people; the others have one entry per line with its time, level and
+// message, and the details of events as separate fields, for programs.
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatLogfmt = "logfmt"
+)
+
+var (
+	logLevel  = logInfo
+	logFormat = formatText
+	logMu     sync.Mutex // Guards logOut and widgetLog.
+	logOut    io.Writer  = os.Stderr
+	widgetLog *rotate.File
+)
+
+// setLogLevel sets the log level from its name.
+func setLogLevel(name string) error {
+	switch name {
+	case "off":
+		logLevel = logOff
+	case "info", "":
+		logLevel = logInfo
+	case "debug":
+		logLevel = logDebug
+	default:
+		return fmt.Errorf("unknown log level %q (use debug, info, or off)", name)
+	}
+	applyLogOutput()
+	return nil
+}
+
+// setLogFormat sets the form of log entries from its name.
+func setLogFormat(name string) error {
+	switch name {
+	case formatText, "":
+		logFormat = formatText
+	case formatJSON, formatLogfmt:
+		logFormat = name
+	default:
+		return fmt.Errorf("unknown log format %q (use text, json, or logfmt)", name)
+	}
+	applyLogOutput()
+	return nil
+}
+
+// setLogFile makes the log go to the end of the file at path.  It is set by
+// the option --log-file, for when the widget is started by something that
+// doesn't keep its output.  If the standard error output is a terminal, the
+// log goes there too, so that someone running the widget by hand sees it.
+// The file is rotated (see package rotate) when it grows past the number of
+// megabytes in the setting FOLIAGE_LOG_MAX_SIZE (10 by default), or has
+// been written to for the number of days in FOLIAGE_LOG_MAX_AGE (7 by
+// default); 0 means no limit.  The setting FOLIAGE_LOG_KEEP gives the number
+// of old files to keep, 5 by default.
+func setLogFile(path string) error {
+	f, err := rotate.Open(path, rotate.Options{
+		MaxSize: int64(settingInt("FOLIAGE_LOG_MAX_SIZE", 10)) << 20,
+		MaxAge:  time.Duration(settingInt("FOLIAGE_LOG_MAX_AGE", 7)) * 24 * time.Hour,
+		Keep:    settingInt("FOLIAGE_LOG_KEEP", 5),
+	})
+	if err != nil {
+		return err
+	}
+	var out io.Writer = f
+	if fi, err := os.Stderr.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		out = io.MultiWriter(f, os.Stderr)
+	}
+	logMu.Lock()
+	logOut, widgetLog = out, f
+	logMu.Unlock()
+	applyLogOutput()
+	return nil
+}
+
+// closeLog closes the log file, if there is one, when the widget exits.
+// Anything logged after that goes to the standard error output.
+func closeLog() {
+	logMu.Lock()
+	f := widgetLog
+	logOut, widgetLog = os.Stderr, nil
+	logMu.Unlock()
+	if f != nil {
+		applyLogOutput()
+		f.Close()
+	}
+}
+
+// applyLogOutput points the log package at the output for the current level
+// and format.  Messages logged with the log package become entries of the
+// current format.
+func applyLogOutput() {
+	logMu.Lock()
+	out := logOut
+	logMu.Unlock()
+	switch {
+	case logLevel == logOff:
+		log.SetOutput(io.Discard)
+	case logFormat == formatText:
+		log.SetFlags(log.LstdFlags)
+		log.SetOutput(out)
+	default:
+		log.SetFlags(0)
+		log.SetOutput(entryWriter{})
+	}
+}
+
+// entryWriter turns the lines written by the log package into entries.
+type entryWriter struct{}
+
+func (entryWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	writeEntry(messageLevel(msg), strings.TrimPrefix(msg, "debug: "), nil)
+	return len(p), nil
+}
+
+// messageLevel returns the level of an entry for a message logged with the
+// log package.  The widget's messages about errors all start with "unable
+// to", or say something failed.
+func messageLevel(msg string) string {
+	switch {
+	case strings.HasPrefix(msg, "debug: "):
+		return "debug"
+	case strings.HasPrefix(msg, "unable to") || strings.Contains(msg, " failed"):
+		return "error"
+	}
+	return "info"
+}
+
+// logEvent logs a notable event, if the log level is at least level: the
+// widget starting or exiting, a request to the control API, or a change in
+// the state of Foliage.  The fields are pairs of names and values describing
+// it, such as "state", "running".  In plain text, they follow the message as
+// name=value pairs.
+func logEvent(level int, msg string, fields ...interface{}) {
+	if logLevel < level {
+		return
+	}
+	if logFormat == formatText {
+		if len(fields) > 0 {
+			msg += " " + logfmtFields(fields)
+		}
+		if level == logDebug {
+			msg = "debug: " + msg
+		}
+		log.Print(msg)
+		return
+	}
+	name := "info"
+	if level == logDebug {
+		name = "debug"
+	}
+	writeEntry(name, msg, fields)
+}
+
+// writeEntry writes an entry in the current format.  The time, level and
+// message come first, followed by the fields, in the order given.
+func writeEntry(level, msg string, fields []interface{}) {
+	all := append([]interface{}{"time", time.Now().Format("2006-01-02T15:04:05.000Z07:00"),
+		"level", level, "msg", msg}, fields...)
+	var line string
+	if logFormat == formatJSON {
+		line = jsonFields(all)
+	} else {
+		line = logfmtFields(all)
+	}
+	logMu.Lock()
+	defer logMu.Unlock()
+	io.WriteString(logOut, line+"\n")
+}
+
+// jsonFields returns a JSON object with the fields, keeping their order
+// (which encoding/json would sort).
+func jsonFields(fields []interface{}) string {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i := 0; i+1 < len(fields); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(fmt.Sprint(fields[i]))
+		value, err := json.Marshal(fieldValue(fields[i+1]))
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(fields[i+1]))
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// logfmtFields returns the fields as name=value pairs, separated by spaces,
+// with values quoted if they need to be.
+func logfmtFields(fields []interface{}) string {
+	var parts []string
+	for i := 0; i+1 < len(fields); i += 2 {
+		value := fmt.Sprint(fieldValue(fields[i+1]))
+		if value == "" || strings.ContainsAny(value, " =\"\\\t\r\n") {
+			value = strconv.Quote(value)
+		}
+		parts = append(parts, fmt.Sprint(fields[i])+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldValue returns the value to log for v: the message of an error, the
+// string form of anything with one, and rounded durations.
+func fieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.Round(time.Millisecond).String()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
+
+// debugf logs a message if the log level is "debug".
+func debugf(format string, args ...interface{}) {
+	if logLevel >= logDebug {
+		log.Printf("debug: "+format, args...)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"fyne.io/systray"
+	"macos-systray-widget/autostart"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// The "Start at Login" menu items, which are checked while the widget is
+// registered to start when the user logs in.
+var loginItems []*systray.MenuItem
+
+// The options the widget was started with, which are passed on to the copy
+// started at login.
+var startOptions *options
+
+// toggleStartAtLogin registers the widget to start at login if the item was
+// unchecked, or removes the registration if it was checked.
+func toggleStartAtLogin(mi *systray.MenuItem) {
+	if err := setStartAtLogin(!mi.Checked(), startOptions, serverURL()); err != nil {
+		log.Printf("unable to change start at login: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to change whether Foliage starts at login: %v", err)})
+	}
+}
+
+// setStartAtLogin registers the widget to start at login with the options o
+// and the Foliage URL, or removes the registration, and checks or unchecks
+// the "Start at Login" items to match.
+func setStartAtLogin(on bool, o *options, url string) error {
+	var err error
+	if on {
+		var args []string
+		if args, err = loginArgs(o, url); err == nil {
+			err = autostart.Enable(args)
+		}
+	} else {
+		err = autostart.Disable()
+	}
+	if err != nil {
+		return err
+	}
+	for _, item := range loginItems {
+		if on {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+	return nil
+}
+
+// loginArgs returns the command line for starting the widget at login, with
+// the given Foliage URL and the same Foliage command, menu, and appearance
+// as this one.  There is
+// no Foliage process to watch at login, so --pid is left out; the widget
+// offers to start Foliage instead, if it knows how.
+func loginArgs(o *options, url string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{exe, "--url", url}
+	if o.command != "" {
+		args = append(args, "--command", o.command)
+	}
+	for _, f := range []struct{ name, path string }{
+		{"--menu", o.menu}, {"--icon", o.icon}, {"--log-file", o.logFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		path, err := filepath.Abs(f.path)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, f.name, path)
+	}
+	if o.title != "" {
+		args = append(args, "--title", o.title)
+	}
+	if o.tooltip != "Foliage" {
+		args = append(args, "--tooltip", o.tooltip)
+	}
+	if o.hotkey != "" {
+		args = append(args, "--hotkey", o.hotkey)
+	}
+	if o.logLevel != "info" {
+		args = append(args, "--log-level", o.logLevel)
+	}
+	if o.logFormat != "text" {
+		args = append(args, "--log-format", o.logFormat)
+	}
+	return args, nil
+}
@@ -1,21 +1,352 @@
 package main
 
 import (
-	"github.com/getlantern/systray"
+	"flag"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/dialog"
 	"macos-systray-widget/icon"
+	"macos-systray-widget/instance"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/menu"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/proxy"
+	"macos-systray-widget/services"
+)
+
+// Exit status used when another copy of the widget is already running and
+// has been handed our arguments.  Foliage checks for this.
+const exitForwarded = 3
+
+// Port for the control API server; 0 means don't run the server.
+var controlPort int
+
+// Shell command to start Foliage, if known.
+var foliageCommand string
+
+// The menu definition.
+var manifest *menu.Manifest
+
+// Text shown next to the icon (on macOS) or as its name (on Linux), and the
+// start of the tooltip.  Sites can change these to brand the tray entry.
+var (
+	trayTitle   string
+	trayTooltip = "Foliage"
+	customIcon  bool // Was the icon given by --icon?
 )
 
+// options holds the values of the command-line flags.
+type options struct {
+	url         string
+	port        int
+	controlPort int
+	menu        string
+	pid         int
+	command     string
+	title       string
+	tooltip     string
+	icon        string
+	iconTheme   string
+	logLevel    string
+	logFormat   string
+	logFile     string
+	start       bool
+	open        bool
+	hotkey      string
+	openURL     string
+	lookUp      string
+	drop        bool
+	job         string
+	dropFiles   []string
+	status      bool
+	lang        string
+
+	installAgent     bool
+	uninstallAgent   bool
+	registerURL      bool
+	unregisterURL    bool
+	installDrop      bool
+	uninstallDrop    bool
+	installScript    bool
+	uninstallScript  bool
+	installService   bool
+	uninstallService bool
+}
+
+// parseFlags parses command-line arguments.  It is used both for our own
+// arguments and for arguments forwarded from a later copy of the widget.
+func parseFlags(name string, args []string, handling flag.ErrorHandling) (*options, error) {
+	o := &options{}
+	fs := flag.NewFlagSet(name, handling)
+	fs.StringVar(&o.url, "url", "", "URL of the Foliage user interface")
+	fs.IntVar(&o.port, "port", 0, "port on localhost where Foliage is listening")
+	fs.IntVar(&o.controlPort, "control-port", settingInt("FOLIAGE_CONTROL_PORT", 0),
+		"port on localhost for the control API (0 = none)")
+	fs.StringVar(&o.menu, "menu", "", "JSON or YAML file defining the menu")
+	fs.IntVar(&o.pid, "pid", 0, "process id of Foliage to watch")
+	fs.StringVar(&o.command, "command", config.Get("FOLIAGE_COMMAND", ""),
+		"shell command to start Foliage")
+	fs.StringVar(&o.title, "title", "", "text to show next to the tray icon")
+	fs.StringVar(&o.tooltip, "tooltip", "Foliage", "text at the start of the tray icon's tooltip")
+	fs.StringVar(&o.icon, "icon", "", "PNG, .ico or .icns file to use as the tray icon")
+	fs.StringVar(&o.iconTheme, "icon-theme", config.Get("FOLIAGE_ICON_THEME", themeAuto),
+		"how the tray icon looks: auto, color, monochrome, light, or dark")
+	fs.StringVar(&o.lang, "lang", config.Get("FOLIAGE_LANG", ""),
+		"language of the menu, notifications and dialogs, such as fr or pt-BR (by default, the system's)")
+	fs.StringVar(&o.logLevel, "log-level", "info", "how much to log: debug, info, or off")
+	fs.StringVar(&o.logFormat, "log-format", "text", "form of log entries: text, json, or logfmt")
+	fs.StringVar(&o.logFile, "log-file", "", "file to append the log to, in place of the standard error")
+	fs.BoolVar(&o.start, "start", false, "start Foliage at launch if it is not running")
+	fs.BoolVar(&o.open, "open", false, "bring Foliage to the front")
+	fs.StringVar(&o.hotkey, "hotkey", config.Get("FOLIAGE_HOTKEY", ""),
+		"keyboard shortcut that brings Foliage to the front, such as CmdOrCtrl+Shift+F")
+	fs.StringVar(&o.openURL, "open-url", "", "foliage:// link to open in Foliage")
+	fs.StringVar(&o.lookUp, "look-up", "", "look up the identifiers in the text in Foliage")
+	fs.BoolVar(&o.installService, "install-service", false,
+		"add \""+services.Name+"\" to the Services menu (macOS only), then exit")
+	fs.BoolVar(&o.uninstallService, "uninstall-service", false, "remove the service, then exit")
+	fs.BoolVar(&o.drop, "drop", false,
+		"ask what to do with the records listed in the files given as arguments")
+	fs.StringVar(&o.job, "job", "",
+		"start a lookup, change or delete job on the records listed in the files given as arguments, then exit")
+	fs.BoolVar(&o.status, "status", false, "print the status of Foliage as JSON, then exit")
+	fs.BoolVar(&o.installScript, "install-script-library", false,
+		"install an AppleScript library for using Foliage from scripts and Shortcuts, then exit")
+	fs.BoolVar(&o.uninstallScript, "uninstall-script-library", false, "remove the script library, then exit")
+	fs.BoolVar(&o.installDrop, "install-drop-target", false,
+		"make a place to drop files of identifiers on for Foliage, then exit")
+	fs.BoolVar(&o.uninstallDrop, "uninstall-drop-target", false, "remove the drop target, then exit")
+	fs.BoolVar(&o.registerURL, "register-url-scheme", false,
+		"register the widget to open foliage:// links, then exit")
+	fs.BoolVar(&o.unregisterURL, "unregister-url-scheme", false,
+		"remove the handler for foliage:// links, then exit")
+	fs.BoolVar(&o.installAgent, "install-agent", false,
+		"install a per-user agent that keeps the widget and Foliage running, then exit")
+	fs.BoolVar(&o.uninstallAgent, "uninstall-agent", false, "remove the agent, then exit")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if o.drop || o.job != "" {
+		o.dropFiles = fs.Args()
+	}
+	return o, nil
+}
+
 func main() {
-	onExit := func() { }
-	systray.Run(onReady, onExit)
+	defer crash.Recover()
+	if err := proxy.Configure(); err != nil {
+		log.Printf("unable to use the proxy: %v", err)
+	}
+	if programName() == "foliaged" {
+		os.Exit(runFoliaged(os.Args[1:]))
+	}
+	if len(os.Args) > 1 {
+		if os.Args[1] == "tray" {
+			// Later copies hand their arguments to the running tray, which
+			// parses them as options, so the subcommand is dropped.
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		} else if c, ok := lookUpSubcommand(os.Args[1]); ok {
+			os.Exit(c.run(os.Args[2:]))
+		}
+	}
+	o, _ := parseFlags(os.Args[0], os.Args[1:], flag.ExitOnError)
+	startOptions = o
+	if o.installAgent || o.uninstallAgent {
+		setServerURL(resolveURL(o.url, o.port))
+		os.Exit(runAgentCommand(o))
+	}
+	if o.registerURL || o.unregisterURL {
+		os.Exit(runURLSchemeCommand(o.registerURL))
+	}
+	if o.installDrop || o.uninstallDrop {
+		os.Exit(runDropTargetCommand(o.installDrop))
+	}
+	absDropFiles(o)
+	if o.installService || o.uninstallService {
+		os.Exit(runServiceCommand(o.installService))
+	}
+	if o.installScript || o.uninstallScript {
+		os.Exit(runScriptLibraryCommand(o.installScript))
+	}
+	if o.job != "" || o.status {
+		setServerURL(resolveURL(o.url, o.port))
+		os.Exit(runScriptCommand(o))
+	}
+	if err := setLogLevel(o.logLevel); err != nil {
+		log.Fatal(err)
+	}
+	if err := setLogFormat(o.logFormat); err != nil {
+		log.Fatal(err)
+	}
+	if o.logFile != "" && logLevel != logOff {
+		if err := setLogFile(o.logFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	setUpCrashes()
+	selectLanguage(o.lang)
+	setServerURL(resolveURL(o.url, o.port))
+	controlPort = o.controlPort
+	foliageCommand = o.command
+	setServerPid(o.pid)
+	manifest = loadManifest(o.menu)
+	trayTitle, trayTooltip = o.title, o.tooltip
+	notify.SetEnabled(config.Bool("FOLIAGE_NOTIFICATIONS", true))
+	logEvent(logInfo, "widget started", "pid", os.Getpid(), "os", runtime.GOOS+"/"+runtime.GOARCH,
+		"url", serverURL(), "control_port", controlPort)
+	if o.icon != "" {
+		if data, err := icon.Load(o.icon); err == nil {
+			iconStates = makeIconStates(data, false)
+			customIcon = true
+		} else {
+			log.Printf("unable to use icon: %v", err)
+		}
+	}
+
+	if !trayAvailable() {
+		// Foliage quits when the widget exits, so don't exit; just do
+		// nothing for as long as Foliage is running.
+		log.Print("no system tray available; running without an icon")
+		watchSignals(func() { cleanUp(nil) })
+		waitForServerExit()
+		cleanUp(nil)
+		return
+	}
+	self := acquireInstance()
+	watchSignals(func() { cleanUp(self) })
+	// A plain click on the icon opens Foliage, like most tray icons do;
+	// the menu is on the right button.  This has to be set before the
+	// icon is created, because on Linux it determines how the icon is
+	// advertised to the desktop.
+	systray.SetOnTapped(func() { open(serverURL()) })
+	setDPIAware()
+	systray.Run(onReady, func() { cleanUp(self) })
+}
+
+// selectLanguage makes the widget show its text in the language with the
+// tag, or, if it is "", in the system's language, if there is a translation
+// into it (see package locale).  Without one, the text is in English.
+func selectLanguage(tag string) {
+	lang, err := locale.Select(tag)
+	if err != nil {
+		log.Printf("unable to read a translation: %v", err)
+	}
+	if tag != "" && lang == locale.Default && !strings.HasPrefix(strings.ToLower(tag), locale.Default) {
+		log.Printf("no translation into %q; using %s", tag, lang)
+	}
+	debugf("showing text in %s", lang)
+}
+
+// cleanUp undoes what the widget set up, when it exits: it withdraws the
+// advertisement of Foliage on the network, stops the control server,
+// removes the instance state file (if self isn't nil), and closes the log.
+func cleanUp(self *instance.Instance) {
+	logEvent(logInfo, "widget exiting")
+	withdrawAdvert()
+	stopControlServer()
+	if self != nil {
+		self.Release()
+	}
+	closeLog()
+}
+
+// acquireInstance makes this the only running copy of the widget.  If another
+// copy is already running, it hands our arguments to that copy and exits.
+// It returns nil if the check could not be made, in which case we carry on
+// without it rather than leave the user with no icon at all.
+func acquireInstance() *instance.Instance {
+	path, err := instance.Path()
+	if err != nil {
+		log.Printf("unable to check for another copy of the widget: %v", err)
+		return nil
+	}
+	self, err := instance.Acquire(path, controlPort)
+	if err == instance.ErrRunning {
+		if err := instance.Forward(path, os.Args[1:]); err != nil {
+			log.Printf("unable to hand over to the running widget: %v", err)
+		} else {
+			log.Print("the widget is already running; handed over to it")
+		}
+		os.Exit(exitForwarded)
+	} else if err != nil {
+		log.Printf("unable to check for another copy of the widget: %v", err)
+		return nil
+	}
+	go self.Serve(handleForwarded)
+	return self
 }
 
 func onReady() {
-	systray.SetTemplateIcon(icon.Data, icon.Data)
-	systray.SetTooltip("Foliage")
-	mQuit := systray.AddMenuItem("Quit", "Quit Foliage")
-	go func() {
-		<-mQuit.ClickedCh
-		systray.Quit()
-	}()
+	atomic.StoreInt32(&trayRunning, 1)
+	if trayTitle != "" {
+		systray.SetTitle(trayTitle)
+	}
+	tc := buildMenu(manifest)
+	if !customIcon {
+		go watchTheme()
+	}
+	registerShortcut(startOptions.hotkey)
+	startClipboardWatch()
+	startScanner()
+	go watchPower()
+	if startOptions.openURL != "" {
+		go openLink(startOptions.openURL)
+	} else if startOptions.lookUp != "" {
+		go lookUpSelection(startOptions.lookUp)
+	} else if len(startOptions.dropFiles) > 0 {
+		go dropFiles(startOptions.dropFiles, startOptions.job)
+	} else if startOptions.open {
+		go bringToFront()
+	}
+	go watchServer()
+	go watchUpdates()
+	go watchSession()
+	go watchFolio()
+	go watchResources()
+	if pid := serverPid(); pid != 0 {
+		go watchProcess(pid)
+	} else if startOptions.start && foliageCommand != "" {
+		go startIfNotRunning()
+	}
+	if controlPort > 0 {
+		startControlServer(tc, controlPort)
+	}
+}
+
+// confirmQuit asks the user whether to quit if Foliage is in the middle of a
+// batch operation, since quitting would leave it half done.  It returns true
+// if there is no operation running, or the user wants to quit anyway.
+func confirmQuit() bool {
+	p := currentJob()
+	if p.Operation == "" {
+		return true
+	}
+	ok, err := dialog.Confirm(locale.T("Quit Foliage"),
+		locale.Sprintf("An operation is in progress (%s). Quitting now will leave it"+
+			" half done. Quit anyway?", p), locale.T("Quit"))
+	if err != nil {
+		// Without a way to ask, don't stop the user from quitting.
+		log.Printf("unable to ask for confirmation: %v", err)
+		return true
+	}
+	return ok
+}
+
+// quitFoliage shuts down the Foliage server (if we know about one) and then
+// the widget itself.
+func quitFoliage() {
+	atomic.StoreInt32(&quitting, 1)
+	if pid := serverPid(); pid != 0 && processAlive(pid) {
+		if err := shutdownServer(pid, shutdownTimeout); err != nil {
+			log.Print(err)
+		}
+	}
+	systray.Quit()
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"macos-systray-widget/batch"
+	"macos-systray-widget/foliolib"
+	"macos-systray-widget/marc"
+)
+
+// runMARC runs the marc subcommand, which writes the MARC records that SRS
+// keeps for instances to a file of MARC 21 or MARCXML: the instances of the
+// records given, by id, or, with --job, of every record the foliaged job
+// with the id did.  Holdings records and items stand for their instances,
+// each of which is written once.  Instances without a MARC record, such as
+// those catalogued in FOLIO itself, are skipped.
+func runMARC(args []string) int {
+	fs := subcommandFlags("marc")
+	job := fs.String("job", "", "write the MARC records of the instances of the records done by the foliaged job with this id")
+	kind := fs.String("kind", string(foliolib.Instance), "the kind of the records given: instance, holdings or item")
+	format := fs.String("format", string(marc.MARC21), "marc21 (ISO 2709) or marcxml")
+	output := fs.String("output", "", "the file to write, by default NAME-marc.mrc (or .xml) for a job, or else the standard output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*job == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return 2
+	}
+	f, err := marc.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	k, ids := foliolib.Kind(*kind), fs.Args()
+	if *job != "" {
+		root, err := journalDir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		j, done, err := batch.Done(root, *job)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if j.Operation == batch.Delete && !j.DryRun {
+			fmt.Fprintf(os.Stderr, "job %s deleted its records, so there is nothing to write\n", *job)
+			return 1
+		}
+		k = j.Kind
+		for _, d := range done {
+			ids = append(ids, d.ID)
+		}
+		if *output == "" {
+			*output = j.Name + "-marc" + f.Extension()
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	c, err := batchClient(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if *output != "" && *output != "-" {
+		if file, err = os.Create(*output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		out = file
+	}
+	w, err := marc.NewWriter(out, f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	written, skipped, failed := 0, 0, 0
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "stopped")
+			return 1
+		}
+		instance, err := c.InstanceOf(ctx, k, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			failed++
+			continue
+		} else if seen[instance] {
+			continue
+		}
+		seen[instance] = true
+		r, err := c.SourceRecord(ctx, instance)
+		if errors.Is(err, foliolib.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "%s: skipped: instance %s has no MARC record\n", id, instance)
+			skipped++
+			continue
+		} else if err == nil {
+			err = w.Write(r)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			failed++
+			continue
+		}
+		written++
+	}
+	err = w.Close()
+	if file != nil && err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	where := ""
+	if file != nil {
+		where = " to " + *output
+	}
+	fmt.Fprintf(os.Stderr, "%s written%s, %d skipped, %d failed\n", count(written, "MARC record"), where, skipped, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,285 @@
+// Package marc reads the MARC records FOLIO's Source Record Storage (SRS)
+// keeps for instances, which it gives as MARC-in-JSON, and writes them as
+// MARC 21 files (ISO 2709, what OCLC and most discovery systems load) or as
+// MARCXML, so that records changed with Foliage can be passed on to other
+// systems without exporting them again from FOLIO.
+package marc
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Record is a MARC record.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a field of a MARC record: a control field (tags 001 to 009),
+// which has only a Value, or a data field, which has indicators and
+// subfields.
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      string
+	Ind2      string
+	Subfields []Subfield
+}
+
+// Subfield is a subfield of a data field.
+type Subfield struct {
+	Code  string
+	Value string
+}
+
+// IsControl reports whether the field is a control field.
+func (f Field) IsControl() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+// Parse reads a record in MARC-in-JSON, as SRS has it in a record's
+// parsedRecord.content:
+//
+//	{"leader": "...", "fields": [{"001": "in00000012"},
+//	  {"245": {"ind1": "1", "ind2": "0", "subfields": [{"a": "Title"}]}}]}
+func Parse(data []byte) (*Record, error) {
+	var raw struct {
+		Leader string            `json:"leader"`
+		Fields []json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unable to read the MARC record: %w", err)
+	}
+	r := &Record{Leader: raw.Leader}
+	for _, data := range raw.Fields {
+		var tagged map[string]json.RawMessage
+		if err := json.Unmarshal(data, &tagged); err != nil || len(tagged) != 1 {
+			return nil, fmt.Errorf("unable to read the MARC record: a field isn't an object with one tag: %s", data)
+		}
+		for tag, value := range tagged {
+			f := Field{Tag: tag}
+			var data struct {
+				Ind1      string              `json:"ind1"`
+				Ind2      string              `json:"ind2"`
+				Subfields []map[string]string `json:"subfields"`
+			}
+			if json.Unmarshal(value, &f.Value) != nil {
+				if err := json.Unmarshal(value, &data); err != nil {
+					return nil, fmt.Errorf("unable to read field %s of the MARC record: %w", tag, err)
+				}
+				f.Ind1, f.Ind2 = data.Ind1, data.Ind2
+				for _, sub := range data.Subfields {
+					for code, v := range sub {
+						f.Subfields = append(f.Subfields, Subfield{code, v})
+					}
+				}
+			}
+			r.Fields = append(r.Fields, f)
+		}
+	}
+	return r, nil
+}
+
+// Format is a form MARC records are written in.
+type Format string
+
+// The forms Writer writes.
+const (
+	MARC21  Format = "marc21"
+	MARCXML Format = "marcxml"
+)
+
+// Extension returns the usual extension of files of records in the form.
+func (f Format) Extension() string {
+	if f == MARCXML {
+		return ".xml"
+	}
+	return ".mrc"
+}
+
+// ParseFormat returns the format with the name, or an error.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case MARC21, MARCXML:
+		return f, nil
+	case "mrc", "iso2709":
+		return MARC21, nil
+	case "xml":
+		return MARCXML, nil
+	}
+	return "", fmt.Errorf("unknown MARC format %q; it can be %s or %s", name, MARC21, MARCXML)
+}
+
+// Writer writes records to a file in a Format.  Close finishes the file.
+type Writer struct {
+	w       io.Writer
+	format  Format
+	started bool
+}
+
+// NewWriter returns a writer of records in the format to w.
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	if _, err := ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, format: format}, nil
+}
+
+// Write writes the record.
+func (w *Writer) Write(r *Record) error {
+	if w.format == MARC21 {
+		data, err := r.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = w.w.Write(data)
+		return err
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := r.writeXML(&b); err != nil {
+		return err
+	}
+	_, err := w.w.Write(b.Bytes())
+	return err
+}
+
+// start writes the start of a MARCXML collection, if it hasn't been.
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := io.WriteString(w.w, xml.Header+`<collection xmlns="http://www.loc.gov/MARC21/slim">`+"\n")
+	return err
+}
+
+// Close finishes the file, ending the collection of a MARCXML file.  It
+// doesn't close the file itself.
+func (w *Writer) Close() error {
+	if w.format != MARCXML {
+		return nil
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "</collection>\n")
+	return err
+}
+
+// The separators of ISO 2709.
+const (
+	subfieldDelimiter = 0x1f
+	fieldTerminator   = 0x1e
+	recordTerminator  = 0x1d
+)
+
+// MarshalBinary returns the record in MARC 21's exchange format (ISO 2709),
+// in UTF-8, as the leader then says.  It fails for records too long for
+// the format: 99,999 bytes, or 9,999 in a field.
+func (r *Record) MarshalBinary() ([]byte, error) {
+	var directory, data bytes.Buffer
+	for _, f := range r.Fields {
+		if len(f.Tag) != 3 {
+			return nil, fmt.Errorf("the MARC field tag %q isn't 3 characters long", f.Tag)
+		}
+		start := data.Len()
+		if f.IsControl() {
+			data.WriteString(f.Value)
+		} else {
+			data.WriteString(indicator(f.Ind1))
+			data.WriteString(indicator(f.Ind2))
+			for _, s := range f.Subfields {
+				data.WriteByte(subfieldDelimiter)
+				data.WriteString(s.Code)
+				data.WriteString(s.Value)
+			}
+		}
+		data.WriteByte(fieldTerminator)
+		length := data.Len() - start
+		if length > 9999 {
+			return nil, fmt.Errorf("MARC field %s is too long (%d bytes)", f.Tag, length)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+	base := 24 + directory.Len()
+	length := base + data.Len() + 1
+	if length > 99999 {
+		return nil, errors.New("the MARC record is too long for MARC 21 (more than 99,999 bytes); write it as MARCXML")
+	}
+	leader := []byte(fmt.Sprintf("%-24.24s", r.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a' // UTF-8.
+	copy(leader[10:12], "22")
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+// indicator returns the indicator, with a blank for none.
+func indicator(ind string) string {
+	if len(ind) != 1 {
+		return " "
+	}
+	return ind
+}
+
+// writeXML writes the record as a MARCXML record element.
+func (r *Record) writeXML(w io.Writer) error {
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	attr := func(name, value string) xml.Attr {
+		return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+	}
+	element := func(name, text string, attrs ...xml.Attr) error {
+		return e.EncodeElement(text, xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+	}
+	record := xml.StartElement{Name: xml.Name{Local: "record"}}
+	if err := e.EncodeToken(record); err != nil {
+		return err
+	}
+	if err := element("leader", r.Leader); err != nil {
+		return err
+	}
+	for _, f := range r.Fields {
+		if f.IsControl() {
+			if err := element("controlfield", f.Value, attr("tag", f.Tag)); err != nil {
+				return err
+			}
+			continue
+		}
+		start := xml.StartElement{Name: xml.Name{Local: "datafield"}, Attr: []xml.Attr{attr("tag", f.Tag), attr("ind1", indicator(f.Ind1)), attr("ind2", indicator(f.Ind2))}}
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, s := range f.Subfields {
+			if err := element("subfield", s.Value, attr("code", s.Code)); err != nil {
+				return err
+			}
+		}
+		if err := e.EncodeToken(start.End()); err != nil {
+			return err
+		}
+	}
+	if err := e.EncodeToken(record.End()); err != nil {
+		return err
+	}
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
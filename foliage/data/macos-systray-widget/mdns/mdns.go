@@ -0,0 +1,45 @@
+// Package mdns advertises a service on the local network with multicast DNS
+// (Bonjour), so that programs on the same network can find it by browsing
+// for its service type, without being told its host and port.  On macOS, the
+// system's Bonjour service does the advertising, through dns-sd.  Elsewhere,
+// this package answers multicast DNS queries for the service itself, on IPv4.
+package mdns
+
+import (
+	"errors"
+	"sync"
+)
+
+// Service describes a service to advertise.
+type Service struct {
+	Name string   // Name of this instance, such as "Foliage on lib-desk-3".
+	Type string   // Service type, such as "_foliage._tcp".
+	Port int      // Port on which this machine offers the service.
+	Text []string // Contents of the TXT record, as "key=value" strings.
+}
+
+// Publication is the advertisement of a service.
+type Publication struct {
+	once sync.Once
+	err  error
+	stop func() error
+}
+
+// Publish starts advertising the service, and keeps advertising it until
+// the publication is closed.
+func Publish(s Service) (*Publication, error) {
+	if s.Name == "" || s.Type == "" || s.Port <= 0 || s.Port > 65535 {
+		return nil, errors.New("a service needs a name, a type and a port")
+	}
+	stop, err := publish(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Publication{stop: stop}, nil
+}
+
+// Close stops advertising the service, telling the network it is gone.
+func (p *Publication) Close() error {
+	p.once.Do(func() { p.err = p.stop() })
+	return p.err
+}
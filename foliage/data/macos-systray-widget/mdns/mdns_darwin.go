@@ -0,0 +1,32 @@
+package mdns
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// publish has the system's Bonjour service advertise s, using dns-sd, which
+// keeps the registration for as long as it runs.  Bonjour picks another
+// name if the one given is already in use on the network.
+func publish(s Service) (func() error, error) {
+	args := append([]string{"-R", s.Name, s.Type, "local", strconv.Itoa(s.Port)}, s.Text...)
+	cmd := exec.Command("dns-sd", args...)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	return func() error {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		err := cmd.Process.Kill()
+		<-done
+		return err
+	}, nil
+}
@@ -0,0 +1,355 @@
+//go:build !darwin
+// +build !darwin
+
+package mdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// The multicast DNS group and port (RFC 6762).
+var group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// The DNS record types, classes and flags used here.
+const (
+	typeA   = 1
+	typePTR = 12
+	typeTXT = 16
+	typeSRV = 33
+	typeANY = 255
+
+	classIN    = 1
+	cacheFlush = 0x8000 // In a record's class: this replaces older records.
+	unicastQ   = 0x8000 // In a question's class: answer the asker directly.
+)
+
+// How long others may keep the records, in seconds: two minutes for the ones
+// about the host, and 75 minutes for the rest, as RFC 6762 suggests.  Answers
+// to ordinary DNS queries get shorter times.
+const (
+	hostTTL    = 120
+	serviceTTL = 4500
+	legacyTTL  = 10
+)
+
+// A name is a DNS name as its labels, without the root.  It isn't kept as a
+// dotted string, because the name of an instance may have dots in it.
+type name []string
+
+// equal reports whether n and m are the same name; case doesn't matter.
+func (n name) equal(m name) bool {
+	if len(n) != len(m) {
+		return false
+	}
+	for i := range n {
+		if !strings.EqualFold(n[i], m[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// record is one of the records advertised.
+type record struct {
+	name   name
+	rtype  uint16
+	unique bool // Are we the only ones with records of this name and type?
+	ttl    uint32
+	data   []byte
+}
+
+// question is a question from a query.
+type question struct {
+	name   name
+	qtype  uint16
+	qclass uint16
+}
+
+// responder answers queries for a service's records.
+type responder struct {
+	conn    *net.UDPConn
+	records []record
+	done    chan struct{}
+}
+
+// publish advertises s by announcing its records and then answering queries
+// for them, until the returned function is called.  It doesn't check whether
+// another machine uses the same name, so the name should include the host's.
+func publish(s Service) (func() error, error) {
+	records, err := serviceRecords(s)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return nil, err
+	}
+	r := &responder{conn: conn, records: records, done: make(chan struct{})}
+	go r.serve()
+	go r.announce()
+	return r.stop, nil
+}
+
+// serviceRecords returns the records that advertise s: a PTR record for
+// browsing, SRV and TXT records for the instance, and A records with the
+// host's addresses.
+func serviceRecords(s Service) ([]record, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	hostName := name{strings.SplitN(host, ".", 2)[0], "local"}
+	serviceType := append(strings.Split(s.Type, "."), "local")
+	instance := append(name{s.Name}, serviceType...)
+	for _, label := range append(instance, hostName[0]) {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("not usable in a DNS name: %q", label)
+		}
+	}
+	records := []record{
+		{name{"_services", "_dns-sd", "_udp", "local"}, typePTR, false, serviceTTL, encodeName(serviceType)},
+		{serviceType, typePTR, false, serviceTTL, encodeName(instance)},
+		{instance, typeSRV, true, hostTTL, srvData(s.Port, hostName)},
+		{instance, typeTXT, true, serviceTTL, txtData(s.Text)},
+	}
+	for _, ip := range localAddrs() {
+		records = append(records, record{hostName, typeA, true, hostTTL, ip})
+	}
+	return records, nil
+}
+
+// localAddrs returns the IPv4 addresses of the network interfaces that are
+// up, other than loopback ones.
+func localAddrs() []net.IP {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+				ips = append(ips, ipnet.IP.To4())
+			}
+		}
+	}
+	return ips
+}
+
+// announce tells the network about the records, twice, a second apart.
+func (r *responder) announce() {
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			select {
+			case <-r.done:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		r.conn.WriteToUDP(message(0, nil, r.records, nil, false, false), group)
+	}
+}
+
+// stop tells the network the records are gone, and stops answering.
+func (r *responder) stop() error {
+	close(r.done)
+	r.conn.WriteToUDP(message(0, nil, r.records, nil, false, true), group)
+	return r.conn.Close()
+}
+
+// serve reads queries and answers them, until the connection is closed.
+func (r *responder) serve() {
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := r.conn.ReadFromUDP(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			continue
+		}
+		r.answer(buf[:n], from)
+	}
+}
+
+// answer answers the query in packet, from the given address, if it asks
+// about any of the records.
+func (r *responder) answer(packet []byte, from *net.UDPAddr) {
+	id, questions, ok := parseQuery(packet)
+	if !ok {
+		return
+	}
+	// Queries from ordinary DNS resolvers come from other ports, and are
+	// answered directly, like a DNS server would.
+	legacy := from.Port != group.Port
+	unicast := legacy
+	chosen := make([]bool, len(r.records))
+	found, service := false, false
+	for _, q := range questions {
+		for i, rec := range r.records {
+			if rec.name.equal(q.name) && (q.qtype == rec.rtype || q.qtype == typeANY) {
+				chosen[i], found = true, true
+				service = service || rec.rtype == typePTR || rec.rtype == typeSRV
+				unicast = unicast || q.qclass&unicastQ != 0
+			}
+		}
+	}
+	if !found {
+		return
+	}
+	var answers, extra []record
+	for i, rec := range r.records {
+		// Someone looking for the service will want the records that say
+		// how to reach it next, so they are sent along.
+		if chosen[i] {
+			answers = append(answers, rec)
+		} else if service && rec.rtype != typePTR {
+			extra = append(extra, rec)
+		}
+	}
+	to := group
+	if unicast {
+		to = from
+	}
+	if !legacy {
+		id, questions = 0, nil
+	}
+	r.conn.WriteToUDP(message(id, questions, answers, extra, legacy, false), to)
+}
+
+// parseQuery returns the id and questions of the query in packet, and false
+// if it isn't a query.
+func parseQuery(packet []byte) (uint16, []question, bool) {
+	if len(packet) < 12 {
+		return 0, nil, false
+	}
+	// Responses, and queries other than standard ones, are ignored.
+	if binary.BigEndian.Uint16(packet[2:])&0xf800 != 0 {
+		return 0, nil, false
+	}
+	var questions []question
+	off := 12
+	for i := 0; i < int(binary.BigEndian.Uint16(packet[4:])); i++ {
+		n, next, ok := parseName(packet, off)
+		if !ok || next+4 > len(packet) {
+			return 0, nil, false
+		}
+		questions = append(questions, question{n,
+			binary.BigEndian.Uint16(packet[next:]), binary.BigEndian.Uint16(packet[next+2:])})
+		off = next + 4
+	}
+	return binary.BigEndian.Uint16(packet), questions, true
+}
+
+// parseName reads the name at off in packet, following the pointers that
+// compressed names have, and returns it with the offset just past it.
+func parseName(packet []byte, off int) (name, int, bool) {
+	var n name
+	end := -1
+	for jumps := 0; off < len(packet); {
+		length := int(packet[off])
+		switch {
+		case length == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return n, end, true
+		case length&0xc0 == 0xc0:
+			if off+2 > len(packet) || jumps > 20 {
+				return nil, 0, false
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(packet[off:]) & 0x3fff)
+			jumps++
+		case length&0xc0 != 0 || off+1+length > len(packet):
+			return nil, 0, false
+		default:
+			n = append(n, string(packet[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+	return nil, 0, false
+}
+
+// message encodes a response with the given answers and additional records.
+// Answers to ordinary DNS queries (legacy ones) repeat the questions, and
+// have short times to live; goodbyes have none.
+func message(id uint16, questions []question, answers, extra []record, legacy, goodbye bool) []byte {
+	b := binary.BigEndian.AppendUint16(nil, id)
+	b = binary.BigEndian.AppendUint16(b, 0x8400) // An authoritative answer.
+	b = binary.BigEndian.AppendUint16(b, uint16(len(questions)))
+	b = binary.BigEndian.AppendUint16(b, uint16(len(answers)))
+	b = binary.BigEndian.AppendUint16(b, 0)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(extra)))
+	for _, q := range questions {
+		b = append(b, encodeName(q.name)...)
+		b = binary.BigEndian.AppendUint16(b, q.qtype)
+		b = binary.BigEndian.AppendUint16(b, q.qclass&^unicastQ)
+	}
+	for _, rec := range append(answers[:len(answers):len(answers)], extra...) {
+		class, ttl := uint16(classIN), rec.ttl
+		if rec.unique && !legacy {
+			class |= cacheFlush
+		}
+		if goodbye {
+			ttl = 0
+		} else if legacy && ttl > legacyTTL {
+			ttl = legacyTTL
+		}
+		b = append(b, encodeName(rec.name)...)
+		b = binary.BigEndian.AppendUint16(b, rec.rtype)
+		b = binary.BigEndian.AppendUint16(b, class)
+		b = binary.BigEndian.AppendUint32(b, ttl)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(rec.data)))
+		b = append(b, rec.data...)
+	}
+	return b
+}
+
+// encodeName encodes n the way DNS messages have it, without compression.
+func encodeName(n name) []byte {
+	var b []byte
+	for _, label := range n {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// srvData returns the data of an SRV record for the port on the host.
+func srvData(port int, host name) []byte {
+	b := []byte{0, 0, 0, 0} // Priority and weight.
+	b = binary.BigEndian.AppendUint16(b, uint16(port))
+	return append(b, encodeName(host)...)
+}
+
+// txtData returns the data of a TXT record with the given strings, each of
+// which can be up to 255 bytes long.
+func txtData(text []string) []byte {
+	if len(text) == 0 {
+		return []byte{0}
+	}
+	var b []byte
+	for _, t := range text {
+		if len(t) > 255 {
+			t = t[:255]
+		}
+		b = append(b, byte(len(t)))
+		b = append(b, t...)
+	}
+	return b
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"fyne.io/systray"
+	"macos-systray-widget/autostart"
+	"macos-systray-widget/browser"
+	"macos-systray-widget/config"
+	"macos-systray-widget/control"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/icon"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/menu"
+)
+
+// loadManifest returns the menu definition from the given file, or from the
+// file named by the setting FOLIAGE_MENU, or the built-in default.  If the
+// file can't be read, the problem is logged and the default is used.
+func loadManifest(path string) *menu.Manifest {
+	if path == "" {
+		path = config.Get("FOLIAGE_MENU", "")
+	}
+	if path != "" {
+		m, err := menu.Load(path)
+		if err == nil {
+			return m
+		}
+		log.Printf("unable to use menu definition: %v", err)
+	}
+	return menu.Default()
+}
+
+// buildMenu creates the tray menu from the manifest.  The hidden slots for
+// menu items added via the control API are created where the manifest has
+// a "dynamic" entry.
+func buildMenu(m *menu.Manifest) *trayControl {
+	var tc *trayControl
+	var add func(items []menu.Item, parent *systray.MenuItem)
+	add = func(items []menu.Item, parent *systray.MenuItem) {
+		for _, item := range items {
+			switch {
+			case item.Separator:
+				if parent == nil {
+					systray.AddSeparator()
+				} else {
+					parent.AddSeparator()
+				}
+			case item.Action == menu.ActionDynamic:
+				if tc == nil && parent == nil {
+					tc = newTrayControl(menuSlots)
+				}
+			case item.Action == menu.ActionProgress:
+				mi := addItem(parent, item, control.Progress{}.String(), false)
+				mi.Disable()
+				progressItems = append(progressItems, mi)
+			case item.Action == menu.ActionSession:
+				mi := addItem(parent, item, sessionTitle(""), false)
+				mi.Disable()
+				sessionItems = append(sessionItems, mi)
+			case item.Action == menu.ActionResources:
+				mi := addItem(parent, item, resourceTitle(), false)
+				mi.Disable()
+				if !showResources() {
+					mi.Hide()
+				}
+				resourceItems = append(resourceItems, mi)
+			case item.Action == menu.ActionInstances:
+				addInstanceSlots(parent, item)
+			case item.Action == menu.ActionTenants:
+				addTenantMenu(parent, item)
+			case item.Action == menu.ActionRecent:
+				addRecentMenu(parent, item)
+			case item.Action == menu.ActionDemoMode:
+				mi := addItem(parent, item, item.Title, true)
+				demoItems = append(demoItems, mi)
+				go handleClicks(mi, item)
+			case item.Action == menu.ActionClipboard:
+				mi := addItem(parent, item, item.Title, true)
+				clipboardItems = append(clipboardItems, mi)
+				go handleClicks(mi, item)
+			case item.Action == menu.ActionLogin:
+				mi := addItem(parent, item, item.Title, true)
+				if autostart.Enabled() {
+					mi.Check()
+				}
+				loginItems = append(loginItems, mi)
+				go handleClicks(mi, item)
+			default:
+				mi := addItem(parent, item, item.Title, false)
+				switch item.Action {
+				case menu.ActionStart:
+					mi.Hide()
+					startItems = append(startItems, mi)
+				case menu.ActionRestart:
+					mi.Hide()
+					restartItems = append(restartItems, mi)
+				case menu.ActionUpdate:
+					mi.Hide()
+					updateItems = append(updateItems, mi)
+				case menu.ActionPause:
+					mi.Hide()
+					pauseItems = append(pauseItems, mi)
+				case menu.ActionResume:
+					mi.Hide()
+					resumeItems = append(resumeItems, mi)
+				case menu.ActionCancel:
+					mi.Hide()
+					cancelItems = append(cancelItems, mi)
+				case menu.ActionReauth:
+					mi.Hide()
+					reauthItems = append(reauthItems, mi)
+				case menu.ActionClipboardLookup:
+					mi.Hide()
+					clipboardLookupItems = append(clipboardLookupItems, mi)
+				case menu.ActionLastRecord:
+					mi.Hide()
+					lastRecordItems = append(lastRecordItems, mi)
+				}
+				if item.Disabled {
+					mi.Disable()
+				}
+				if len(item.Items) > 0 {
+					add(item.Items, mi)
+				} else {
+					go handleClicks(mi, item)
+				}
+			}
+		}
+	}
+	add(m.Items, nil)
+	if tc == nil {
+		tc = newTrayControl(0)
+	}
+	return tc
+}
+
+// addItem adds a menu item with the given title to the parent item, or to
+// the top level of the menu if parent is nil.  A checkbox item is needed for
+// showing a check mark on Linux.  If the manifest gives an icon for the item,
+// it is shown next to the title (except on Linux desktops that don't show
+// menu icons).
+func addItem(parent *systray.MenuItem, item menu.Item, title string, checkbox bool) *systray.MenuItem {
+	title, tooltip := locale.T(title), locale.T(item.Tooltip)
+	var mi *systray.MenuItem
+	switch {
+	case parent == nil && checkbox:
+		mi = systray.AddMenuItemCheckbox(title, tooltip, false)
+	case parent == nil:
+		mi = systray.AddMenuItem(title, tooltip)
+	case checkbox:
+		mi = parent.AddSubMenuItemCheckbox(title, tooltip, false)
+	default:
+		mi = parent.AddSubMenuItem(title, tooltip)
+	}
+	if item.Icon != "" {
+		if data, err := icon.Load(item.Icon); err == nil {
+			mi.SetIcon(data)
+		} else {
+			log.Printf("unable to use icon for menu item %q: %v", item.Title, err)
+		}
+	}
+	return mi
+}
+
+// handleClicks performs the item's action each time the item is clicked.
+func handleClicks(mi *systray.MenuItem, item menu.Item) {
+	defer crash.Recover()
+	for range mi.ClickedCh {
+		debugf("menu item %q chosen", item.Title)
+		switch item.Action {
+		case menu.ActionOpen:
+			open(serverURL() + item.URL)
+		case menu.ActionURL:
+			open(item.URL)
+		case menu.ActionCommand:
+			runCommand(item.Command)
+		case menu.ActionQuit:
+			if confirmQuit() {
+				quitFoliage()
+				return
+			}
+		case menu.ActionStart, menu.ActionRestart:
+			startFoliage(true)
+		case menu.ActionPause:
+			pauseJob()
+		case menu.ActionResume:
+			resumeJob()
+		case menu.ActionCancel:
+			cancelJob()
+		case menu.ActionReauth:
+			reauthenticate()
+		case menu.ActionDemoMode:
+			toggleDemoMode(mi)
+		case menu.ActionLogin:
+			toggleStartAtLogin(mi)
+		case menu.ActionClipboard:
+			toggleClipboardWatch(mi)
+		case menu.ActionClipboardLookup:
+			lookUpClipboard()
+		case menu.ActionLastRecord:
+			openLastRecord()
+		case menu.ActionCopyURL:
+			copyURL()
+		case menu.ActionLog:
+			openLog()
+		case menu.ActionBackups:
+			openBackups()
+		case menu.ActionAbout:
+			showAbout()
+		case menu.ActionPreferences:
+			showPreferences()
+		case menu.ActionCheckUpdate:
+			checkForUpdate(true)
+		case menu.ActionUpdate:
+			openUpdate()
+		}
+	}
+}
+
+func open(url string) {
+	if err := browser.Open(url); err != nil {
+		log.Printf("unable to open %s: %v", url, err)
+	}
+}
+
+// runCommand runs a shell command, without waiting for it to finish.
+func runCommand(command string) {
+	cmd := shellCommand(command)
+	if err := cmd.Start(); err != nil {
+		log.Printf("unable to run %q: %v", command, err)
+		return
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Printf("command %q failed: %v", strings.TrimSpace(command), err)
+		}
+	}()
+}
@@ -0,0 +1,245 @@
+// Package menu reads the definition of the widget's menu from a manifest
+// file, so that the menu can be changed without rebuilding the widget.
+//
+// A manifest is a JSON or YAML file containing a list of items.  Each item
+// is either a separator or a menu entry with a title, an optional tooltip,
+// and an action to perform when it is chosen.  An entry can instead have a
+// list of items of its own, in which case it is shown as a submenu.  An entry
+// can also name an image file ("icon", a PNG file, or an .ico file holding
+// a PNG image) to show beside its title, and can be shown grayed out so that
+// it can't be chosen ("disabled").  Paths of icons are relative to the
+// directory containing the manifest.  The actions are:
+//
+//	open     open the Foliage user interface (or the path given by "url",
+//	         relative to the Foliage URL) in the default browser
+//	url      open the URL given by "url" in the default browser
+//	command  run the shell command given by "command"
+//	copy-url put the Foliage URL on the clipboard
+//	log      open Foliage's log file in the default viewer
+//	backups  open the folder of record backups in the file manager
+//	about    show the version of Foliage and the FOLIO tenant it is using
+//	preferences
+//	         show the Preferences window, for the widget's own settings
+//	check-update
+//	         check now whether a newer release of Foliage is available
+//	update   open the page for a newer release of Foliage; this entry is
+//	         only shown when one is available, and its title is replaced by
+//	         "Update available — " and the version number
+//	quit     quit Foliage
+//	start    start Foliage and open it in the browser; this entry is only
+//	         shown when Foliage is not responding, and only if the widget
+//	         knows how to start it
+//	restart  start Foliage again; this entry is only shown after Foliage has
+//	         stopped unexpectedly, and only if the widget knows how to start it
+//	pause    pause Foliage's batch operation; only shown while one is running
+//	resume   resume the paused batch operation; only shown while it is paused
+//	cancel   stop Foliage's batch operation, after asking for confirmation;
+//	         only shown while one is running
+//	reauthenticate
+//	         open Foliage and ask for FOLIO credentials, to get a new token;
+//	         only shown when the token has expired or is about to expire
+//	demo-mode
+//	         turn Foliage's demo mode, in which it doesn't change any
+//	         records, on or off; the entry is checked while it is on
+//	watch-clipboard
+//	         watch the clipboard for item barcodes and FOLIO UUIDs, or stop;
+//	         the entry is checked while the widget is watching
+//	clipboard-lookup
+//	         look up the identifiers found on the clipboard in Foliage; only
+//	         shown while the clipboard is being watched and holds some, and
+//	         its title is replaced by "Look Up", the identifiers (or how
+//	         many there are) and "in Foliage"
+//	last-record
+//	         open the record Foliage last showed or changed in FOLIO's own
+//	         interface; only shown once Foliage has reported one, and only
+//	         if the tenant's interface is known, and its tooltip says which
+//	         record it is
+//	start-at-login
+//	         register the widget to start when the user logs in, or remove
+//	         the registration; the entry is checked while it is registered
+//	tenants  a submenu of the FOLIO tenants listed in the tenants file (see
+//	         package tenants), with the one Foliage is using checked;
+//	         choosing another switches Foliage to it, after confirmation.
+//	         There is no submenu if no tenants are listed
+//	recent   a submenu of the lookups and batch jobs Foliage has reported
+//	         most recently (up to 10); choosing one opens its results in the
+//	         browser.  The entry is only shown once there is something in it
+//	progress not clickable; shows the progress of the batch operation that
+//	         Foliage is running, or "Idle" (the title is not needed)
+//	session  not clickable; shows the FOLIO tenant Foliage is using and
+//	         whether it is logged in (the title is not needed)
+//	resources
+//	         not clickable; shows how much memory and processor time the
+//	         Foliage process uses (the title is not needed); only shown if
+//	         the setting FOLIAGE_SHOW_RESOURCES is true
+//	dynamic  not a real entry; marks where the items added by Foliage using
+//	         the control API should appear
+//	instances
+//	         not a real entry; marks where other running Foliage instances
+//	         (at different URLs) appear, each as a submenu with Open, its
+//	         status, and Quit
+//
+// For example, in YAML:
+//
+//	items:
+//	  - title: Open Foliage
+//	    action: open
+//	  - action: dynamic
+//	  - separator: true
+//	  - title: Quit
+//	    action: quit
+package menu
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions that a menu item can perform.
+const (
+	ActionOpen            = "open"
+	ActionURL             = "url"
+	ActionCommand         = "command"
+	ActionCopyURL         = "copy-url"
+	ActionLog             = "log"
+	ActionBackups         = "backups"
+	ActionAbout           = "about"
+	ActionPreferences     = "preferences"
+	ActionCheckUpdate     = "check-update"
+	ActionUpdate          = "update"
+	ActionQuit            = "quit"
+	ActionStart           = "start"
+	ActionRestart         = "restart"
+	ActionPause           = "pause"
+	ActionResume          = "resume"
+	ActionCancel          = "cancel"
+	ActionReauth          = "reauthenticate"
+	ActionDemoMode        = "demo-mode"
+	ActionLogin           = "start-at-login"
+	ActionClipboard       = "watch-clipboard"
+	ActionClipboardLookup = "clipboard-lookup"
+	ActionLastRecord      = "last-record"
+	ActionRecent          = "recent"
+	ActionTenants         = "tenants"
+	ActionProgress        = "progress"
+	ActionSession         = "session"
+	ActionResources       = "resources"
+	ActionDynamic         = "dynamic"
+	ActionInstances       = "instances"
+)
+
+// Item is one entry in the menu.
+type Item struct {
+	Title     string `json:"title" yaml:"title"`
+	Tooltip   string `json:"tooltip" yaml:"tooltip"`
+	Separator bool   `json:"separator" yaml:"separator"`
+	Action    string `json:"action" yaml:"action"`
+	URL       string `json:"url" yaml:"url"`
+	Command   string `json:"command" yaml:"command"`
+	Icon      string `json:"icon" yaml:"icon"`         // Image file shown beside the title.
+	Disabled  bool   `json:"disabled" yaml:"disabled"` // Show the item grayed out?
+	Items     []Item `json:"items" yaml:"items"`
+}
+
+// Manifest is the complete menu definition.
+type Manifest struct {
+	Items []Item `json:"items" yaml:"items"`
+}
+
+//go:embed default.json
+var defaultManifest []byte
+
+// Default returns the built-in menu definition.
+func Default() *Manifest {
+	m, err := parse(defaultManifest, false)
+	if err != nil {
+		panic("invalid built-in menu manifest: " + err.Error())
+	}
+	return m
+}
+
+// Load reads the manifest in the named file.  Files whose names end in
+// ".yaml" or ".yml" are read as YAML; anything else is read as JSON.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	m, err := parse(data, ext == ".yaml" || ext == ".yml")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	resolveIcons(m.Items, filepath.Dir(path))
+	return m, nil
+}
+
+// resolveIcons makes the paths of the items' icons relative to dir.
+func resolveIcons(items []Item, dir string) {
+	for i := range items {
+		if items[i].Icon != "" && !filepath.IsAbs(items[i].Icon) {
+			items[i].Icon = filepath.Join(dir, items[i].Icon)
+		}
+		resolveIcons(items[i].Items, dir)
+	}
+}
+
+func parse(data []byte, isYAML bool) (*Manifest, error) {
+	m := &Manifest{}
+	var err error
+	if isYAML {
+		err = yaml.Unmarshal(data, m)
+	} else {
+		err = json.Unmarshal(data, m)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(m.Items); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func validate(items []Item) error {
+	for _, item := range items {
+		switch {
+		case item.Separator, item.Action == ActionDynamic, item.Action == ActionInstances,
+			item.Action == ActionProgress, item.Action == ActionSession, item.Action == ActionResources:
+			continue
+		}
+		if item.Title == "" {
+			return fmt.Errorf("menu item without a title")
+		}
+		if len(item.Items) > 0 {
+			if err := validate(item.Items); err != nil {
+				return err
+			}
+			continue
+		}
+		switch item.Action {
+		case ActionOpen, ActionCopyURL, ActionLog, ActionBackups, ActionAbout, ActionPreferences,
+			ActionCheckUpdate, ActionUpdate, ActionQuit, ActionStart, ActionRestart,
+			ActionPause, ActionResume, ActionCancel, ActionReauth, ActionDemoMode,
+			ActionLogin, ActionRecent, ActionTenants, ActionClipboard, ActionClipboardLookup,
+			ActionLastRecord:
+		case ActionURL:
+			if item.URL == "" {
+				return fmt.Errorf("menu item %q has no url", item.Title)
+			}
+		case ActionCommand:
+			if item.Command == "" {
+				return fmt.Errorf("menu item %q has no command", item.Title)
+			}
+		default:
+			return fmt.Errorf("menu item %q has unknown action %q", item.Title, item.Action)
+		}
+	}
+	return nil
+}
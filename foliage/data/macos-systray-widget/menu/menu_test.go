@@ -0,0 +1,80 @@
+package menu
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// actions returns the actions of the items and their submenus.
+func actions(items []Item) map[string]bool {
+	found := map[string]bool{}
+	for _, item := range items {
+		if item.Action != "" {
+			found[item.Action] = true
+		}
+		for action := range actions(item.Items) {
+			found[action] = true
+		}
+	}
+	return found
+}
+
+func TestDefault(t *testing.T) {
+	m := Default()
+	if len(m.Items) == 0 {
+		t.Fatal("the built-in menu has no items")
+	}
+	found := actions(m.Items)
+	for _, action := range []string{ActionOpen, ActionLastRecord, ActionResources, ActionQuit} {
+		if !found[action] {
+			t.Errorf("the built-in menu has no %q item", action)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		isYAML  bool
+		wantErr string
+	}{
+		{"json", `{"items": [{"title": "Open", "action": "open"}, {"separator": true}]}`, false, ""},
+		{"yaml", "items:\n  - title: Last record\n    action: last-record\n", true, ""},
+		{"submenu", `{"items": [{"title": "More", "items": [{"title": "Quit", "action": "quit"}]}]}`, false, ""},
+		{"unknown action", `{"items": [{"title": "Fly", "action": "fly"}]}`, false, `unknown action "fly"`},
+		{"no title", `{"items": [{"action": "open"}]}`, false, "without a title"},
+		{"no url", `{"items": [{"title": "Site", "action": "url"}]}`, false, "has no url"},
+		{"no command", `{"items": [{"title": "Run", "action": "command"}]}`, false, "has no command"},
+		{"bad json", `{"items": [`, false, "unexpected end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse([]byte(tt.data), tt.isYAML)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("parse: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("parse: got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadResolvesIcons(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "menu.yaml")
+	data := "items:\n  - title: Open\n    action: open\n    icon: open.png\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "open.png"); m.Items[0].Icon != want {
+		t.Errorf("icon is %q, want %q", m.Items[0].Icon, want)
+	}
+}
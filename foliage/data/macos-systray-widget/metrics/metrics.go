@@ -0,0 +1,229 @@
+// Package metrics keeps counters, gauges and histograms, and serves them in
+// the text format Prometheus reads, so that a program's work can be graphed
+// and alerted on alongside everything else a hosting team runs.  Each metric
+// can have labels, such as the kind of request or the result, and keeps a
+// series of values for each combination of their values that it has seen.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds metrics, and writes them out.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*metric
+}
+
+// NewRegistry returns a registry with no metrics.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// metric is a metric with its series.
+type metric struct {
+	name, help, kind string
+	labels           []string
+	buckets          []float64 // For histograms.
+	series           map[string]*series
+}
+
+// series is the value of a metric for one set of label values.
+type series struct {
+	values []string
+	value  float64  // The value, for counters and gauges; the sum, for histograms.
+	counts []uint64 // For histograms, the count in each bucket, and then in all.
+}
+
+func (r *Registry) add(name, help, kind string, buckets []float64, labels []string) *metric {
+	m := &metric{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: map[string]*series{}}
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+	return m
+}
+
+// get returns the series for the label values, making it if need be.  The
+// registry's lock must be held.
+func (m *metric) get(values []string) *series {
+	if len(values) != len(m.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, not %d", m.name, len(m.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...)}
+		if m.kind == "histogram" {
+			s.counts = make([]uint64, len(m.buckets)+1)
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+// Counter is a count that only goes up, such as of requests made.
+type Counter struct {
+	r *Registry
+	m *metric
+}
+
+// Counter adds a counter to the registry.  Its name should end in "_total".
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r, r.add(name, help, "counter", nil, labels)}
+}
+
+// Inc adds one to the counter, for the label values.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Counter) Add(v float64, values ...string) {
+	c.r.mu.Lock()
+	c.m.get(values).value += v
+	c.r.mu.Unlock()
+}
+
+// Gauge is a value that goes up and down, such as the number of jobs
+// running.
+type Gauge struct {
+	r *Registry
+	m *metric
+}
+
+// Gauge adds a gauge to the registry.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r, r.add(name, help, "gauge", nil, labels)}
+}
+
+// Set sets the gauge, for the label values.
+func (g *Gauge) Set(v float64, values ...string) {
+	g.r.mu.Lock()
+	g.m.get(values).value = v
+	g.r.mu.Unlock()
+}
+
+// Add adds v to the gauge, which may be negative.
+func (g *Gauge) Add(v float64, values ...string) {
+	g.r.mu.Lock()
+	g.m.get(values).value += v
+	g.r.mu.Unlock()
+}
+
+// Histogram counts values, such as how long requests take, in buckets by
+// their size.
+type Histogram struct {
+	r *Registry
+	m *metric
+}
+
+// Histogram adds a histogram to the registry, with buckets for values up to
+// each of the bounds, which are in increasing order.
+func (r *Registry) Histogram(name, help string, bounds []float64, labels ...string) *Histogram {
+	return &Histogram{r, r.add(name, help, "histogram", bounds, labels)}
+}
+
+// Observe counts a value, for the label values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.m.get(values)
+	for i, bound := range h.m.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.counts[len(h.m.buckets)]++
+	s.value += v
+}
+
+// WriteTo writes the metrics in Prometheus's text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	b := bufio.NewWriter(cw)
+	r.mu.Lock()
+	for _, m := range r.metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind)
+		keys := make([]string, 0, len(m.series))
+		for k := range m.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := m.series[k]
+			if m.kind != "histogram" {
+				fmt.Fprintf(b, "%s%s %s\n", m.name, labels(m.labels, s.values, "", ""), number(s.value))
+				continue
+			}
+			for i, bound := range m.buckets {
+				fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, labels(m.labels, s.values, "le", number(bound)), s.counts[i])
+			}
+			all := s.counts[len(m.buckets)]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", m.name, labels(m.labels, s.values, "le", "+Inf"), all)
+			fmt.Fprintf(b, "%s_sum%s %s\n", m.name, labels(m.labels, s.values, "", ""), number(s.value))
+			fmt.Fprintf(b, "%s_count%s %d\n", m.name, labels(m.labels, s.values, "", ""), all)
+		}
+	}
+	r.mu.Unlock()
+	err := b.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP serves the metrics, for Prometheus to scrape.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+// labels returns the label names and values in braces, with an extra label,
+// if name isn't "", or "" if there are none.
+func labels(names, values []string, name, value string) string {
+	if len(names) == 0 && name == "" {
+		return ""
+	}
+	var parts []string
+	for i, n := range names {
+		parts = append(parts, n+`="`+escapeValue(values[i])+`"`)
+	}
+	if name != "" {
+		parts = append(parts, name+`="`+value+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var (
+	escapeValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace
+	escapeHelp  = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace
+)
+
+// number returns v as Prometheus writes numbers.
+func number(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,51 @@
+// Package notify posts desktop notifications, such as the one shown when a
+// batch job finishes.
+package notify
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNotSupported is returned by Post on systems where notifications are
+// not implemented.
+var ErrNotSupported = errors.New("notifications are not supported on this system")
+
+// Notification is a message to show to the user.
+type Notification struct {
+	Title   string
+	Message string
+	URL     string  // Optional URL to open when the notification is clicked.
+	Action  *Action // Optional button shown with the notification.
+}
+
+// Action is a button in a notification that opens a URL when clicked.
+// Not all systems can show buttons in notifications.
+type Action struct {
+	Label string
+	URL   string
+}
+
+// Nonzero while the user has turned notifications off.
+var muted int32
+
+// SetEnabled turns notifications on or off.  While they are off, Post does
+// nothing.
+func SetEnabled(on bool) {
+	if on {
+		atomic.StoreInt32(&muted, 0)
+	} else {
+		atomic.StoreInt32(&muted, 1)
+	}
+}
+
+// Post shows the notification using the system's notification facility.
+func Post(n Notification) error {
+	if atomic.LoadInt32(&muted) != 0 {
+		return nil
+	}
+	if n.Title == "" {
+		n.Title = "Foliage"
+	}
+	return post(n)
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"os/exec"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// post posts the notification to Notification Center.  Notifications posted
+// using AppleScript can't do anything when clicked, so if the notification
+// has a URL and the program terminal-notifier is installed, we use that
+// instead, because it can open the URL when the notification is clicked.
+func post(n Notification) error {
+	if n.URL != "" {
+		if tn, err := exec.LookPath("terminal-notifier"); err == nil {
+			return exec.Command(tn, "-title", n.Title, "-message", n.Message,
+				"-open", n.URL).Run()
+		}
+	}
+	script := "display notification " + shellquote.AppleScript(n.Message) +
+		" with title " + shellquote.AppleScript(n.Title)
+	return exec.Command("osascript", "-e", script).Run()
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"log"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"macos-systray-widget/browser"
+)
+
+// Names used by the freedesktop.org desktop notifications specification,
+// which is implemented by GNOME, KDE, and most other Linux desktops.
+const (
+	busName      = "org.freedesktop.Notifications"
+	objectPath   = "/org/freedesktop/Notifications"
+	notifyMethod = busName + ".Notify"
+	actionSignal = "ActionInvoked"
+	closedSignal = "NotificationClosed"
+	actionKey    = "open"
+	defaultKey   = "default" // Action invoked by clicking the notification.
+	expireTime   = int32(-1) // Let the notification server decide.
+)
+
+var (
+	connOnce sync.Once
+	conn     *dbus.Conn
+	connErr  error
+
+	// URLs to open for notifications, by notification id and action key.
+	mu      sync.Mutex
+	actions = map[uint32]map[string]string{}
+)
+
+// post sends the notification to the desktop's notification server over
+// the D-Bus session bus.
+func post(n Notification) error {
+	connOnce.Do(connect)
+	if connErr != nil {
+		return connErr
+	}
+	// Actions are given as pairs of key and label.  The default action is
+	// invoked by clicking the notification itself and has no label shown.
+	var buttons []string
+	urls := map[string]string{}
+	if n.URL != "" {
+		buttons = append(buttons, defaultKey, "")
+		urls[defaultKey] = n.URL
+	}
+	if n.Action != nil && n.Action.URL != "" {
+		label := n.Action.Label
+		if label == "" {
+			label = "Open"
+		}
+		buttons = append(buttons, actionKey, label)
+		urls[actionKey] = n.Action.URL
+	}
+	obj := conn.Object(busName, objectPath)
+	call := obj.Call(notifyMethod, 0, "Foliage", uint32(0), "", n.Title, n.Message,
+		buttons, map[string]dbus.Variant{}, expireTime)
+	if call.Err != nil {
+		return call.Err
+	}
+	var id uint32
+	if err := call.Store(&id); err != nil {
+		return err
+	}
+	if len(urls) > 0 {
+		mu.Lock()
+		actions[id] = urls
+		mu.Unlock()
+	}
+	return nil
+}
+
+// connect opens the session bus and starts listening for the signals sent
+// when the user clicks a notification's button or the notification closes.
+// If there is no session bus, notifications are not supported.
+func connect() {
+	conn, connErr = dbus.ConnectSessionBus()
+	if connErr != nil {
+		log.Printf("unable to connect to D-Bus session bus: %v", connErr)
+		connErr = ErrNotSupported
+		return
+	}
+	for _, member := range []string{actionSignal, closedSignal} {
+		err := conn.AddMatchSignal(dbus.WithMatchInterface(busName), dbus.WithMatchMember(member))
+		if err != nil {
+			log.Printf("unable to watch for notification signals: %v", err)
+		}
+	}
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	go handleSignals(signals)
+}
+
+// handleSignals opens the URL for a notification when the user clicks it or
+// its button, and forgets the URLs when the notification is closed.
+func handleSignals(signals <-chan *dbus.Signal) {
+	for sig := range signals {
+		if len(sig.Body) < 2 {
+			continue
+		}
+		id, ok := sig.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		mu.Lock()
+		urls := actions[id]
+		delete(actions, id)
+		mu.Unlock()
+		if sig.Name != busName+"."+actionSignal {
+			continue
+		}
+		key, _ := sig.Body[1].(string)
+		if url, found := urls[key]; found {
+			if err := browser.Open(url); err != nil {
+				log.Printf("unable to open %s: %v", url, err)
+			}
+		}
+	}
+}
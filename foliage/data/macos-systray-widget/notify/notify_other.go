@@ -0,0 +1,8 @@
+//go:build !darwin && !windows && !linux
+// +build !darwin,!windows,!linux
+
+package notify
+
+func post(n Notification) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// Toast notifications must be attributed to a registered application user
+// model ID.  Foliage doesn't register one of its own, so we use the ID of
+// Windows PowerShell, which is present on all versions of Windows that
+// support toasts.
+const appID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// The script that shows the toast, with placeholders for the toast XML and
+// the application ID.
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml(%s)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(%s).Show($toast)
+`
+
+// post shows the notification as a Windows toast, by running a PowerShell
+// script that uses the WinRT toast notification API.
+func post(n Notification) error {
+	script := fmt.Sprintf(toastScript, shellquote.PowerShell(toastXML(n)),
+		shellquote.PowerShell(appID))
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive",
+		"-EncodedCommand", encode(script))
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Run()
+}
+
+// toastXML returns the XML description of the toast.  The toast itself and
+// its action button both use protocol activation, meaning that Windows opens
+// the URL when they are clicked.
+func toastXML(n Notification) string {
+	var b strings.Builder
+	if n.URL != "" {
+		b.WriteString(`<toast activationType="protocol" launch="` + escape(n.URL) + `">`)
+	} else {
+		b.WriteString(`<toast>`)
+	}
+	b.WriteString(`<visual><binding template="ToastGeneric">`)
+	b.WriteString("<text>" + escape(n.Title) + "</text>")
+	b.WriteString("<text>" + escape(n.Message) + "</text>")
+	b.WriteString("</binding></visual>")
+	if n.Action != nil && n.Action.URL != "" {
+		label := n.Action.Label
+		if label == "" {
+			label = "Open"
+		}
+		b.WriteString(`<actions><action activationType="protocol"`)
+		b.WriteString(` content="` + escape(label) + `"`)
+		b.WriteString(` arguments="` + escape(n.Action.URL) + `"/></actions>`)
+	}
+	b.WriteString("</toast>")
+	return b.String()
+}
+
+// escape returns s with XML special characters escaped.
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// encode returns the script in the form needed by -EncodedCommand: base64
+// encoding of the UTF-16LE representation of the text.
+func encode(script string) string {
+	units := utf16.Encode([]rune(script))
+	buf := make([]byte, 2*len(units))
+	for i, u := range units {
+		buf[2*i] = byte(u)
+		buf[2*i+1] = byte(u >> 8)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
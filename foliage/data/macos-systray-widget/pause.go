@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"fyne.io/systray"
+	"macos-systray-widget/control"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// Menu items for pausing, resuming, and canceling Foliage's batch operation.
+// They are hidden except while an operation is running, and only the one of
+// pause and resume that applies is shown.
+var pauseItems, resumeItems, cancelItems []*systray.MenuItem
+
+// The most recent progress report from Foliage.
+var (
+	jobMu sync.Mutex
+	job   control.Progress
+)
+
+// setJob records the progress of Foliage's batch operation and updates the
+// menu to suit.
+func setJob(p control.Progress) {
+	jobMu.Lock()
+	job = p
+	jobMu.Unlock()
+	showJobItems(p.Operation != "", p.Paused)
+}
+
+// currentJob returns the most recent progress report from Foliage.  Its
+// Operation is empty if no operation is running.
+func currentJob() control.Progress {
+	jobMu.Lock()
+	defer jobMu.Unlock()
+	return job
+}
+
+// showJobItems shows the pause or resume items to suit the state of the
+// batch operation reported by Foliage.
+func showJobItems(running, paused bool) {
+	showItems(pauseItems, running && !paused)
+	showItems(resumeItems, running && paused)
+	showItems(cancelItems, running)
+}
+
+// pauseJob asks Foliage to pause its batch operation.  The menu changes
+// when Foliage reports that the operation is paused.
+func pauseJob() {
+	if err := jobs.Pause(serverURL(), controlToken()); err != nil {
+		log.Printf("unable to pause job: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to pause the job: %v", err)})
+	}
+}
+
+// resumeJob asks Foliage to resume its paused batch operation.
+func resumeJob() {
+	if err := jobs.Resume(serverURL(), controlToken()); err != nil {
+		log.Printf("unable to resume job: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to resume the job: %v", err)})
+	}
+}
+
+// cancelJob asks the user to confirm, and then asks Foliage to stop its batch
+// operation.  The notification afterward says how far the operation got;
+// the Foliage window lists the records that were and weren't processed.
+func cancelJob() {
+	ok, err := dialog.Confirm(locale.T("Cancel Job"),
+		locale.T("Stop the batch operation that Foliage is running? Records that have"+
+			" already been processed will stay changed."), locale.T("Cancel Job"))
+	if err != nil {
+		log.Printf("unable to ask for confirmation: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	p, err := jobs.Cancel(serverURL(), controlToken())
+	if err != nil {
+		log.Printf("unable to cancel job: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to cancel the job: %v", err)})
+		return
+	}
+	message := locale.T("The job has been stopped.")
+	if p != nil {
+		message = locale.Sprintf("Stopped “%s” after %d of %d steps.", p.Operation, p.Done, p.Total)
+	}
+	notify.Post(notify.Notification{Message: message, URL: serverURL()})
+}
@@ -0,0 +1,43 @@
+// Package power tells the widget when the computer is about to sleep and
+// when it has woken up, so that work that needs the network, such as a batch
+// job changing FOLIO records, can be stopped cleanly first.
+package power
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotSupported is returned on systems where the widget can't find out
+// about sleep.
+var ErrNotSupported = errors.New("sleep notifications are not supported on this system")
+
+// Event is a change in the computer's power state.
+type Event int
+
+const (
+	// Sleep means the computer is about to sleep.
+	Sleep Event = iota
+	// Wake means the computer has woken up.
+	Wake
+)
+
+func (e Event) String() string {
+	if e == Sleep {
+		return "sleep"
+	}
+	return "wake"
+}
+
+var watchOnce sync.Once
+
+// Watch calls changed with Sleep before the computer sleeps, and with Wake
+// after it wakes.  The computer waits for changed(Sleep) to return before
+// sleeping, but only for a few seconds, so it should be quick.  Watch
+// returns once watching has started; it only watches the first time it is
+// called.
+func Watch(changed func(Event)) error {
+	err := errors.New("already watching for sleep")
+	watchOnce.Do(func() { err = watch(changed) })
+	return err
+}
@@ -0,0 +1,69 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package power
+
+/*
+#cgo LDFLAGS: -framework IOKit
+#include <IOKit/pwr_mgt/IOPMLib.h>
+#include <IOKit/IOMessage.h>
+#include <dispatch/dispatch.h>
+
+extern void powerChanged(int sleeping);
+
+static io_connect_t rootPort;
+
+// handlePower answers the power management messages.  The system waits for
+// IOAllowPowerChange (for up to 30 seconds) before it sleeps, so the Go
+// handler runs first.  Messages asking whether the system may sleep, when
+// it is idle, are always allowed.
+static void handlePower(void *refcon, io_service_t service, natural_t type, void *arg) {
+	switch (type) {
+	case kIOMessageCanSystemSleep:
+		IOAllowPowerChange(rootPort, (long)arg);
+		break;
+	case kIOMessageSystemWillSleep:
+		powerChanged(1);
+		IOAllowPowerChange(rootPort, (long)arg);
+		break;
+	case kIOMessageSystemHasPoweredOn:
+		powerChanged(0);
+		break;
+	}
+}
+
+// watchPower registers for power management messages, which are delivered
+// on a dispatch queue rather than the main run loop.
+static int watchPower(void) {
+	IONotificationPortRef port;
+	io_object_t notifier;
+	rootPort = IORegisterForSystemPower(NULL, &port, handlePower, &notifier);
+	if (rootPort == MACH_PORT_NULL) {
+		return -1;
+	}
+	IONotificationPortSetDispatchQueue(port, dispatch_get_global_queue(DISPATCH_QUEUE_PRIORITY_DEFAULT, 0));
+	return 0;
+}
+*/
+import "C"
+
+import "errors"
+
+var handler func(Event)
+
+//export powerChanged
+func powerChanged(sleeping C.int) {
+	if sleeping != 0 {
+		handler(Sleep)
+	} else {
+		go handler(Wake)
+	}
+}
+
+func watch(changed func(Event)) error {
+	handler = changed
+	if C.watchPower() != 0 {
+		return errors.New("unable to register for power notifications")
+	}
+	return nil
+}
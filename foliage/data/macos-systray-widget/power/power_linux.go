@@ -0,0 +1,74 @@
+package power
+
+import (
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The names of systemd-logind, which sends a signal before the computer
+// sleeps and again when it wakes.
+const (
+	logindName    = "org.freedesktop.login1"
+	logindPath    = "/org/freedesktop/login1"
+	managerIface  = logindName + ".Manager"
+	sleepSignal   = "PrepareForSleep"
+	inhibitMethod = managerIface + ".Inhibit"
+)
+
+// watch listens for logind's PrepareForSleep signal on the system bus.  To
+// give changed(Sleep) time to run, it holds a delay lock, which makes logind
+// wait (for up to five seconds, by default) until the lock is let go before
+// sleeping; a new lock is taken after each wake.
+func watch(changed func(Event)) error {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return ErrNotSupported
+	}
+	var running bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, logindName).Store(&running); err != nil || !running {
+		conn.Close()
+		return ErrNotSupported
+	}
+	err = conn.AddMatchSignal(dbus.WithMatchObjectPath(logindPath),
+		dbus.WithMatchInterface(managerIface), dbus.WithMatchMember(sleepSignal))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	lock := inhibit(conn)
+	go func() {
+		for s := range signals {
+			if s.Name != managerIface+"."+sleepSignal || len(s.Body) != 1 {
+				continue
+			}
+			if sleeping, _ := s.Body[0].(bool); sleeping {
+				changed(Sleep)
+				if lock != nil {
+					lock.Close()
+					lock = nil
+				}
+			} else {
+				if lock == nil {
+					lock = inhibit(conn)
+				}
+				go changed(Wake)
+			}
+		}
+	}()
+	return nil
+}
+
+// inhibit takes a delay lock on sleep, and returns the file that holds it,
+// or nil if logind won't give one.
+func inhibit(conn *dbus.Conn) *os.File {
+	var fd dbus.UnixFD
+	err := conn.Object(logindName, logindPath).Call(inhibitMethod, 0,
+		"sleep", "Foliage", "Pausing Foliage's batch job", "delay").Store(&fd)
+	if err != nil {
+		return nil
+	}
+	return os.NewFile(uintptr(fd), "sleep inhibitor")
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !linux && (!darwin || !cgo)
+// +build !windows
+// +build !linux
+// +build !darwin !cgo
+
+package power
+
+func watch(changed func(Event)) error {
+	return ErrNotSupported
+}
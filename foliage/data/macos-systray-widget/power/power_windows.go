@@ -0,0 +1,52 @@
+package power
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	powrprof                                   = syscall.NewLazyDLL("powrprof.dll")
+	procPowerRegisterSuspendResumeNotification = powrprof.NewProc("PowerRegisterSuspendResumeNotification")
+)
+
+const (
+	deviceNotifyCallback  = 2
+	pbtAPMSuspend         = 0x4
+	pbtAPMResumeAutomatic = 0x12
+)
+
+// The callback and its registration, kept for the life of the program.
+var (
+	params struct {
+		callback uintptr
+		context  uintptr
+	}
+	registration uintptr
+)
+
+// watch registers a callback for suspend and resume, which needs no window
+// of our own (unlike WM_POWERBROADCAST) but needs Windows 8 or later.  The
+// system waits up to two seconds for the callback to return on suspend.  It
+// sends PBT_APMRESUMEAUTOMATIC on every resume, whether or not the user
+// woke the computer.
+func watch(changed func(Event)) error {
+	if procPowerRegisterSuspendResumeNotification.Find() != nil {
+		return ErrNotSupported
+	}
+	params.callback = syscall.NewCallback(func(context, event, setting uintptr) uintptr {
+		switch event {
+		case pbtAPMSuspend:
+			changed(Sleep)
+		case pbtAPMResumeAutomatic:
+			go changed(Wake)
+		}
+		return 0
+	})
+	r, _, _ := procPowerRegisterSuspendResumeNotification.Call(deviceNotifyCallback,
+		uintptr(unsafe.Pointer(&params)), uintptr(unsafe.Pointer(&registration)))
+	if r != 0 {
+		return syscall.Errno(r)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"macos-systray-widget/autostart"
+	"macos-systray-widget/config"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/health"
+	"macos-systray-widget/hotkey"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// The icon themes, as named in the Preferences window.
+var themeChoices = []struct{ name, label string }{
+	{themeAuto, "Automatic"},
+	{themeColor, "Color"},
+	{themeMonochrome, "Monochrome"},
+	{themeLight, "For light taskbars"},
+	{themeDark, "For dark taskbars"},
+}
+
+// Held while the Preferences window is open, so that only one can be.
+var prefsMu sync.Mutex
+
+// preferences are the widget settings the Preferences window changes.
+type preferences struct {
+	url           string
+	pollInterval  string // Seconds, as typed.
+	notifications bool
+	startAtLogin  bool
+	hotkey        string
+	iconTheme     string
+	showResources bool
+	crashReports  bool
+}
+
+// currentPreferences returns the settings in effect now, or as they will be
+// the next time the widget starts, for those that only take effect then.
+func currentPreferences() preferences {
+	interval := settingInt("FOLIAGE_POLL_INTERVAL", 0)
+	if interval <= 0 {
+		interval = int(health.DefaultInterval.Seconds())
+	}
+	themeMu.Lock()
+	theme := iconTheme
+	themeMu.Unlock()
+	return preferences{
+		url:           config.Get("FOLIAGE_URL", serverURL()),
+		pollInterval:  strconv.Itoa(interval),
+		notifications: config.Bool("FOLIAGE_NOTIFICATIONS", true),
+		startAtLogin:  autostart.Enabled(),
+		hotkey:        config.Get("FOLIAGE_HOTKEY", startOptions.hotkey),
+		iconTheme:     theme,
+		showResources: showResources(),
+		crashReports:  config.Bool("FOLIAGE_CRASH_REPORTS", false),
+	}
+}
+
+// showPreferences shows the Preferences window, and saves and applies the
+// settings the user changes in it.  The settings are saved in the user's
+// preferences file (see package config).  Some of them only take effect the
+// next time the widget starts, and the user is told so.
+func showPreferences() {
+	if !prefsMu.TryLock() {
+		return
+	}
+	defer prefsMu.Unlock()
+	old := currentPreferences()
+	p := old
+	for {
+		values, err := dialog.Form(locale.T("Foliage Preferences"), "", preferenceFields(p))
+		if err != nil {
+			log.Printf("unable to show the preferences: %v", err)
+			notify.Post(notify.Notification{Message: locale.Sprintf("Unable to show the preferences: %v."+
+				" The settings can be changed in %s.", err, preferencesPath())})
+			return
+		}
+		if values == nil {
+			return
+		}
+		p = preferencesFrom(values)
+		problem := checkPreferences(p)
+		if problem == "" {
+			break
+		}
+		dialog.Info(locale.T("Foliage Preferences"), problem)
+	}
+	savePreferences(old, p)
+}
+
+// preferenceFields returns the fields of the Preferences window, filled in
+// from p.
+func preferenceFields(p preferences) []dialog.Field {
+	var themes []string
+	theme := locale.T(themeChoices[0].label)
+	for _, t := range themeChoices {
+		themes = append(themes, locale.T(t.label))
+		if t.name == p.iconTheme {
+			theme = locale.T(t.label)
+		}
+	}
+	fields := []dialog.Field{
+		{Label: locale.T("Foliage URL"), Value: p.url},
+		{Label: locale.T("Check Foliage every (seconds)"), Value: p.pollInterval},
+		{Label: locale.T("Show notifications"), Value: fmt.Sprint(p.notifications), Check: true},
+		{Label: locale.T("Start at login"), Value: fmt.Sprint(p.startAtLogin), Check: true},
+		{Label: locale.T("Keyboard shortcut"), Value: p.hotkey},
+		{Label: locale.T("Icon"), Value: theme, Choices: themes},
+		{Label: locale.T("Show memory and CPU use in the menu"), Value: fmt.Sprint(p.showResources), Check: true},
+	}
+	// There's only a choice about crash reports if there's somewhere to
+	// send them.
+	if config.Get("FOLIAGE_SENTRY_DSN", "") != "" {
+		fields = append(fields, dialog.Field{Label: locale.T("Send crash reports"), Value: fmt.Sprint(p.crashReports), Check: true})
+	}
+	return fields
+}
+
+// preferencesFrom reads the values of the fields made by preferenceFields.
+func preferencesFrom(values []string) preferences {
+	p := preferences{
+		url:           strings.TrimSuffix(strings.TrimSpace(values[0]), "/"),
+		pollInterval:  strings.TrimSpace(values[1]),
+		notifications: values[2] == "true",
+		startAtLogin:  values[3] == "true",
+		hotkey:        strings.TrimSpace(values[4]),
+		iconTheme:     themeAuto,
+		showResources: values[6] == "true",
+		crashReports:  config.Bool("FOLIAGE_CRASH_REPORTS", false),
+	}
+	if len(values) > 7 {
+		p.crashReports = values[7] == "true"
+	}
+	for _, t := range themeChoices {
+		if locale.T(t.label) == values[5] {
+			p.iconTheme = t.name
+		}
+	}
+	return p
+}
+
+// checkPreferences returns a description of what is wrong with p, or "" if
+// nothing is.
+func checkPreferences(p preferences) string {
+	if u, err := url.Parse(p.url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return locale.T("The Foliage URL should be a web address, such as http://localhost:8080.")
+	}
+	if n, err := strconv.Atoi(p.pollInterval); err != nil || n < 1 {
+		return locale.T("The time between checks of Foliage should be a whole number of seconds.")
+	}
+	if p.hotkey != "" {
+		if _, err := hotkey.Parse(p.hotkey); err != nil {
+			return locale.Sprintf("The keyboard shortcut is not one the widget understands: %v."+
+				" Shortcuts are written like Ctrl+Shift+F.", err)
+		}
+	}
+	return ""
+}
+
+// savePreferences saves the settings in p, and applies the ones that have
+// changed since old.
+func savePreferences(old, p preferences) {
+	// Only the settings the user changed are saved, so that the others
+	// still follow the settings file.
+	changed := map[string]string{}
+	for _, s := range []struct{ key, old, new string }{
+		{"FOLIAGE_URL", old.url, p.url},
+		{"FOLIAGE_POLL_INTERVAL", old.pollInterval, p.pollInterval},
+		{"FOLIAGE_NOTIFICATIONS", fmt.Sprint(old.notifications), fmt.Sprint(p.notifications)},
+		{"FOLIAGE_HOTKEY", old.hotkey, p.hotkey},
+		{"FOLIAGE_ICON_THEME", old.iconTheme, p.iconTheme},
+		{"FOLIAGE_SHOW_RESOURCES", fmt.Sprint(old.showResources), fmt.Sprint(p.showResources)},
+		{"FOLIAGE_CRASH_REPORTS", fmt.Sprint(old.crashReports), fmt.Sprint(p.crashReports)},
+	} {
+		if s.new != s.old {
+			changed[s.key] = s.new
+		}
+	}
+	if err := config.Save(changed); err != nil {
+		log.Printf("unable to save the preferences: %v", err)
+		dialog.Info(locale.T("Foliage Preferences"), locale.Sprintf("Unable to save the preferences: %v", err))
+		return
+	}
+	log.Printf("preferences saved in %s", preferencesPath())
+	notify.SetEnabled(p.notifications)
+	if p.iconTheme != old.iconTheme {
+		setIconTheme(p.iconTheme)
+	}
+	if p.showResources != old.showResources {
+		showItems(resourceItems, p.showResources)
+	}
+	if p.crashReports != old.crashReports {
+		setUpCrashes()
+	}
+	// The copy started at login gets the URL and shortcut on its command
+	// line, so it is registered again if they change.
+	if p.startAtLogin != old.startAtLogin || (p.startAtLogin && (p.url != old.url || p.hotkey != old.hotkey)) {
+		o := *startOptions
+		o.hotkey = p.hotkey
+		if err := setStartAtLogin(p.startAtLogin, &o, p.url); err != nil {
+			log.Printf("unable to change start at login: %v", err)
+			dialog.Info(locale.T("Foliage Preferences"), locale.Sprintf("Unable to change whether Foliage starts at login: %v", err))
+		}
+	}
+	if p.url != old.url || p.pollInterval != old.pollInterval || p.hotkey != old.hotkey {
+		dialog.Info(locale.T("Foliage Preferences"), locale.T("Changes to the Foliage URL, the time between"+
+			" checks and the keyboard shortcut take effect the next time the widget starts."))
+	}
+}
+
+// preferencesPath returns the path of the preferences file, for messages.
+func preferencesPath() string {
+	path, err := config.PreferencesFile()
+	if err != nil {
+		return locale.T("the widget's preferences file")
+	}
+	return path
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errNoInterrupt is returned by interruptProcess on systems where a process
+// cannot be asked to exit.  Signals work everywhere except Windows.
+var errNoInterrupt = errors.New("processes cannot be interrupted on this system")
+
+// interruptProcess asks the process to exit gracefully.  Foliage treats
+// SIGINT the same way as a ^C typed on the command line, and does an orderly
+// shutdown of the PyWebIO server in response.
+func interruptProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGINT)
+}
+
+// processAlive returns true if the process with the given pid still exists.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows has no equivalent of SIGINT that one process can send to another
+// unrelated console process.  On Windows, Foliage watches the widget process
+// and does an orderly shutdown by itself when the widget exits, so the way
+// to ask Foliage to quit is simply for the widget to exit.  That holds both
+// for a Foliage that started the widget and for one the widget started,
+// which watches the process named by FOLIAGE_WIDGET_PID (see widgetEnvVar).
+var errNoInterrupt = errors.New("processes cannot be interrupted on Windows")
+
+// interruptProcess returns errNoInterrupt on Windows.  (See above.)
+func interruptProcess(p *os.Process) error {
+	return errNoInterrupt
+}
+
+// processAlive returns true if the process with the given pid still exists.
+func processAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	// STILL_ACTIVE (259) is returned for processes that have not exited.
+	return code == 259
+}
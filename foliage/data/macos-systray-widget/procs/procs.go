@@ -0,0 +1,60 @@
+// Package procs lists the processes running on this computer, and finds the
+// process listening on a TCP port, so that processes left behind by Foliage
+// can be found without the user hunting for them in Task Manager or
+// Activity Monitor.  It also tells how much processor time and memory a
+// process uses, so that the widget can keep an eye on Foliage.
+package procs
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotSupported is returned on systems where processes can't be listed.
+var ErrNotSupported = errors.New("listing processes is not supported on this system")
+
+// Process describes a running process.
+type Process struct {
+	Pid  int
+	PPid int    // The pid of its parent.
+	Name string // The name of its program, without a directory or ".exe".
+	// Args holds its command line, where the system makes it available to
+	// other processes; on Windows, it is empty.
+	Args []string
+}
+
+// List returns the processes running on this computer that the caller can
+// see.
+func List() ([]Process, error) {
+	return list()
+}
+
+// Listening returns the pid of the process listening on the given TCP port,
+// or 0 if there isn't one the caller can see.
+func Listening(port int) (int, error) {
+	return listening(port)
+}
+
+// Usage is how much of the computer a process uses.
+type Usage struct {
+	CPU time.Duration // The processor time it has used since it started.
+	// Memory is the memory it has in use, in bytes: its resident set, or
+	// on Windows, its working set.
+	Memory uint64
+}
+
+// Resources returns how much of the computer the process with the pid uses.
+func Resources(pid int) (Usage, error) {
+	return resources(pid)
+}
+
+// programName returns the name of a program from its path.
+func programName(path string) string {
+	name := filepath.Base(strings.ReplaceAll(path, `\`, "/"))
+	if strings.EqualFold(filepath.Ext(name), ".exe") {
+		name = name[:len(name)-len(".exe")]
+	}
+	return name
+}
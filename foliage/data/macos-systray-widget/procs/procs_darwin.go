@@ -0,0 +1,89 @@
+package procs
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// list runs ps.  ps shows command lines with spaces between the arguments,
+// so an argument with a space in it comes out as two.
+func list() ([]Process, error) {
+	out, err := exec.Command("ps", "-axww", "-o", "pid=,ppid=,args=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run ps: %w", err)
+	}
+	var ps []Process
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		ps = append(ps, Process{Pid: pid, PPid: ppid, Name: programName(fields[2]), Args: fields[2:]})
+	}
+	return ps, nil
+}
+
+// listening runs lsof.
+func listening(port int) (int, error) {
+	out, err := exec.Command("lsof", "-nP", "-t", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN").Output()
+	if err != nil {
+		// lsof exits with status 1 when nothing matches.
+		if _, ok := err.(*exec.ExitError); ok && len(out) == 0 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unable to run lsof: %w", err)
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0]))
+	return pid, nil
+}
+
+// resources runs ps, which gives the resident set in kilobytes and the
+// processor time as [[DD-]HH:]MM:SS.SS.
+func resources(pid int) (Usage, error) {
+	out, err := exec.Command("ps", "-o", "rss=,time=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return Usage{}, fmt.Errorf("unable to run ps: %w", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return Usage{}, fmt.Errorf("unable to read the output of ps: %q", out)
+	}
+	rss, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return Usage{}, fmt.Errorf("unable to read the output of ps: %q", out)
+	}
+	cpu, err := parseCPUTime(fields[1])
+	if err != nil {
+		return Usage{}, fmt.Errorf("unable to read the output of ps: %q", out)
+	}
+	return Usage{CPU: cpu, Memory: rss * 1024}, nil
+}
+
+// parseCPUTime parses a processor time as ps writes it.
+func parseCPUTime(s string) (time.Duration, error) {
+	var days int64
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		d, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		days, s = d, s[i+1:]
+	}
+	var seconds float64
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, err
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(seconds*float64(time.Second)), nil
+}
@@ -0,0 +1,119 @@
+package procs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The units of processor time in /proc, which Linux always reports in
+// hundredths of a second, whatever the kernel's own clock rate.
+const clockTicks = 100
+
+// list reads the processes from /proc.
+func list() ([]Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var ps []Process
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			continue // It has exited.
+		}
+		// The name is in parentheses, and may contain spaces and
+		// parentheses itself; the parent's pid is the second field after.
+		end := bytes.LastIndexByte(stat, ')')
+		start := bytes.IndexByte(stat, '(')
+		if start < 0 || end < start {
+			continue
+		}
+		p := Process{Pid: pid, Name: string(stat[start+1 : end])}
+		if fields := strings.Fields(string(stat[end+1:])); len(fields) > 1 {
+			p.PPid, _ = strconv.Atoi(fields[1])
+		}
+		if cmdline, err := os.ReadFile(filepath.Join("/proc", e.Name(), "cmdline")); err == nil && len(cmdline) > 0 {
+			p.Args = strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+			// The name in stat is cut to 15 characters.
+			p.Name = programName(p.Args[0])
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// listening finds the socket listening on the port in /proc/net, and then
+// the process with that socket open.
+func listening(port int) (int, error) {
+	inodes := map[string]bool{}
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(table)
+		if err != nil {
+			continue
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			// sl local_address rem_address st ... inode
+			fields := strings.Fields(s.Text())
+			if len(fields) < 10 || fields[3] != "0A" { // 0A is LISTEN.
+				continue
+			}
+			i := strings.LastIndexByte(fields[1], ':')
+			if p, err := strconv.ParseInt(fields[1][i+1:], 16, 32); err == nil && int(p) == port {
+				inodes[fields[9]] = true
+			}
+		}
+		f.Close()
+	}
+	if len(inodes) == 0 {
+		return 0, nil
+	}
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		target, err := os.Readlink(fd)
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		if inodes[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")] {
+			var pid int
+			fmt.Sscanf(fd, "/proc/%d/", &pid)
+			return pid, nil
+		}
+	}
+	return 0, nil
+}
+
+// resources reads the process's processor time and resident set from its
+// stat file in /proc.
+func resources(pid int) (Usage, error) {
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return Usage{}, err
+	}
+	// The fields after the name start with the third, the state; utime
+	// and stime are the 14th and 15th, and rss, in pages, the 24th.
+	var fields []string
+	if end := bytes.LastIndexByte(stat, ')'); end >= 0 {
+		fields = strings.Fields(string(stat[end+1:]))
+	}
+	if len(fields) < 22 {
+		return Usage{}, fmt.Errorf("unable to read the status of process %d", pid)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+	return Usage{
+		CPU:    time.Duration(utime+stime) * time.Second / clockTicks,
+		Memory: rss * uint64(os.Getpagesize()),
+	}, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package procs
+
+func list() ([]Process, error) {
+	return nil, ErrNotSupported
+}
+
+func listening(port int) (int, error) {
+	return 0, ErrNotSupported
+}
+
+func resources(pid int) (Usage, error) {
+	return Usage{}, ErrNotSupported
+}
@@ -0,0 +1,115 @@
+package procs
+
+import (
+	"encoding/binary"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	getExtendedTcpTable  = syscall.NewLazyDLL("iphlpapi.dll").NewProc("GetExtendedTcpTable")
+	getProcessMemoryInfo = syscall.NewLazyDLL("kernel32.dll").NewProc("K32GetProcessMemoryInfo")
+)
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS, which
+// K32GetProcessMemoryInfo fills in.
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// Arguments of GetExtendedTcpTable.
+const (
+	afInet                 = 2
+	tcpTableOwnerPidListen = 3
+	errInsufficientBuffer  = 122
+)
+
+// list takes a snapshot of the processes with the Tool Help functions.  They
+// give the name of each program but not its command line.
+func list() ([]Process, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snapshot)
+	var ps []Process
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		ps = append(ps, Process{
+			Pid:  int(entry.ProcessID),
+			PPid: int(entry.ParentProcessID),
+			Name: programName(windows.UTF16ToString(entry.ExeFile[:])),
+		})
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// listening looks through the table of listening IPv4 sockets and the
+// processes that own them.  Foliage listens on IPv4.
+func listening(port int) (int, error) {
+	size := uint32(4096)
+	for {
+		buf := make([]byte, size)
+		r, _, _ := getExtendedTcpTable.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)),
+			0, afInet, tcpTableOwnerPidListen, 0)
+		if r == errInsufficientBuffer {
+			continue
+		}
+		if r != 0 {
+			return 0, syscall.Errno(r)
+		}
+		// The table is a count followed by rows of six 32-bit values:
+		// state, local address, local port, remote address, remote
+		// port, and pid.  Ports are in network byte order.
+		n := binary.LittleEndian.Uint32(buf)
+		for i := uint32(0); i < n; i++ {
+			row := buf[4+i*24 : 4+(i+1)*24]
+			if int(binary.BigEndian.Uint16(row[8:10])) == port {
+				return int(binary.LittleEndian.Uint32(row[20:24])), nil
+			}
+		}
+		return 0, nil
+	}
+}
+
+// resources asks for the process's times and memory counters, which only
+// need the right to query limited information about it.
+func resources(pid int) (Usage, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return Usage{}, err
+	}
+	defer windows.CloseHandle(h)
+	var created, exited, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &created, &exited, &kernel, &user); err != nil {
+		return Usage{}, err
+	}
+	var mem processMemoryCounters
+	mem.cb = uint32(unsafe.Sizeof(mem))
+	if r, _, err := getProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mem)), uintptr(mem.cb)); r == 0 {
+		return Usage{}, err
+	}
+	return Usage{CPU: filetimeDuration(kernel) + filetimeDuration(user), Memory: uint64(mem.WorkingSetSize)}, nil
+}
+
+// filetimeDuration returns the length of time a FILETIME holds, in units of
+// 100 nanoseconds.
+func filetimeDuration(ft windows.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
@@ -0,0 +1,113 @@
+// Package proxy sends the program's requests to other servers, such as
+// FOLIO, through a proxy, for networks that can only reach them that way.
+// By default, the proxy is the one the environment variables HTTPS_PROXY
+// and HTTP_PROXY give (or their lower-case versions), except for hosts in
+// NO_PROXY, as is usual.  The setting FOLIAGE_PROXY names a proxy to use
+// instead, which can be an HTTP, HTTPS or SOCKS5 proxy, such as
+// "http://proxy.example.edu:3128" or "socks5://127.0.0.1:1080"; the user
+// name and password, if the proxy needs them, go in the URL.  NO_PROXY
+// still applies to it.  FOLIAGE_PROXY can also be "direct", for making all
+// requests directly, whatever the environment says.
+//
+// Requests to this computer itself, such as those to Foliage, never go
+// through a proxy.
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"macos-systray-widget/config"
+)
+
+// Func returns the function that chooses the proxy for a request, as the
+// Proxy of an http.Transport, for a setting like FOLIAGE_PROXY.
+func Func(setting string) (func(*http.Request) (*url.URL, error), error) {
+	switch strings.ToLower(setting) {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case "direct", "none":
+		return nil, nil
+	}
+	u, err := url.Parse(setting)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", setting, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: it has to be an http, https or socks5 URL", setting)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: it has no host", setting)
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass(req.URL.Host, noProxy) {
+			return nil, nil
+		}
+		return u, nil
+	}, nil
+}
+
+// Configure makes requests sent with Go's default transport, which the
+// program's HTTP clients use, go through the proxy FOLIAGE_PROXY gives.
+func Configure() error {
+	f, err := Func(config.Get("FOLIAGE_PROXY", ""))
+	if err != nil {
+		return err
+	}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		t.Proxy = f
+	}
+	return nil
+}
+
+// bypass reports whether requests to hostport should be made directly:
+// hosts on this computer, and hosts that NO_PROXY (a list of names,
+// domains, addresses and networks, separated by commas) matches.
+func bypass(hostport, noProxy string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = hostport, ""
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	ip := net.ParseIP(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (ip != nil && ip.IsLoopback()) {
+		return true
+	}
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		// A name matches the host with that name and the hosts in it.
+		entry = strings.TrimPrefix(strings.Trim(entry, "[]"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
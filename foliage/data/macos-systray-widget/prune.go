@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"macos-systray-widget/backup"
+)
+
+// runPrune runs the prune subcommand, which removes old backups of records
+// (see backup.Prune), by the rules given or in the settings
+// FOLIAGE_BACKUP_KEEP and FOLIAGE_BACKUP_MAX_AGE (in days), and lists what
+// it removed; with --dry-run, it lists what it would remove.
+func runPrune(args []string) int {
+	fs := subcommandFlags("prune")
+	keep := fs.Int("keep", settingInt("FOLIAGE_BACKUP_KEEP", 0), "keep this many of the newest backups of each record (0 for no limit)")
+	days := fs.Int("older-than", settingInt("FOLIAGE_BACKUP_MAX_AGE", 0), "remove backups older than this many days (0 for no limit)")
+	dryRun := fs.Bool("dry-run", false, "list the backups that would be removed, without removing them")
+	quiet := fs.Bool("quiet", false, "print only the totals")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *keep < 0 || *days < 0 {
+		fs.Usage()
+		return 2
+	}
+	if *keep == 0 && *days == 0 {
+		fmt.Fprintln(os.Stderr, "no rules for pruning backups: give --keep or --older-than, or set FOLIAGE_BACKUP_KEEP or FOLIAGE_BACKUP_MAX_AGE")
+		return 2
+	}
+	dir, err := backup.Dir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	policy := backup.Policy{Keep: *keep, MaxAge: time.Duration(*days) * 24 * time.Hour}
+	removed, err := backup.Prune(dir, policy, time.Now(), *dryRun)
+	verb := "removed"
+	if *dryRun {
+		verb = "would remove"
+	}
+	var size int64
+	records := map[string]bool{}
+	for _, r := range removed {
+		if !*quiet {
+			fmt.Printf("%s %s (%s, %s)\n", verb, r.Path, r.Time.Local().Format("2006-01-02 15:04"), r.Reason)
+		}
+		size += r.Size
+		records[r.ID] = true
+	}
+	fmt.Printf("%s %s of %s from %s, %s\n", verb, count(len(removed), "backup"), count(len(records), "record"), dir, showSize(size))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// count returns n things, such as "1 backup" or "2 backups".
+func count(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// showSize returns a number of bytes in the units people use.
+func showSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"macos-systray-widget/crash"
+	"macos-systray-widget/health"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/tenants"
+)
+
+// How long a check of the FOLIO server may take, and how many checks in a
+// row have to fail before FOLIO counts as unreachable, so that one lost
+// request doesn't raise the alarm.
+const (
+	folioTimeout  = 10 * time.Second
+	folioFailures = 2
+)
+
+// The FOLIO (OKAPI) server Foliage is using, as its status reports it.
+var (
+	folioMu     sync.Mutex
+	folioServer string
+)
+
+// setFolioServer records the FOLIO server Foliage is using.
+func setFolioServer(url string) {
+	folioMu.Lock()
+	folioServer = url
+	folioMu.Unlock()
+}
+
+// watchFolio checks every so often that the FOLIO server Foliage is using can
+// be reached from this machine, while Foliage is running.  When it can't,
+// the icon gets a gray dot and a notification says so, because the problem
+// is usually the network (such as a VPN connection that dropped) rather than
+// Foliage; another notification says when FOLIO can be reached again.  The
+// setting FOLIAGE_FOLIO_CHECK_INTERVAL gives the number of seconds between
+// checks, 30 by default; 0 turns checking off.  It does not return.
+func watchFolio() {
+	defer crash.Recover()
+	interval := settingInt("FOLIAGE_FOLIO_CHECK_INTERVAL", 30)
+	if interval <= 0 {
+		return
+	}
+	failures, offline := 0, false
+	checked, transport := "", http.RoundTripper(nil)
+	for range time.Tick(time.Duration(interval) * time.Second) {
+		folioMu.Lock()
+		server := folioServer
+		folioMu.Unlock()
+		if server != checked {
+			checked, transport = server, folioTransport(server)
+		}
+		iconMu.Lock()
+		running := healthName == "running"
+		iconMu.Unlock()
+		if server == "" || !running {
+			// Without Foliage, there's no telling which server to check,
+			// and the icon already shows that something is wrong.
+			failures, offline = 0, false
+			setFolioOffline(false)
+			continue
+		}
+		err := health.Reachable(server, folioTimeout, transport)
+		if err == nil {
+			failures = 0
+			if offline {
+				offline = false
+				log.Printf("FOLIO at %s can be reached again", server)
+				setFolioOffline(false)
+				notify.Post(notify.Notification{Message: locale.Sprintf("FOLIO (%s) can be reached again.", hostOf(server))})
+			}
+			continue
+		}
+		debugf("unable to reach FOLIO at %s: %v", server, err)
+		if failures++; failures == folioFailures {
+			offline = true
+			log.Printf("FOLIO at %s cannot be reached: %v", server, err)
+			setFolioOffline(true)
+			notify.Post(notify.Notification{Message: locale.Sprintf("Foliage can't reach FOLIO (%s)."+
+				" Check this computer's network connection, and its VPN connection if FOLIO needs one.",
+				hostOf(server))})
+		}
+	}
+}
+
+// folioTransport returns the transport for checking the FOLIO server, if the
+// list of tenants gives TLS settings for it (such as the bundle of an
+// internal certificate authority), or nil.
+func folioTransport(server string) http.RoundTripper {
+	if server == "" {
+		return nil
+	}
+	path, err := tenants.ConfiguredPath()
+	if err != nil {
+		return nil
+	}
+	list, err := tenants.Load(path)
+	t, ok := tenants.Find(list, server, "")
+	if err != nil || !ok {
+		return nil
+	}
+	o := t.TLS(filepath.Dir(path))
+	if o.IsZero() {
+		return nil
+	}
+	transport, err := o.Transport()
+	if err != nil {
+		log.Printf("unable to use the TLS settings for FOLIO at %s: %v", server, err)
+		return nil
+	}
+	if o.InsecureSkipVerify {
+		log.Printf("WARNING: not checking the TLS certificate of FOLIO at %s, as the list of tenants says", server)
+	}
+	return transport
+}
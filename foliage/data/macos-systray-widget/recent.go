@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sync"
+
+	"fyne.io/systray"
+	"macos-systray-widget/control"
+	"macos-systray-widget/menu"
+)
+
+// The number of entries kept in the "Recent" submenu.
+const recentSlots = 10
+
+// recentMenu is a "Recent" submenu.  Like the slots for items added by
+// Foliage (see control.go), its entries are created in advance and hidden
+// until they are needed.  The submenu itself is hidden while it is empty.
+type recentMenu struct {
+	parent *systray.MenuItem
+	slots  []*systray.MenuItem
+}
+
+var (
+	recentMu      sync.Mutex
+	recentMenus   []*recentMenu
+	recentEntries []control.MenuItem // Newest first.
+)
+
+// addRecentMenu adds a "Recent" submenu for the manifest item to parent, or
+// to the top level of the menu if parent is nil.
+func addRecentMenu(parent *systray.MenuItem, item menu.Item) {
+	rm := &recentMenu{parent: addItem(parent, item, item.Title, false)}
+	rm.parent.Hide()
+	for i := 0; i < recentSlots; i++ {
+		slot := rm.parent.AddSubMenuItem("", "")
+		slot.Hide()
+		rm.slots = append(rm.slots, slot)
+		go func(i int) {
+			for range slot.ClickedCh {
+				openRecent(i)
+			}
+		}(i)
+	}
+	recentMu.Lock()
+	recentMenus = append(recentMenus, rm)
+	recentMu.Unlock()
+}
+
+// addRecent puts an entry at the top of the "Recent" submenus.  An earlier
+// entry for the same URL is removed, and the oldest entry is dropped once
+// there are more than recentSlots.
+func addRecent(entry control.MenuItem) {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	entries := []control.MenuItem{entry}
+	for _, e := range recentEntries {
+		if e.URL != entry.URL && len(entries) < recentSlots {
+			entries = append(entries, e)
+		}
+	}
+	recentEntries = entries
+	for _, rm := range recentMenus {
+		for i, slot := range rm.slots {
+			if i < len(entries) {
+				slot.SetTitle(entries[i].Title)
+				slot.SetTooltip(entries[i].Tooltip)
+				slot.Show()
+			} else {
+				slot.Hide()
+			}
+		}
+		rm.parent.Show()
+	}
+}
+
+// openRecent opens the i'th entry in the "Recent" submenus.
+func openRecent(i int) {
+	recentMu.Lock()
+	var url string
+	if i < len(recentEntries) {
+		url = absoluteURL(recentEntries[i].URL)
+	}
+	recentMu.Unlock()
+	debugf("opening recent entry %d: %s", i, url)
+	if url != "" {
+		open(url)
+	}
+}
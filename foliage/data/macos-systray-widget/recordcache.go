@@ -0,0 +1,98 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/cache"
+	"macos-systray-widget/config"
+	"macos-systray-widget/foliolib"
+)
+
+// How long, by default, foliaged uses the records in its cache before
+// getting them from FOLIO again.
+const cacheTTL = time.Hour
+
+// cachePath returns the path of foliaged's cache of records (see package
+// cache) for the client's tenant: a database, named by a hash of the tenant
+// and its URL, in the folder named by the setting FOLIAGE_CACHE, or else
+// foliaged/cache in Foliage's data directory.  A setting of "off" turns the
+// cache off, and the path is "".
+func cachePath(c *foliolib.Client) (string, error) {
+	dir := config.Get("FOLIAGE_CACHE", "")
+	if strings.EqualFold(dir, "off") {
+		return "", nil
+	} else if dir == "" {
+		data, err := appdirs.UserDataDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(data, "foliaged", "cache")
+	}
+	sum := sha256.Sum256([]byte(c.Tenant + "@" + c.URL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".db"), nil
+}
+
+// openCache returns foliaged's cache of records for the client's tenant,
+// whose records are used for FOLIAGE_CACHE_TTL minutes, or an hour; it is
+// nil if the cache is turned off.
+func openCache(c *foliolib.Client) (*cache.Store, error) {
+	path, err := cachePath(c)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	ttl := time.Duration(settingInt("FOLIAGE_CACHE_TTL", int(cacheTTL/time.Minute))) * time.Minute
+	return cache.Open(path, ttl)
+}
+
+// runCache is "foliaged cache", which says what is in the cache of records,
+// and "foliaged cache clear", which empties it, or, with --stale, removes
+// the records that are no longer used.
+func runCache(args []string) int {
+	fs := subcommandFlags("foliaged")
+	stale := fs.Bool("stale", false, "remove only the stale records")
+	if len(args) > 0 && args[0] == "clear" {
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 {
+			return 2
+		}
+	} else if len(args) != 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s cache [clear [--stale]]\n", foliagedName())
+		return 2
+	}
+	c, err := foliolib.FromSettings()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	store, err := openCache(c)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	} else if store == nil {
+		fmt.Println("the cache of records is turned off")
+		return 0
+	}
+	defer store.Close()
+	if len(args) == 0 {
+		st, err := store.Stats()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%s in %s (%d stale), %s\n", count(st.Records, "record"), store.Path, st.Stale, showSize(st.Size))
+		return 0
+	}
+	n, err := store.Clear(*stale)
+	fmt.Printf("removed %s from %s\n", count(n, "record"), store.Path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
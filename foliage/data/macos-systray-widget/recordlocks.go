@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"os/user"
+	"strings"
+	"syscall"
+	"time"
+
+	"macos-systray-widget/batch"
+	"macos-systray-widget/config"
+	"macos-systray-widget/locks"
+)
+
+// The address "foliaged locks serve" listens at by default.
+const lockListenAddr = ":8737"
+
+// lockTTL returns how long locks last without being renewed: the setting
+// FOLIAGE_LOCK_TTL, in minutes, or locks.DefaultTTL.
+func lockTTL() time.Duration {
+	return time.Duration(settingInt("FOLIAGE_LOCK_TTL", int(locks.DefaultTTL/time.Minute))) * time.Minute
+}
+
+// lockClient returns a client of the lock server named by the setting
+// FOLIAGE_LOCK_SERVER, taking locks for the job with the id, or nil if no
+// server is set.
+func lockClient(job string) *locks.Client {
+	server := config.Get("FOLIAGE_LOCK_SERVER", "")
+	if server == "" {
+		return nil
+	}
+	return &locks.Client{
+		URL:   server,
+		Token: config.Get("FOLIAGE_LOCK_TOKEN", ""),
+		Job:   job,
+		Owner: lockOwner(),
+		HTTP:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// lockOwner returns who is running jobs, as user@computer.
+func lockOwner() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	return name + "@" + host
+}
+
+// lockRecords sets the options to lock the records the job with the id
+// changes or deletes, if a lock server is set, and returns a function to
+// release the locks once the job is done.  Records other jobs have locked
+// are logged, and skipped if the setting FOLIAGE_LOCK_CONFLICT is "skip",
+// rather than "warn" (the default).
+func lockRecords(ctx context.Context, job *batch.Job, id string, opts *batch.Options) func() {
+	lc := lockClient(id)
+	if lc == nil || job.DryRun || job.Operation == batch.Lookup {
+		return func() {}
+	}
+	ctx, stop := context.WithCancel(ctx)
+	go lc.KeepAlive(ctx, lockTTL())
+	opts.SkipLocked = strings.EqualFold(config.Get("FOLIAGE_LOCK_CONFLICT", "warn"), "skip")
+	opts.Lock = func(ctx context.Context, record string) (string, error) {
+		conflicts, err := lc.Acquire(ctx, record)
+		if err != nil || len(conflicts) == 0 {
+			return "", err
+		}
+		log.Printf("warning: record %s is locked: %s", record, conflicts[0])
+		return conflicts[0].String(), nil
+	}
+	return func() {
+		stop()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := lc.Release(ctx); err != nil {
+			log.Printf("unable to release the locks of job %s: %v", id, err)
+		}
+	}
+}
+
+// runLocks is "foliaged locks serve", which runs the lock server at
+// --listen, and "foliaged locks", which lists the locks the server named by
+// FOLIAGE_LOCK_SERVER holds.
+func runLocks(args []string) int {
+	if len(args) > 0 && args[0] == "serve" {
+		fs := subcommandFlags("foliaged")
+		listen := fs.String("listen", config.Get("FOLIAGE_LOCK_LISTEN", lockListenAddr), "the address to serve locks at")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 {
+			return 2
+		}
+		return serveLocks(*listen)
+	} else if len(args) > 1 || len(args) == 1 && args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "usage: %s locks [list | serve [--listen ADDR]]\n", foliagedName())
+		return 2
+	}
+	lc := lockClient("")
+	if lc == nil {
+		fmt.Fprintln(os.Stderr, "no lock server: set FOLIAGE_LOCK_SERVER to its URL")
+		return 1
+	}
+	list, err := lc.List(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, l := range list {
+		fmt.Printf("%s\t%s\t%s\t%s\n", l.ID, l.Job, l.Owner, l.Since.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(os.Stderr, "%s held\n", count(len(list), "lock"))
+	return 0
+}
+
+// serveLocks serves locks at the address until it is told to stop.
+func serveLocks(addr string) int {
+	table := &locks.Table{TTL: lockTTL(), Token: config.Get("FOLIAGE_LOCK_TOKEN", "")}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if table.Token == "" {
+		log.Printf("warning: anyone who can reach the lock server can take locks; set FOLIAGE_LOCK_TOKEN to stop them")
+	}
+	srv := &http.Server{Handler: table, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	log.Printf("serving record locks at http://%s/locks", l.Addr())
+	if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/procs"
+)
+
+// What Foliage uses of the computer, as last measured: its share of a
+// processor, in percent (or -1 until there have been two measurements), and
+// its memory, in bytes (or 0 if it hasn't been measured).  Guarded by
+// resourceMu.
+var (
+	resourceMu     sync.Mutex
+	resourceCPU    float64
+	resourceMemory uint64
+)
+
+// The menu rows that show what Foliage uses, which are hidden unless the
+// setting FOLIAGE_SHOW_RESOURCES is true.
+var resourceItems []*systray.MenuItem
+
+// showResources returns whether the menu shows what Foliage uses.
+func showResources() bool {
+	return config.Bool("FOLIAGE_SHOW_RESOURCES", false)
+}
+
+// memoryLimit returns how much memory Foliage may use before the widget
+// warns about it, from the setting FOLIAGE_MEMORY_LIMIT, in megabytes, or 0
+// if there's no limit.
+func memoryLimit() uint64 {
+	mb := settingInt("FOLIAGE_MEMORY_LIMIT", 2048)
+	if mb <= 0 {
+		return 0
+	}
+	return uint64(mb) << 20
+}
+
+// watchResources measures how much processor time and memory the Foliage
+// process uses, every FOLIAGE_RESOURCE_INTERVAL seconds (15 by default; 0
+// turns measuring off), and shows it in the menu's resources rows.  When
+// Foliage uses more memory than memoryLimit, the icon gets an orange dot and
+// a notification suggests restarting it, since long sessions can grow until
+// the computer freezes; the warning goes away once it uses less than 90% of
+// the limit.  Only the process the widget knows to be Foliage's (see
+// serverPid) is measured.  It does not return.
+func watchResources() {
+	defer crash.Recover()
+	interval := settingInt("FOLIAGE_RESOURCE_INTERVAL", 15)
+	if interval <= 0 {
+		return
+	}
+	var last procs.Usage
+	var lastPid int
+	var lastTime time.Time
+	high := false
+	for range time.Tick(time.Duration(interval) * time.Second) {
+		pid := serverPid()
+		usage, err := procs.Resources(pid)
+		if pid != 0 && err != nil {
+			debugf("unable to measure what process %d uses: %v", pid, err)
+		}
+		if pid == 0 || err != nil {
+			lastPid, high = 0, false
+			setResources(-1, 0)
+			setMemoryHigh(false)
+			continue
+		}
+		now := time.Now()
+		cpu := -1.0
+		if pid == lastPid && now.After(lastTime) {
+			cpu = float64(usage.CPU-last.CPU) / float64(now.Sub(lastTime)) * 100
+		}
+		last, lastPid, lastTime = usage, pid, now
+		setResources(cpu, usage.Memory)
+		limit := memoryLimit()
+		switch {
+		case !high && limit > 0 && usage.Memory > limit:
+			high = true
+			log.Printf("Foliage (process %d) is using %s of memory, more than the limit of %s",
+				pid, showSize(int64(usage.Memory)), showSize(int64(limit)))
+			setMemoryHigh(true)
+			notify.Post(notify.Notification{Message: locale.Sprintf("Foliage is using %s of memory."+
+				" Restart it once its job is done, before it slows down this computer.", showSize(int64(usage.Memory)))})
+		case high && (limit == 0 || usage.Memory < limit/10*9):
+			high = false
+			log.Printf("Foliage (process %d) is using %s of memory again", pid, showSize(int64(usage.Memory)))
+			setMemoryHigh(false)
+		}
+	}
+}
+
+// setResources records what Foliage uses, and updates the menu and tooltip.
+func setResources(cpu float64, memory uint64) {
+	resourceMu.Lock()
+	resourceCPU, resourceMemory = cpu, memory
+	resourceMu.Unlock()
+	text := resourceTitle()
+	for _, mi := range resourceItems {
+		mi.SetTitle(text)
+	}
+	iconMu.Lock()
+	name := shownName
+	iconMu.Unlock()
+	if name == "memory-high" {
+		updateTooltip()
+	}
+}
+
+// resourceText describes what Foliage uses, such as "1.2 GB of memory, 3%
+// CPU", or returns "" if it hasn't been measured.
+func resourceText() string {
+	resourceMu.Lock()
+	cpu, memory := resourceCPU, resourceMemory
+	resourceMu.Unlock()
+	switch {
+	case memory == 0:
+		return ""
+	case cpu < 0:
+		return locale.Sprintf("%s of memory", showSize(int64(memory)))
+	}
+	return locale.Sprintf("%s of memory, %s CPU", showSize(int64(memory)), fmt.Sprintf("%.0f%%", cpu))
+}
+
+// resourceTitle returns the title of the menu rows that show what Foliage
+// uses.
+func resourceTitle() string {
+	if text := resourceText(); text != "" {
+		return locale.Sprintf("Foliage: %s", text)
+	}
+	return locale.T("Foliage: not measured")
+}
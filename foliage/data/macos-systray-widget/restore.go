@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"macos-systray-widget/backup"
+	"macos-systray-widget/batch"
+	"macos-systray-widget/foliolib"
+)
+
+// runRestore runs the restore subcommand, which puts records back in FOLIO
+// as they were backed up (see backup.Restore): the backups given, each a
+// backup file or the id or backup folder of a record, meaning its newest
+// backup, or, with --job, every record the foliaged job with the id changed
+// or deleted, as it was just before.  --dry-run says what it would do.
+func runRestore(args []string) int {
+	fs := subcommandFlags("restore")
+	job := fs.String("job", "", "restore the records changed by the foliaged job with this id")
+	kind := fs.String("kind", "", "the kind of records, if it can't be told from the backups: instance, holdings, item, loan or user")
+	dryRun := fs.Bool("dry-run", false, "say what would be restored, without changing FOLIO")
+	force := fs.Bool("force", false, "restore records even if they have been changed again since the backup")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*job == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return 2
+	}
+	dir, err := backup.Dir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	type todo struct {
+		kind foliolib.Kind
+		snap backup.Snapshot
+	}
+	var list []todo
+	if *job != "" {
+		root, err := journalDir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		j, changes, err := batch.Changes(root, *job)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		seen := map[string]bool{}
+		for _, ch := range changes {
+			if seen[ch.ID] {
+				continue
+			}
+			seen[ch.ID] = true
+			snap, ok, err := backup.Latest(dir, ch.ID, ch.Time)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			} else if !ok {
+				fmt.Fprintf(os.Stderr, "%s: skipped: there is no backup of the record from before the job changed it\n", ch.ID)
+				continue
+			}
+			list = append(list, todo{j.Kind, snap})
+		}
+	} else {
+		for _, arg := range fs.Args() {
+			path, err := snapshotPath(dir, arg)
+			var r foliolib.Record
+			if err == nil {
+				r, err = backup.Read(path)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			k := foliolib.Kind(*kind)
+			if k == "" {
+				var ok bool
+				if k, ok = backup.KindOf(r); !ok {
+					fmt.Fprintf(os.Stderr, "unable to tell what kind of record %s is; give it with --kind\n", r.ID())
+					return 1
+				}
+			}
+			snap, err := backup.Stat(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			list = append(list, todo{k, snap})
+		}
+	}
+	if len(list) == 0 {
+		fmt.Println("nothing to restore")
+		return 0
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	c, err := batchClient(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	counts := map[string]int{}
+	for _, t := range list {
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "stopped")
+			return 1
+		}
+		res := backup.Restore(ctx, c, t.kind, t.snap, backup.RestoreOptions{DryRun: *dryRun, Force: *force, Dir: dir})
+		counts[res.Status]++
+		fmt.Printf("%s: %s, from %s\n", res.ID, res.Message, res.Backup.Path)
+		if *dryRun || res.Status == backup.Conflict {
+			for _, d := range res.Differences {
+				fmt.Printf("    %s\n", d)
+			}
+		}
+	}
+	verb := ""
+	if *dryRun {
+		verb = "would be "
+	}
+	fmt.Printf("%d %srestored, %d %sre-created, %d unchanged, %d in conflict, %d failed\n",
+		counts[backup.Restored], verb, counts[backup.Recreated], verb, counts[backup.Unchanged], counts[backup.Conflict], counts[backup.Failed])
+	if counts[backup.Conflict] > 0 {
+		fmt.Fprintln(os.Stderr, "records in conflict have been changed again since the backup; --force restores them anyway")
+	}
+	if counts[backup.Conflict]+counts[backup.Failed] > 0 {
+		return 1
+	}
+	return 0
+}
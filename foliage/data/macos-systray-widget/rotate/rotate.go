@@ -0,0 +1,153 @@
+// Package rotate writes log files that are rotated when they get too big or
+// too old, so that a program that runs for months doesn't fill the disk.
+// Rotating a file renames it with ".1" added to its name, after renaming
+// the one with ".1" to ".2", and so on; the oldest files, beyond the number
+// to keep, are removed.
+package rotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options say when to rotate a file and how many old ones to keep.
+type Options struct {
+	// MaxSize is the size in bytes past which the file is rotated, or 0
+	// if there is no limit.
+	MaxSize int64
+	// MaxAge is how long the file is written to before it is rotated,
+	// counted from when it was opened (or last rotated), or 0 if there is
+	// no limit.  A file that was last changed longer ago than that is
+	// rotated when it is opened.
+	MaxAge time.Duration
+	// Keep is the number of old files to keep.
+	Keep int
+}
+
+// File is a log file that is rotated as its options say.
+type File struct {
+	path string
+	opts Options
+
+	mu      sync.Mutex
+	f       *os.File
+	size    int64
+	started time.Time
+}
+
+// Open opens the file at path for appending, creating it if need be.
+func Open(path string, opts Options) (*File, error) {
+	lf := &File{path: path, opts: opts}
+	if fi, err := os.Stat(path); err == nil && fi.Size() > 0 && opts.MaxAge > 0 &&
+		time.Since(fi.ModTime()) > opts.MaxAge {
+		lf.shift()
+	}
+	if err := lf.open(); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// Write appends p to the file, rotating it first if p would make it too big
+// or the file is too old.  Each write is kept in one file, so p should be a
+// whole entry.
+func (lf *File) Write(p []byte) (int, error) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if lf.size > 0 && ((lf.opts.MaxSize > 0 && lf.size+int64(len(p)) > lf.opts.MaxSize) ||
+		(lf.opts.MaxAge > 0 && time.Since(lf.started) >= lf.opts.MaxAge)) {
+		lf.rotate()
+	}
+	if lf.f == nil {
+		return 0, os.ErrClosed
+	}
+	n, err := lf.f.Write(p)
+	lf.size += int64(n)
+	return n, err
+}
+
+// Close writes what has been written to the file to disk, and closes it.
+func (lf *File) Close() error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if lf.f == nil {
+		return os.ErrClosed
+	}
+	lf.f.Sync()
+	err := lf.f.Close()
+	lf.f = nil
+	return err
+}
+
+// open opens the file and finds out how big it is.
+func (lf *File) open() error {
+	f, err := os.OpenFile(lf.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	lf.f, lf.size, lf.started = f, fi.Size(), time.Now()
+	return nil
+}
+
+// rotate closes the file, shifts the old files along, and starts a new file.
+// The file is closed first because Windows can't rename open files.  If a
+// new file can't be made, writing goes on in the one just rotated, if it
+// can be reopened.
+func (lf *File) rotate() {
+	lf.f.Close()
+	lf.shift()
+	if err := lf.open(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start a new log file: %v\n", err)
+		lf.f, _ = os.OpenFile(lf.oldPath(1), os.O_WRONLY|os.O_APPEND, 0o644)
+		lf.started = time.Now()
+	}
+}
+
+// shift renames the file and the old files, removing those beyond the
+// number to keep.  Failures are ignored: a file that can't be renamed is
+// left to grow.
+func (lf *File) shift() {
+	for _, n := range lf.oldNumbers() {
+		if n >= lf.opts.Keep {
+			os.Remove(lf.oldPath(n))
+		}
+	}
+	for n := lf.opts.Keep - 1; n >= 1; n-- {
+		os.Rename(lf.oldPath(n), lf.oldPath(n+1))
+	}
+	if lf.opts.Keep > 0 {
+		os.Rename(lf.path, lf.oldPath(1))
+	} else {
+		os.Remove(lf.path)
+	}
+}
+
+// oldPath returns the path of the n'th old file.
+func (lf *File) oldPath(n int) string {
+	return lf.path + "." + strconv.Itoa(n)
+}
+
+// oldNumbers returns the numbers of the old files there are.
+func (lf *File) oldNumbers() []int {
+	matches, _ := filepath.Glob(lf.path + ".*")
+	var numbers []int
+	for _, m := range matches {
+		if n, err := strconv.Atoi(strings.TrimPrefix(m, lf.path+".")); err == nil && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
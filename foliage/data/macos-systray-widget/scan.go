@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"macos-systray-widget/config"
+	"macos-systray-widget/health"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/scanner"
+)
+
+// startScanner listens for a barcode scanner, if the setting FOLIAGE_SCANNER
+// turns that on.  It is off by default, because on Windows and macOS it
+// means looking at every key the user types.  The settings
+// FOLIAGE_SCANNER_PREFIX and FOLIAGE_SCANNER_SUFFIX give what the scanner
+// sends before and after each barcode, written with Go escapes such as \t
+// and \n; FOLIAGE_SCANNER_GAP gives the longest pause within a scan (such as
+// 80ms); and on Linux, FOLIAGE_SCANNER_DEVICE gives the scanner's input
+// device.
+func startScanner() {
+	if !config.Bool("FOLIAGE_SCANNER", false) {
+		return
+	}
+	o := scanner.Options{
+		Prefix: unescape(config.Get("FOLIAGE_SCANNER_PREFIX", "")),
+		Suffix: unescape(config.Get("FOLIAGE_SCANNER_SUFFIX", "")),
+		Device: config.Get("FOLIAGE_SCANNER_DEVICE", ""),
+	}
+	if gap := config.Get("FOLIAGE_SCANNER_GAP", ""); gap != "" {
+		if d, err := time.ParseDuration(gap); err == nil {
+			o.MaxGap = d
+		} else {
+			log.Printf("ignoring FOLIAGE_SCANNER_GAP: %v", err)
+		}
+	}
+	if err := scanner.Listen(o, lookUpScanned); err != nil {
+		log.Printf("unable to listen for a barcode scanner: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to listen for the barcode scanner: %v", err)})
+		return
+	}
+	log.Print("listening for a barcode scanner")
+}
+
+// lookUpScanned hands a scanned barcode to Foliage as a lookup.  The user
+// may be working in another application, so Foliage isn't brought to the
+// front; it reports what it found in a notification instead, which opens
+// Foliage when clicked.
+func lookUpScanned(barcode string) {
+	debugf("scanned %s", barcode)
+	if !health.NewChecker(serverURL()).Check() && foliageCommand != "" {
+		startFoliage(false)
+	}
+	err := jobs.Lookup(serverURL(), controlToken(), []string{barcode}, "", true)
+	if err != nil {
+		log.Printf("unable to hand the scanned barcode to Foliage: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to look up %s: %v", barcode, err)})
+	}
+}
+
+// unescape interprets Go escapes in a setting, such as \t for Tab, and
+// returns the setting unchanged if it has none or is malformed.
+func unescape(s string) string {
+	if u, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return u
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+// Package scanner listens for barcodes read by a USB barcode scanner, even
+// when the browser showing Foliage isn't the application in front.  Such
+// scanners pretend to be keyboards: they type the barcode, usually followed
+// by Enter.  What gives a scan away is its speed, since a scanner types
+// each character within a few milliseconds of the last, far faster than a
+// person does.  Keys typed by a person are looked at and let through, and
+// nothing is kept but a scan in progress.
+//
+// On Windows, the keys are seen with a low-level keyboard hook.  On macOS,
+// they are seen with an event tap, which the user has to allow in the
+// Input Monitoring section of the Privacy & Security settings.  On Linux,
+// the scanner is read directly as an input device; it is either found by
+// name or named in Options.Device, and the user needs permission to read it
+// (usually by being in the "input" group).
+package scanner
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNotSupported is returned on systems where scanners can't be listened
+// for.
+var ErrNotSupported = errors.New("listening for a barcode scanner is not supported on this system")
+
+// Options describe what the scanner sends.  The zero value suits most
+// scanners, which send nothing before the barcode and Enter after it.
+type Options struct {
+	Prefix    string        // Sent before each barcode, if the scanner is set up to.
+	Suffix    string        // Sent after each barcode; "\n" (Enter) if empty.
+	MaxGap    time.Duration // Longest pause within a scan; 50 ms if zero.
+	MinLength int           // Shortest barcode accepted; 4 if zero.
+	Device    string        // On Linux, the scanner's input device, if not found by name.
+}
+
+// The longest scan looked for; anything longer is dropped as it arrives.
+const maxLength = 128
+
+// Listen calls scanned with each barcode read, until the program exits.
+// It returns an error if it can't listen.  scanned is called in a goroutine
+// of its own.
+func Listen(o Options, scanned func(barcode string)) error {
+	if o.Suffix == "" {
+		o.Suffix = "\n"
+	}
+	if o.MaxGap <= 0 {
+		o.MaxGap = 50 * time.Millisecond
+	}
+	if o.MinLength <= 0 {
+		o.MinLength = 4
+	}
+	return listen(o, func() *decoder { return &decoder{o: o, scanned: scanned} })
+}
+
+// A decoder picks scans out of the characters typed on one keyboard.  Its
+// methods are called by one thread at a time.
+type decoder struct {
+	o       Options
+	scanned func(string)
+	buf     []rune
+	last    time.Time
+}
+
+// key takes a character typed at time t.  Enter is '\n' and Tab is '\t'.
+func (d *decoder) key(r rune, t time.Time) {
+	if len(d.buf) > 0 && t.Sub(d.last) > d.o.MaxGap {
+		d.buf = d.buf[:0]
+	}
+	d.last = t
+	if len(d.buf) >= maxLength {
+		return
+	}
+	d.buf = append(d.buf, r)
+	text := string(d.buf)
+	if !strings.HasSuffix(text, d.o.Suffix) {
+		return
+	}
+	d.buf = d.buf[:0]
+	text = strings.TrimSuffix(text, d.o.Suffix)
+	if d.o.Prefix != "" {
+		i := strings.LastIndex(text, d.o.Prefix)
+		if i < 0 {
+			return
+		}
+		text = text[i+len(d.o.Prefix):]
+	}
+	if text = strings.TrimSpace(text); utf8.RuneCountInString(text) >= d.o.MinLength {
+		go d.scanned(text)
+	}
+}
+
+// reset drops a scan in progress, as when a key that no scanner sends is
+// pressed.
+func (d *decoder) reset() {
+	d.buf = d.buf[:0]
+}
@@ -0,0 +1,100 @@
+//go:build darwin && cgo
+// +build darwin,cgo
+
+package scanner
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices -framework CoreFoundation
+#include <ApplicationServices/ApplicationServices.h>
+
+extern void scannerKey(UniChar c);
+extern void scannerReset(void);
+
+static CFMachPortRef tap;
+
+static CGEventRef tapped(CGEventTapProxy proxy, CGEventType type, CGEventRef event, void *data) {
+	if (type == kCGEventTapDisabledByTimeout || type == kCGEventTapDisabledByUserInput) {
+		// The system turns a tap off if it is slow to answer; turn it
+		// back on.
+		CGEventTapEnable(tap, true);
+		return event;
+	}
+	if (type == kCGEventKeyDown) {
+		UniChar chars[4];
+		UniCharCount n = 0;
+		CGEventKeyboardGetUnicodeString(event, 4, &n, chars);
+		CGEventFlags mods = kCGEventFlagMaskCommand | kCGEventFlagMaskControl | kCGEventFlagMaskAlternate;
+		if (n == 1 && !(CGEventGetFlags(event) & mods)) {
+			scannerKey(chars[0]);
+		} else {
+			scannerReset();
+		}
+	}
+	return event;
+}
+
+// makeTap creates a listen-only tap for key presses.  Without the user's
+// permission to monitor input, it asks for it and fails.
+static int makeTap(void) {
+	if (!CGPreflightListenEventAccess()) {
+		CGRequestListenEventAccess();
+		return 0;
+	}
+	tap = CGEventTapCreate(kCGSessionEventTap, kCGHeadInsertEventTap,
+		kCGEventTapOptionListenOnly, CGEventMaskBit(kCGEventKeyDown), tapped, NULL);
+	return tap != NULL;
+}
+
+// runTap delivers the tap's events on the calling thread, and never returns.
+static void runTap(void) {
+	CFRunLoopSourceRef source = CFMachPortCreateRunLoopSource(NULL, tap, 0);
+	CFRunLoopAddSource(CFRunLoopGetCurrent(), source, kCFRunLoopCommonModes);
+	CGEventTapEnable(tap, true);
+	CFRunLoopRun();
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// The decoder for the tap, which is only called on the tap's thread.
+var tapDecoder *decoder
+
+//export scannerKey
+func scannerKey(c C.UniChar) {
+	r := rune(c)
+	if r == '\r' {
+		r = '\n'
+	}
+	// The events' own timestamps are in different units on different Macs,
+	// and the tap sees each event as it happens anyway.
+	tapDecoder.key(r, time.Now())
+}
+
+//export scannerReset
+func scannerReset() {
+	tapDecoder.reset()
+}
+
+// listen taps the keys typed on every keyboard, on a thread of its own
+// that runs the tap's run loop.  The tap only listens, so the keys still
+// reach the application in front.
+func listen(o Options, newDecoder func() *decoder) error {
+	tapDecoder = newDecoder()
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if C.makeTap() == 0 {
+			errc <- errors.New("the widget needs permission to monitor input, in the" +
+				" Input Monitoring section of the Privacy & Security settings")
+			return
+		}
+		errc <- nil
+		C.runTap()
+	}()
+	return <-errc
+}
@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// An input event, as struct input_event in linux/input.h.
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+const (
+	evKey      = 0x01
+	keyRelease = 0
+	keyPress   = 1
+)
+
+// Key codes from linux/input-event-codes.h, for the keys scanners type.
+const (
+	keyLeftShift  = 42
+	keyRightShift = 54
+)
+
+var keyRunes = map[uint16]rune{
+	2: '1', 3: '2', 4: '3', 5: '4', 6: '5', 7: '6', 8: '7', 9: '8', 10: '9', 11: '0',
+	12: '-', 15: '\t', 28: '\n', 52: '.', 57: ' ', 96: '\n',
+	16: 'q', 17: 'w', 18: 'e', 19: 'r', 20: 't', 21: 'y', 22: 'u', 23: 'i', 24: 'o', 25: 'p',
+	30: 'a', 31: 's', 32: 'd', 33: 'f', 34: 'g', 35: 'h', 36: 'j', 37: 'k', 38: 'l',
+	44: 'z', 45: 'x', 46: 'c', 47: 'v', 48: 'b', 49: 'n', 50: 'm',
+	71: '7', 72: '8', 73: '9', 74: '-', 75: '4', 76: '5', 77: '6', 79: '1', 80: '2',
+	81: '3', 82: '0',
+}
+
+// listen reads each scanner device in a goroutine of its own.  Only the
+// scanner is read, rather than every keyboard, and reading it doesn't stop
+// its keys from reaching the application in front.
+func listen(o Options, newDecoder func() *decoder) error {
+	devices, err := findDevices(o.Device)
+	if err != nil {
+		return err
+	}
+	var files []*os.File
+	for _, path := range devices {
+		f, err := os.Open(path)
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			if errors.Is(err, os.ErrPermission) {
+				return fmt.Errorf("no permission to read the scanner at %s; the user usually"+
+					" needs to be in the \"input\" group", path)
+			}
+			return err
+		}
+		files = append(files, f)
+	}
+	for _, f := range files {
+		go read(f, newDecoder())
+	}
+	return nil
+}
+
+// read passes the keys read from the device to the decoder, until the
+// device goes away.
+func read(f *os.File, d *decoder) {
+	defer f.Close()
+	var e inputEvent
+	buf := make([]byte, unsafe.Sizeof(e))
+	shift := false
+	for {
+		if _, err := io.ReadFull(f, buf); err != nil {
+			log.Printf("stopped reading the scanner at %s: %v", f.Name(), err)
+			return
+		}
+		e = *(*inputEvent)(unsafe.Pointer(&buf[0]))
+		if e.Type != evKey || (e.Value != keyPress && e.Value != keyRelease) {
+			continue
+		}
+		if e.Code == keyLeftShift || e.Code == keyRightShift {
+			shift = e.Value == keyPress
+			continue
+		}
+		if e.Value != keyPress {
+			continue
+		}
+		r, ok := keyRunes[e.Code]
+		if !ok {
+			d.reset()
+			continue
+		}
+		if shift && r >= 'a' && r <= 'z' {
+			r += 'A' - 'a'
+		} else if shift && r == '-' {
+			r = '_'
+		}
+		d.key(r, time.Unix(int64(e.Time.Sec), int64(e.Time.Usec)*int64(time.Microsecond)))
+	}
+}
+
+// findDevices returns the device given, or else the event devices of the
+// keyboards whose names say they are barcode scanners.
+func findDevices(device string) ([]string, error) {
+	if device != "" {
+		return []string{device}, nil
+	}
+	f, err := os.Open("/proc/bus/input/devices")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var devices []string
+	var name string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "N: Name="):
+			name = strings.ToLower(line)
+		case strings.HasPrefix(line, "H: Handlers="):
+			if !strings.Contains(name, "barcode") && !strings.Contains(name, "scanner") {
+				continue
+			}
+			handlers := strings.Fields(strings.TrimPrefix(line, "H: Handlers="))
+			isKeyboard := false
+			for _, h := range handlers {
+				isKeyboard = isKeyboard || h == "kbd"
+			}
+			for _, h := range handlers {
+				if isKeyboard && strings.HasPrefix(h, "event") {
+					devices = append(devices, filepath.Join("/dev/input", h))
+				}
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, errors.New("no barcode scanner was found; its input device can be" +
+			" given with the setting FOLIAGE_SCANNER_DEVICE")
+	}
+	return devices, nil
+}
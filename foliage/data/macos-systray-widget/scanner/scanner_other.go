@@ -0,0 +1,10 @@
+//go:build !windows && !linux && (!darwin || !cgo)
+// +build !windows
+// +build !linux
+// +build !darwin !cgo
+
+package scanner
+
+func listen(o Options, newDecoder func() *decoder) error {
+	return ErrNotSupported
+}
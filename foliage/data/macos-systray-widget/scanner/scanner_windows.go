@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32                = syscall.NewLazyDLL("user32.dll")
+	procSetWindowsHookExW = user32.NewProc("SetWindowsHookExW")
+	procCallNextHookEx    = user32.NewProc("CallNextHookEx")
+	procGetMessageW       = user32.NewProc("GetMessageW")
+)
+
+const (
+	whKeyboardLL = 13
+	wmKeyDown    = 0x0100
+	wmSysKeyDown = 0x0104
+)
+
+// Virtual-key codes, from WinUser.h.
+const (
+	vkTab      = 0x09
+	vkReturn   = 0x0D
+	vkShift    = 0x10
+	vkSpace    = 0x20
+	vkNumpad0  = 0x60
+	vkSubtract = 0x6D
+	vkLShift   = 0xA0
+	vkRShift   = 0xA1
+	vkOEMMinus = 0xBD
+	vkOEMDot   = 0xBE
+)
+
+type kbdllHookStruct struct {
+	vkCode      uint32
+	scanCode    uint32
+	flags       uint32
+	time        uint32
+	dwExtraInfo uintptr
+}
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// listen installs a low-level keyboard hook, which sees the keys typed on
+// every keyboard, scanner included.  The hook is called on the thread that
+// installed it, which has to wait for messages for that to happen.  The
+// keys are always passed on.
+func listen(o Options, newDecoder func() *decoder) error {
+	d := newDecoder()
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		shift := false
+		hook := syscall.NewCallback(func(code int, wParam, lParam uintptr) uintptr {
+			if code >= 0 {
+				k := *(**kbdllHookStruct)(unsafe.Pointer(&lParam))
+				down := wParam == wmKeyDown || wParam == wmSysKeyDown
+				if k.vkCode == vkShift || k.vkCode == vkLShift || k.vkCode == vkRShift {
+					shift = down
+				} else if down {
+					// The time is in milliseconds since the system started.
+					t := time.Unix(0, int64(k.time)*int64(time.Millisecond))
+					if r := keyRune(k.vkCode, shift); r != 0 {
+						d.key(r, t)
+					} else {
+						d.reset()
+					}
+				}
+			}
+			r, _, _ := procCallNextHookEx.Call(0, uintptr(code), wParam, lParam)
+			return r
+		})
+		h, _, err := procSetWindowsHookExW.Call(whKeyboardLL, hook, 0, 0)
+		if h == 0 {
+			errc <- err
+			return
+		}
+		errc <- nil
+		var m msg
+		for {
+			if r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0); int32(r) <= 0 {
+				return
+			}
+		}
+	}()
+	return <-errc
+}
+
+// keyRune returns the character typed by the key, for the keys scanners
+// type, or 0.
+func keyRune(vk uint32, shift bool) rune {
+	switch {
+	case vk >= '0' && vk <= '9' && !shift:
+		return rune(vk)
+	case vk >= 'A' && vk <= 'Z':
+		if shift {
+			return rune(vk)
+		}
+		return rune(vk) + 'a' - 'A'
+	case vk >= vkNumpad0 && vk <= vkNumpad0+9:
+		return rune('0' + vk - vkNumpad0)
+	case vk == vkReturn:
+		return '\n'
+	case vk == vkTab:
+		return '\t'
+	case vk == vkSpace:
+		return ' '
+	case vk == vkOEMMinus && !shift, vk == vkSubtract:
+		return '-'
+	case vk == vkOEMMinus:
+		return '_'
+	case vk == vkOEMDot && !shift:
+		return '.'
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"macos-systray-widget/batch"
+)
+
+// schedules keeps track of the scheduled jobs in foliaged's spool: when each
+// last ran, and which run of it is unfinished, if one is.  It is kept in a
+// file in the journal directory, so that foliaged knows, when it starts
+// again, which runs it missed while it wasn't running.
+type schedules struct {
+	path string
+	Jobs map[string]*scheduled `json:"jobs"` // By the job file's path.
+}
+
+// scheduled is what is known of a scheduled job.
+type scheduled struct {
+	Last    time.Time `json:"last"`              // When the last run started.
+	Started time.Time `json:"started"`           // When the unfinished run started.
+	Running string    `json:"running,omitempty"` // The id of the unfinished run.
+}
+
+// loadSchedules reads what is known of the scheduled jobs, from the journal
+// directory root.
+func loadSchedules(root string) *schedules {
+	s := &schedules{path: filepath.Join(root, "schedules.json"), Jobs: map[string]*scheduled{}}
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		err = json.Unmarshal(data, s)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("unable to read %s, so missed runs of scheduled jobs won't be known: %v", s.path, err)
+	}
+	if s.Jobs == nil {
+		s.Jobs = map[string]*scheduled{}
+	}
+	return s
+}
+
+func (s *schedules) save() {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(s.path), 0o755); err == nil {
+			err = os.WriteFile(s.path, data, 0o644)
+		}
+	}
+	if err != nil {
+		log.Printf("unable to save %s: %v", s.path, err)
+	}
+}
+
+// entry returns what is known of the job from the job file at path.
+func (s *schedules) entry(path string) *scheduled {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	e, ok := s.Jobs[path]
+	if !ok {
+		e = &scheduled{}
+		s.Jobs[path] = e
+	}
+	return e
+}
+
+// running returns the id of the job's unfinished run, if it has one.
+func (s *schedules) running(path string) string {
+	return s.entry(path).Running
+}
+
+// due reports whether the job from the job file at path is due to run, now.
+// A run is missed if it was due more than grace ago; the job's policy says
+// whether to run it anyway, once however many were missed, or skip it.  A
+// job that is new to the spool first runs the next time its schedule comes
+// round.
+func (s *schedules) due(path string, job *batch.Job, now time.Time, grace time.Duration) bool {
+	e := s.entry(path)
+	if e.Last.IsZero() {
+		e.Last = now
+		s.save()
+		log.Printf("job %s is scheduled (%s); it first runs at %s", path, job.Schedule, showTime(job.Next(now)))
+		return false
+	}
+	next := job.Next(e.Last)
+	if next.IsZero() || next.After(now) {
+		return false
+	}
+	if now.Sub(next) > grace {
+		if job.Missed == batch.Skip {
+			e.Last = now
+			s.save()
+			log.Printf("skipping the run of job %s due at %s, which was missed; it next runs at %s",
+				path, showTime(next), showTime(job.Next(now)))
+			return false
+		}
+		log.Printf("catching up with the run of job %s due at %s, which was missed", path, showTime(next))
+	}
+	return true
+}
+
+// start records that a run of the job, with the journal id, has started.
+func (s *schedules) start(path, id string, now time.Time) {
+	e := s.entry(path)
+	e.Running, e.Started = id, now
+	s.save()
+}
+
+// finish records that the job's run, which started when it was first due
+// (or, if it has a journal, when start was called) has finished, and logs
+// when it next runs.
+func (s *schedules) finish(path string, job *batch.Job, started time.Time) {
+	e := s.entry(path)
+	if e.Running != "" {
+		started = e.Started
+	}
+	e.Last, e.Running, e.Started = started, "", time.Time{}
+	s.save()
+	log.Printf("job %s next runs at %s", path, showTime(job.Next(time.Now())))
+}
+
+// showTime returns t as it is written in foliaged's log.
+func showTime(t time.Time) string {
+	if t.IsZero() {
+		return "no time (its schedule never comes round)"
+	}
+	return t.Format("2006-01-02 15:04 MST")
+}
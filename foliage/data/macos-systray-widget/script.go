@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"macos-systray-widget/health"
+	"macos-systray-widget/instance"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/scripting"
+	"macos-systray-widget/status"
+)
+
+// runScriptCommand carries out --job or --status, for scripts, Shortcuts
+// and scheduled tasks, and returns the exit status.  Unlike the other
+// options that hand something to the widget, these don't leave a widget
+// running if there wasn't one, so a script can wait for them to finish.
+func runScriptCommand(o *options) int {
+	var err error
+	if o.status {
+		err = printStatus()
+	} else {
+		err = startJobs(o)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// startJobs hands the files given with --job to the running widget, which
+// starts the jobs the same way it does for dropped files.  If no widget is
+// running, it asks Foliage to start them itself.
+func startJobs(o *options) error {
+	if !knownOperation(o.job) {
+		return fmt.Errorf("unknown kind of job %q (use lookup, change or delete)", o.job)
+	}
+	if len(o.dropFiles) == 0 {
+		return fmt.Errorf("no files of identifiers given for the %s job", o.job)
+	}
+	for _, path := range o.dropFiles {
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+	}
+	if path, err := instance.Path(); err == nil {
+		if err := instance.Forward(path, os.Args[1:]); err == nil {
+			fmt.Println("Handed the job to the Foliage widget.")
+			return nil
+		}
+	}
+	if !health.NewChecker(serverURL()).Check() {
+		return fmt.Errorf("Foliage is not running at %s", serverURL())
+	}
+	for _, path := range o.dropFiles {
+		if o.job == "delete" && !confirmDelete(filepath.Base(path)) {
+			return fmt.Errorf("the deletion of the records in %s was not confirmed", filepath.Base(path))
+		}
+		if err := jobs.Batch(serverURL(), controlToken(), path, o.job, o.job == "delete"); err != nil {
+			return fmt.Errorf("Foliage can't work on %s: %v", filepath.Base(path), err)
+		}
+		fmt.Printf("Started a %s job on %s.\n", o.job, filepath.Base(path))
+	}
+	return nil
+}
+
+// printStatus prints what Foliage's status endpoint says about it, as JSON,
+// with its URL and whether it is running, which is false if it doesn't
+// answer.
+func printStatus() error {
+	info, _ := status.Fetch(serverURL())
+	out, err := json.MarshalIndent(struct {
+		URL     string `json:"url"`
+		Running bool   `json:"running"`
+		*status.Info
+	}{serverURL(), info != nil, info}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// runScriptLibraryCommand carries out --install-script-library or
+// --uninstall-script-library and returns the exit status.
+func runScriptLibraryCommand(install bool) int {
+	var err error
+	if install {
+		var exe, path string
+		if exe, err = os.Executable(); err == nil {
+			if path, err = scripting.Install(exe); err == nil {
+				fmt.Printf("Scripts can now use Foliage through the library %s.\n", path)
+			}
+		}
+	} else if err = scripting.Uninstall(); err == nil {
+		fmt.Println("Removed the script library.")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+// Package scripting lets AppleScript scripts, and through them the macOS
+// Shortcuts app, use the widget's commands.  It installs an AppleScript
+// script library named "Foliage" whose handlers run the widget with the
+// options --open, --job and --status, so that a script can say, for
+// example,
+//
+//	tell script "Foliage" to startJob("delete", "/Users/me/withdrawn.csv")
+//
+// On other systems, scripts and scheduled tasks run the widget with those
+// options directly.
+package scripting
+
+import "errors"
+
+// ErrNotSupported is returned on systems that have no script libraries.
+var ErrNotSupported = errors.New("script libraries are not supported on this system")
+
+// Install makes the script library for the program exe, replacing any made
+// earlier, and returns its path.
+func Install(exe string) (string, error) {
+	return install(exe)
+}
+
+// Uninstall removes the script library, if there is one.
+func Uninstall() error {
+	return uninstall()
+}
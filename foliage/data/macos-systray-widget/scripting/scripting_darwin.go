@@ -0,0 +1,77 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// libraryPath returns the path of the script library, in the user's own
+// Script Libraries folder, where "tell script" looks for it by name.
+func libraryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "Script Libraries", "Foliage.scpt"), nil
+}
+
+// The script library.  Its handlers return what the widget prints, so that
+// foliageStatus() returns the status as JSON text, which Shortcuts can turn
+// into a dictionary.  The widget started by openFoliage() may be the one
+// that stays in the menu bar, so it is left running in the background.
+const library = `property widget : %s
+
+-- Open Foliage in the browser, starting the widget if it isn't running.
+on openFoliage()
+	do shell script quoted form of widget & " --open > /dev/null 2>&1 &"
+end openFoliage
+
+-- Start a batch job ("lookup", "change" or "delete") on the identifiers in
+-- a .csv, .txt or .xlsx file, given as a path or a file reference.
+on startJob(operation, identifierFile)
+	if class of identifierFile is text then
+		set thePath to identifierFile
+	else
+		set thePath to POSIX path of identifierFile
+	end if
+	return do shell script quoted form of widget & " --job " & quoted form of operation & " " & quoted form of thePath
+end startJob
+
+-- Return the status of Foliage as JSON text.
+on foliageStatus()
+	return do shell script quoted form of widget & " --status"
+end foliageStatus
+`
+
+func install(exe string) (string, error) {
+	path, err := libraryPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	script := fmt.Sprintf(library, shellquote.AppleScript(exe))
+	out, err := exec.Command("osacompile", "-o", path, "-e", script).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("osacompile failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return path, nil
+}
+
+func uninstall() error {
+	path, err := libraryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
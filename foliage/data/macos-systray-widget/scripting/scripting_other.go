@@ -0,0 +1,12 @@
+//go:build !darwin
+// +build !darwin
+
+package scripting
+
+func install(exe string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func uninstall() error {
+	return ErrNotSupported
+}
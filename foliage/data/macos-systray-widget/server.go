@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"macos-systray-widget/health"
+)
+
+// How long to wait for Foliage to exit after asking it to, before we give up
+// and kill it, and how often to check in the meantime.
+const (
+	shutdownTimeout  = 10 * time.Second
+	shutdownInterval = 100 * time.Millisecond
+)
+
+// Process id of the Foliage server, or 0 if we don't know it.  Foliage
+// tells us its process id when it starts the widget; if the widget starts
+// Foliage itself, it's the id of the process it started.  Accessed
+// atomically, because it can change while the widget runs.
+var foliagePid int64
+
+// serverPid returns the process id of the Foliage server, or 0 if unknown.
+func serverPid() int {
+	return int(atomic.LoadInt64(&foliagePid))
+}
+
+// setServerPid records the process id of the Foliage server.
+func setServerPid(pid int) {
+	atomic.StoreInt64(&foliagePid, int64(pid))
+}
+
+// Address of the Foliage user interface, set from flags and settings in main,
+// and changed if the widget starts Foliage on another port (see
+// launcher.go).  urlChecker is watchServer's health checker, which follows
+// the address.  Guarded by urlMu.
+var (
+	urlMu      sync.Mutex
+	foliageURL string
+	urlChecker *health.Checker
+)
+
+// serverURL returns the address of the Foliage user interface.
+func serverURL() string {
+	urlMu.Lock()
+	defer urlMu.Unlock()
+	return foliageURL
+}
+
+// setServerURL records the address of the Foliage user interface, which
+// menu actions and health checks use from then on.
+func setServerURL(url string) {
+	urlMu.Lock()
+	foliageURL = url
+	c := urlChecker
+	urlMu.Unlock()
+	if c != nil {
+		c.SetURL(url)
+	}
+}
+
+// waitForServerExit returns when the Foliage process has exited.  It returns
+// immediately if there is no Foliage process.
+func waitForServerExit() {
+	for pid := serverPid(); pid != 0 && processAlive(pid); {
+		time.Sleep(time.Second)
+	}
+}
+
+// shutdownServer asks the Foliage process with the given pid to exit, waits
+// up to timeout for it to do so, and kills it if it is still running after
+// that.  It returns an error only if the process could not be stopped.
+func shutdownServer(pid int, timeout time.Duration) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	log.Printf("asking Foliage process %d to exit", pid)
+	if err := interruptProcess(p); err == errNoInterrupt {
+		log.Printf("leaving it to Foliage process %d to exit after we do", pid)
+		return nil
+	} else if err != nil {
+		log.Printf("unable to interrupt process %d: %v", pid, err)
+	} else {
+		deadline := time.Now().Add(timeout)
+		for time.Now().Before(deadline) {
+			if !processAlive(pid) {
+				log.Printf("Foliage process %d has exited", pid)
+				return nil
+			}
+			time.Sleep(shutdownInterval)
+		}
+		log.Printf("Foliage process %d did not exit after %v", pid, timeout)
+	}
+	log.Printf("killing Foliage process %d", pid)
+	if err := p.Kill(); err != nil && processAlive(pid) {
+		return fmt.Errorf("unable to kill process %d: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/services"
+)
+
+// lookUpSelection hands the identifiers in text selected in another
+// application, and given with --look-up, to Foliage.  Unlike the clipboard,
+// a selection is chosen to be looked up, so any word in it is taken for an
+// identifier; Foliage works out what kind each one is.
+func lookUpSelection(text string) {
+	ids := identifierFields(text)
+	if len(ids) == 0 {
+		notify.Post(notify.Notification{Message: locale.Sprintf("Foliage can look up at most %d"+
+			" identifiers at a time, separated by spaces, commas or new lines.", clipboardMaxIDs)})
+		return
+	}
+	debugf("looking up %d identifiers from a selection", len(ids))
+	if err := lookUp(ids, ""); err != nil {
+		log.Printf("unable to hand the lookup to Foliage: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to look up %s: %v", describeIDs(ids), err)})
+	}
+}
+
+// runServiceCommand carries out --install-service or --uninstall-service
+// and returns the exit status.
+func runServiceCommand(install bool) int {
+	var err error
+	if install {
+		var exe, path string
+		if exe, err = os.Executable(); err == nil {
+			if path, err = services.Install(exe); err == nil {
+				fmt.Printf("Added %q to the Services menu (%s).\n", services.Name, path)
+			}
+		}
+	} else if err = services.Uninstall(); err == nil {
+		fmt.Println("Removed the service.")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
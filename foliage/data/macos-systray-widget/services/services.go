@@ -0,0 +1,25 @@
+// Package services adds "Look Up in Foliage" to the Services menu on macOS,
+// so that text selected in any application can be looked up in Foliage.
+// The service is an Automator Quick Action, kept in the user's Services
+// folder, that runs the widget with the option --look-up followed by the
+// selected text.  Applications only offer it when text is selected.
+package services
+
+import "errors"
+
+// ErrNotSupported is returned on systems that have no Services menu.
+var ErrNotSupported = errors.New("the Services menu is only available on macOS")
+
+// Name is the name of the service, as the Services menu shows it.
+const Name = "Look Up in Foliage"
+
+// Install makes the service for the program exe, replacing any made
+// earlier, and returns its path.
+func Install(exe string) (string, error) {
+	return install(exe)
+}
+
+// Uninstall removes the service, if there is one.
+func Uninstall() error {
+	return uninstall()
+}
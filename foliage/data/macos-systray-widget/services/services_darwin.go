@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// pbs is the program that keeps the list of services; telling it to update
+// makes the new service show up without logging out.
+const pbs = "/System/Library/CoreServices/pbs"
+
+// The service's Info.plist, which tells the system it takes text.
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>NSServices</key>
+	<array>
+		<dict>
+			<key>NSMenuItem</key>
+			<dict>
+				<key>default</key>
+				<string>` + Name + `</string>
+			</dict>
+			<key>NSMessage</key>
+			<string>runWorkflowAsService</string>
+			<key>NSSendTypes</key>
+			<array>
+				<string>public.utf8-plain-text</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+// The workflow: a single "Run Shell Script" action that is given the
+// selected text on its standard input.  It is the document Automator saves
+// for such a Quick Action, less the parts Automator only uses for editing.
+// The command goes in the %s.
+const workflow = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>AMApplicationBuild</key>
+	<string>523</string>
+	<key>AMApplicationVersion</key>
+	<string>2.10</string>
+	<key>AMDocumentVersion</key>
+	<string>2</string>
+	<key>actions</key>
+	<array>
+		<dict>
+			<key>action</key>
+			<dict>
+				<key>AMAccepts</key>
+				<dict>
+					<key>Container</key>
+					<string>List</string>
+					<key>Optional</key>
+					<true/>
+					<key>Types</key>
+					<array>
+						<string>com.apple.cocoa.string</string>
+					</array>
+				</dict>
+				<key>AMActionVersion</key>
+				<string>2.0.3</string>
+				<key>AMApplication</key>
+				<array>
+					<string>Automator</string>
+				</array>
+				<key>AMParameterProperties</key>
+				<dict>
+					<key>COMMAND_STRING</key>
+					<dict/>
+					<key>CheckedForUserDefaultShell</key>
+					<dict/>
+					<key>inputMethod</key>
+					<dict/>
+					<key>shell</key>
+					<dict/>
+					<key>source</key>
+					<dict/>
+				</dict>
+				<key>AMProvides</key>
+				<dict>
+					<key>Container</key>
+					<string>List</string>
+					<key>Types</key>
+					<array>
+						<string>com.apple.cocoa.string</string>
+					</array>
+				</dict>
+				<key>ActionBundlePath</key>
+				<string>/System/Library/Automator/Run Shell Script.action</string>
+				<key>ActionName</key>
+				<string>Run Shell Script</string>
+				<key>ActionParameters</key>
+				<dict>
+					<key>COMMAND_STRING</key>
+					<string>%s</string>
+					<key>CheckedForUserDefaultShell</key>
+					<true/>
+					<key>inputMethod</key>
+					<integer>0</integer>
+					<key>shell</key>
+					<string>/bin/sh</string>
+					<key>source</key>
+					<string></string>
+				</dict>
+				<key>BundleIdentifier</key>
+				<string>com.apple.RunShellScript</string>
+				<key>CFBundleVersion</key>
+				<string>2.0.3</string>
+				<key>CanShowSelectedItemsWhenRun</key>
+				<false/>
+				<key>CanShowWhenRun</key>
+				<true/>
+				<key>Category</key>
+				<array>
+					<string>AMCategoryUtilities</string>
+				</array>
+				<key>Class Name</key>
+				<string>RunShellScriptAction</string>
+				<key>InputUUID</key>
+				<string>7A4C6E2D-0B1F-4D3A-9E5C-2F8B1A6D4C30</string>
+				<key>OutputUUID</key>
+				<string>3E9D1B7F-5C2A-4E8B-A1D6-9F0C4B2E7A51</string>
+				<key>UUID</key>
+				<string>C5B2F8A1-6D3E-4A9C-B7E0-1D4F8C2A9E63</string>
+				<key>UnlocalizedApplications</key>
+				<array>
+					<string>Automator</string>
+				</array>
+				<key>isViewVisible</key>
+				<true/>
+			</dict>
+			<key>isViewVisible</key>
+			<true/>
+		</dict>
+	</array>
+	<key>connectors</key>
+	<dict/>
+	<key>workflowMetaData</key>
+	<dict>
+		<key>serviceInputTypeIdentifier</key>
+		<string>com.apple.Automator.text</string>
+		<key>serviceOutputTypeIdentifier</key>
+		<string>com.apple.Automator.nothing</string>
+		<key>serviceProcessesInput</key>
+		<integer>0</integer>
+		<key>workflowTypeIdentifier</key>
+		<string>com.apple.Automator.servicesMenu</string>
+	</dict>
+</dict>
+</plist>
+`
+
+// workflowPath returns the path of the service, in the user's own Services
+// folder.
+func workflowPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "Services", Name+".workflow"), nil
+}
+
+// install writes the workflow.  Its command runs the widget in the
+// background, so that the service finishes at once; the widget hands the
+// text to the running copy, if there is one, or else starts in the menu bar.
+func install(exe string) (string, error) {
+	path, err := workflowPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return "", err
+	}
+	contents := filepath.Join(path, "Contents")
+	if err := os.MkdirAll(contents, 0o755); err != nil {
+		return "", err
+	}
+	command := shellquote.Shell(exe) + ` --look-up "$(cat)" > /dev/null 2>&1 &`
+	var escaped bytes.Buffer
+	if err := xml.EscapeText(&escaped, []byte(command)); err != nil {
+		return "", err
+	}
+	files := map[string]string{
+		"Info.plist":     infoPlist,
+		"document.wflow": fmt.Sprintf(workflow, escaped.String()),
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(contents, name), []byte(text), 0o644); err != nil {
+			return "", err
+		}
+	}
+	exec.Command(pbs, "-update").Run()
+	return path, nil
+}
+
+func uninstall() error {
+	path, err := workflowPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	exec.Command(pbs, "-update").Run()
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !darwin
+// +build !darwin
+
+package services
+
+func install(exe string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func uninstall() error {
+	return ErrNotSupported
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/status"
+)
+
+// How often to ask Foliage about its FOLIO session while it is running,
+// and how long before the FOLIO token expires to start warning about it.
+const (
+	sessionInterval = time.Minute
+	expiryWarning   = 15 * time.Minute
+)
+
+// A description of Foliage's FOLIO session, for the tooltip, and a channel
+// for asking the session watcher to refresh it right away.
+var (
+	sessionMu  sync.Mutex
+	session    string
+	sessionNow = make(chan struct{}, 1)
+)
+
+// The "Re-authenticate…" menu items, which are shown when the token needs
+// renewing, and the menu rows that describe the session.
+var reauthItems, sessionItems []*systray.MenuItem
+
+// currentSession returns the description of Foliage's FOLIO session, or an
+// empty string if we don't know it.
+func currentSession() string {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return session
+}
+
+// refreshSession asks the session watcher to check the session now.
+func refreshSession() {
+	select {
+	case sessionNow <- struct{}{}:
+	default:
+	}
+}
+
+// watchSession periodically asks Foliage which FOLIO tenant it is using and
+// whether it holds a valid token, and updates the tooltip to match.
+// Multi-tenant institutions need to be sure which FOLIO they're changing.
+// If the token has expired or is about to, it also changes the icon and
+// shows the "Re-authenticate…" menu items, so that the user can get a new
+// token before a batch operation fails partway through.  It does not return.
+func watchSession() {
+	defer crash.Recover()
+	for {
+		select {
+		case <-sessionNow:
+		case <-time.After(sessionInterval):
+		}
+		text := ""
+		if info, err := status.Fetch(serverURL()); err == nil {
+			debugf("server status: %+v", *info)
+			text = sessionText(info, time.Now())
+			warn := tokenNeedsRenewal(info, time.Now())
+			showItems(reauthItems, warn)
+			setTokenWarning(warn)
+			showDemoMode(info.DemoMode)
+			showActiveTenant(info)
+			setFolioServer(info.FolioURL)
+			crash.SetTag("foliage_version", info.Version)
+			advertise(info)
+		} else {
+			log.Printf("unable to get status from %s: %v", serverURL(), err)
+			advertise(nil)
+		}
+		sessionMu.Lock()
+		changed := text != session
+		session = text
+		sessionMu.Unlock()
+		if changed {
+			updateTooltip()
+			for _, mi := range sessionItems {
+				mi.SetTitle(sessionTitle(text))
+			}
+		}
+	}
+}
+
+// sessionTitle returns the title of the menu rows that describe the session,
+// given the description of it.
+func sessionTitle(text string) string {
+	if text == "" {
+		return locale.T("FOLIO: unknown")
+	}
+	return locale.Sprintf("FOLIO: %s", text)
+}
+
+// tokenNeedsRenewal reports whether Foliage has FOLIO credentials whose token
+// is not valid, or expires within expiryWarning of now.
+func tokenNeedsRenewal(info *status.Info, now time.Time) bool {
+	if info.TenantID == "" && info.FolioURL == "" {
+		return false
+	}
+	if !info.LoggedIn {
+		return true
+	}
+	expires, ok := info.Expires()
+	return ok && expires.Sub(now) < expiryWarning
+}
+
+// sessionText describes the FOLIO session in a status report, in the form
+// "tenant@host — logged in", or "tenant@host — token expires in 9 min" if
+// the token is about to expire, followed by " — demo mode" if Foliage is in
+// demo mode.
+func sessionText(info *status.Info, now time.Time) string {
+	if info.TenantID == "" && info.FolioURL == "" {
+		return locale.T("no FOLIO credentials")
+	}
+	host := info.FolioURL
+	if u, err := url.Parse(info.FolioURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	login := locale.T("not logged in")
+	if info.LoggedIn {
+		login = locale.T("logged in")
+		if expires, ok := info.Expires(); ok {
+			switch left := expires.Sub(now); {
+			case left <= 0:
+				login = locale.T("token expired")
+			case left < expiryWarning:
+				login = locale.Sprintf("token expires in %d min", int(left.Minutes())+1)
+			}
+		}
+	}
+	if info.DemoMode {
+		login += " — " + locale.T("demo mode")
+	}
+	return fmt.Sprintf("%s@%s — %s", info.TenantID, host, login)
+}
+
+// reauthenticate opens Foliage in the browser, asking it to show the form
+// for entering FOLIO credentials, from which it gets a new token.
+func reauthenticate() {
+	open(serverURL() + "/?reauthenticate")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"macos-systray-widget/config"
+)
+
+// The cookie that holds the token once a browser has used a link with it,
+// and how long a browser that fails to give a password or token is made to
+// wait, to slow down guessing.
+const (
+	shareCookie  = "foliage_share"
+	shareBackoff = time.Second
+)
+
+// runShare runs the share subcommand, which makes Foliage on this computer
+// available to other computers on the local network, so that a supervisor
+// can follow or operate a long batch job from their own desk.  Requests are
+// passed on to Foliage the same way the https subcommand does, but only
+// once they are authenticated: with the user name (FOLIAGE_SHARE_USER,
+// "foliage" by default) and password in the setting FOLIAGE_SHARE_PASSWORD,
+// by HTTP basic authentication, if the password is set, or otherwise with a
+// token, FOLIAGE_SHARE_TOKEN or one made up each time, given in the link
+// printed at startup.  It serves HTTPS, with a certificate for this
+// computer's names and addresses signed by the certificate authority the
+// https subcommand uses, unless --http is given.  The setting
+// FOLIAGE_SHARE_PORT gives the port, 8444 by default.  It runs until it is
+// interrupted.
+func runShare(args []string) int {
+	fs := subcommandFlags("share")
+	listen := fs.Int("listen", settingInt("FOLIAGE_SHARE_PORT", 8444), "the port to serve on")
+	plain := fs.Bool("http", false, "serve plain HTTP rather than HTTPS")
+	urlFlag := fs.String("url", "", "the URL of Foliage, over HTTP")
+	port := fs.Int("port", 0, "the port of Foliage on this computer")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	backend, err := foliageBackend(*urlFlag, *port)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	auth := shareAuth{
+		user:     config.Get("FOLIAGE_SHARE_USER", "foliage"),
+		password: config.Get("FOLIAGE_SHARE_PASSWORD", ""),
+		token:    config.Get("FOLIAGE_SHARE_TOKEN", ""),
+	}
+	if auth.password == "" && auth.token == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to make a token: %v\n", err)
+			return 1
+		}
+		auth.token = hex.EncodeToString(b)
+	}
+	proto := "https"
+	if *plain {
+		proto = "http"
+	}
+	auth.next = foliageProxy(backend, proto)
+	hosts := lanHosts()
+	server := &http.Server{
+		Addr:              net.JoinHostPort("", strconv.Itoa(*listen)),
+		Handler:           auth,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("Sharing Foliage (%s) on the local network at:\n", backend)
+	for _, h := range hosts {
+		link := proto + "://" + net.JoinHostPort(h, strconv.Itoa(*listen)) + "/"
+		if auth.password == "" {
+			link += "?token=" + auth.token
+		}
+		fmt.Println("  " + link)
+	}
+	if auth.password != "" {
+		fmt.Printf("with the user name %q and the password in FOLIAGE_SHARE_PASSWORD.\n", auth.user)
+	} else {
+		fmt.Println("Anyone with one of these links can use Foliage; keep them private.")
+	}
+	logEvent(logInfo, "sharing Foliage", "address", server.Addr, "protocol", proto, "password", auth.password != "")
+	if *plain {
+		fmt.Println("Passwords, tokens and FOLIO data are sent unencrypted with --http.")
+		err = server.ListenAndServe()
+	} else {
+		files, cerr := certificates(false, hosts...)
+		if cerr != nil {
+			fmt.Fprintln(os.Stderr, cerr)
+			return 1
+		}
+		fmt.Printf("Browsers on other computers need to trust %s, or they will warn about the certificate.\n", files.CA)
+		err = server.ListenAndServeTLS(files.Cert, files.Key)
+	}
+	log.Printf("unable to share Foliage: %v", err)
+	return 1
+}
+
+// shareAuth lets through requests that are authenticated by the password or
+// the token, whichever is set.
+type shareAuth struct {
+	user, password, token string
+	next                  http.Handler
+}
+
+func (a shareAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.password != "" {
+		user, password, ok := r.BasicAuth()
+		if ok && equalSecret(user, a.user) && equalSecret(password, a.password) {
+			a.next.ServeHTTP(w, r)
+			return
+		}
+		a.refuse(w, r)
+		w.Header().Set("WWW-Authenticate", `Basic realm="Foliage", charset="UTF-8"`)
+		http.Error(w, "A user name and password are needed to use Foliage.", http.StatusUnauthorized)
+		return
+	}
+	// A link with the token sets the cookie, and the token is taken out of
+	// the address, so that it isn't left in the browser's history.
+	if token := r.URL.Query().Get("token"); token != "" && equalSecret(token, a.token) {
+		http.SetCookie(w, &http.Cookie{Name: shareCookie, Value: token, Path: "/",
+			HttpOnly: true, Secure: r.TLS != nil, SameSite: http.SameSiteStrictMode})
+		q := r.URL.Query()
+		q.Del("token")
+		r.URL.RawQuery = q.Encode()
+		logEvent(logInfo, "shared Foliage opened", "remote", r.RemoteAddr)
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+		return
+	}
+	if c, err := r.Cookie(shareCookie); err == nil && equalSecret(c.Value, a.token) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+	if bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); equalSecret(bearer, a.token) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+	a.refuse(w, r)
+	http.Error(w, "Use the link given when Foliage was shared.", http.StatusUnauthorized)
+}
+
+// refuse logs a request that wasn't authenticated, and makes it wait.
+func (a shareAuth) refuse(w http.ResponseWriter, r *http.Request) {
+	logEvent(logInfo, "shared Foliage refused a request", "remote", r.RemoteAddr, "path", r.URL.Path)
+	time.Sleep(shareBackoff)
+}
+
+// equalSecret compares a secret given with the real one in constant time.
+func equalSecret(given, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(given), []byte(secret)) == 1
+}
+
+// lanHosts returns the names and addresses other computers on the local
+// network may know this one by: its name, its Bonjour name, and its
+// addresses other than loopback and link-local ones.
+func lanHosts() []string {
+	var hosts []string
+	if name, err := os.Hostname(); err == nil && name != "" {
+		hosts = append(hosts, name)
+		if !strings.Contains(name, ".") {
+			hosts = append(hosts, name+".local")
+		}
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.IsGlobalUnicast() {
+				hosts = append(hosts, ipnet.IP.String())
+			}
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log"
+
+	"macos-systray-widget/browser"
+	"macos-systray-widget/hotkey"
+)
+
+// registerShortcut makes the keyboard shortcut given by --hotkey (or the
+// setting FOLIAGE_HOTKEY) bring Foliage to the front from any application.
+func registerShortcut(spec string) {
+	if spec == "" {
+		return
+	}
+	k, err := hotkey.Parse(spec)
+	if err == nil {
+		err = hotkey.Register(k, bringToFront)
+	}
+	if err != nil {
+		log.Printf("unable to use the shortcut %s: %v", spec, err)
+		return
+	}
+	log.Printf("%s brings Foliage to the front", k)
+}
+
+// bringToFront switches to a browser tab showing Foliage, if it can find
+// one, and otherwise opens Foliage in a new one.
+func bringToFront() {
+	found, err := browser.Focus(serverURL())
+	if err != nil {
+		debugf("unable to look for a Foliage tab: %v", err)
+	}
+	if !found {
+		open(serverURL())
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+)
+
+// How long to give the widget to clean up after a signal, beyond the time
+// it may take to stop Foliage, before it exits regardless.
+const signalGrace = 5 * time.Second
+
+// Nonzero once the tray icon is showing, so that shutting down has to take
+// it down first.  Accessed atomically.
+var trayRunning int32
+
+// watchSignals makes the widget shut down cleanly when it is interrupted
+// (with Ctrl+C in a terminal) or asked to stop (by SIGTERM, as service
+// managers and logging out do).  On Windows, closing the console window,
+// logging off and shutting down count as SIGTERM too.  The tray icon is
+// taken down, the control server stopped, the instance state file removed
+// and the log file closed, by the same code that runs when the user quits;
+// cleanup does that when there is no tray icon.  If the setting
+// FOLIAGE_STOP_ON_SIGNAL is true, a Foliage that the widget started itself
+// is stopped first.  A second signal makes the widget exit right away.
+func watchSignals(cleanup func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logEvent(logInfo, "shutting down on signal", "signal", sig)
+		go func() {
+			<-signals
+			log.Print("exiting at once on a second signal")
+			os.Exit(1)
+		}()
+		grace := signalGrace
+		stop := config.Bool("FOLIAGE_STOP_ON_SIGNAL", false)
+		if stop {
+			grace += shutdownTimeout
+		}
+		time.AfterFunc(grace, func() {
+			log.Printf("unable to shut down within %v; exiting anyway", grace)
+			os.Exit(1)
+		})
+		if pid := int(atomic.LoadInt64(&startedPid)); stop && pid != 0 && processAlive(pid) {
+			atomic.StoreInt32(&quitting, 1)
+			if err := shutdownServer(pid, shutdownTimeout); err != nil {
+				log.Print(err)
+			}
+		}
+		if atomic.LoadInt32(&trayRunning) != 0 {
+			systray.Quit()
+			return
+		}
+		cleanup()
+		os.Exit(0)
+	}()
+}
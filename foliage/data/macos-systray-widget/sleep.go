@@ -0,0 +1,134 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"macos-systray-widget/crash"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/health"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/power"
+	"macos-systray-widget/status"
+)
+
+// How long to wait after waking for FOLIO to be reachable again (the network
+// often takes a while to come back), how often to try, and how long each
+// try may take.
+const (
+	wakeWait    = 2 * time.Minute
+	wakeRetry   = 5 * time.Second
+	wakeTimeout = 10 * time.Second
+)
+
+// What happened to Foliage's batch job when the computer last went to
+// sleep: the job that was running, if any, and whether it was paused.
+var (
+	sleepMu     sync.Mutex
+	sleepJob    string
+	sleepPaused bool
+)
+
+// watchPower watches for the computer going to sleep and waking up.
+func watchPower() {
+	err := power.Watch(func(e power.Event) {
+		defer crash.Recover()
+		debugf("computer power event: %v", e)
+		if e == power.Sleep {
+			goingToSleep()
+		} else {
+			wokeUp()
+		}
+	})
+	if err != nil && err != power.ErrNotSupported {
+		log.Printf("unable to watch for sleep: %v", err)
+	}
+}
+
+// goingToSleep asks Foliage to pause its batch job, if it is running one,
+// because the network goes away while the computer sleeps and the records
+// being changed would be left half done.  It has to be quick; the computer
+// only waits a few seconds.
+func goingToSleep() {
+	p := currentJob()
+	if p.Operation == "" || p.Paused {
+		return
+	}
+	err := jobs.Pause(serverURL(), controlToken())
+	if err != nil {
+		log.Printf("unable to pause job before sleeping: %v", err)
+	} else {
+		log.Printf("paused job before sleeping: %s", p)
+	}
+	sleepMu.Lock()
+	sleepJob, sleepPaused = p.String(), err == nil
+	sleepMu.Unlock()
+}
+
+// wokeUp waits for FOLIO to be reachable again, and then offers to resume
+// the job that goingToSleep paused.  If the job couldn't be paused, the user
+// is warned to check its results instead.
+func wokeUp() {
+	refreshSession()
+	sleepMu.Lock()
+	job, paused := sleepJob, sleepPaused
+	sleepJob, sleepPaused = "", false
+	sleepMu.Unlock()
+	if job == "" {
+		return
+	}
+	if !paused {
+		notify.Post(notify.Notification{
+			Message: locale.Sprintf("The computer went to sleep during a job (%s) that could not be"+
+				" paused first. Check the job's results in Foliage for records that failed.", job),
+			URL: serverURL(),
+		})
+		return
+	}
+	info, err := waitForFolio()
+	if info != nil && (info.Job == nil || !info.Job.Paused) {
+		// The user has resumed or canceled the job already.
+		return
+	}
+	if err != nil {
+		log.Printf("FOLIO is not reachable after waking: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Foliage paused a job (%s) when the computer"+
+			" went to sleep, and FOLIO can't be reached since it woke up. Choose \"Resume Job\" in"+
+			" the Foliage menu when the network is back.", job)})
+		return
+	}
+	ok, err := dialog.Confirm(locale.T("Resume Job"), locale.Sprintf("Foliage paused a job (%s) when the computer"+
+		" went to sleep. FOLIO can be reached again. Resume the job?", job), locale.T("Resume"))
+	if err != nil {
+		log.Printf("unable to ask whether to resume the job: %v", err)
+		return
+	}
+	if ok {
+		resumeJob()
+	}
+}
+
+// waitForFolio waits until Foliage answers and the FOLIO server it uses can
+// be reached, for up to wakeWait.  It returns Foliage's status, if Foliage
+// answered, and why FOLIO couldn't be reached, if it couldn't.
+func waitForFolio() (*status.Info, error) {
+	var info *status.Info
+	var err error
+	for deadline := time.Now().Add(wakeWait); ; {
+		if info, err = status.Fetch(serverURL()); err == nil {
+			if info.Job == nil || !info.Job.Paused {
+				return info, nil
+			}
+			if err = health.Reachable(info.FolioURL, wakeTimeout, folioTransport(info.FolioURL)); err == nil {
+				return info, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return info, err
+		}
+		time.Sleep(wakeRetry)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/health"
+	"macos-systray-widget/icon"
+	"macos-systray-widget/locale"
+)
+
+// iconState describes how the tray icon looks in a given state.
+type iconState struct {
+	data     []byte // Icon image.
+	template bool   // Let macOS render it as a monochrome template?
+	status   string // Words added to the tooltip, if any.
+}
+
+// The states the tray icon can be in, by name.  The names of the states
+// that come from health checks are the health.State names with dashes
+// instead of spaces, so that they can also be set via the control API.
+// Guarded by iconMu once the icon is showing.
+var iconStates = makeIconStates(icon.Data, true)
+
+// makeIconStates returns the icon states, derived from the given image.
+// The built-in image is a template icon on macOS (meaning the system renders
+// it in monochrome to match the menu bar), but icons with colored badges,
+// and icons given by the user, have to be set as regular icons.
+func makeIconStates(data []byte, template bool) map[string]iconState {
+	return map[string]iconState{
+		"running":        {data, template, ""},
+		"starting":       {icon.Dimmed(data, 0.4), template, "starting"},
+		"not-responding": {icon.Badged(data, icon.Red), false, "not responding"},
+		"stopped":        {icon.Badged(icon.Dimmed(data, 0.4), icon.Red), false, "stopped"},
+		"busy":           {icon.Badged(data, icon.Green), false, "working"},
+		"token-expiring": {icon.Badged(data, icon.Yellow), false, "FOLIO token expiring"},
+		"offline":        {icon.Badged(data, icon.Gray), false, "FOLIO unreachable"},
+		"memory-high":    {icon.Badged(data, icon.Orange), false, "using too much memory"},
+	}
+}
+
+// What we last learned from health checks, whether Foliage has told us it
+// is running a batch operation, whether its FOLIO token needs renewing,
+// whether the FOLIO server can be reached, and whether Foliage is using too
+// much memory.  While the server is running, the icon has an orange dot if
+// it is using too much memory, or else a gray dot if FOLIO can't be
+// reached, or else a yellow dot if the token needs renewing, or else a green
+// dot if an operation is running.
+var (
+	iconMu       sync.Mutex
+	healthName   = "starting"
+	busy         bool
+	tokenWarning bool
+	folioOffline bool
+	memoryHigh   bool
+	shownName    string // The state the icon is showing.
+)
+
+// stateName returns the icon state name for a health state.
+func stateName(state health.State) string {
+	return strings.ReplaceAll(state.String(), " ", "-")
+}
+
+// watchServer polls the Foliage server and updates the tray icon and
+// tooltip to reflect its state.  The setting FOLIAGE_POLL_INTERVAL gives the
+// number of seconds between checks.  The checks follow the server if it
+// moves to another port.  It does not return.
+func watchServer() {
+	defer crash.Recover()
+	changes := make(chan health.State)
+	urlMu.Lock()
+	checker := health.NewChecker(foliageURL)
+	if seconds := settingInt("FOLIAGE_POLL_INTERVAL", 0); seconds > 0 {
+		checker.Interval = time.Duration(seconds) * time.Second
+	}
+	urlChecker = checker
+	urlMu.Unlock()
+	go checker.Run(changes)
+	for state := range changes {
+		// Once the watchdog knows Foliage has stopped, the health check
+		// results say nothing new.
+		logEvent(logInfo, "server state changed", "state", state, "url", serverURL())
+		iconMu.Lock()
+		healthName = stateName(state)
+		iconMu.Unlock()
+		if atomic.LoadInt32(&stopped) == 0 {
+			refreshIcon()
+			showStartItems(state)
+		}
+		if state == health.Running {
+			refreshSession()
+		} else {
+			advertise(nil)
+		}
+	}
+}
+
+// setBusy records whether Foliage is running a batch operation, and updates
+// the icon if that changes what it should show.
+func setBusy(b bool) {
+	iconMu.Lock()
+	changed := b != busy
+	busy = b
+	iconMu.Unlock()
+	if changed && atomic.LoadInt32(&stopped) == 0 {
+		refreshIcon()
+	}
+}
+
+// setTokenWarning records whether Foliage's FOLIO token needs renewing, and
+// updates the icon if that changes what it should show.
+func setTokenWarning(b bool) {
+	iconMu.Lock()
+	changed := b != tokenWarning
+	tokenWarning = b
+	iconMu.Unlock()
+	if changed && atomic.LoadInt32(&stopped) == 0 {
+		refreshIcon()
+	}
+}
+
+// setFolioOffline records whether the FOLIO server can't be reached, and
+// updates the icon if that changes what it should show.
+func setFolioOffline(b bool) {
+	iconMu.Lock()
+	changed := b != folioOffline
+	folioOffline = b
+	iconMu.Unlock()
+	if changed && atomic.LoadInt32(&stopped) == 0 {
+		refreshIcon()
+	}
+}
+
+// setMemoryHigh records whether Foliage is using more memory than it should,
+// and updates the icon if that changes what it should show.
+func setMemoryHigh(b bool) {
+	iconMu.Lock()
+	changed := b != memoryHigh
+	memoryHigh = b
+	iconMu.Unlock()
+	if changed && atomic.LoadInt32(&stopped) == 0 {
+		refreshIcon()
+	}
+}
+
+// refreshIcon shows the icon state for what we know about the server.
+func refreshIcon() {
+	iconMu.Lock()
+	name := healthName
+	if name == "running" {
+		switch {
+		case memoryHigh:
+			name = "memory-high"
+		case folioOffline:
+			name = "offline"
+		case tokenWarning:
+			name = "token-expiring"
+		case busy:
+			name = "busy"
+		}
+	}
+	iconMu.Unlock()
+	showState(name)
+}
+
+// setIconImage replaces the image the icon states are derived from, and
+// redraws the icon.
+func setIconImage(data []byte, template bool) {
+	states := makeIconStates(data, template)
+	iconMu.Lock()
+	iconStates = states
+	name := shownName
+	iconMu.Unlock()
+	if name != "" {
+		showState(name)
+	}
+}
+
+// showState changes the tray icon and tooltip to the named state.
+func showState(name string) error {
+	iconMu.Lock()
+	state, ok := iconStates[name]
+	iconMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown icon state %q", name)
+	}
+	if state.template {
+		systray.SetTemplateIcon(state.data, state.data)
+	} else {
+		systray.SetIcon(state.data)
+	}
+	iconMu.Lock()
+	shownName = name
+	iconMu.Unlock()
+	updateTooltip()
+	return nil
+}
+
+// updateTooltip sets the tooltip to suit the state the icon is showing.  It
+// starts with the text given by --tooltip, normally "Foliage".  While
+// Foliage is running, the tooltip goes on to describe the FOLIO tenant it is
+// using and the batch operation it is running, if any, for example
+// "Foliage — caltech@okapi.example.org — logged in".  Otherwise, it says
+// what's wrong.  The icon's label for screen readers is the same, but always
+// says what state the icon shows, as in "Foliage (working) — …".
+func updateTooltip() {
+	iconMu.Lock()
+	name := shownName
+	status := iconStates[name].status
+	iconMu.Unlock()
+	if status == "" {
+		status = "running"
+	}
+	// Screen readers can't describe the icon's dimming or colored dot, so
+	// its label always says what the state is.
+	label := fmt.Sprintf("%s (%s)", trayTooltip, locale.T(status))
+	if name != "running" && name != "busy" && name != "token-expiring" && name != "memory-high" {
+		systray.SetTooltip(label)
+		setTrayLabel(label)
+		return
+	}
+	var parts []string
+	if s := currentSession(); s != "" {
+		parts = append(parts, s)
+	}
+	if p := currentJob(); p.Operation != "" {
+		parts = append(parts, p.String())
+	}
+	if name == "memory-high" {
+		parts = append(parts, resourceText())
+	}
+	systray.SetTooltip(strings.Join(append([]string{trayTooltip}, parts...), " — "))
+	setTrayLabel(strings.Join(append([]string{label}, parts...), " — "))
+}
@@ -0,0 +1,69 @@
+// Package status fetches information about a running Foliage server from its
+// status endpoint, /status, which returns a JSON object describing the
+// server's version, the FOLIO tenant it is using, and the state of its
+// FOLIO token.
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How long to wait for the server to answer.
+const timeout = 5 * time.Second
+
+// Info is the server's description of itself.
+type Info struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Pid      int    `json:"pid"`
+	Python   string `json:"python"`
+	Platform string `json:"platform"`
+	FolioURL string `json:"folio_url"`
+	TenantID string `json:"tenant_id"`
+	LoggedIn bool   `json:"logged_in"` // Does Foliage hold a valid token?
+	DemoMode bool   `json:"demo_mode"`
+	Job      *Job   `json:"job"` // The batch job running, or nil if none is.
+
+	// When the token expires, in seconds since the epoch, or 0 if it
+	// doesn't or Foliage can't tell.
+	TokenExpires int64 `json:"token_expires"`
+}
+
+// Job describes the batch job Foliage is running.
+type Job struct {
+	Operation string `json:"operation"`
+	Done      int    `json:"done"`
+	Total     int    `json:"total"`
+	Errors    int    `json:"errors"`
+	Paused    bool   `json:"paused"`
+}
+
+// Expires returns the time the token expires, and false if it doesn't.
+func (info *Info) Expires() (time.Time, bool) {
+	if info.TokenExpires == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(info.TokenExpires, 0), true
+}
+
+// Fetch gets the status of the Foliage server at the given base URL.
+func Fetch(url string) (*Info, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(strings.TrimSuffix(url, "/") + "/status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status request failed: %s", resp.Status)
+	}
+	var info Info
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("unable to read server status: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"macos-systray-widget/control"
+	"macos-systray-widget/health"
+	"macos-systray-widget/instance"
+	"macos-systray-widget/status"
+)
+
+// reportStatus asks the Foliage server at the URL for its status, and the
+// FOLIO server it uses whether it can be reached, and returns what they
+// say, without the widget's part.
+func reportStatus(url string) control.Status {
+	st := control.Status{Server: control.ServerStatus{URL: url}}
+	info, err := status.Fetch(url)
+	if err != nil {
+		st.Server.Error = err.Error()
+		return st
+	}
+	st.Server.Up = true
+	st.Server.Version, st.Server.Pid = info.Version, info.Pid
+	st.Server.Python, st.Server.Platform = info.Python, info.Platform
+	st.Folio = control.FolioStatus{
+		URL:      info.FolioURL,
+		TenantID: info.TenantID,
+		LoggedIn: info.LoggedIn,
+		DemoMode: info.DemoMode,
+	}
+	if t, ok := info.Expires(); ok {
+		st.Folio.TokenExpires = &t
+	}
+	if info.FolioURL != "" {
+		st.Folio.Reachable = health.Reachable(info.FolioURL, folioTimeout, folioTransport(info.FolioURL)) == nil
+	}
+	if j := info.Job; j != nil && j.Operation != "" {
+		st.Job = &control.Progress{Operation: j.Operation, Done: j.Done, Total: j.Total, Errors: j.Errors, Paused: j.Paused}
+	}
+	return st
+}
+
+// trayStatus returns the status the tray reports for GET /status.
+func trayStatus() control.Status {
+	st := reportStatus(serverURL())
+	iconMu.Lock()
+	state := shownName
+	iconMu.Unlock()
+	st.Widget = &control.WidgetStatus{
+		Version:     strings.TrimPrefix(widgetVersion(), "foliage-widget@"),
+		Pid:         os.Getpid(),
+		State:       state,
+		ControlPort: controlPort,
+	}
+	if p := currentJob(); st.Job == nil && p.Operation != "" {
+		st.Job = &p
+	}
+	return st
+}
+
+// helperStatus asks the running tray for its status, over its control
+// server, or, if no tray with one is running, asks Foliage at the URL
+// directly.
+func helperStatus(url string) control.Status {
+	path, err := instance.Path()
+	if err != nil {
+		return reportStatus(url)
+	}
+	state, err := instance.Read(path)
+	if err != nil {
+		return reportStatus(url)
+	}
+	if state.ControlPort > 0 {
+		st, err := control.FetchStatus(state.ControlPort)
+		if err == nil {
+			return *st
+		}
+		debugf("unable to get the status of the tray: %v", err)
+	}
+	st := reportStatus(url)
+	if instance.Forward(path, nil) == nil {
+		// The tray runs without a control server, so all there is to
+		// tell of it is that it runs.
+		st.Widget = &control.WidgetStatus{Pid: state.Pid}
+	}
+	return st
+}
+
+// printHelperStatus prints the status, as JSON or as a summary, and returns
+// the exit status of the status subcommand: 0 if Foliage is up, and 1 if it
+// isn't.
+func printHelperStatus(st control.Status, asJSON bool) int {
+	code := 0
+	if !st.Server.Up {
+		code = 1
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(st)
+		return code
+	}
+	switch w := st.Widget; {
+	case w == nil:
+		fmt.Println("Tray:    not running")
+	case w.Version == "":
+		fmt.Printf("Tray:    running (process %d)\n", w.Pid)
+	default:
+		fmt.Printf("Tray:    %s (version %s, process %d)\n", w.State, w.Version, w.Pid)
+	}
+	if s := st.Server; s.Up {
+		fmt.Printf("Foliage: up at %s (version %s, process %d)\n", s.URL, s.Version, s.Pid)
+	} else {
+		fmt.Printf("Foliage: down at %s (%s)\n", s.URL, s.Error)
+		return code
+	}
+	if f := st.Folio; f.URL != "" {
+		reach := "reachable"
+		if !f.Reachable {
+			reach = "unreachable"
+		}
+		login := "not logged in"
+		switch {
+		case f.LoggedIn && f.TokenExpires != nil:
+			login = "token expires " + f.TokenExpires.Local().Format("2006-01-02 15:04")
+			if time.Until(*f.TokenExpires) <= 0 {
+				login = "token expired " + f.TokenExpires.Local().Format("2006-01-02 15:04")
+			}
+		case f.LoggedIn:
+			login = "logged in"
+		}
+		fmt.Printf("FOLIO:   tenant %s at %s, %s, %s\n", f.TenantID, f.URL, reach, login)
+	}
+	if st.Folio.DemoMode {
+		fmt.Println("         (demo mode)")
+	}
+	if st.Job != nil {
+		fmt.Printf("Job:     %s\n", st.Job)
+	} else {
+		fmt.Println("Job:     none")
+	}
+	return code
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/identifiers"
+	"macos-systray-widget/jobs"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/menu"
+	"macos-systray-widget/notify"
+	"macos-systray-widget/status"
+	"macos-systray-widget/tenants"
+)
+
+// The tenants offered in the "FOLIO Server" submenus, loaded when the menu is
+// built, and the submenus' entries for them, in the same order.
+var (
+	tenantMu    sync.Mutex
+	tenantList  []tenants.Tenant
+	tenantItems [][]*systray.MenuItem
+)
+
+// identifierTypes returns the kinds of identifiers the records of the tenant
+// Foliage is using have, from its entry in the list of tenants, if it has
+// one with any, or else identifiers.Default.
+func identifierTypes() identifiers.Types {
+	t, ok := tenants.Find(loadTenants(), config.Get("FOLIO_OKAPI_URL", ""), config.Get("FOLIO_OKAPI_TENANT_ID", ""))
+	if !ok {
+		return identifiers.Default()
+	}
+	return t.IdentifierTypes()
+}
+
+// loadTenants reads the list of tenants from the file named by the setting
+// FOLIAGE_TENANTS, or from the default file.  Problems are logged, and leave
+// the list empty.
+func loadTenants() []tenants.Tenant {
+	path, err := tenants.ConfiguredPath()
+	if err != nil {
+		log.Printf("unable to find the list of FOLIO tenants: %v", err)
+		return nil
+	}
+	list, err := tenants.Load(path)
+	if err != nil {
+		log.Printf("unable to use the list of FOLIO tenants: %v", err)
+	}
+	return list
+}
+
+// addTenantMenu adds a "FOLIO Server" submenu for the manifest item, with an
+// entry for each tenant.  There is no submenu if no tenants are configured.
+func addTenantMenu(parent *systray.MenuItem, item menu.Item) {
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+	if tenantList == nil {
+		tenantList = loadTenants()
+	}
+	if len(tenantList) == 0 {
+		return
+	}
+	sub := addItem(parent, item, item.Title, false)
+	var items []*systray.MenuItem
+	for i, t := range tenantList {
+		mi := sub.AddSubMenuItemCheckbox(t.Name, locale.Sprintf("%s at %s", t.TenantID, t.URL), false)
+		items = append(items, mi)
+		go func(i int, mi *systray.MenuItem) {
+			for range mi.ClickedCh {
+				switchTenant(i)
+			}
+		}(i, mi)
+	}
+	tenantItems = append(tenantItems, items)
+}
+
+// showActiveTenant checks the entry for the tenant Foliage is using.
+func showActiveTenant(info *status.Info) {
+	tenantMu.Lock()
+	defer tenantMu.Unlock()
+	for _, items := range tenantItems {
+		for i, mi := range items {
+			if tenantList[i].Matches(info.FolioURL, info.TenantID) {
+				mi.Check()
+			} else {
+				mi.Uncheck()
+			}
+		}
+	}
+}
+
+// switchTenant asks Foliage to switch to the i'th tenant, after the user
+// confirms.  Foliage uses the token it last had for that tenant, if it has
+// one; otherwise, the user is asked to log in.
+func switchTenant(i int) {
+	tenantMu.Lock()
+	t := tenantList[i]
+	tenantMu.Unlock()
+	info, err := status.Fetch(serverURL())
+	if err != nil {
+		notify.Post(notify.Notification{Message: locale.T("Foliage is not responding, so it can't switch servers.")})
+		refreshSession()
+		return
+	}
+	if t.Matches(info.FolioURL, info.TenantID) {
+		// Clicking toggles the check mark; put it back.
+		showActiveTenant(info)
+		return
+	}
+	ok, err := dialog.Confirm(locale.T("Switch FOLIO Server"),
+		locale.Sprintf("Switch Foliage to %s (%s)? Changes made from now on will go to"+
+			" that FOLIO tenant.", t.Name, t.TenantID), locale.T("Switch"))
+	if err != nil {
+		log.Printf("unable to ask for confirmation: %v", err)
+	}
+	if !ok {
+		showActiveTenant(info)
+		return
+	}
+	log.Printf("switching Foliage to tenant %s at %s", t.TenantID, t.URL)
+	err = jobs.SwitchTenant(serverURL(), controlToken(), t.URL, t.TenantID)
+	if err != nil {
+		log.Printf("unable to switch tenants: %v", err)
+		notify.Post(notify.Notification{Message: locale.Sprintf("Unable to switch FOLIO servers: %v", err)})
+		showActiveTenant(info)
+		return
+	}
+	refreshSession()
+	if info, err := status.Fetch(serverURL()); err == nil && !info.LoggedIn {
+		// Foliage has no token for this tenant yet.
+		reauthenticate()
+	}
+}
+
+// runTenants runs the tenants subcommand, which prints the list of tenants,
+// or with --json, the list as the JSON array of its entries.  Foliage uses
+// it to check that a request to switch tenants is for one in the list,
+// since it can't read YAML files itself.
+func runTenants(args []string) int {
+	fs := subcommandFlags("tenants")
+	asJSON := fs.Bool("json", false, "print the list as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path, err := tenants.ConfiguredPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	list, err := tenants.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *asJSON {
+		if list == nil {
+			list = []tenants.Tenant{}
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+	for _, t := range list {
+		fmt.Printf("%s\t%s\t%s\n", t.Name, t.TenantID, t.URL)
+	}
+	return 0
+}
@@ -0,0 +1,169 @@
+// Package tenants reads the list of FOLIO tenants that the widget's
+// "FOLIO Server" submenu offers to switch Foliage between.  The list is a
+// JSON or YAML file, like the menu manifest:
+//
+//	tenants:
+//	  - name: Caltech (production)
+//	    url: https://okapi-caltech.folio.ebsco.com
+//	    tenant_id: fs00001011
+//	    ui_url: https://caltech.folio.ebsco.com
+//	  - name: Caltech (test)
+//	    url: https://okapi-caltech-test.folio.ebsco.com
+//	    tenant_id: fs00001012
+//	    rate_limit: 5
+//	    max_in_flight: 2
+//	  - name: Library (self-hosted)
+//	    url: https://okapi.library.example.edu
+//	    tenant_id: diku
+//	    ca_bundle: /etc/ssl/library-ca.pem
+//
+// The url is the address of the tenant's OKAPI server, as given to Foliage
+// in FOLIO_OKAPI_URL, and the optional ui_url that of its staff interface,
+// where the widget opens records.  The optional rate_limit (requests a second),
+// rate_burst and max_in_flight limit how hard Go programs that use the
+// tenant, through package foliolib, work it.  The optional ca_bundle,
+// client_cert, client_key and insecure_skip_verify are its TLS settings
+// (see package tlsconfig), used by the widget and by Go programs.  The
+// optional identifiers say what the tenant's identifiers look like (see
+// package identifiers).
+package tenants
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"macos-systray-widget/appdirs"
+	"macos-systray-widget/config"
+	"macos-systray-widget/identifiers"
+	"macos-systray-widget/tlsconfig"
+)
+
+// Tenant is one FOLIO tenant in the list.
+type Tenant struct {
+	Name     string `json:"name" yaml:"name"`
+	URL      string `json:"url" yaml:"url"`
+	TenantID string `json:"tenant_id" yaml:"tenant_id"`
+
+	// UIURL is the address of the tenant's FOLIO staff interface, such as
+	// https://caltech.folio.ebsco.com, for opening records in it.
+	UIURL string `json:"ui_url,omitempty" yaml:"ui_url,omitempty"`
+
+	RateLimit   float64 `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	RateBurst   int     `json:"rate_burst,omitempty" yaml:"rate_burst,omitempty"`
+	MaxInFlight int     `json:"max_in_flight,omitempty" yaml:"max_in_flight,omitempty"`
+
+	CABundle           string `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey          string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+
+	Identifiers identifiers.Types `json:"identifiers,omitempty" yaml:"identifiers,omitempty"`
+}
+
+// IdentifierTypes returns the kinds of identifiers the tenant's records
+// have: its own, if it has any, or else identifiers.Default.
+func (t Tenant) IdentifierTypes() identifiers.Types {
+	if len(t.Identifiers) > 0 {
+		return t.Identifiers
+	}
+	return identifiers.Default()
+}
+
+// TLS returns the tenant's TLS settings.  Relative paths are taken to be
+// relative to the directory of the list, dir.
+func (t Tenant) TLS(dir string) tlsconfig.Options {
+	abs := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	return tlsconfig.Options{
+		CABundle:           abs(t.CABundle),
+		ClientCert:         abs(t.ClientCert),
+		ClientKey:          abs(t.ClientKey),
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+}
+
+// Matches reports whether the tenant is the one at the OKAPI URL folioURL
+// with the id tenantID, ignoring a final slash in the URLs.
+func (t Tenant) Matches(folioURL, tenantID string) bool {
+	return strings.TrimSuffix(t.URL, "/") == strings.TrimSuffix(folioURL, "/") &&
+		t.TenantID == tenantID
+}
+
+// DefaultPath returns the path of the list when no other is given: the
+// file tenants.yaml in Foliage's data directory.
+func DefaultPath() (string, error) {
+	dir, err := appdirs.UserDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tenants.yaml"), nil
+}
+
+// ConfiguredPath returns the path of the list named by the setting
+// FOLIAGE_TENANTS, or else DefaultPath.
+func ConfiguredPath() (string, error) {
+	if path := config.Get("FOLIAGE_TENANTS", ""); path != "" {
+		return path, nil
+	}
+	return DefaultPath()
+}
+
+// Find returns the tenant in the list that Matches, if there is one.  With
+// an empty tenantID, it returns the first tenant at folioURL.
+func Find(list []Tenant, folioURL, tenantID string) (Tenant, bool) {
+	for _, t := range list {
+		if t.Matches(folioURL, tenantID) || (tenantID == "" && t.Matches(folioURL, t.TenantID)) {
+			return t, true
+		}
+	}
+	return Tenant{}, false
+}
+
+// Load reads the list in the named file.  Files whose names end in ".json"
+// are read as JSON; anything else is read as YAML.  A missing file is not an
+// error; it is the same as an empty list.
+func Load(path string) ([]Tenant, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Tenants []Tenant `json:"tenants" yaml:"tenants"`
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(data, &list)
+	} else {
+		err = yaml.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, t := range list.Tenants {
+		if t.URL == "" || t.TenantID == "" {
+			return nil, fmt.Errorf("%s: tenant %q needs a url and a tenant_id", path, t.Name)
+		}
+		if t.RateLimit < 0 || t.RateBurst < 0 || t.MaxInFlight < 0 {
+			return nil, fmt.Errorf("%s: tenant %q has a negative limit", path, t.Name)
+		}
+		if err := t.Identifiers.Check(); err != nil {
+			return nil, fmt.Errorf("%s: tenant %q: %w", path, t.Name, err)
+		}
+	}
+	for i := range list.Tenants {
+		if list.Tenants[i].Name == "" {
+			list.Tenants[i].Name = list.Tenants[i].TenantID
+		}
+	}
+	return list.Tenants, nil
+}
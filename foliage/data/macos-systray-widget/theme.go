@@ -0,0 +1,112 @@
+package main
+
+import (
+	"log"
+	"runtime"
+	"sync"
+
+	"macos-systray-widget/crash"
+	"macos-systray-widget/icon"
+	"macos-systray-widget/theme"
+)
+
+// The icon themes, chosen with the option --icon-theme or the setting
+// FOLIAGE_ICON_THEME (or in the Preferences window).
+const (
+	themeAuto       = "auto"       // Suit the taskbar or menu bar, as far as we can tell.
+	themeColor      = "color"      // The built-in icon, in full color.
+	themeMonochrome = "monochrome" // In one color, to suit the taskbar or menu bar.
+	themeLight      = "light"      // Monochrome, for light taskbars.
+	themeDark       = "dark"       // Monochrome, for dark taskbars.
+)
+
+// The icon theme in use, and what we know about the taskbar's theme.
+var (
+	themeMu      sync.Mutex
+	iconTheme    = themeAuto
+	taskbarKnown bool
+	taskbarDark  bool
+)
+
+// watchTheme shows the icon in the theme chosen in the settings.  In the
+// automatic theme, it replaces the built-in icon with a monochrome version
+// that suits the theme of the taskbar, and switches versions when the theme
+// changes, on systems where the theme package can tell (that is, Windows).
+// On macOS, the built-in icon is a template icon, which the system already
+// renders to suit the menu bar.  It does not return while watching.
+func watchTheme() {
+	defer crash.Recover()
+	setIconTheme(startOptions.iconTheme)
+	dark, err := theme.Dark()
+	if err == theme.ErrNotSupported {
+		return
+	} else if err != nil {
+		log.Printf("unable to find the taskbar theme: %v", err)
+		return
+	}
+	setTaskbarDark(dark)
+	err = theme.Watch(func(dark bool) {
+		debugf("taskbar theme changed; dark: %v", dark)
+		setTaskbarDark(dark)
+	})
+	if err != nil {
+		log.Printf("unable to watch for taskbar theme changes: %v", err)
+	}
+}
+
+// setIconTheme switches the icon to the named theme.  Unknown names mean
+// the automatic theme.
+func setIconTheme(name string) {
+	themeMu.Lock()
+	iconTheme = name
+	themeMu.Unlock()
+	showIconTheme()
+}
+
+// setTaskbarDark records the theme of the taskbar, and changes the icon to
+// suit it if the icon theme is automatic.
+func setTaskbarDark(dark bool) {
+	themeMu.Lock()
+	taskbarKnown, taskbarDark = true, dark
+	themeMu.Unlock()
+	showIconTheme()
+}
+
+// showIconTheme redraws the icon in the current theme, unless the icon was
+// given by --icon, which is always shown as it is.  The monochrome theme is
+// a template icon on macOS, which the system draws in the menu bar's color,
+// and elsewhere, in the color for the taskbar's theme if we know it, or for a
+// dark taskbar, which most Linux desktops have, if we don't.
+func showIconTheme() {
+	if customIcon {
+		return
+	}
+	themeMu.Lock()
+	name, known, dark := iconTheme, taskbarKnown, taskbarDark
+	themeMu.Unlock()
+	switch {
+	case name == themeColor:
+		setIconImage(icon.Data, false)
+	case name == themeLight:
+		setIconImage(themedIcon(false), false)
+	case name == themeDark:
+		setIconImage(themedIcon(true), false)
+	case name == themeMonochrome && runtime.GOOS == "darwin":
+		setIconImage(icon.Data, true)
+	case name == themeMonochrome:
+		setIconImage(themedIcon(dark || !known), false)
+	case known:
+		setIconImage(themedIcon(dark), false)
+	default:
+		setIconImage(icon.Data, true)
+	}
+}
+
+// themedIcon returns the built-in icon in a color that stands out against
+// a dark or light taskbar.
+func themedIcon(dark bool) []byte {
+	if dark {
+		return icon.Tinted(icon.Data, icon.OnDark)
+	}
+	return icon.Tinted(icon.Data, icon.OnLight)
+}
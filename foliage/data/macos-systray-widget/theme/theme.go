@@ -0,0 +1,22 @@
+// Package theme tells whether the desktop uses a dark theme for the taskbar
+// or menu bar where the tray icon appears, so that the widget can use an
+// icon that stands out against it.  On macOS, there is no need for this,
+// because the system renders the widget's template icon to suit the menu
+// bar; this package is used on Windows, where icons are drawn as they are.
+package theme
+
+import "errors"
+
+// ErrNotSupported is returned on systems where the theme can't be found.
+var ErrNotSupported = errors.New("theme detection is not supported on this system")
+
+// Dark reports whether the taskbar uses a dark theme.
+func Dark() (bool, error) {
+	return dark()
+}
+
+// Watch calls changed with the new value of Dark each time the theme
+// changes.  It does not return unless watching fails.
+func Watch(changed func(dark bool)) error {
+	return watch(changed)
+}
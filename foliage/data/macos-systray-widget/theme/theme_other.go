@@ -0,0 +1,12 @@
+//go:build !windows
+// +build !windows
+
+package theme
+
+func dark() (bool, error) {
+	return false, ErrNotSupported
+}
+
+func watch(changed func(dark bool)) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,52 @@
+package theme
+
+import (
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// Windows 10 and later record the theme of the taskbar (as opposed to that of
+// applications) in this registry value, which is 0 for the dark theme.
+// Earlier versions have no such value and always use a dark taskbar.
+const (
+	personalizeKey = `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`
+	lightValue     = "SystemUsesLightTheme"
+)
+
+func dark() (bool, error) {
+	k, err := registry.OpenKey(registry.CURRENT_USER, personalizeKey, registry.QUERY_VALUE)
+	if err == registry.ErrNotExist {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer k.Close()
+	light, _, err := k.GetIntegerValue(lightValue)
+	if err == registry.ErrNotExist {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return light == 0, nil
+}
+
+func watch(changed func(dark bool)) error {
+	k, err := registry.OpenKey(registry.CURRENT_USER, personalizeKey, registry.NOTIFY|registry.QUERY_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	last, _ := dark()
+	for {
+		// This blocks until a value under the key changes.
+		err := windows.RegNotifyChangeKeyValue(windows.Handle(k), false,
+			windows.REG_NOTIFY_CHANGE_LAST_SET, 0, false)
+		if err != nil {
+			return err
+		}
+		if d, err := dark(); err == nil && d != last {
+			last = d
+			changed(d)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package tlsconfig makes the TLS settings for connecting to a FOLIO that
+// doesn't have a certificate from a public certificate authority, as
+// self-hosted FOLIOs often don't: a bundle of the certificates of the
+// authorities to trust (in addition to the system's), a client certificate
+// for FOLIOs that ask for one, and, for testing only, not verifying the
+// server's certificate at all.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Options are the TLS settings for a server.  The zero value uses the
+// system's settings.
+type Options struct {
+	CABundle   string // A PEM file of the certificate authorities to trust.
+	ClientCert string // A PEM file of the client certificate.
+	ClientKey  string // A PEM file of its private key, if it isn't in ClientCert.
+
+	// InsecureSkipVerify turns off checking the server's certificate, so
+	// that anyone in between can read and change what is sent, including
+	// FOLIO tokens and passwords.  Programs should log a warning whenever
+	// it is used.
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether o uses the system's settings.
+func (o Options) IsZero() bool {
+	return o == Options{}
+}
+
+// Config returns the TLS configuration for the options.
+func (o Options) Config() (*tls.Config, error) {
+	c := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+	if o.CABundle != "" {
+		pem, err := os.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			// Windows had no system pool before Go 1.18.
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s has no PEM certificates", o.CABundle)
+		}
+		c.RootCAs = pool
+	}
+	if o.ClientCert != "" {
+		key := o.ClientKey
+		if key == "" {
+			key = o.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to use client certificate: %w", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	} else if o.ClientKey != "" {
+		return nil, fmt.Errorf("a client key was given without a client certificate")
+	}
+	return c, nil
+}
+
+// Transport returns an HTTP transport like the default one, but with the
+// options' TLS configuration.
+func (o Options) Transport() (*http.Transport, error) {
+	c, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = c
+	return t, nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+
+	"fyne.io/systray"
+)
+
+// trayAvailable returns true if there is a graphical desktop we can put an
+// icon on.  On Linux, the systray library uses GTK and AppIndicator, and GTK
+// aborts the program if it cannot open a display, so we check first.
+func trayAvailable() bool {
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// setTrayLabel gives the tray entry the label as its title, which is what
+// screen readers call it, unless --title gave it one.
+func setTrayLabel(label string) {
+	if trayTitle == "" {
+		systray.SetTitle(label)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"errors"
+	"log"
+
+	"macos-systray-widget/accessibility"
+)
+
+// trayAvailable returns true if there is a graphical desktop we can put an
+// icon on.  On macOS and Windows, there always is.
+func trayAvailable() bool {
+	return true
+}
+
+// setTrayLabel makes the label what screen readers call the tray icon.  On
+// Windows, that is the tooltip, so there is nothing to do.
+func setTrayLabel(label string) {
+	if err := accessibility.SetTrayLabel(label); err != nil && !errors.Is(err, accessibility.ErrNotSupported) {
+		log.Printf("unable to label the tray icon for screen readers: %v", err)
+	}
+}
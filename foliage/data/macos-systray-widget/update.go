@@ -0,0 +1,100 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/config"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/dialog"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/status"
+	"macos-systray-widget/update"
+)
+
+// How often to check for a new release, and how long to wait after startup
+// before the first check, so as not to slow down startup.
+const (
+	updateInterval = 24 * time.Hour
+	updateDelay    = time.Minute
+)
+
+// Menu items that announce an available update.  They are hidden until a
+// check finds one.
+var updateItems []*systray.MenuItem
+
+// The release page of the newest release found, if it is newer than the
+// running version.
+var (
+	updateMu  sync.Mutex
+	updateURL string
+)
+
+// watchUpdates checks for a new release of Foliage periodically, unless this
+// has been turned off using the setting FOLIAGE_CHECK_UPDATES.
+func watchUpdates() {
+	defer crash.Recover()
+	if !config.Bool("FOLIAGE_CHECK_UPDATES", true) {
+		log.Print("update checks are turned off")
+		return
+	}
+	time.Sleep(updateDelay)
+	for {
+		checkForUpdate(false)
+		time.Sleep(updateInterval)
+	}
+}
+
+// checkForUpdate compares the running version of Foliage with the latest
+// release, and shows the update menu items if the release is newer.  If
+// manual is true, the user asked for the check, so the outcome is reported
+// in a dialog even if there is no update.
+func checkForUpdate(manual bool) {
+	report := func(format string, args ...interface{}) {
+		log.Printf(format, args...)
+		if manual {
+			if err := dialog.Info(locale.T("Foliage Updates"), locale.Sprintf(format, args...)); err != nil {
+				log.Printf("unable to show dialog: %v", err)
+			}
+		}
+	}
+	info, err := status.Fetch(serverURL())
+	if err != nil {
+		report("Unable to find out which version of Foliage is running: %v", err)
+		return
+	}
+	latest, err := update.Latest()
+	if err != nil {
+		report("Unable to check for Foliage updates: %v", err)
+		return
+	}
+	if !update.Newer(latest.Version, info.Version) {
+		report("Foliage %s is the latest version.", info.Version)
+		return
+	}
+	log.Printf("Foliage %s is available (running %s)", latest.Version, info.Version)
+	updateMu.Lock()
+	updateURL = latest.URL
+	updateMu.Unlock()
+	for _, item := range updateItems {
+		item.SetTitle(locale.Sprintf("Update available — %s", latest.Version))
+		item.SetTooltip(locale.Sprintf("Foliage %s is running; open the page for release %s",
+			info.Version, latest.Version))
+		item.Show()
+	}
+	if manual {
+		open(latest.URL)
+	}
+}
+
+// openUpdate opens the page for the available release.
+func openUpdate() {
+	updateMu.Lock()
+	url := updateURL
+	updateMu.Unlock()
+	if url != "" {
+		open(url)
+	}
+}
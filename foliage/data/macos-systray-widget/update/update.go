@@ -0,0 +1,95 @@
+// Package update finds out whether a newer release of Foliage is available,
+// by asking GitHub for the latest release of caltechlibrary/foliage.
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LatestURL is the GitHub API endpoint describing the latest release.
+const LatestURL = "https://api.github.com/repos/caltechlibrary/foliage/releases/latest"
+
+// How long to wait for GitHub to answer.
+const timeout = 15 * time.Second
+
+// Release describes a published release of Foliage.
+type Release struct {
+	Version string // Version number, without any leading "v".
+	URL     string // Web page for the release.
+}
+
+type release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Latest returns the most recent published release.
+func Latest() (*Release, error) {
+	req, err := http.NewRequest("GET", LatestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub release request failed: %s", resp.Status)
+	}
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unable to read GitHub release: %w", err)
+	}
+	if r.TagName == "" {
+		return nil, fmt.Errorf("GitHub release has no tag")
+	}
+	return &Release{Version: strings.TrimPrefix(r.TagName, "v"), URL: r.HTMLURL}, nil
+}
+
+// Newer reports whether version a is newer than version b.  Versions are
+// compared number by number (so 1.10.0 is newer than 1.9.2); anything after
+// the numbers, such as "rc1", is ignored.
+func Newer(a, b string) bool {
+	x, y := numbers(a), numbers(b)
+	for i := 0; i < len(x) || i < len(y); i++ {
+		var m, n int
+		if i < len(x) {
+			m = x[i]
+		}
+		if i < len(y) {
+			n = y[i]
+		}
+		if m != n {
+			return m > n
+		}
+	}
+	return false
+}
+
+// numbers returns the leading numeric parts of a dotted version string.
+func numbers(version string) []int {
+	var result []int
+	for _, part := range strings.Split(strings.TrimPrefix(version, "v"), ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		result = append(result, n)
+		if end < len(part) {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"macos-systray-widget/config"
+)
+
+// Foliage's default port, used if nothing tells us otherwise.
+const defaultPort = 8080
+
+// resolveURL works out the address of the Foliage user interface.  In order
+// of precedence, it uses the --url flag, the --port flag, the FOLIAGE_URL
+// setting, the PORT setting, and finally the Foliage default port.  (The
+// settings are read from the environment or the Foliage settings file.)
+func resolveURL(urlFlag string, portFlag int) string {
+	if urlFlag != "" {
+		return strings.TrimSuffix(urlFlag, "/")
+	}
+	if portFlag > 0 {
+		return localURL(portFlag)
+	}
+	if url := config.Get("FOLIAGE_URL", ""); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	if port := settingInt("PORT", 0); port > 0 {
+		return localURL(port)
+	}
+	return localURL(defaultPort)
+}
+
+// settingInt returns the integer value of the named setting, or def if the
+// setting is not set or is not an integer.
+func settingInt(key string, def int) int {
+	if value, err := strconv.Atoi(config.Get(key, "")); err == nil {
+		return value
+	}
+	return def
+}
+
+func localURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d", port)
+}
@@ -0,0 +1,27 @@
+// Package urlscheme registers the widget as the handler for foliage:// URLs,
+// so that links in other applications can reach Foliage.  When the user
+// opens such a link, the system runs the widget with the option --open-url
+// and the link.  Each system keeps its URL handlers in a different place: the
+// registry on Windows, a desktop entry on Linux, and on macOS the
+// Info.plist of an application bundle, which this package makes for the
+// purpose.
+package urlscheme
+
+import "errors"
+
+// Scheme is the URL scheme the widget handles.
+const Scheme = "foliage"
+
+// ErrNotSupported is returned on systems where no handler can be registered.
+var ErrNotSupported = errors.New("registering a URL handler is not supported on this system")
+
+// Register makes the program exe the handler for foliage:// URLs, replacing
+// any earlier registration.
+func Register(exe string) error {
+	return register(exe)
+}
+
+// Unregister removes the registration, if there is one.
+func Unregister() error {
+	return unregister()
+}
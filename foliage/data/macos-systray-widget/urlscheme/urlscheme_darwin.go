@@ -0,0 +1,85 @@
+package urlscheme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+)
+
+// lsregister tells Launch Services about the bundle, so that the scheme
+// works at once rather than after Launch Services next scans for apps.
+const lsregister = "/System/Library/Frameworks/CoreServices.framework/Frameworks/" +
+	"LaunchServices.framework/Support/lsregister"
+
+// appPath returns the path of the handler bundle, in the user's own
+// Applications folder.
+func appPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Applications", "Foliage URL Handler.app"), nil
+}
+
+// register makes a small AppleScript application that runs exe with the
+// link.  macOS delivers URLs to applications as Apple events rather than
+// command-line arguments, and AppleScript applications turn them into calls
+// of their "open location" handlers, which saves the widget from handling
+// Apple events itself.  The bundle's Info.plist then declares the scheme.
+func register(exe string) error {
+	app, err := appPath()
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`on open location theURL
+	do shell script quoted form of %s & " --open-url " & quoted form of theURL & " > /dev/null 2>&1 &"
+end open location
+`, shellquote.AppleScript(exe))
+	if err := os.MkdirAll(filepath.Dir(app), 0o755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(app); err != nil {
+		return err
+	}
+	plist := filepath.Join(app, "Contents", "Info.plist")
+	for _, args := range [][]string{
+		{"osacompile", "-o", app, "-e", script},
+		{"plutil", "-replace", "CFBundleIdentifier", "-string", "org.caltechlibrary.foliage.urlhandler", plist},
+		{"plutil", "-replace", "LSUIElement", "-bool", "YES", plist},
+		{"plutil", "-replace", "CFBundleURLTypes", "-json",
+			`[{"CFBundleURLName": "Foliage link", "CFBundleURLSchemes": ["` + Scheme + `"]}]`, plist},
+		{lsregister, "-f", app},
+	} {
+		if err := run(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unregister() error {
+	app, err := appPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(app); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	// Forget the bundle before removing it, while Launch Services can
+	// still read it.
+	run(lsregister, "-u", app)
+	return os.RemoveAll(app)
+}
+
+func run(args ...string) error {
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v: %s", filepath.Base(args[0]), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package urlscheme
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// The key for the scheme under HKEY_CURRENT_USER\Software\Classes, which
+// registers it for the current user only, without needing administrator
+// rights.
+const classKey = `Software\Classes\` + Scheme
+
+func register(exe string) error {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, classKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	if err := k.SetStringValue("", "URL:Foliage"); err != nil {
+		return err
+	}
+	// This empty value is what marks the key as a URL scheme.
+	if err := k.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+	cmd, _, err := registry.CreateKey(k, `shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer cmd.Close()
+	return cmd.SetStringValue("", syscall.EscapeArg(exe)+` --open-url "%1"`)
+}
+
+func unregister() error {
+	// Keys must be deleted from the bottom up.
+	for _, key := range []string{`\shell\open\command`, `\shell\open`, `\shell`, ""} {
+		err := registry.DeleteKey(registry.CURRENT_USER, classKey+key)
+		if err != nil && err != registry.ErrNotExist {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package urlscheme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"macos-systray-widget/internal/shellquote"
+	"macos-systray-widget/internal/xdg"
+)
+
+// The name of the desktop entry that declares the handler.
+const desktopName = "foliage-url-handler.desktop"
+
+// desktopPath returns the path of the desktop entry in the user's
+// applications directory.
+func desktopPath() (string, error) {
+	dir, err := xdg.DataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "applications", desktopName), nil
+}
+
+// register writes a desktop entry for the MIME type that desktops use for
+// the scheme, and makes it the default for that type.  NoDisplay keeps the
+// entry out of the applications menu.
+func register(exe string) error {
+	path, err := desktopPath()
+	if err != nil {
+		return err
+	}
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Foliage
+Comment=Open foliage:// links in Foliage
+Exec=%s --open-url %%u
+MimeType=x-scheme-handler/%s;
+NoDisplay=true
+Terminal=false
+`, shellquote.DesktopExec(exe), Scheme)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil {
+		return err
+	}
+	out, err := exec.Command("xdg-mime", "default", desktopName, "x-scheme-handler/"+Scheme).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xdg-mime failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func unregister() error {
+	path, err := desktopPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"macos-systray-widget/batch"
+	"macos-systray-widget/foliolib"
+	"macos-systray-widget/identifiers"
+)
+
+// runValidate is "foliaged validate", which checks a job's input file
+// before the job is run (see batch.Job.Validate), prints the problems it
+// finds, and writes a copy of the file saying what is wrong with each row.
+// It exits with status 1 if there are problems.
+func runValidate(args []string) int {
+	fs := subcommandFlags("foliaged")
+	folio := fs.Bool("folio", false, "also check that each identifier is of exactly one record in FOLIO")
+	output := fs.String("output", "", "where to write the annotated copy of the input file")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s validate [--folio] [--output FILE] JOB\n", foliagedName())
+		return 2
+	}
+	job, err := batch.LoadJob(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var c *foliolib.Client
+	var types identifiers.Types
+	if *folio {
+		if c, err = folioClient(ctx, nil); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		types = c.Identifiers
+	} else if settings, err := foliolib.FromSettings(); err == nil {
+		types = settings.Identifiers
+	}
+	if *output == "" {
+		*output = job.ValidationPath()
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	v, err := job.Validate(ctx, types, c, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(*output)
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, p := range v.Problems {
+		fmt.Println(p)
+	}
+	checked := ""
+	if v.Checked {
+		checked = ", looked up in FOLIO"
+	}
+	fmt.Printf("%s: %s, %s%s; %s\n", job.InputPath(), count(v.Rows, "row"), count(v.Identifiers, "identifier"), checked, count(len(v.Problems), "problem"))
+	if len(v.Problems) == 0 {
+		os.Remove(*output)
+		return 0
+	}
+	fmt.Printf("the rows, with their problems, are in %s\n", *output)
+	return 1
+}
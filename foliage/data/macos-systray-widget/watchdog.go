@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/systray"
+	"macos-systray-widget/crash"
+	"macos-systray-widget/locale"
+	"macos-systray-widget/notify"
+)
+
+// How often the watchdog checks that the Foliage process is still running.
+const watchInterval = time.Second
+
+// Flags set when the user has asked to quit, and when the watchdog has found
+// that Foliage has stopped.  Accessed atomically.
+var quitting, stopped int32
+
+// watchProcess watches the Foliage process with the given pid.  If it exits
+// when the user hasn't asked to quit, the tray icon is changed to show that
+// Foliage has stopped, the user is notified, and the menu items for starting
+// Foliage again are shown (if we have a command for doing that).
+func watchProcess(pid int) {
+	defer crash.Recover()
+	for processAlive(pid) {
+		time.Sleep(watchInterval)
+	}
+	if atomic.LoadInt32(&quitting) != 0 || serverPid() != pid {
+		return
+	}
+	log.Printf("Foliage process %d exited unexpectedly", pid)
+	atomic.StoreInt32(&stopped, 1)
+	showState("stopped")
+	message := locale.T("Foliage has stopped unexpectedly.")
+	if foliageCommand != "" {
+		message = locale.T("Foliage has stopped unexpectedly. Use the Foliage menu to restart it.")
+		showItems(restartItems, true)
+	}
+	err := notify.Post(notify.Notification{Title: "Foliage", Message: message})
+	if err != nil && err != notify.ErrNotSupported {
+		log.Printf("unable to post notification: %v", err)
+	}
+}
+
+// showItems shows or hides the given menu items.
+func showItems(items []*systray.MenuItem, show bool) {
+	for _, item := range items {
+		if show {
+			item.Show()
+		} else {
+			item.Hide()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package webhook posts messages to chat services' incoming webhooks, such
+// as a summary of a batch job that has finished, for teams who coordinate
+// their work in Slack or Microsoft Teams.  The form of the message is chosen
+// by the webhook's URL: Slack's webhooks (hooks.slack.com) get Slack's
+// form, Teams' (webhook.office.com, and the Workflows webhooks of
+// logic.azure.com and powerplatform.com) get an Adaptive Card, and any
+// other URL gets the message as plain JSON, with its text in "text", which
+// is what Mattermost and Rocket.Chat, among others, expect.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// How long to wait for a webhook to answer.
+const timeout = 15 * time.Second
+
+// Message is what is posted.
+type Message struct {
+	Title string `json:"title"`
+	Text  string `json:"text,omitempty"` // Paragraphs, separated by blank lines.
+	Facts []Fact `json:"facts,omitempty"`
+	Link  string `json:"link,omitempty"` // A URL for more, such as a file of results.
+}
+
+// Fact is a name and a value, shown as a row of a table, where the service
+// has them.
+type Fact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Kind is a kind of webhook, by the service it belongs to.
+type Kind string
+
+// The kinds of webhooks.
+const (
+	Slack   Kind = "slack"
+	Teams   Kind = "teams"
+	Generic Kind = "generic"
+)
+
+// KindOf returns the kind of the webhook at the URL.
+func KindOf(webhook string) Kind {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return Generic
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return Slack
+	case strings.HasSuffix(host, ".webhook.office.com"), host == "outlook.office.com",
+		strings.HasSuffix(host, ".logic.azure.com"), strings.HasSuffix(host, ".powerplatform.com"):
+		return Teams
+	}
+	return Generic
+}
+
+// Post posts the message to the webhook.
+func Post(ctx context.Context, webhook string, m Message) error {
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%q isn't a webhook URL", webhook)
+	}
+	var body interface{}
+	switch KindOf(webhook) {
+	case Slack:
+		body = slack(m)
+	case Teams:
+		body = teams(m)
+	default:
+		generic := m
+		generic.Text = m.String()
+		body = generic
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to post to the webhook at %s: %w", u.Host, err)
+	}
+	defer resp.Body.Close()
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook at %s answered %s: %s", u.Host, resp.Status, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
+
+// String returns the message as plain text, with the facts as lines of
+// their own, for services that take only text.
+func (m Message) String() string {
+	var b strings.Builder
+	b.WriteString(m.Title)
+	if len(m.Facts) > 0 {
+		b.WriteString("\n")
+		for _, f := range m.Facts {
+			fmt.Fprintf(&b, "\n%s: %s", f.Name, f.Value)
+		}
+	}
+	if m.Text != "" {
+		b.WriteString("\n\n" + m.Text)
+	}
+	if m.Link != "" {
+		b.WriteString("\n\n" + m.Link)
+	}
+	return b.String()
+}
+
+// slack returns the message in Slack's form, with its own markup.
+func slack(m Message) interface{} {
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*", escape(m.Title))
+	for _, f := range m.Facts {
+		fmt.Fprintf(&b, "\n• %s: %s", escape(f.Name), escape(f.Value))
+	}
+	if m.Text != "" {
+		b.WriteString("\n\n" + escape(m.Text))
+	}
+	if m.Link != "" {
+		fmt.Fprintf(&b, "\n\n<%s|Results>", m.Link)
+	}
+	return map[string]interface{}{"text": b.String()}
+}
+
+// teams returns the message as an Adaptive Card, which both Teams'
+// connectors and its Workflows take.
+func teams(m Message) interface{} {
+	body := []interface{}{
+		map[string]interface{}{"type": "TextBlock", "text": m.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+	if len(m.Facts) > 0 {
+		var facts []interface{}
+		for _, f := range m.Facts {
+			facts = append(facts, map[string]string{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+	// A line break in a TextBlock isn't always shown as one, so each line
+	// has a block of its own, with the space between paragraphs only.
+	for _, p := range strings.Split(m.Text, "\n\n") {
+		for i, line := range strings.Split(p, "\n") {
+			block := map[string]interface{}{"type": "TextBlock", "text": line, "wrap": true}
+			if i > 0 {
+				block["spacing"] = "None"
+			}
+			if line != "" {
+				body = append(body, block)
+			}
+		}
+	}
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if m.Link != "" {
+		card["actions"] = []interface{}{map[string]string{"type": "Action.OpenUrl", "title": "Results", "url": m.Link}}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	}
+}
@@ -37,6 +37,8 @@ from   foliage.ui import confirm, notify, user_file, stop_processbar
 from   foliage.ui import tell_success, tell_warning, tell_failure
 from   foliage.ui import note_info, note_warn, note_error
 from   foliage.ui import PROGRESS_BOX, PROGRESS_TEXT
+from   foliage.jobs import job_progress, job_finished
+from   foliage.recent import record_job
 
 
 # Tab definition class.
@@ -95,6 +97,7 @@ def stop():
     log(f'stopping')
     interrupt()
     stop_processbar()
+    job_finished()
 
 
 _results = []
@@ -104,6 +107,16 @@ def clear_results():
     _results = []
 
 
+def failure_count():
+    return sum(1 for result in _results if not result['success'])
+
+
+def show_progress(operation, done, steps):
+    # This is also where the job waits if the user pauses it from the widget.
+    set_processbar('bar', done/steps)
+    job_progress(operation, done, steps, failure_count())
+
+
 def record_result(record_or_id, success, notes):
     global _results
     id = record_or_id if isinstance(record_or_id, str) else record_or_id.id
@@ -137,13 +150,15 @@ def flagged(record_or_id, msg, why = ''):
     tell_warning(f'Note about **{id}**{comment}: ' + msg + '.')
 
 
-def do_delete():
+def do_delete(confirmed = False):
+    '''Delete the records in the text box, after asking the user to confirm,
+    unless "confirmed" says the user already has (in the widget's dialog).'''
     log(f'do_delete invoked')
     identifiers = unique_identifiers(pin.textbox_delete)
     if not identifiers:
         note_error('Please input at least one barcode or other type of id.')
         return
-    if not confirm('Warning: if the deletions include holdings and/or instance'
+    if not confirmed and not confirm('Warning: if the deletions include holdings and/or instance'
                    ' records, all their associated items and holdings will'
                    ' also be deleted. Only do this if you have verified the'
                    ' implications first. Proceed?', danger = True):
@@ -153,6 +168,7 @@ def do_delete():
     reset_interrupts()
     steps = 2*len(identifiers)       # Count getting records, for more action.
     folio = Folio()
+    stopped = False
     with use_scope('output', clear = True):
         put_grid([[
             put_scope('current_activity', [
@@ -172,7 +188,7 @@ def do_delete():
                     failed(id, f'unrecognized identifier **{id}**')
                     continue
                 done += 1
-                set_processbar('bar', done/steps)
+                show_progress('Getting records', done, steps)
                 if record.kind not in _HANDLERS.keys():
                     skipped(id, f'deleting {record.kind} records is not supported')
                     continue
@@ -189,12 +205,14 @@ def do_delete():
                     put_markdown(text).style(PROGRESS_TEXT)
                 _HANDLERS[record.kind](record)
                 done += 1
-                set_processbar('bar', done/steps)
+                show_progress('Deleting records', done, steps)
             set_processbar('bar', 1)
             clear_scope('current_activity')
         except Interrupted as ex:
+            # Stopped by the user, here or from the widget.  Still offer the
+            # summary, so they can see what was done before it stopped.
             tell_warning('**Stopped**.')
-            return
+            stopped = True
         except Exception as ex:
             import traceback
             log('Exception info: ' + str(ex) + '\n' + traceback.format_exc())
@@ -202,9 +220,17 @@ def do_delete():
             return
         finally:
             stop_processbar()
+            job_finished()
 
+        if stopped:
+            clear_scope('current_activity')
+            what = pluralized('record', _results, True)
+            text = f'Stopped after processing {what}; the rest were not deleted.'
+        else:
+            text = 'Finished deletions.'
+        record_job('Deleting records', text, _results)
         put_grid([[
-            put_markdown('Finished deletions.').style('margin-top: 6px'),
+            put_markdown(text).style('margin-top: 6px'),
             put_button('Export summary', outline = True,
                        onclick = lambda: do_export('foliage-deletions.csv'),
                        ).style('text-align: right')
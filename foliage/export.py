@@ -1,6 +1,11 @@
 '''
 export.py: let the user export records and save them to a file
 
+If the system tray widget can show the system's own Save dialog, the user
+chooses where to save the file with it, and Foliage writes the file there.
+Otherwise, the file is downloaded by the browser, which usually puts it in
+the user's Downloads folder.
+
 Copyright
 ---------
 
@@ -14,6 +19,7 @@ from   commonpy.interrupt import wait
 import csv
 from   io import BytesIO, StringIO
 import json
+from   os.path import basename, splitext
 from   pywebio.input import input, select, checkbox, radio
 from   pywebio.input import NUMBER, TEXT, input_update, input_group
 from   pywebio.output import put_text, put_markdown, put_row, put_html
@@ -32,6 +38,8 @@ import threading
 
 from   foliage.folio import Folio, RecordKind, IdKind, TypeKind
 from   foliage.ui import quit_app, reload_page, confirm, notify, note_error
+from   foliage.ui import note_info, note_warn
+from   foliage.widget_control import widget_choose_file
 
 
 # Main functions.
@@ -101,7 +109,7 @@ def export_data(data_list, filename):
             writer.writerow(item_dict)
         tmp.seek(0)
         bytes = BytesIO(tmp.read().encode('utf8')).getvalue()
-        download(filename, bytes)
+        save_file(filename, bytes)
 
 
 # Miscellaneous helper functions.
@@ -144,7 +152,7 @@ def export_records_csv(records, kind):
             writer.writerow(item_dict)
         tmp.seek(0)
         bytes = BytesIO(tmp.read().encode('utf8')).getvalue()
-        download(f'{slugify(kind)}-records.csv', bytes)
+        save_file(f'{slugify(kind)}-records.csv', bytes)
 
 
 def export_records_json(records, kind):
@@ -156,4 +164,26 @@ def export_records_json(records, kind):
         json.dump(records_json, tmp)
         tmp.seek(0)
         bytes = BytesIO(tmp.read().encode('utf8')).getvalue()
-        download(f'{slugify(kind)}-records.json', bytes)
+        save_file(f'{slugify(kind)}-records.json', bytes)
+
+
+def save_file(filename, bytes):
+    '''Save the bytes in a file the user chooses, suggesting filename.'''
+    path = widget_choose_file('save', 'Save the exported records as:',
+                              name = filename, types = [splitext(filename)[1][1:]])
+    if path is None:
+        # The widget can't show a Save dialog; have the browser download it.
+        download(filename, bytes)
+        return
+    if not path:
+        log('user canceled saving the exported records')
+        return
+    log(f'writing exported records to {path}')
+    try:
+        with open(path, 'wb') as f:
+            f.write(bytes)
+    except OSError as ex:
+        log(f'unable to write {path}: ' + str(ex))
+        note_error(f'Unable to save {basename(path)}: {str(ex)}')
+        return
+    note_info(f'Saved {basename(path)}.')
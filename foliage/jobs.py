@@ -0,0 +1,122 @@
+'''
+jobs.py: keep track of the batch operation that Foliage is running
+
+Foliage runs one batch operation at a time (changing or deleting a list of
+records).  The loops that carry out those operations call job_progress()
+after each step.  That function reports the progress to the system tray
+widget, and it is also where the operation can be paused: if the user has
+chosen "Pause job" in the widget's menu, job_progress() does not return
+until the user resumes the job (or stops it altogether).
+
+The widget asks for pausing, resuming and canceling through the endpoints in
+server.py, which call pause_job(), resume_job() and cancel_job().  Those are
+called from Tornado's thread, while the operation runs in a PyWebIO session
+thread, so the state is guarded by a lock.  Canceling works the same way as
+the Stop button in the Foliage window, by interrupting the operation.
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   commonpy.interrupt import wait, raise_for_interrupts, interrupt
+from   sidetrack import log
+import threading
+
+from   foliage.widget_control import widget_progress, widget_idle
+
+
+# Internal variables.
+# .............................................................................
+
+_lock = threading.Lock()
+'''Guards the variables below.'''
+
+_job = None
+'''Latest progress of the running job, as a dict, or None if none is running.'''
+
+_resumed = threading.Event()
+'''Set unless the running job has been paused.'''
+_resumed.set()
+
+
+# Exported functions.
+# .............................................................................
+
+def job_progress(operation, done, total, errors = 0):
+    '''Record the progress of the running job, and wait if it is paused.
+
+    Raises commonpy.interrupt.Interrupted if the job has been stopped.
+    '''
+    global _job
+    with _lock:
+        _job = {'operation': operation, 'done': done, 'total': total,
+                'errors': errors}
+        _report()
+    while not _resumed.is_set():
+        raise_for_interrupts()
+        wait(0.5)
+    raise_for_interrupts()
+
+
+def job_finished():
+    '''Record that the running job has finished or been stopped.'''
+    global _job
+    with _lock:
+        _job = None
+        _resumed.set()
+    widget_idle()
+
+
+def pause_job():
+    '''Pause the running job.  Returns False if there is no job to pause.'''
+    with _lock:
+        if not _job:
+            return False
+        log(f'pausing job: {_job["operation"]}')
+        _resumed.clear()
+        _report()
+    return True
+
+
+def resume_job():
+    '''Resume the paused job.  Returns False if there is no job to resume.'''
+    with _lock:
+        if not _job:
+            return False
+        log(f'resuming job: {_job["operation"]}')
+        _resumed.set()
+        _report()
+    return True
+
+
+def cancel_job():
+    '''Stop the running job.  Returns a dict describing how far the job had
+    gotten, or None if there is no job to stop.'''
+    with _lock:
+        if not _job:
+            return None
+        log(f'canceling job: {_job["operation"]}')
+        job = dict(_job)
+        interrupt()
+        # Wake up the job if it's paused, so that it sees the interrupt.
+        _resumed.set()
+    return job
+
+
+def current_job():
+    '''Return a dict describing the running job, or None.'''
+    with _lock:
+        return dict(_job, paused = not _resumed.is_set()) if _job else None
+
+
+# Internal functions.
+# .............................................................................
+
+def _report():
+    # Must be called with _lock held.
+    widget_progress(_job['operation'], _job['done'], _job['total'],
+                    _job['errors'], paused = not _resumed.is_set())
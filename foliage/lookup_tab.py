@@ -34,9 +34,11 @@ from   foliage.base_tab import FoliageTab
 from   foliage.export import export_records
 from   foliage.folio import Folio, RecordKind, IdKind, TypeKind
 from   foliage.folio import unique_identifiers
+from   foliage.recent import record_lookup
 from   foliage.ui import confirm, notify, user_file, stop_processbar
 from   foliage.ui import tell_success, tell_warning, tell_failure
 from   foliage.ui import note_info, note_warn, note_error, PROGRESS_BOX
+from   foliage.widget_control import widget_notify, widget_last_record
 
 
 # Tab definition class.
@@ -116,6 +118,54 @@ _last_inventory_api = True
 _last_open_loans = True
 _location_map = None
 
+_requests = []
+'''Lookups asked for from outside Foliage, as (identifiers, kind, report).'''
+
+_requests_lock = threading.Lock()
+
+
+def request_lookup(identifiers, kind = None, report = False):
+    '''Ask for a lookup from outside Foliage, such as from a foliage:// link.
+
+    The lookup is carried out by the next Foliage page that checks for one
+    (see run_requested_lookup()).  This is called from the web server's
+    thread, not a page's session thread, so it can't change the page itself.
+    If report is True, the outcome is also reported in a notification from
+    the system tray widget, for lookups asked for by a barcode scanner.
+    '''
+    log(f'lookup requested for {identifiers}')
+    with _requests_lock:
+        _requests.append((identifiers, kind, report))
+
+
+def run_requested_lookup():
+    '''If a lookup has been asked for, put it in the lookup tab and do it.'''
+    with _requests_lock:
+        if not _requests:
+            return
+        identifiers, kind, report = _requests.pop(0)
+    summary = run_lookup(identifiers, kind)
+    if report:
+        if not summary:
+            summary = 'The lookup did not finish.'
+        if len(identifiers) == 1:
+            summary = f'{identifiers[0]}: {summary}'
+        widget_notify(summary, url = '/')
+
+
+def run_lookup(identifiers, kind = None):
+    '''Put the identifiers (and kind, if given) in the lookup tab, and do it.
+
+    Returns the summary of what was found, or None if the lookup did not
+    finish.
+    '''
+    # The lookup tab is the first tab, but the user may have switched away.
+    run_js('document.querySelector(".webio-tabs > label")?.click()')
+    pin.textbox_find = '\n'.join(identifiers)
+    if kind:
+        pin.select_kind = RecordKind(kind)
+    return do_find()
+
 
 def load_file():
     log(f'user requesting file upload')
@@ -248,6 +298,7 @@ def do_find():
     folio = Folio()
     init_location_map()
     total_found = 0
+    summary = None
     with use_scope('output', clear = True):
         put_grid([[
             put_scope('current_activity', [
@@ -289,6 +340,7 @@ def do_find():
                 show_index = (len(records) > 1)
                 for index, record in enumerate(records, start = 1):
                     print_record(record, id, index, show_index, pin.show_raw == 'json')
+                widget_last_record(records[-1])
                 total_found += len(records)
             except Interrupted as ex:
                 log('stopping due to interruption')
@@ -310,6 +362,7 @@ def do_find():
             summary = (f'Found {total_found} {kind_wanted} records by looking up '
                        + pluralized('unique identifier', identifiers, True)
                        + '.')
+            record_lookup(identifiers, kind_wanted)
             put_grid([[
                 put_markdown(summary).style('margin-top: 6px'),
                 put_button('Export', outline = True,
@@ -317,6 +370,7 @@ def do_find():
                            ).style('text-align: right')
             ]]).style('margin: 1.5em 17px auto 17px')
         _running = False
+    return summary
 
 
 def field(record, field_name, subfield_name = None, list_joiner = ', '):
@@ -0,0 +1,110 @@
+'''
+recent.py: remember the lookups and batch jobs Foliage has done recently
+
+Staff sometimes close the browser tab showing the results of a long job and
+lose them.  This module keeps the last few lookups and batch jobs so they
+can be reopened from the system tray widget's "Recent" submenu.  Each one
+is reported to the widget as it finishes, with a URL for reopening it:
+
+* a lookup is reopened by loading the Foliage page with ?recent=N in the
+  URL, which makes the page do the same lookup again (see __main__.py)
+
+* a batch job is reopened by loading /recent/N, a page made by the web
+  server (see server.py) listing the outcome for each record, the same
+  information as the job's "Export summary" button provides
+
+The entries are kept in memory only, so they go away when Foliage exits.
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   commonpy.data_utils import pluralized
+from   sidetrack import log
+import threading
+import time
+
+from   foliage.widget_control import widget_recent
+
+
+# Internal constants.
+# .............................................................................
+
+_MAX_RECENT = 10
+'''Number of entries kept (the same number the widget's submenu shows).'''
+
+_MAX_TITLE_IDS = 2
+'''Number of identifiers named in the title of a lookup's entry.'''
+
+
+# Internal variables.
+# .............................................................................
+
+_lock = threading.Lock()
+'''Guards the variables below.'''
+
+_entries = []
+'''The recent entries, oldest first, as dicts.'''
+
+_next_number = 1
+'''Number of the next entry, used in its URL.'''
+
+
+# Exported functions.
+# .............................................................................
+
+def record_lookup(identifiers, kind):
+    '''Remember a lookup of the given identifiers for records of "kind".'''
+    kind = getattr(kind, 'value', kind)     # Could be a RecordKind.
+    shown = ', '.join(identifiers[:_MAX_TITLE_IDS])
+    if len(identifiers) > _MAX_TITLE_IDS:
+        shown += f' and {len(identifiers) - _MAX_TITLE_IDS} more'
+    entry = _add({'kind': 'lookup', 'title': f'Look up {shown}',
+                  'identifiers': list(identifiers), 'record_kind': kind})
+    widget_recent(entry['title'], f'Look up {kind} records again',
+                  f'/?recent={entry["number"]}')
+
+
+def record_job(operation, summary, results):
+    '''Remember a batch job.
+
+    "operation" names the job (e.g., "Changing records"), "summary" is the
+    sentence Foliage showed when it finished, and "results" is the job's list
+    of dicts with the keys "id", "success" and "notes".
+    '''
+    results = [{'id': r['id'], 'success': r['success'], 'notes': r['notes']}
+               for r in results]
+    failures = sum(1 for r in results if not r['success'])
+    title = f'{operation}: {pluralized("record", results, True)}'
+    if failures:
+        title += f' ({failures} failed)'
+    entry = _add({'kind': 'job', 'title': title, 'summary': summary,
+                  'results': results})
+    widget_recent(entry['title'], summary, f'/recent/{entry["number"]}')
+
+
+def recent_entry(number):
+    '''Return the entry with the given number, or None if it's gone.'''
+    with _lock:
+        for entry in _entries:
+            if entry['number'] == number:
+                return entry
+    return None
+
+
+# Internal functions.
+# .............................................................................
+
+def _add(entry):
+    global _next_number
+    with _lock:
+        entry.update(number = _next_number, time = time.time())
+        _next_number += 1
+        _entries.append(entry)
+        del _entries[:-_MAX_RECENT]
+    log(f'recorded recent {entry["kind"]} #{entry["number"]}: {entry["title"]}')
+    return entry
@@ -0,0 +1,383 @@
+'''
+server.py: run the Foliage web server
+
+Foliage used to start its server by calling PyWebIO's start_server().  That
+function doesn't provide a way to add request handlers of our own, so this
+module sets up the Tornado application itself (which is how the PyWebIO docs
+describe integrating PyWebIO into an existing Tornado application) and adds
+a small status endpoint alongside the PyWebIO application.
+
+The status endpoint is at /status.  A GET request returns a JSON object with
+the Foliage version and information about the FOLIO tenant currently in use,
+including whether Foliage holds a valid FOLIO token and when the token
+expires, if it does.  (Checking the token takes a request to FOLIO, so the
+result is reused for a few minutes.)
+It is used by the system tray widget for its "About Foliage" dialog, so that
+support staff can find out exactly which version a user is running.  The
+FOLIO token is never included.
+
+The job endpoints let the widget control the batch operation that Foliage is
+running (see jobs.py).  They take POST requests at /job/pause, /job/resume
+and /job/cancel, and answer with a JSON object of the form {"ok": true} or,
+with an HTTP status of 400 or more (409 if no job is running, or one is
+running that the request would get in the way of), {"ok": false, "error":
+"..."}, like the widget's own control API.  The answer
+to /job/cancel also has a field "job" describing how far the job had gotten.
+
+The demo mode endpoints let the widget turn Foliage's demo mode (in which
+Foliage goes through the motions of changing records without changing them)
+on and off, so that trainers can switch modes without restarting Foliage.
+They take POST requests at /demo-mode/on and /demo-mode/off, and answer the
+same way as the job endpoints.  Switching modes is refused while a job is
+running, so that a job can't end up changing only some of the records.
+
+The lookup endpoint lets the widget hand Foliage a lookup to do, for the
+widget's foliage:// links (such as foliage://lookup?barcode=350110123456).
+It takes POST requests at /lookup whose body is a JSON object with a list of
+identifiers in the field "identifiers" and, optionally, the kind of record
+to retrieve in the field "kind".  The lookup is done by the next Foliage
+page to check for one, which is within a second if a page is open.  If the
+field "notify" is true, the outcome is also reported in a notification from
+the widget, for barcodes read by a scanner while the user is working in
+another application.
+
+The batch endpoint starts a batch operation on a file of identifiers, for
+files dropped on the widget (see batch.py).  It takes POST requests at
+/batch whose body is a JSON object with the path of the file in the field
+"file" and the operation ("lookup", "change" or "delete") in the field
+"operation".  A deletion also needs the field "confirmed" to be true, which
+says that the user has confirmed it, with the warning the Foliage page gives
+before deleting records; it is refused with status 400 otherwise.  The
+server reads the file itself, so that long lists don't have to be pasted
+into the browser.  It refuses files that it can't read or that have no
+identifiers in them, and requests made while a job is running.
+
+The tenant endpoint lets the widget switch Foliage to another FOLIO tenant,
+for the widget's "FOLIO Server" menu.  It takes POST requests at /tenant
+whose body is a JSON object with the OKAPI URL in the field "url" and the
+tenant id in the field "tenant_id".  The tenant must be one in the widget's
+list of tenants (which the helper program reads; see credential_helper.py),
+and others are refused with status 400.  Foliage uses the token it last had
+for that tenant, if it kept one; otherwise it is left without a token, and
+the status endpoint reports that it is not logged in.  Switching tenants is
+refused while a job is running.
+
+The recent results endpoint, /recent/N, shows the outcome of a recent batch
+job (see recent.py) as a simple page listing each record and what happened
+to it.  It's what the entries in the widget's "Recent" submenu open.  For a
+recent lookup, it sends the browser to the Foliage page to do the lookup
+again.  These pages take GET requests from the browser, which can't send
+the control token, and only show what the Foliage page itself showed.
+
+Requests to the job, demo mode, lookup, batch and tenant endpoints must
+include the widget's control token (the setting FOLIAGE_CONTROL_TOKEN) in the
+header X-Foliage-Token, and are all refused if there is no token.  Requests
+that come from a web page other than Foliage's own, as the Origin header that
+browsers send says, are refused too, so that other pages the user visits
+can't start jobs or switch tenants.
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   decouple import config
+import hmac
+import html
+import json
+import os
+import platform
+from   sidetrack import log
+import threading
+import time
+import tornado.ioloop
+import tornado.web
+import webbrowser
+
+from   pywebio import STATIC_PATH
+from   pywebio.platform.tornado import webio_handler
+
+from   foliage import __version__
+from   foliage.batch import request_batch
+from   foliage.credentials import current_credentials, token_expiration
+from   foliage.credentials import switch_tenant, tenant_configured
+from   foliage.folio import Folio, RecordKind
+from   foliage.jobs import pause_job, resume_job, cancel_job, current_job
+from   foliage.lookup_tab import request_lookup
+from   foliage.recent import recent_entry
+
+
+# Internal constants.
+# .............................................................................
+
+_MAX_PAYLOAD_SIZE = 200 * 2**20
+'''Largest message accepted from the browser (the same as PyWebIO's default).'''
+
+_TOKEN_HEADER = 'X-Foliage-Token'
+'''Header carrying the control token, the same one the widget's API uses.'''
+
+_TOKEN_CHECK_INTERVAL = 300
+'''Number of seconds for which the result of checking the token is reused.'''
+
+
+# Internal variables.
+# .............................................................................
+
+_loop = None
+'''The server's event loop, while it runs.'''
+
+_origins = set()
+'''Origins of the Foliage page, from which control requests are accepted.'''
+
+_token_check = {'creds': None, 'time': 0, 'valid': False}
+'''The last token check: the credentials checked, when, and the outcome.'''
+
+_token_lock = threading.Lock()
+
+
+# Exported functions.
+# .............................................................................
+
+def start_foliage_server(app, port, debug = False, open_webbrowser = True):
+    '''Start the web server for the PyWebIO application function "app".
+
+    This does what PyWebIO's start_server() does, and also serves our status
+    endpoint.  It does not return until the server is stopped.
+    '''
+    # cdn = False makes it load PyWebIO JS code from our local copy.
+    handlers = [(r'/', webio_handler(app, cdn = False)),
+                (r'/status', StatusHandler),
+                (r'/job/(pause|resume|cancel)', JobHandler),
+                (r'/demo-mode/(on|off)', DemoModeHandler),
+                (r'/lookup', LookupHandler),
+                (r'/batch', BatchHandler),
+                (r'/tenant', TenantHandler),
+                (r'/recent/([0-9]+)', RecentHandler),
+                (r'/(.*)', tornado.web.StaticFileHandler,
+                 {'path': STATIC_PATH, 'default_filename': 'index.html'})]
+    application = tornado.web.Application(
+        handlers, debug = debug, websocket_max_message_size = _MAX_PAYLOAD_SIZE)
+    application.listen(int(port), max_buffer_size = _MAX_PAYLOAD_SIZE)
+    url = f'http://localhost:{port}'
+    _origins.update({url, f'http://127.0.0.1:{port}', f'http://[::1]:{port}'})
+    log(f'Foliage server listening at {url}')
+    global _loop
+    loop = _loop = tornado.ioloop.IOLoop.current()
+    if open_webbrowser:
+        loop.add_callback(webbrowser.open, url)
+    loop.start()
+
+
+def stop_foliage_server():
+    '''Make start_foliage_server() return.  It can be called from any thread.'''
+    if _loop:
+        log('stopping Foliage server')
+        _loop.add_callback(_loop.stop)
+
+
+def status():
+    '''Return a dict describing this Foliage process.'''
+    creds = current_credentials()
+    return {
+        'name'          : 'Foliage',
+        'version'       : __version__,
+        'pid'           : os.getpid(),
+        'python'        : platform.python_version(),
+        'platform'      : platform.platform(),
+        'folio_url'     : creds.url,
+        'tenant_id'     : creds.tenant_id,
+        'logged_in'     : _token_valid(creds),
+        'token_expires' : token_expiration(creds.token),
+        'demo_mode'     : os.environ.get('DEMO_MODE') == 'True',
+        'job'           : current_job(),
+    }
+
+
+# Internal functions.
+# .............................................................................
+
+def _token_valid(creds):
+    '''Return True if the credentials include a token that FOLIO accepts.'''
+    if not creds.token:
+        return False
+    with _token_lock:
+        if (creds != _token_check['creds']
+                or time.time() - _token_check['time'] > _TOKEN_CHECK_INTERVAL):
+            _token_check.update(creds = creds, time = time.time(),
+                                valid = bool(Folio.credentials_valid()))
+        return _token_check['valid']
+
+
+# Internal classes.
+# .............................................................................
+
+class StatusHandler(tornado.web.RequestHandler):
+    '''Answer GET requests on the status endpoint.'''
+
+    async def get(self):
+        # Checking the token can take a while, so do it in another thread
+        # rather than hold up the server (including the PyWebIO app).
+        loop = tornado.ioloop.IOLoop.current()
+        info = await loop.run_in_executor(None, status)
+        self.set_header('Content-Type', 'application/json')
+        self.set_header('Cache-Control', 'no-store')
+        self.write(json.dumps(info))
+
+
+class RecentHandler(tornado.web.RequestHandler):
+    '''Answer GET requests for the results of recent lookups and jobs.'''
+
+    def get(self, number):
+        entry = recent_entry(int(number))
+        if not entry:
+            raise tornado.web.HTTPError(404, reason = 'No longer available')
+        if entry['kind'] == 'lookup':
+            self.redirect(f'/?recent={number}')
+            return
+        rows = ''.join(f'<tr><td>{html.escape(r["id"])}</td>'
+                       f'<td>{"Success" if r["success"] else "Failure"}</td>'
+                       f'<td>{html.escape(r["notes"])}</td></tr>'
+                       for r in entry['results'])
+        when = time.strftime('%Y-%m-%d %H:%M', time.localtime(entry['time']))
+        self.set_header('Cache-Control', 'no-store')
+        self.write(f'''<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Foliage: {html.escape(entry['title'])}</title>
+<style>body {{font-family: sans-serif; margin: 2em}} td, th {{padding: 2px 12px;
+text-align: left; vertical-align: top}}</style></head>
+<body><h1>{html.escape(entry['title'])}</h1>
+<p>{html.escape(entry['summary'])} (Finished {when}.)</p>
+<table><tr><th>Record</th><th>Outcome</th><th>Notes</th></tr>{rows}</table>
+</body></html>''')
+
+
+class ControlHandler(tornado.web.RequestHandler):
+    '''Base class for the handlers of POST requests from the widget.'''
+
+    def prepare(self):
+        token = config('FOLIAGE_CONTROL_TOKEN', default = '')
+        given = self.request.headers.get(_TOKEN_HEADER, '')
+        origin = self.request.headers.get('Origin')
+        if origin is not None and origin not in _origins:
+            log(f'refused control request from {origin}')
+            self._refuse('requests from other web pages are not accepted')
+        elif not token or not hmac.compare_digest(token, given):
+            self._refuse('invalid or missing token')
+
+    def _refuse(self, error):
+        self._fail(403, error)
+        self.finish()
+
+    def _reply(self, job = None):
+        self.set_header('Content-Type', 'application/json')
+        self.write(json.dumps({'ok': True, 'job': job}))
+
+    def _fail(self, status, error):
+        # Every refusal has an HTTP status saying so, as well as the error,
+        # the same as the widget's own control API.
+        self.set_status(status)
+        self.set_header('Content-Type', 'application/json')
+        self.write(json.dumps({'ok': False, 'error': error}))
+
+
+class JobHandler(ControlHandler):
+    '''Answer POST requests on the job endpoints.'''
+
+    def post(self, command):
+        if command == 'cancel':
+            job = cancel_job()
+            done = bool(job)
+        else:
+            job = None
+            done = pause_job() if command == 'pause' else resume_job()
+        if done:
+            self._reply(job)
+        else:
+            self._fail(409, 'no job is running')
+
+
+class DemoModeHandler(ControlHandler):
+    '''Answer POST requests on the demo mode endpoints.'''
+
+    def post(self, setting):
+        if current_job():
+            self._fail(409, 'demo mode cannot be changed while a job is running')
+            return
+        log(f'widget set demo mode {setting}')
+        os.environ['DEMO_MODE'] = str(setting == 'on')
+        self._reply()
+
+
+class LookupHandler(ControlHandler):
+    '''Answer POST requests on the lookup endpoint.'''
+
+    def post(self):
+        try:
+            body = json.loads(self.request.body or b'{}')
+            identifiers = body.get('identifiers', [])
+            kind = body.get('kind') or None
+            report = bool(body.get('notify'))
+        except (ValueError, AttributeError):
+            self._fail(400, 'the request is not a JSON object')
+            return
+        if isinstance(identifiers, list):
+            identifiers = [id for id in map(str, identifiers) if id.strip()]
+        if not identifiers or not isinstance(identifiers, list):
+            self._fail(400, 'no identifiers given')
+        elif kind and (kind not in RecordKind or kind == RecordKind.UNKNOWN):
+            self._fail(400, f'unknown kind of record: {kind}')
+        else:
+            request_lookup(identifiers, kind, report)
+            self._reply()
+
+
+class BatchHandler(ControlHandler):
+    '''Answer POST requests on the batch endpoint.'''
+
+    def post(self):
+        try:
+            body = json.loads(self.request.body or b'{}')
+            path, operation = body.get('file'), body.get('operation')
+            confirmed = body.get('confirmed') is True
+        except (ValueError, AttributeError):
+            self._fail(400, 'the request is not a JSON object')
+            return
+        if not isinstance(path, str) or not path:
+            self._fail(400, 'no file given')
+        elif operation == 'delete' and not confirmed:
+            log('refused a delete batch the user has not confirmed')
+            self._fail(400, 'deleting records must be confirmed by the user first')
+        elif current_job():
+            self._fail(409, 'another job is already running')
+        else:
+            try:
+                request_batch(operation, path, confirmed)
+            except (ValueError, OSError) as ex:
+                self._fail(400, str(ex))
+                return
+            self._reply()
+
+
+class TenantHandler(ControlHandler):
+    '''Answer POST requests on the tenant endpoint.'''
+
+    def post(self):
+        try:
+            body = json.loads(self.request.body or b'{}')
+            url, tenant_id = body.get('url'), body.get('tenant_id')
+        except (ValueError, AttributeError):
+            self._fail(400, 'the request is not a JSON object')
+            return
+        if not isinstance(url, str) or not isinstance(tenant_id, str) \
+           or not url or not tenant_id:
+            self._fail(400, 'a url and a tenant_id are needed')
+        elif current_job():
+            self._fail(409, 'the tenant cannot be changed while a job is running')
+        elif not tenant_configured(url, tenant_id):
+            log(f'refused to switch to unlisted tenant {tenant_id} at {url}')
+            self._fail(400, 'the tenant is not in the list of tenants')
+        else:
+            switch_tenant(url.rstrip('/'), tenant_id)
+            self._reply()
@@ -105,6 +105,33 @@ from   sidetrack import log
 import sys
 
 
+# Exit status of the Go widget program when another copy of it is already
+# running and has taken over watching this Foliage process.
+GO_WIDGET_FORWARDED = 3
+
+# Windows' access right for finding out whether a process has exited, and
+# the exit code of one that hasn't.
+_PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+_STILL_ACTIVE = 259
+
+
+def go_widget_path():
+    '''Return the path to the Go helper program for this platform.
+
+    The program is foliage-helper; it runs the tray widget as well as the
+    credential helper and other subcommands.  Copies built before it had
+    that name were called macos-systray-widget, and are used if there is no
+    foliage-helper.
+    '''
+    data_dir = realpath(join(dirname(__file__), 'data', 'macos-systray-widget'))
+    ext = '.exe' if sys.platform.startswith('win') else ''
+    helper = join(data_dir, 'foliage-helper' + ext)
+    if exists(helper):
+        return helper
+    old_name = join(data_dir, 'macos-systray-widget' + ext)
+    return old_name if exists(old_name) else helper
+
+
 class SystemWidget():
     '''Encapsulate the control of a taskbar/system tray widget
 
@@ -125,6 +152,11 @@ class SystemWidget():
        method running() tests whether the process is still running; if it's
        not, it means either the user quit the widget using the "Quit" menu
        or else the widget was killed somehow externally to Foliage.
+
+    The same separate program can also be built for Windows and Linux.  If it
+    is present, it is used in preference to the PyQt widget.  On Linux, if
+    there is no graphical desktop, the program shows nothing and simply
+    waits for Foliage to exit, so it is safe to start it unconditionally.
     '''
 
     def __init__(self):
@@ -134,6 +166,8 @@ class SystemWidget():
         self.widget_thread = None
         if sys.platform.startswith('darwin'):
             self.start_macos_widget()
+        elif exists(self.go_widget_path()):
+            self.start_go_widget()
         else:
             self.start_windows_widget()
 
@@ -151,8 +185,11 @@ class SystemWidget():
         user can select the "Quit" menu item to exit the widget.  If the
         widget process is not running, this method returns False.
         '''
-        if sys.platform.startswith('darwin'):
-            return self.widget_process and (self.widget_process.poll() is None)
+        if self.widget_process:
+            # If another copy of the Go widget was already running, ours
+            # hands over to it and exits; the other copy now watches us.
+            status = self.widget_process.poll()
+            return status is None or status == GO_WIDGET_FORWARDED
         else:
             return self.widget_info and self.widget_info['running']
 
@@ -186,18 +223,54 @@ class SystemWidget():
             log('letting Windows widget get terminated normally on quit')
 
 
+    def go_widget_path(self):
+        '''Return the path to the Go widget program for this platform.'''
+        return go_widget_path()
+
+
     def start_macos_widget(self):
         '''Start the Foliage system tray widget on macOS.'''
-        import subprocess
-        data_dir = realpath(join(dirname(__file__), 'data'))
-        widget = join(data_dir, 'macos-systray-widget', 'macos-systray-widget')
-        if exists(widget):
-            log('starting macos systray widget: ' + widget)
-            self.widget_process = subprocess.Popen(widget)
+        if exists(self.go_widget_path()):
+            self.start_go_widget()
         else:
             log('macos widget binary is not at the expected path')
 
 
+    def start_go_widget(self):
+        '''Start the Go system tray widget program as a subprocess.
+
+        The same Go program is used on macOS and (if it has been built for
+        Windows and bundled with Foliage) on Windows.
+        '''
+        import subprocess
+        widget = self.go_widget_path()
+        log('starting Go systray widget: ' + widget)
+        self.config_widget_control()
+        port = os.environ.get('PORT', '8080')
+        args = [widget, '--port', port, '--pid', str(os.getpid())]
+        self.widget_process = subprocess.Popen(args)
+
+
+    def config_widget_control(self):
+        '''Set up the Go widget's control API, which we use to send it updates.
+
+        Unless the settings FOLIAGE_CONTROL_PORT and FOLIAGE_CONTROL_TOKEN are
+        already defined, this picks a free port and a random token, and sets
+        them as environment variables; the widget inherits them when we start
+        it, and widget_control.py reads them when sending updates.
+        '''
+        from decouple import config
+        import secrets
+        import socket
+        if not config('FOLIAGE_CONTROL_PORT', default = ''):
+            with socket.socket() as s:
+                s.bind(('127.0.0.1', 0))
+                os.environ['FOLIAGE_CONTROL_PORT'] = str(s.getsockname()[1])
+        if not config('FOLIAGE_CONTROL_TOKEN', default = ''):
+            os.environ['FOLIAGE_CONTROL_TOKEN'] = secrets.token_hex(16)
+        log('widget control port is ' + config('FOLIAGE_CONTROL_PORT'))
+
+
     def start_windows_widget(self):
         '''Start the taskbar widget on Windows.'''
         # We use a structured type, not a simple Boolean, because the value
@@ -276,3 +349,71 @@ class SystemWidget():
         # widget thread & return so caller can proceed to its own event loop.
 
         self.widget_info = widget_info
+
+
+class ParentWidget():
+    '''Stand in for SystemWidget when the Go widget started this Foliage.
+
+    The widget runs on its own then, so there is nothing to start or stop,
+    but Foliage has to exit when the widget does.  On macOS and Linux, the
+    widget interrupts Foliage when the user quits it, but Windows gives it no
+    way to, so this watches the widget's process, whose id the widget passes
+    in the environment variable FOLIAGE_WIDGET_PID.  As with SystemWidget,
+    running() tells the Foliage page when the widget has exited, so that it
+    can close its window; on_exit() also stops the server when no page is
+    open to notice.
+    '''
+
+    def __init__(self, pid):
+        self.pid = pid
+
+
+    def running(self):
+        '''Return True if the widget process is still running.'''
+        return process_alive(self.pid)
+
+
+    def stop(self):
+        '''Do nothing; the widget exits by itself.'''
+        pass
+
+
+    def on_exit(self, callback):
+        '''Call the function "callback" (in another thread) once the widget
+        process has exited, after giving the Foliage page time to notice.'''
+        import threading
+
+        def watch():
+            while process_alive(self.pid):
+                wait(1)
+            log(f'widget process {self.pid} has exited')
+            # The page checks running() every second (see foliage_page()).
+            wait(3)
+            callback()
+
+        threading.Thread(target = watch, daemon = True).start()
+
+
+def process_alive(pid):
+    '''Return True if the process with the given process id is running.'''
+    if sys.platform.startswith('win'):
+        # os.kill() would end the process on Windows, so ask Windows.
+        import ctypes
+        kernel32 = ctypes.windll.kernel32
+        handle = kernel32.OpenProcess(_PROCESS_QUERY_LIMITED_INFORMATION, False, pid)
+        if not handle:
+            return False
+        try:
+            code = ctypes.c_ulong()
+            if not kernel32.GetExitCodeProcess(handle, ctypes.byref(code)):
+                return False
+            return code.value == _STILL_ACTIVE
+        finally:
+            kernel32.CloseHandle(handle)
+    try:
+        os.kill(pid, 0)
+    except ProcessLookupError:
+        return False
+    except PermissionError:
+        pass
+    return True
@@ -28,7 +28,7 @@ from   commonpy.string_utils import antiformat
 from   contextlib import contextmanager
 from   decouple import config
 import os
-from   os.path import exists, dirname, join, basename, abspath
+from   os.path import exists, dirname, join, basename, abspath, splitext
 from   PyQt5.QtWidgets import QApplication, QMessageBox
 import pywebio
 from   pywebio.input import input, file_upload
@@ -44,6 +44,8 @@ from   rich.style import Style
 import sys
 import threading
 
+from   foliage.widget_control import widget_choose_file
+
 if __debug__:
     from sidetrack import set_debug, log
 
@@ -241,6 +243,9 @@ margin: 0;
 font-size: 90%;
 '''
 
+FILE_TYPES = ['.csv', '.txt', '.xlsx']
+'''The kinds of files that user_file() and file_text() can read.'''
+
 EXCEL_MIME_TYPES = [
     'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet',
     'application/vnd.ms-excel'
@@ -352,6 +357,13 @@ def reload_page():
     run_js('reload_page()')
 
 
+def show_tab(title):
+    '''Switch the Foliage page to the tab with the given title.'''
+    log(f'switching to tab "{title}"')
+    run_js('[...document.querySelectorAll(".webio-tabs > label")]'
+           f'.find(label => label.textContent.trim() == {title!r})?.click()')
+
+
 def image_data(file_name):
     '''Return the data from the given image file.'''
     here = dirname(__file__)
@@ -367,7 +379,22 @@ def image_data(file_name):
 def user_file(instructions):
     '''Ask the user to upload a file and return the contents as text.
     Currently supports plain text, CSV, and MS Office .xslx files.
+
+    If the system tray widget can show the system's own Open dialog, that is
+    used instead of the browser's upload dialog, and the file is read
+    directly.
     '''
+    path = widget_choose_file('open', instructions,
+                              types = [t[1:] for t in FILE_TYPES])
+    if path is not None:
+        if not path:
+            return None
+        try:
+            return file_text(path)
+        except (ValueError, OSError) as ex:
+            log(f'unable to read {path}: ' + str(ex))
+            notify(f'Unable to read {basename(path)}: {str(ex)}')
+            return None
     result = file_upload(instructions,
                          help_text = 'The file can be in any of the following'
                          + ' formats: .txt (plain text), .csv (comma-separated'
@@ -398,6 +425,23 @@ def user_file(instructions):
     return None
 
 
+def file_text(path):
+    '''Return the contents of the file at path as text, like user_file().
+
+    Raises ValueError if the file is not of a kind Foliage can read, and
+    OSError if it can't be read.
+    '''
+    extension = splitext(path)[1].lower()
+    if extension not in FILE_TYPES:
+        raise ValueError(f'unsupported type of file: {basename(path)}')
+    if extension == '.xlsx':
+        from openpyxl import load_workbook
+        ws = load_workbook(path, read_only = True).active
+        return '\n'.join(str(v) for v in flattened(ws.values) if v is not None)
+    with open(path, encoding = 'utf-8-sig', errors = 'replace') as f:
+        return f.read()
+
+
 # The remainder of this file implements functions for reporting info, warning,
 # success and failure, using 2 separate approaches. Summary of the scheme:
 #
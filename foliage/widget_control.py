@@ -0,0 +1,173 @@
+'''
+widget_control.py: send updates to the system tray widget
+
+The Go version of the system tray widget (in data/macos-systray-widget/) has a
+small HTTP control API on localhost.  When Foliage starts the widget, it
+picks a port and a random token for the API and puts them in the environment
+variables FOLIAGE_CONTROL_PORT and FOLIAGE_CONTROL_TOKEN, which the widget
+inherits (see system_widget.py).  The functions in this module use those
+settings to tell the widget what Foliage is doing, such as how far along a
+batch change operation is.  The widget can also show the system's own file
+dialogs for Foliage (see widget_choose_file()), because the browser's
+dialogs can't pick a folder or a location to save to.
+
+Sending an update must never hold up the operation being reported on, so
+updates are queued and sent by a background thread.  Progress updates are
+coalesced: if several arrive while one is being sent, only the latest one is
+sent next.  Failures are logged and otherwise ignored, because the widget is
+a convenience and Foliage works fine without it.
+
+Copyright
+---------
+
+Copyright (c) 2021-2022 by the California Institute of Technology.  This code
+is open-source software released under a 3-clause BSD license.  Please see the
+file "LICENSE" for more information.
+'''
+
+from   decouple import config
+import json
+from   sidetrack import log
+import threading
+import urllib.error
+import urllib.request
+
+
+# Internal constants.
+# .............................................................................
+
+_TIMEOUT = 3
+'''Number of seconds to wait for the widget to answer a request.'''
+
+_DIALOG_TIMEOUT = 3600
+'''Number of seconds to wait for the user to close a file dialog.'''
+
+
+# Internal variables.
+# .............................................................................
+
+_lock = threading.Condition()
+'''Guards the pending updates and wakes up the sender thread.'''
+
+_pending = []
+'''List of (command, body) pairs waiting to be sent, in order.'''
+
+_sender = None
+'''The background thread that sends updates, started on first use.'''
+
+
+# Exported functions.
+# .............................................................................
+
+def widget_control_available():
+    '''Return True if the widget's control API is configured.'''
+    return bool(config('FOLIAGE_CONTROL_PORT', default = ''))
+
+
+def widget_progress(operation, done, total, errors = 0, paused = False):
+    '''Tell the widget how far a batch operation has gotten.'''
+    _send('progress', {'operation': operation, 'done': done, 'total': total,
+                       'errors': errors, 'paused': paused}, coalesce = True)
+
+
+def widget_idle():
+    '''Tell the widget that no batch operation is running.'''
+    _send('progress', {'operation': ''}, coalesce = True)
+
+
+def widget_notify(message, title = 'Foliage', url = ''):
+    '''Have the widget post a desktop notification.
+
+    The url, if any, is opened when the notification is clicked; a path
+    starting with / is relative to the Foliage URL.
+    '''
+    _send('notify', {'title': title, 'message': message, 'url': url})
+
+
+def widget_recent(title, tooltip, url):
+    '''Add an entry to the widget's "Recent" submenu.'''
+    _send('add-recent', {'title': title, 'tooltip': tooltip, 'url': url})
+
+
+def widget_last_record(record):
+    '''Tell the widget the record last shown or changed, so that its
+    "Open Last Record in FOLIO" entry can open it in FOLIO.'''
+    data = record.data or {}
+    body = {'kind': str(getattr(record.kind, 'value', record.kind)),
+            'id': record.id,
+            'instance_id': data.get('instanceId', ''),
+            'holdings_id': data.get('holdingsRecordId', ''),
+            'user_id': data.get('userId', ''),
+            'title': data.get('barcode') or data.get('hrid') or ''}
+    _send('last-record', body, coalesce = True)
+
+
+def widget_choose_file(kind, title = '', directory = '', name = '', types = None):
+    '''Have the widget show a native dialog and return the path chosen.
+
+    The kind is 'open' (to choose a file to read), 'save' (to choose a file
+    to write, with name as the suggested file name) or 'folder'.  The types
+    are file extensions such as 'csv', to limit the files that can be chosen.
+    This waits until the user closes the dialog, and returns '' if the user
+    canceled it, or None if the widget can't show dialogs, so that the
+    caller can fall back to the browser's own way of doing it.
+    '''
+    if not widget_control_available():
+        return None
+    body = {'kind': kind, 'title': title, 'directory': directory,
+            'name': name, 'types': types or []}
+    try:
+        answer = _post('choose-file', body, _DIALOG_TIMEOUT)
+    except Exception as ex:
+        log(f'unable to have the widget show a file dialog: {str(ex)}')
+        return None
+    if not answer.get('ok'):
+        log(f'widget could not show a file dialog: {answer.get("error")}')
+        return None
+    return answer.get('path') or ''
+
+
+# Internal functions.
+# .............................................................................
+
+def _post(command, body, timeout):
+    '''Send the command to the widget now, and return its answer.'''
+    port  = config('FOLIAGE_CONTROL_PORT')
+    token = config('FOLIAGE_CONTROL_TOKEN', default = '')
+    request = urllib.request.Request(
+        f'http://127.0.0.1:{port}/{command}', method = 'POST',
+        data = json.dumps(body).encode('utf-8'),
+        headers = {'Content-Type': 'application/json',
+                   'X-Foliage-Token': token})
+    try:
+        with urllib.request.urlopen(request, timeout = timeout) as response:
+            return json.loads(response.read() or b'{}')
+    except urllib.error.HTTPError as ex:
+        # The widget's error answers have a body saying what went wrong.
+        return json.loads(ex.read() or b'{}')
+
+
+def _send(command, body, coalesce = False):
+    global _sender
+    if not widget_control_available():
+        return
+    with _lock:
+        if coalesce:
+            _pending[:] = [(c, b) for (c, b) in _pending if c != command]
+        _pending.append((command, body))
+        if not _sender:
+            _sender = threading.Thread(target = _send_pending, daemon = True)
+            _sender.start()
+        _lock.notify()
+
+
+def _send_pending():
+    while True:
+        with _lock:
+            while not _pending:
+                _lock.wait()
+            command, body = _pending.pop(0)
+        try:
+            _post(command, body, _TIMEOUT)
+        except Exception as ex:
+            log(f'unable to send {command} to widget: {str(ex)}')
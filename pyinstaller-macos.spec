@@ -83,7 +83,7 @@ data_files = [ ('foliage/data/index.tpl', 'data'),
                # destination and just 'data' like the one above.
                ('foliage/data/foliage-icon-r.png', 'foliage/data'),
                ('foliage/data/foliage-icon.png', 'foliage/data'),
-               ('foliage/data/macos-systray-widget/macos-systray-widget',
+               ('foliage/data/macos-systray-widget/foliage-helper',
                 'foliage/data/macos-systray-widget/'),
                # My local hacked copy of PyWebIO.
                ('../PyWebIO/pywebio/platform/tpl', 'pywebio/platform/tpl'),
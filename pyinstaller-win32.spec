@@ -42,6 +42,8 @@ data_files = [ ('foliage/data/index.tpl', 'data'),
                ('foliage/data/foliage-icon-128x128.png', 'foliage/data'),
                ('foliage/data/foliage-icon-256x256.png', 'foliage/data'),
                ('foliage/data/foliage-icon.ico', 'foliage/data'),
+               ('foliage/data/macos-systray-widget/foliage-helper.exe',
+                'foliage/data/macos-systray-widget/'),
                # Local hacked copy of PyWebIO.
                ('../PyWebIO/pywebio/platform/tpl', 'pywebio/platform/tpl'),
                ('../PyWebIO/pywebio/html', 'pywebio/html'),